@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"payroll-system/api/response"
 	"time"
@@ -24,7 +25,12 @@ func NewAttendanceHandler(service service.AttendanceServiceInterface) *Attendanc
 // SubmitAttendanceRequest represents the request body for submitting attendance.
 type SubmitAttendanceRequest struct {
 	CheckInTime  string `json:"check_in_time" binding:"required"` // YYYY-MM-DD HH:MM:SS
-	CheckOutTime string `json:"check_out_time"`                   // YYYY-MM-DD HH:MM:SS
+	CheckOutTime string `json:"check_out_time"`                   // YYYY-MM-DD HH:MM:SS, omitted to stay clocked in
+}
+
+// UpdateAttendancePolicyRequest represents the request body for changing the attendance policy.
+type UpdateAttendancePolicyRequest struct {
+	OpenAttendance string `json:"open_attendance" binding:"required"` // unpaid or shift_end
 }
 
 // SubmitAttendance handles the submission of employee attendance.
@@ -41,13 +47,14 @@ func (h *AttendanceHandler) SubmitAttendance(c *gin.Context) {
 		return
 	}
 
-	var checkOutTime time.Time
+	var checkOutTime *time.Time
 	if req.CheckOutTime != "" {
-		checkOutTime, err = time.Parse("2006-01-02 15:04:05", req.CheckOutTime)
+		parsed, err := time.Parse("2006-01-02 15:04:05", req.CheckOutTime)
 		if err != nil {
 			response.Error(c, http.StatusBadRequest, "Invalid check_out_time format. Use YYYY-MM-DD HH:MM:SS", nil)
 			return
 		}
+		checkOutTime = &parsed
 	}
 
 	// Get current user from context
@@ -64,9 +71,170 @@ func (h *AttendanceHandler) SubmitAttendance(c *gin.Context) {
 
 	attendance, err := h.service.SubmitAttendance(currentUser.ID, checkInTime, checkOutTime, ipAddress, requestID)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to submit attendance", err.Error())
+		switch {
+		case errors.Is(err, service.ErrAttendanceNotAllowed):
+			response.Error(c, http.StatusBadRequest, "Attendance not allowed on this day", err.Error())
+		case errors.Is(err, service.ErrPeriodLocked):
+			response.Error(c, http.StatusConflict, "Payroll period is locked", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to submit attendance", err.Error())
+		}
 		return
 	}
 
 	response.Success(c, "Attendance submitted successfully", response.ToAttendanceResponse(attendance))
 }
+
+// GetAttendanceHistory handles an employee's request to list their attendances, latest first. It is paginated and can
+// be filtered by date range and payroll period, see bindHistoryFilter.
+func (h *AttendanceHandler) GetAttendanceHistory(c *gin.Context) {
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	filter, ok := bindHistoryFilter(c)
+	if !ok {
+		return
+	}
+
+	attendances, total, err := h.service.GetAttendanceHistory(currentUser.ID, filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve attendances", err.Error())
+		return
+	}
+
+	response.Success(c, "Attendances retrieved successfully", response.ToPageResponse(response.ToAttendanceListResponse(attendances), filter.Page, filter.PageSize, total))
+}
+
+// ClockIn handles an employee clocking in at the current server time.
+func (h *AttendanceHandler) ClockIn(c *gin.Context) {
+	// Get current user from context
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	attendance, err := h.service.ClockIn(currentUser.ID, time.Now(), ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAttendanceNotAllowed):
+			response.Error(c, http.StatusBadRequest, "Attendance not allowed on this day", err.Error())
+		case errors.Is(err, service.ErrAlreadyClockedIn):
+			response.Error(c, http.StatusConflict, "Already clocked in", err.Error())
+		case errors.Is(err, service.ErrPeriodLocked):
+			response.Error(c, http.StatusConflict, "Payroll period is locked", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to clock in", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Clocked in successfully", response.ToAttendanceResponse(attendance))
+}
+
+// ClockOut handles an employee clocking out at the current server time.
+func (h *AttendanceHandler) ClockOut(c *gin.Context) {
+	// Get current user from context
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	attendance, err := h.service.ClockOut(currentUser.ID, time.Now(), ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotClockedIn):
+			response.Error(c, http.StatusConflict, "Not clocked in", err.Error())
+		case errors.Is(err, service.ErrPeriodLocked):
+			response.Error(c, http.StatusConflict, "Payroll period is locked", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to clock out", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Clocked out successfully", response.ToAttendanceResponse(attendance))
+}
+
+// CloseOpenAttendances handles an admin closing the attendances left open at the end of their day.
+func (h *AttendanceHandler) CloseOpenAttendances(c *gin.Context) {
+	// Get current user from context
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	closed, err := h.service.CloseOpenAttendances(time.Now(), currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to close open attendances", err.Error())
+		return
+	}
+
+	response.Success(c, "Open attendances closed successfully", gin.H{"closed": closed})
+}
+
+// GetAttendancePolicy handles an admin retrieving the attendance policy in force.
+func (h *AttendanceHandler) GetAttendancePolicy(c *gin.Context) {
+	policy, err := h.service.GetAttendancePolicy()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve attendance policy", err.Error())
+		return
+	}
+
+	response.Success(c, "Attendance policy retrieved successfully", response.ToAttendancePolicyResponse(policy))
+}
+
+// UpdateAttendancePolicy handles an admin changing how attendances left open are closed.
+func (h *AttendanceHandler) UpdateAttendancePolicy(c *gin.Context) {
+	var req UpdateAttendancePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	// Get current user from context
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	policy, err := h.service.UpdateOpenAttendanceAction(req.OpenAttendance, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOpenAttendanceAction) {
+			response.Error(c, http.StatusBadRequest, "Invalid open attendance action", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to update attendance policy", err.Error())
+		return
+	}
+
+	response.Success(c, "Attendance policy updated successfully", response.ToAttendancePolicyResponse(policy))
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -15,6 +16,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
 	mockSvc "payroll-system/tests/mocks/service"
 )
 
@@ -56,7 +58,7 @@ func TestSubmitAttendance(t *testing.T) {
 				}, h.SubmitAttendance)
 			},
 			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
-				mockService.EXPECT().SubmitAttendance(currentUser.ID, checkInTime, checkOutTime, gomock.Any(), gomock.Any()).
+				mockService.EXPECT().SubmitAttendance(currentUser.ID, checkInTime, &checkOutTime, gomock.Any(), gomock.Any()).
 					Return(&domain.Attendance{UserID: currentUser.ID}, nil).Times(1)
 			},
 			expectedStatus:       http.StatusOK,
@@ -74,7 +76,7 @@ func TestSubmitAttendance(t *testing.T) {
 				}, h.SubmitAttendance)
 			},
 			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
-				mockService.EXPECT().SubmitAttendance(currentUser.ID, checkInTime, time.Time{}, gomock.Any(), gomock.Any()).
+				mockService.EXPECT().SubmitAttendance(currentUser.ID, checkInTime, (*time.Time)(nil), gomock.Any(), gomock.Any()).
 					Return(&domain.Attendance{UserID: currentUser.ID}, nil).Times(1)
 			},
 			expectedStatus:       http.StatusOK,
@@ -128,6 +130,42 @@ func TestSubmitAttendance(t *testing.T) {
 			expectedStatus:       http.StatusUnauthorized,
 			expectedBodyContains: "User not authenticated",
 		},
+		{
+			name: "Error - Rest Day",
+			requestBody: SubmitAttendanceRequest{
+				CheckInTime: checkInStr,
+			},
+			setupMiddleware: func(r *gin.Engine, h *AttendanceHandler) {
+				r.POST("/attendance", func(c *gin.Context) {
+					c.Set("currentUser", currentUser)
+					c.Next()
+				}, h.SubmitAttendance)
+			},
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().SubmitAttendance(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w on rest days: Saturday is not a working day of the Standard schedule", service.ErrAttendanceNotAllowed)).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "attendance cannot be submitted on rest days",
+		},
+		{
+			name: "Error - Payroll Period Locked",
+			requestBody: SubmitAttendanceRequest{
+				CheckInTime: checkInStr,
+			},
+			setupMiddleware: func(r *gin.Engine, h *AttendanceHandler) {
+				r.POST("/attendance", func(c *gin.Context) {
+					c.Set("currentUser", currentUser)
+					c.Next()
+				}, h.SubmitAttendance)
+			},
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().SubmitAttendance(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: 2025-08-18 falls in the payroll period 2025-08-01 to 2025-08-31, which has already been processed", service.ErrPeriodLocked)).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll period is locked",
+		},
 		{
 			name: "Error - Service Fails to Submit",
 			requestBody: SubmitAttendanceRequest{
@@ -185,3 +223,335 @@ func TestSubmitAttendance(t *testing.T) {
 		})
 	}
 }
+
+func TestAttendanceHandler_ClockInOut(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		Username:  "testuser",
+	}
+	checkIn := time.Date(2025, 8, 18, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name                 string
+		path                 string
+		mockService          func(mockService *mockSvc.MockAttendanceServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name: "Success - Clock In",
+			path: "/attendances/clock-in",
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().ClockIn(currentUser.ID, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.Attendance{UserID: currentUser.ID, Date: checkIn, CheckInTime: checkIn}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"check_in_time":"2025-08-18 09:00:00","check_out_time":null,"hours_worked":0`,
+		},
+		{
+			name: "Error - Already Clocked In",
+			path: "/attendances/clock-in",
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().ClockIn(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: clock out of the attendance of 2025-08-18 first", service.ErrAlreadyClockedIn)).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Already clocked in",
+		},
+		{
+			name: "Error - Clock In On Holiday",
+			path: "/attendances/clock-in",
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().ClockIn(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w on holidays: 2025-08-18 is Cuti Bersama", service.ErrAttendanceNotAllowed)).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Attendance not allowed on this day",
+		},
+		{
+			name: "Success - Clock Out",
+			path: "/attendances/clock-out",
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				checkOut := checkIn.Add(8 * time.Hour)
+				mockService.EXPECT().ClockOut(currentUser.ID, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.Attendance{UserID: currentUser.ID, Date: checkIn, CheckInTime: checkIn, CheckOutTime: &checkOut}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"check_out_time":"2025-08-18 17:00:00","hours_worked":8`,
+		},
+		{
+			name: "Error - Not Clocked In",
+			path: "/attendances/clock-out",
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().ClockOut(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: clock in first", service.ErrNotClockedIn)).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Not clocked in",
+		},
+		{
+			name: "Error - Clock Out In Locked Payroll Period",
+			path: "/attendances/clock-out",
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().ClockOut(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: 2025-08-18 falls in the payroll period 2025-08-01 to 2025-08-31, which has already been processed", service.ErrPeriodLocked)).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll period is locked",
+		},
+		{
+			name: "Error - Service Fails to Clock Out",
+			path: "/attendances/clock-out",
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().ClockOut(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to clock out",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockAttendanceServiceInterface(ctrl)
+			handler := NewAttendanceHandler(mockService)
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, tc.path, nil)
+
+			router := gin.Default()
+			withUser := func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }
+			router.POST("/attendances/clock-in", withUser, handler.ClockIn)
+			router.POST("/attendances/clock-out", withUser, handler.ClockOut)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestAttendanceHandler_GetAttendanceHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		Username:  "testuser",
+	}
+	periodID := uuid.New()
+	monday := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	// Check-in and check-out times are loaded from the database without their date
+	checkOut := time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC)
+	attendance := domain.Attendance{UserID: currentUser.ID, Date: monday, CheckInTime: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), CheckOutTime: &checkOut}
+
+	testCases := []struct {
+		name                 string
+		query                string
+		mockService          func(mockService *mockSvc.MockAttendanceServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name: "Success - Default Page",
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().GetAttendanceHistory(currentUser.ID, domain.HistoryFilter{Page: 1, PageSize: DefaultHistoryPageSize}).
+					Return([]domain.Attendance{attendance}, int64(1), nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"check_in_time":"2025-08-18 09:00:00","check_out_time":"2025-08-18 17:00:00","hours_worked":8}],"page":1,"page_size":20,"total_items":1,"total_pages":1`,
+		},
+		{
+			name:  "Success - Filtered",
+			query: "?from=2025-08-01&to=2025-08-31&payroll_period_id=" + periodID.String() + "&page=2&page_size=10",
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().GetAttendanceHistory(currentUser.ID, gomock.Any()).
+					DoAndReturn(func(_ uuid.UUID, filter domain.HistoryFilter) ([]domain.Attendance, int64, error) {
+						assert.Equal(t, "2025-08-01", filter.From.Format("2006-01-02"))
+						assert.Equal(t, "2025-08-31", filter.To.Format("2006-01-02"))
+						assert.Equal(t, periodID, *filter.PayrollPeriodID)
+						assert.Equal(t, 10, filter.Offset())
+						return []domain.Attendance{}, int64(11), nil
+					}).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"items":[],"page":2,"page_size":10,"total_items":11,"total_pages":2`,
+		},
+		{
+			name:                 "Error - Invalid From Date",
+			query:                "?from=01-08-2025",
+			mockService:          func(mockService *mockSvc.MockAttendanceServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid from format",
+		},
+		{
+			name:                 "Error - To Before From",
+			query:                "?from=2025-08-31&to=2025-08-01",
+			mockService:          func(mockService *mockSvc.MockAttendanceServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid date range",
+		},
+		{
+			name:                 "Error - Invalid Payroll Period ID",
+			query:                "?payroll_period_id=august",
+			mockService:          func(mockService *mockSvc.MockAttendanceServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid payroll_period_id format",
+		},
+		{
+			name:                 "Error - Invalid Page",
+			query:                "?page=0",
+			mockService:          func(mockService *mockSvc.MockAttendanceServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid page",
+		},
+		{
+			name:                 "Error - Page Size Too Large",
+			query:                "?page_size=500",
+			mockService:          func(mockService *mockSvc.MockAttendanceServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid page_size: use 1 to 100",
+		},
+		{
+			name: "Error - Service Fails",
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().GetAttendanceHistory(gomock.Any(), gomock.Any()).
+					Return(nil, int64(0), errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve attendances",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockAttendanceServiceInterface(ctrl)
+			handler := NewAttendanceHandler(mockService)
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/attendances"+tc.query, nil)
+
+			router := gin.Default()
+			router.GET("/attendances", func(c *gin.Context) {
+				c.Set("currentUser", currentUser)
+				c.Next()
+			}, handler.GetAttendanceHistory)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestAttendanceHandler_AttendancePolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		Username:  "admin",
+		Role:      "admin",
+	}
+
+	testCases := []struct {
+		name                 string
+		method               string
+		requestBody          string
+		mockService          func(mockService *mockSvc.MockAttendanceServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:   "Success - Get Policy",
+			method: http.MethodGet,
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().GetAttendancePolicy().Return(service.DefaultAttendancePolicy(), nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"open_attendance":"unpaid","pay_mode":"full_day"`,
+		},
+		{
+			name:   "Error - Service Fails to Get Policy",
+			method: http.MethodGet,
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().GetAttendancePolicy().Return(service.AttendancePolicy{}, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve attendance policy",
+		},
+		{
+			name:        "Success - Update Policy",
+			method:      http.MethodPut,
+			requestBody: `{"open_attendance":"shift_end"}`,
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().UpdateOpenAttendanceAction("shift_end", currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(service.AttendancePolicy{OpenAttendance: service.OpenAttendanceShiftEnd, PayMode: domain.AttendancePayFullDay}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"open_attendance":"shift_end"`,
+		},
+		{
+			name:                 "Error - Missing Open Attendance",
+			method:               http.MethodPut,
+			requestBody:          `{}`,
+			mockService:          func(mockService *mockSvc.MockAttendanceServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:        "Error - Invalid Open Attendance",
+			method:      http.MethodPut,
+			requestBody: `{"open_attendance":"paid"}`,
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().UpdateOpenAttendanceAction("paid", gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(service.AttendancePolicy{}, fmt.Errorf("%w \"paid\", use unpaid or shift_end", service.ErrInvalidOpenAttendanceAction)).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid open attendance action",
+		},
+		{
+			name:        "Error - Service Fails to Update Policy",
+			method:      http.MethodPut,
+			requestBody: `{"open_attendance":"unpaid"}`,
+			mockService: func(mockService *mockSvc.MockAttendanceServiceInterface) {
+				mockService.EXPECT().UpdateOpenAttendanceAction(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(service.AttendancePolicy{}, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to update attendance policy",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockAttendanceServiceInterface(ctrl)
+			handler := NewAttendanceHandler(mockService)
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, "/attendance-policy", bytes.NewBufferString(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			withUser := func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }
+			router.GET("/attendance-policy", withUser, handler.GetAttendancePolicy)
+			router.PUT("/attendance-policy", withUser, handler.UpdateAttendancePolicy)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"payroll-system/api/response"
 
@@ -43,6 +44,14 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	requestID := c.GetHeader("X-Request-ID")
 
 	user, err := h.authService.RegisterUser(req.Username, req.Password, req.Role, ipAddress, requestID)
+	if errors.Is(err, service.ErrEmployeeRegistration) {
+		c.JSON(http.StatusForbidden, response.APIResponse{
+			Code:    http.StatusForbidden,
+			Message: "Employees are created by an admin with POST /api/admin/employees",
+			Data:    err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.APIResponse{
 			Code:    http.StatusInternalServerError,
@@ -14,6 +14,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
 	mockSvc "payroll-system/tests/mocks/service"
 )
 
@@ -28,23 +29,37 @@ func TestAuthHandler_Register(t *testing.T) {
 		expectedBodyContains string
 	}{
 		{
-			name: "Success - Register Employee",
+			name: "Success - Register Admin",
 			requestBody: RegisterRequest{
-				Username: "newuser",
+				Username: "newadmin",
 				Password: "password123",
-				Role:     "employee",
+				Role:     "admin",
 			},
 			mockService: func(mockService *mockSvc.MockAuthServiceInterface) {
-				mockService.EXPECT().RegisterUser("newuser", "password123", "employee", gomock.Any(), gomock.Any()).
+				mockService.EXPECT().RegisterUser("newadmin", "password123", "admin", gomock.Any(), gomock.Any()).
 					Return(&domain.User{
 						BaseModel: domain.BaseModel{ID: uuid.New()},
-						Username:  "newuser",
-						Role:      "employee",
+						Username:  "newadmin",
+						Role:      "admin",
 					}, nil).Times(1)
 			},
 			expectedStatus:       http.StatusCreated,
 			expectedBodyContains: "User registered successfully",
 		},
+		{
+			name: "Error - Employee Registration",
+			requestBody: RegisterRequest{
+				Username: "newuser",
+				Password: "password123",
+				Role:     "employee",
+			},
+			mockService: func(mockService *mockSvc.MockAuthServiceInterface) {
+				mockService.EXPECT().RegisterUser("newuser", "password123", "employee", gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrEmployeeRegistration).Times(1)
+			},
+			expectedStatus:       http.StatusForbidden,
+			expectedBodyContains: "Employees are created by an admin",
+		},
 		{
 			name:                 "Error - Invalid JSON Payload",
 			requestBody:          `{"username": "badjson",}`,
@@ -0,0 +1,328 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payroll-system/api/response"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+)
+
+// EmployeeHandler handles employee management related HTTP requests.
+type EmployeeHandler struct {
+	service service.EmployeeServiceInterface
+}
+
+// NewEmployeeHandler creates a new EmployeeHandler.
+func NewEmployeeHandler(service service.EmployeeServiceInterface) *EmployeeHandler {
+	return &EmployeeHandler{service: service}
+}
+
+// CreateEmployeeRequest represents the request body for creating an employee.
+type CreateEmployeeRequest struct {
+	Username   string          `json:"username" binding:"required"`
+	Password   string          `json:"password" binding:"required"`
+	Salary     decimal.Decimal `json:"salary"`                       // accepts a JSON number or a decimal string
+	PTKPStatus string          `json:"ptkp_status"`                  // defaults to TK/0
+	HireDate   string          `json:"hire_date" binding:"required"` // YYYY-MM-DD
+}
+
+// UpdateEmployeeRequest represents the request body for updating an employee.
+type UpdateEmployeeRequest struct {
+	Username   string `json:"username" binding:"required"`
+	PTKPStatus string `json:"ptkp_status" binding:"required"`
+	HireDate   string `json:"hire_date"` // YYYY-MM-DD, keeps the current hire date when empty
+}
+
+// TerminateEmployeeRequest represents the request body for terminating an employee.
+type TerminateEmployeeRequest struct {
+	TerminationDate string `json:"termination_date" binding:"required"` // YYYY-MM-DD, last day of employment
+}
+
+// AssignManagerRequest represents the request body for assigning an employee their manager.
+type AssignManagerRequest struct {
+	ManagerID *string `json:"manager_id"` // ID of the manager's user, null for no manager
+}
+
+// CreateEmployee handles creating an employee user together with their employee profile.
+func (h *EmployeeHandler) CreateEmployee(c *gin.Context) {
+	var req CreateEmployeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	if !req.Salary.IsPositive() {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", "salary must be greater than 0")
+		return
+	}
+	if req.PTKPStatus != "" && !domain.IsValidPTKPStatus(req.PTKPStatus) {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", "invalid ptkp_status")
+		return
+	}
+
+	hireDate, err := time.Parse("2006-01-02", req.HireDate)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid hire_date format. Use YYYY-MM-DD.", nil)
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	profile, err := h.service.CreateEmployee(req.Username, req.Password, req.Salary, req.PTKPStatus, hireDate, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		if errors.Is(err, service.ErrUsernameTaken) {
+			response.Error(c, http.StatusConflict, "Username is already taken", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to create employee", err.Error())
+		return
+	}
+
+	response.Success(c, "Employee created successfully", response.ToEmployeeResponse(profile))
+}
+
+// UpdateEmployee handles updating the username, PTKP status and hire date of an employee.
+func (h *EmployeeHandler) UpdateEmployee(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid employee ID format", nil)
+		return
+	}
+
+	var req UpdateEmployeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+	if !domain.IsValidPTKPStatus(req.PTKPStatus) {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", "invalid ptkp_status")
+		return
+	}
+
+	var hireDate *time.Time
+	if req.HireDate != "" {
+		parsed, err := time.Parse("2006-01-02", req.HireDate)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid hire_date format. Use YYYY-MM-DD.", nil)
+			return
+		}
+		hireDate = &parsed
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	profile, err := h.service.UpdateEmployee(userID, req.Username, req.PTKPStatus, hireDate, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmployeeNotFound):
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+		case errors.Is(err, service.ErrUsernameTaken):
+			response.Error(c, http.StatusConflict, "Username is already taken", err.Error())
+		case errors.Is(err, service.ErrInvalidEmploymentDates):
+			response.Error(c, http.StatusBadRequest, "Invalid hire_date", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to update employee", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Employee updated successfully", response.ToEmployeeResponse(profile))
+}
+
+// GetEmployee handles retrieving a single employee.
+func (h *EmployeeHandler) GetEmployee(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid employee ID format", nil)
+		return
+	}
+
+	profile, err := h.service.GetEmployee(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrEmployeeNotFound) {
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve employee", err.Error())
+		return
+	}
+
+	response.Success(c, "Employee retrieved successfully", response.ToEmployeeResponse(profile))
+}
+
+// GetEmployees handles listing the employees, optionally filtered by the "search" query parameter.
+func (h *EmployeeHandler) GetEmployees(c *gin.Context) {
+	profiles, err := h.service.SearchEmployees(c.Query("search"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve employees", err.Error())
+		return
+	}
+
+	response.Success(c, "Employees retrieved successfully", response.ToEmployeeListResponse(profiles))
+}
+
+// DeactivateEmployee handles deactivating an employee.
+func (h *EmployeeHandler) DeactivateEmployee(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid employee ID format", nil)
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	profile, err := h.service.DeactivateEmployee(userID, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmployeeNotFound):
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+		case errors.Is(err, service.ErrEmployeeDeactivated):
+			response.Error(c, http.StatusConflict, "Employee is already deactivated", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to deactivate employee", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Employee deactivated successfully", response.ToEmployeeResponse(profile))
+}
+
+// TerminateEmployee handles recording the last day of employment of an employee.
+func (h *EmployeeHandler) TerminateEmployee(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid employee ID format", nil)
+		return
+	}
+
+	var req TerminateEmployeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	terminationDate, err := time.Parse("2006-01-02", req.TerminationDate)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid termination_date format. Use YYYY-MM-DD.", nil)
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	profile, err := h.service.TerminateEmployee(userID, terminationDate, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmployeeNotFound):
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+		case errors.Is(err, service.ErrInvalidEmploymentDates):
+			response.Error(c, http.StatusBadRequest, "Invalid termination_date", err.Error())
+		case errors.Is(err, service.ErrEmployeeTerminated):
+			response.Error(c, http.StatusConflict, "Employee is already terminated", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to terminate employee", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Employee terminated successfully", response.ToEmployeeResponse(profile))
+}
+
+// AssignManager handles setting the manager an employee reports to. A null manager_id leaves the
+// employee without a manager.
+func (h *EmployeeHandler) AssignManager(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid employee ID format", nil)
+		return
+	}
+
+	var req AssignManagerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	var managerID *uuid.UUID
+	if req.ManagerID != nil {
+		id, err := uuid.Parse(*req.ManagerID)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid manager ID format", nil)
+			return
+		}
+		managerID = &id
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	profile, err := h.service.AssignManager(userID, managerID, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmployeeNotFound):
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+		case errors.Is(err, service.ErrInvalidManager):
+			response.Error(c, http.StatusBadRequest, "Invalid manager", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to assign manager", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Manager assigned successfully", response.ToEmployeeResponse(profile))
+}
@@ -0,0 +1,504 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+	mockSvc "payroll-system/tests/mocks/service"
+)
+
+func TestEmployeeHandler_CreateEmployee(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		Username:  "adminuser",
+	}
+	employeeID := uuid.New()
+	salary := decimal.NewFromInt(6000000)
+	hireDate := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+	withUser := func(r *gin.Engine, h *EmployeeHandler) {
+		r.POST("/employees", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, h.CreateEmployee)
+	}
+
+	testCases := []struct {
+		name                 string
+		requestBody          any
+		setupMiddleware      func(r *gin.Engine, h *EmployeeHandler)
+		mockService          func(mockService *mockSvc.MockEmployeeServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:            "Success - Create Employee",
+			requestBody:     CreateEmployeeRequest{Username: "alice", Password: "secret", Salary: salary, PTKPStatus: domain.PTKPStatusK1, HireDate: "2025-06-16"},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().CreateEmployee("alice", "secret", salary, domain.PTKPStatusK1, hireDate, currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.EmployeeProfile{
+						UserID:     employeeID,
+						User:       domain.User{BaseModel: domain.BaseModel{ID: employeeID}, Username: "alice"},
+						Salary:     salary,
+						PTKPStatus: domain.PTKPStatusK1,
+						HireDate:   &hireDate,
+					}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"username":"alice","salary":"6000000","ptkp_status":"K/1","hire_date":"2025-06-16","termination_date":null,"work_schedule_id":null,"manager_id":null,"is_active":true`,
+		},
+		{
+			name:                 "Error - Invalid JSON",
+			requestBody:          `{"username": "alice",}`,
+			setupMiddleware:      withUser,
+			mockService:          func(mockService *mockSvc.MockEmployeeServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:                 "Error - Salary Not Positive",
+			requestBody:          CreateEmployeeRequest{Username: "alice", Password: "secret", Salary: decimal.Zero, HireDate: "2025-06-16"},
+			setupMiddleware:      withUser,
+			mockService:          func(mockService *mockSvc.MockEmployeeServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "salary must be greater than 0",
+		},
+		{
+			name:                 "Error - Invalid PTKP Status",
+			requestBody:          CreateEmployeeRequest{Username: "alice", Password: "secret", Salary: salary, PTKPStatus: "X/9", HireDate: "2025-06-16"},
+			setupMiddleware:      withUser,
+			mockService:          func(mockService *mockSvc.MockEmployeeServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "invalid ptkp_status",
+		},
+		{
+			name:        "Error - User Not Authenticated",
+			requestBody: CreateEmployeeRequest{Username: "alice", Password: "secret", Salary: salary, HireDate: "2025-06-16"},
+			setupMiddleware: func(r *gin.Engine, h *EmployeeHandler) {
+				r.POST("/employees", h.CreateEmployee)
+			},
+			mockService:          func(mockService *mockSvc.MockEmployeeServiceInterface) {},
+			expectedStatus:       http.StatusUnauthorized,
+			expectedBodyContains: "User not authenticated",
+		},
+		{
+			name:            "Error - Username Taken",
+			requestBody:     CreateEmployeeRequest{Username: "alice", Password: "secret", Salary: salary, HireDate: "2025-06-16"},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().CreateEmployee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrUsernameTaken).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Username is already taken",
+		},
+		{
+			name:            "Error - Service Failure",
+			requestBody:     CreateEmployeeRequest{Username: "alice", Password: "secret", Salary: salary, HireDate: "2025-06-16"},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().CreateEmployee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to create employee",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockEmployeeServiceInterface(ctrl)
+			handler := NewEmployeeHandler(mockService)
+
+			tc.mockService(mockService)
+
+			var reqBody []byte
+			if bodyStr, ok := tc.requestBody.(string); ok {
+				reqBody = []byte(bodyStr)
+			} else {
+				reqBody, _ = json.Marshal(tc.requestBody)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/employees", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			tc.setupMiddleware(router, handler)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestEmployeeHandler_UpdateEmployee(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}}
+	employeeID := uuid.New()
+
+	testCases := []struct {
+		name                 string
+		employeeID           string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockEmployeeServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success - Update Employee",
+			employeeID:  employeeID.String(),
+			requestBody: UpdateEmployeeRequest{Username: "alice.w", PTKPStatus: domain.PTKPStatusK0},
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().UpdateEmployee(employeeID, "alice.w", domain.PTKPStatusK0, (*time.Time)(nil), currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.EmployeeProfile{UserID: employeeID, User: domain.User{Username: "alice.w"}, PTKPStatus: domain.PTKPStatusK0}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"username":"alice.w"`,
+		},
+		{
+			name:                 "Error - Invalid Employee ID",
+			employeeID:           "not-a-uuid",
+			requestBody:          UpdateEmployeeRequest{Username: "alice.w", PTKPStatus: domain.PTKPStatusK0},
+			mockService:          func(mockService *mockSvc.MockEmployeeServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid employee ID format",
+		},
+		{
+			name:                 "Error - Missing PTKP Status",
+			employeeID:           employeeID.String(),
+			requestBody:          map[string]string{"username": "alice.w"},
+			mockService:          func(mockService *mockSvc.MockEmployeeServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:        "Error - Employee Not Found",
+			employeeID:  employeeID.String(),
+			requestBody: UpdateEmployeeRequest{Username: "alice.w", PTKPStatus: domain.PTKPStatusK0},
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().UpdateEmployee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrEmployeeNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Employee not found",
+		},
+		{
+			name:        "Error - Username Taken",
+			employeeID:  employeeID.String(),
+			requestBody: UpdateEmployeeRequest{Username: "bob", PTKPStatus: domain.PTKPStatusK0},
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().UpdateEmployee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrUsernameTaken).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Username is already taken",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockEmployeeServiceInterface(ctrl)
+			handler := NewEmployeeHandler(mockService)
+
+			tc.mockService(mockService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/employees/%s", tc.employeeID), bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.PUT("/employees/:id", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.UpdateEmployee)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestEmployeeHandler_GetEmployees(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockSvc.NewMockEmployeeServiceInterface(ctrl)
+	handler := NewEmployeeHandler(mockService)
+	router := gin.Default()
+	router.GET("/employees", handler.GetEmployees)
+	router.GET("/employees/:id", handler.GetEmployee)
+
+	deactivatedAt := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	mockService.EXPECT().SearchEmployees("ali").Return([]domain.EmployeeProfile{
+		{User: domain.User{Username: "alice"}},
+		{User: domain.User{Username: "alina", DeactivatedAt: &deactivatedAt}},
+	}, nil).Times(1)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/employees?search=ali", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"username":"alice"`)
+	assert.Contains(t, w.Body.String(), `"is_active":false,"deactivated_at":"2025-07-01T09:00:00Z"`)
+
+	employeeID := uuid.New()
+	mockService.EXPECT().GetEmployee(employeeID).Return(nil, service.ErrEmployeeNotFound).Times(1)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("/employees/%s", employeeID), nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Employee not found")
+}
+
+func TestEmployeeHandler_DeactivateEmployee(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}}
+	employeeID := uuid.New()
+	deactivatedAt := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name                 string
+		mockService          func(mockService *mockSvc.MockEmployeeServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name: "Success - Deactivate Employee",
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().DeactivateEmployee(employeeID, currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.EmployeeProfile{UserID: employeeID, User: domain.User{DeactivatedAt: &deactivatedAt}}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"is_active":false`,
+		},
+		{
+			name: "Error - Already Deactivated",
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().DeactivateEmployee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrEmployeeDeactivated).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Employee is already deactivated",
+		},
+		{
+			name: "Error - Service Failure",
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().DeactivateEmployee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to deactivate employee",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockEmployeeServiceInterface(ctrl)
+			handler := NewEmployeeHandler(mockService)
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/employees/%s/deactivate", employeeID), nil)
+
+			router := gin.Default()
+			router.POST("/employees/:id/deactivate", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.DeactivateEmployee)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestEmployeeHandler_TerminateEmployee(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}}
+	employeeID := uuid.New()
+	terminationDate := time.Date(2025, 8, 8, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name                 string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockEmployeeServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success - Terminate Employee",
+			requestBody: TerminateEmployeeRequest{TerminationDate: "2025-08-08"},
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().TerminateEmployee(employeeID, terminationDate, currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.EmployeeProfile{UserID: employeeID, TerminationDate: &terminationDate}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"termination_date":"2025-08-08"`,
+		},
+		{
+			name:                 "Error - Invalid Termination Date",
+			requestBody:          TerminateEmployeeRequest{TerminationDate: "08-08-2025"},
+			mockService:          func(mockService *mockSvc.MockEmployeeServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid termination_date format",
+		},
+		{
+			name:        "Error - Before Hire Date",
+			requestBody: TerminateEmployeeRequest{TerminationDate: "2025-08-08"},
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().TerminateEmployee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrInvalidEmploymentDates).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "termination date cannot be before the hire date",
+		},
+		{
+			name:        "Error - Already Terminated",
+			requestBody: TerminateEmployeeRequest{TerminationDate: "2025-08-08"},
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().TerminateEmployee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrEmployeeTerminated).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Employee is already terminated",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockEmployeeServiceInterface(ctrl)
+			handler := NewEmployeeHandler(mockService)
+
+			tc.mockService(mockService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/employees/%s/terminate", employeeID), bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.POST("/employees/:id/terminate", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.TerminateEmployee)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestEmployeeHandler_AssignManager(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}}
+	employeeID := uuid.New()
+	managerID := uuid.New()
+	managerIDString := managerID.String()
+	invalidID := "not-a-uuid"
+
+	testCases := []struct {
+		name                 string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockEmployeeServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success - Assign Manager",
+			requestBody: AssignManagerRequest{ManagerID: &managerIDString},
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().AssignManager(employeeID, &managerID, currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.EmployeeProfile{UserID: employeeID, ManagerID: &managerID}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: fmt.Sprintf(`"manager_id":"%s"`, managerID),
+		},
+		{
+			name:        "Success - Remove Manager",
+			requestBody: AssignManagerRequest{},
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().AssignManager(employeeID, nil, currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.EmployeeProfile{UserID: employeeID}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"manager_id":null`,
+		},
+		{
+			name:                 "Error - Invalid Manager ID",
+			requestBody:          AssignManagerRequest{ManagerID: &invalidID},
+			mockService:          func(mockService *mockSvc.MockEmployeeServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid manager ID format",
+		},
+		{
+			name:        "Error - Reporting Loop",
+			requestBody: AssignManagerRequest{ManagerID: &managerIDString},
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().AssignManager(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: the manager reports to the employee", service.ErrInvalidManager)).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "invalid manager: the manager reports to the employee",
+		},
+		{
+			name:        "Error - Employee Not Found",
+			requestBody: AssignManagerRequest{ManagerID: &managerIDString},
+			mockService: func(mockService *mockSvc.MockEmployeeServiceInterface) {
+				mockService.EXPECT().AssignManager(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrEmployeeNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Employee not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockEmployeeServiceInterface(ctrl)
+			handler := NewEmployeeHandler(mockService)
+
+			tc.mockService(mockService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/employees/%s/manager", employeeID), bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.PUT("/employees/:id/manager", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.AssignManager)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
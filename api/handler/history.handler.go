@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"payroll-system/api/response"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"payroll-system/internal/domain"
+)
+
+// Page sizes of the employee history listings.
+const (
+	DefaultHistoryPageSize = 20
+	MaxHistoryPageSize     = 100
+)
+
+// bindHistoryFilter reads the filter of an employee history listing from the query parameters:
+// from and to as YYYY-MM-DD, payroll_period_id, page and page_size. It responds with 400 and returns
+// false if a parameter is invalid.
+func bindHistoryFilter(c *gin.Context) (domain.HistoryFilter, bool) {
+	filter := domain.HistoryFilter{Page: 1, PageSize: DefaultHistoryPageSize}
+
+	if v := c.Query("from"); v != "" {
+		from, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid from format. Use YYYY-MM-DD.", nil)
+			return filter, false
+		}
+		filter.From = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid to format. Use YYYY-MM-DD.", nil)
+			return filter, false
+		}
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		response.Error(c, http.StatusBadRequest, "Invalid date range: to is before from", nil)
+		return filter, false
+	}
+
+	if v := c.Query("payroll_period_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid payroll_period_id format", nil)
+			return filter, false
+		}
+		filter.PayrollPeriodID = &id
+	}
+
+	if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			response.Error(c, http.StatusBadRequest, "Invalid page", nil)
+			return filter, false
+		}
+		filter.Page = page
+	}
+	if v := c.Query("page_size"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 1 || size > MaxHistoryPageSize {
+			response.Error(c, http.StatusBadRequest, "Invalid page_size: use 1 to "+strconv.Itoa(MaxHistoryPageSize), nil)
+			return filter, false
+		}
+		filter.PageSize = size
+	}
+	return filter, true
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payroll-system/api/response"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+)
+
+// HolidayHandler handles holiday calendar related HTTP requests.
+type HolidayHandler struct {
+	service service.HolidayServiceInterface
+}
+
+// NewHolidayHandler creates a new HolidayHandler.
+func NewHolidayHandler(service service.HolidayServiceInterface) *HolidayHandler {
+	return &HolidayHandler{service: service}
+}
+
+// HolidayRequest represents the request body for creating or updating a holiday.
+type HolidayRequest struct {
+	Date string `json:"date" binding:"required"` // YYYY-MM-DD
+	Name string `json:"name" binding:"required"`
+	Type string `json:"type"` // national or collective_leave, defaults to national
+}
+
+// CreateHoliday handles adding a holiday to the calendar.
+func (h *HolidayHandler) CreateHoliday(c *gin.Context) {
+	var req HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD.", nil)
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	holiday, err := h.service.CreateHoliday(date, req.Name, req.Type, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDuplicateHoliday):
+			response.Error(c, http.StatusConflict, "Holiday already exists for this date", err.Error())
+		case errors.Is(err, service.ErrInvalidHolidayType):
+			response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to create holiday", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Holiday created successfully", response.ToHolidayResponse(holiday))
+}
+
+// UpdateHoliday handles changing the date, name and type of a holiday.
+func (h *HolidayHandler) UpdateHoliday(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid holiday ID format", nil)
+		return
+	}
+
+	var req HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD.", nil)
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	holiday, err := h.service.UpdateHoliday(id, date, req.Name, req.Type, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrHolidayNotFound):
+			response.Error(c, http.StatusNotFound, "Holiday not found", nil)
+		case errors.Is(err, service.ErrDuplicateHoliday):
+			response.Error(c, http.StatusConflict, "Holiday already exists for this date", err.Error())
+		case errors.Is(err, service.ErrInvalidHolidayType):
+			response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to update holiday", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Holiday updated successfully", response.ToHolidayResponse(holiday))
+}
+
+// DeleteHoliday handles removing a holiday from the calendar.
+func (h *HolidayHandler) DeleteHoliday(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid holiday ID format", nil)
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	if err := h.service.DeleteHoliday(id, currentUser.ID, ipAddress, requestID); err != nil {
+		if errors.Is(err, service.ErrHolidayNotFound) {
+			response.Error(c, http.StatusNotFound, "Holiday not found", nil)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to delete holiday", err.Error())
+		return
+	}
+
+	response.Success(c, "Holiday deleted successfully", nil)
+}
+
+// GetHolidays handles listing the holidays of the year given by the "year" query parameter,
+// the current year by default.
+func (h *HolidayHandler) GetHolidays(c *gin.Context) {
+	year := time.Now().Year()
+	if q := c.Query("year"); q != "" {
+		parsed, err := strconv.Atoi(q)
+		if err != nil || parsed < 1 {
+			response.Error(c, http.StatusBadRequest, "Invalid year", nil)
+			return
+		}
+		year = parsed
+	}
+
+	holidays, err := h.service.GetHolidays(year)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve holidays", err.Error())
+		return
+	}
+
+	response.Success(c, "Holidays retrieved successfully", response.ToHolidayListResponse(holidays))
+}
+
+// ImportHolidays handles importing the holidays of an iCalendar (.ics) file, uploaded as the
+// multipart form field "file". The optional form field "type" sets the type of every imported holiday.
+func (h *HolidayHandler) ImportHolidays(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", "an iCalendar file is required in the \"file\" field")
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to read uploaded file", err.Error())
+		return
+	}
+	defer file.Close()
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	result, err := h.service.ImportHolidays(file, c.PostForm("type"), currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidHolidayType), errors.Is(err, service.ErrInvalidICalendar):
+			response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		case errors.Is(err, service.ErrDuplicateHoliday):
+			response.Error(c, http.StatusConflict, "Holiday already exists for this date", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to import holidays", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Holidays imported successfully", response.ToHolidayImportResponse(result))
+}
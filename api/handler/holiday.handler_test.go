@@ -0,0 +1,436 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+	mockSvc "payroll-system/tests/mocks/service"
+)
+
+func TestHolidayHandler_CreateHoliday(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		Username:  "adminuser",
+	}
+	date := time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)
+	withUser := func(r *gin.Engine, h *HolidayHandler) {
+		r.POST("/holidays", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, h.CreateHoliday)
+	}
+
+	testCases := []struct {
+		name                 string
+		requestBody          any
+		setupMiddleware      func(r *gin.Engine, h *HolidayHandler)
+		mockService          func(mockService *mockSvc.MockHolidayServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:            "Success - Create Holiday",
+			requestBody:     HolidayRequest{Date: "2025-08-17", Name: "Hari Kemerdekaan"},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().CreateHoliday(date, "Hari Kemerdekaan", "", currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.Holiday{Date: date, Name: "Hari Kemerdekaan", Type: domain.HolidayTypeNational}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"date":"2025-08-17","name":"Hari Kemerdekaan","type":"national"`,
+		},
+		{
+			name:                 "Error - Missing Name",
+			requestBody:          HolidayRequest{Date: "2025-08-17"},
+			setupMiddleware:      withUser,
+			mockService:          func(mockService *mockSvc.MockHolidayServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:                 "Error - Invalid Date",
+			requestBody:          HolidayRequest{Date: "17-08-2025", Name: "Hari Kemerdekaan"},
+			setupMiddleware:      withUser,
+			mockService:          func(mockService *mockSvc.MockHolidayServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid date format",
+		},
+		{
+			name:        "Error - User Not Authenticated",
+			requestBody: HolidayRequest{Date: "2025-08-17", Name: "Hari Kemerdekaan"},
+			setupMiddleware: func(r *gin.Engine, h *HolidayHandler) {
+				r.POST("/holidays", h.CreateHoliday)
+			},
+			mockService:          func(mockService *mockSvc.MockHolidayServiceInterface) {},
+			expectedStatus:       http.StatusUnauthorized,
+			expectedBodyContains: "User not authenticated",
+		},
+		{
+			name:            "Error - Invalid Type",
+			requestBody:     HolidayRequest{Date: "2025-08-17", Name: "Hari Kemerdekaan", Type: "regional"},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().CreateHoliday(gomock.Any(), gomock.Any(), "regional", gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrInvalidHolidayType).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "holiday type must be national or collective_leave",
+		},
+		{
+			name:            "Error - Date Already On Calendar",
+			requestBody:     HolidayRequest{Date: "2025-08-17", Name: "Hari Kemerdekaan"},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().CreateHoliday(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrDuplicateHoliday).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Holiday already exists for this date",
+		},
+		{
+			name:            "Error - Service Failure",
+			requestBody:     HolidayRequest{Date: "2025-08-17", Name: "Hari Kemerdekaan"},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().CreateHoliday(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to create holiday",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockHolidayServiceInterface(ctrl)
+			handler := NewHolidayHandler(mockService)
+
+			tc.mockService(mockService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/holidays", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			tc.setupMiddleware(router, handler)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestHolidayHandler_UpdateHoliday(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}}
+	holidayID := uuid.New()
+	date := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	body := HolidayRequest{Date: "2025-08-18", Name: "Cuti Bersama", Type: domain.HolidayTypeCollectiveLeave}
+
+	testCases := []struct {
+		name                 string
+		holidayID            string
+		mockService          func(mockService *mockSvc.MockHolidayServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:      "Success - Update Holiday",
+			holidayID: holidayID.String(),
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().UpdateHoliday(holidayID, date, "Cuti Bersama", domain.HolidayTypeCollectiveLeave, currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.Holiday{BaseModel: domain.BaseModel{ID: holidayID}, Date: date, Name: "Cuti Bersama", Type: domain.HolidayTypeCollectiveLeave}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"type":"collective_leave"`,
+		},
+		{
+			name:                 "Error - Invalid Holiday ID",
+			holidayID:            "not-a-uuid",
+			mockService:          func(mockService *mockSvc.MockHolidayServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid holiday ID format",
+		},
+		{
+			name:      "Error - Holiday Not Found",
+			holidayID: holidayID.String(),
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().UpdateHoliday(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrHolidayNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Holiday not found",
+		},
+		{
+			name:      "Error - Date Already On Calendar",
+			holidayID: holidayID.String(),
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().UpdateHoliday(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrDuplicateHoliday).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Holiday already exists for this date",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockHolidayServiceInterface(ctrl)
+			handler := NewHolidayHandler(mockService)
+
+			tc.mockService(mockService)
+
+			reqBody, _ := json.Marshal(body)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/holidays/%s", tc.holidayID), bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.PUT("/holidays/:id", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.UpdateHoliday)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestHolidayHandler_DeleteHoliday(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}}
+	holidayID := uuid.New()
+
+	testCases := []struct {
+		name                 string
+		holidayID            string
+		mockService          func(mockService *mockSvc.MockHolidayServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:      "Success - Delete Holiday",
+			holidayID: holidayID.String(),
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().DeleteHoliday(holidayID, currentUser.ID, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: "Holiday deleted successfully",
+		},
+		{
+			name:                 "Error - Invalid Holiday ID",
+			holidayID:            "not-a-uuid",
+			mockService:          func(mockService *mockSvc.MockHolidayServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid holiday ID format",
+		},
+		{
+			name:      "Error - Holiday Not Found",
+			holidayID: holidayID.String(),
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().DeleteHoliday(holidayID, gomock.Any(), gomock.Any(), gomock.Any()).Return(service.ErrHolidayNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Holiday not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockHolidayServiceInterface(ctrl)
+			handler := NewHolidayHandler(mockService)
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/holidays/%s", tc.holidayID), nil)
+
+			router := gin.Default()
+			router.DELETE("/holidays/:id", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.DeleteHoliday)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestHolidayHandler_GetHolidays(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name                 string
+		query                string
+		mockService          func(mockService *mockSvc.MockHolidayServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:  "Success - Holidays Of A Year",
+			query: "?year=2025",
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().GetHolidays(2025).Return([]domain.Holiday{
+					{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Name: "Tahun Baru", Type: domain.HolidayTypeNational},
+				}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"date":"2025-01-01","name":"Tahun Baru"`,
+		},
+		{
+			name: "Success - Defaults To The Current Year",
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().GetHolidays(time.Now().Year()).Return(nil, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"data":[]`,
+		},
+		{
+			name:                 "Error - Invalid Year",
+			query:                "?year=last",
+			mockService:          func(mockService *mockSvc.MockHolidayServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid year",
+		},
+		{
+			name:  "Error - Service Failure",
+			query: "?year=2025",
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().GetHolidays(2025).Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve holidays",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockHolidayServiceInterface(ctrl)
+			handler := NewHolidayHandler(mockService)
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/holidays"+tc.query, nil)
+
+			router := gin.Default()
+			router.GET("/holidays", handler.GetHolidays)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestHolidayHandler_ImportHolidays(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}}
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20250817\r\nSUMMARY:Hari Kemerdekaan\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	testCases := []struct {
+		name                 string
+		withFile             bool
+		holidayType          string
+		mockService          func(mockService *mockSvc.MockHolidayServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success - Import Holidays",
+			withFile:    true,
+			holidayType: domain.HolidayTypeNational,
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().ImportHolidays(gomock.Any(), domain.HolidayTypeNational, currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&service.HolidayImport{
+						Created: []domain.Holiday{{Date: time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC), Name: "Hari Kemerdekaan", Type: domain.HolidayTypeNational}},
+					}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"created_count":1,"skipped_count":0`,
+		},
+		{
+			name:                 "Error - Missing File",
+			mockService:          func(mockService *mockSvc.MockHolidayServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "an iCalendar file is required",
+		},
+		{
+			name:     "Error - Invalid iCalendar File",
+			withFile: true,
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().ImportHolidays(gomock.Any(), "", gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: no events found", service.ErrInvalidICalendar)).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "invalid iCalendar file: no events found",
+		},
+		{
+			name:     "Error - Service Failure",
+			withFile: true,
+			mockService: func(mockService *mockSvc.MockHolidayServiceInterface) {
+				mockService.EXPECT().ImportHolidays(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to import holidays",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockHolidayServiceInterface(ctrl)
+			handler := NewHolidayHandler(mockService)
+
+			tc.mockService(mockService)
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			if tc.withFile {
+				part, _ := writer.CreateFormFile("file", "holidays.ics")
+				_, _ = part.Write([]byte(ics))
+			}
+			if tc.holidayType != "" {
+				_ = writer.WriteField("type", tc.holidayType)
+			}
+			_ = writer.Close()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/holidays/import", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			router := gin.Default()
+			router.POST("/holidays/import", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.ImportHolidays)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payroll-system/api/response"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+)
+
+// LeaveHandler handles leave request and leave balance related HTTP requests.
+type LeaveHandler struct {
+	service service.LeaveServiceInterface
+}
+
+// NewLeaveHandler creates a new LeaveHandler.
+func NewLeaveHandler(service service.LeaveServiceInterface) *LeaveHandler {
+	return &LeaveHandler{service: service}
+}
+
+// SubmitLeaveRequestRequest represents the request body for requesting leave.
+type SubmitLeaveRequestRequest struct {
+	LeaveType string `json:"leave_type" binding:"required"` // annual, sick, maternity or unpaid
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string `json:"end_date" binding:"required"`   // YYYY-MM-DD, last day of leave
+	Reason    string `json:"reason"`
+}
+
+// ReviewLeaveRequestRequest represents the request body for approving or rejecting a leave request.
+type ReviewLeaveRequestRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Note   string `json:"note"`
+}
+
+// SetLeaveEntitlementRequest represents the request body for setting an employee's yearly leave entitlement.
+type SetLeaveEntitlementRequest struct {
+	Year      int             `json:"year" binding:"required,gt=0"`
+	LeaveType string          `json:"leave_type" binding:"required"` // annual, sick or maternity
+	Days      decimal.Decimal `json:"days"`                          // accepts a JSON number or a decimal string
+}
+
+// SubmitLeaveRequest handles an employee's request for leave.
+func (h *LeaveHandler) SubmitLeaveRequest(c *gin.Context) {
+	var req SubmitLeaveRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid start_date format. Use YYYY-MM-DD.", nil)
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid end_date format. Use YYYY-MM-DD.", nil)
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	leave, err := h.service.SubmitLeaveRequest(currentUser.ID, req.LeaveType, startDate, endDate, req.Reason, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidLeaveRequest):
+			response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		case errors.Is(err, service.ErrInsufficientLeaveBalance):
+			response.Error(c, http.StatusBadRequest, "Insufficient leave balance", err.Error())
+		case errors.Is(err, service.ErrLeaveOverlap):
+			response.Error(c, http.StatusConflict, "Leave request overlaps another leave request", err.Error())
+		case errors.Is(err, service.ErrEmployeeNotFound):
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+		case errors.Is(err, service.ErrPeriodLocked):
+			response.Error(c, http.StatusConflict, "Payroll period is locked", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to submit leave request", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Leave request submitted successfully", response.ToLeaveRequestResponse(leave))
+}
+
+// GetLeaveRequests handles listing the current employee's leave requests.
+func (h *LeaveHandler) GetLeaveRequests(c *gin.Context) {
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	leaves, err := h.service.GetLeaveRequests(currentUser.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve leave requests", err.Error())
+		return
+	}
+
+	response.Success(c, "Leave requests retrieved successfully", response.ToLeaveRequestListResponse(leaves))
+}
+
+// GetPendingLeaveRequests handles listing the pending leave requests the current user can review:
+// every pending request for an admin, the requests of their direct reports for a manager.
+func (h *LeaveHandler) GetPendingLeaveRequests(c *gin.Context) {
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	leaves, err := h.service.GetPendingLeaveRequests(currentUser)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve leave requests", err.Error())
+		return
+	}
+
+	response.Success(c, "Leave requests retrieved successfully", response.ToLeaveRequestListResponse(leaves))
+}
+
+// ReviewLeaveRequest handles approving or rejecting a pending leave request.
+func (h *LeaveHandler) ReviewLeaveRequest(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid leave request ID format", nil)
+		return
+	}
+
+	var req ReviewLeaveRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	approve := req.Status == domain.LeaveStatusApproved
+	leave, err := h.service.ReviewLeaveRequest(id, approve, req.Note, currentUser, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrLeaveRequestNotFound):
+			response.Error(c, http.StatusNotFound, "Leave request not found", nil)
+		case errors.Is(err, service.ErrLeaveReviewForbidden):
+			response.Error(c, http.StatusForbidden, "Not allowed to review this leave request", err.Error())
+		case errors.Is(err, service.ErrLeaveRequestReviewed):
+			response.Error(c, http.StatusConflict, "Leave request has already been reviewed", err.Error())
+		case errors.Is(err, service.ErrInsufficientLeaveBalance):
+			response.Error(c, http.StatusConflict, "Insufficient leave balance", err.Error())
+		case errors.Is(err, service.ErrPeriodLocked):
+			response.Error(c, http.StatusConflict, "Payroll period is locked", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to review leave request", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Leave request reviewed successfully", response.ToLeaveRequestResponse(leave))
+}
+
+// GetLeaveBalances handles retrieving the current employee's leave balances for a year, the
+// current one unless the year query parameter is set.
+func (h *LeaveHandler) GetLeaveBalances(c *gin.Context) {
+	year := time.Now().Year()
+	if q := c.Query("year"); q != "" {
+		parsed, err := strconv.Atoi(q)
+		if err != nil || parsed < 1 {
+			response.Error(c, http.StatusBadRequest, "Invalid year", nil)
+			return
+		}
+		year = parsed
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	balances, err := h.service.GetLeaveBalances(currentUser.ID, year, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrEmployeeNotFound) {
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve leave balances", err.Error())
+		return
+	}
+
+	response.Success(c, "Leave balances retrieved successfully", response.ToLeaveBalanceListResponse(balances))
+}
+
+// GetEmployeeLeaveBalances handles retrieving an employee's leave balances for a year, the current
+// one unless the year query parameter is set.
+func (h *LeaveHandler) GetEmployeeLeaveBalances(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid employee ID format", nil)
+		return
+	}
+
+	year := time.Now().Year()
+	if q := c.Query("year"); q != "" {
+		parsed, err := strconv.Atoi(q)
+		if err != nil || parsed < 1 {
+			response.Error(c, http.StatusBadRequest, "Invalid year", nil)
+			return
+		}
+		year = parsed
+	}
+
+	balances, err := h.service.GetLeaveBalances(userID, year, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrEmployeeNotFound) {
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve leave balances", err.Error())
+		return
+	}
+
+	response.Success(c, "Leave balances retrieved successfully", response.ToLeaveBalanceListResponse(balances))
+}
+
+// SetLeaveEntitlement handles setting an employee's yearly entitlement for a leave type.
+func (h *LeaveHandler) SetLeaveEntitlement(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid employee ID format", nil)
+		return
+	}
+
+	var req SetLeaveEntitlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	entitlement, err := h.service.SetLeaveEntitlement(userID, req.Year, req.LeaveType, req.Days, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidLeaveEntitlement):
+			response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		case errors.Is(err, service.ErrEmployeeNotFound):
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to set leave entitlement", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Leave entitlement set successfully", response.ToLeaveEntitlementResponse(entitlement))
+}
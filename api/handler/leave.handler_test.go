@@ -0,0 +1,388 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+	mockSvc "payroll-system/tests/mocks/service"
+)
+
+func TestLeaveHandler_SubmitLeaveRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "jdoe"}
+	startDate := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)
+	validRequest := SubmitLeaveRequestRequest{LeaveType: domain.LeaveTypeAnnual, StartDate: "2025-03-10", EndDate: "2025-03-11", Reason: "Family trip"}
+
+	testCases := []struct {
+		name                 string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockLeaveServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success - Submit Leave Request",
+			requestBody: validRequest,
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().SubmitLeaveRequest(currentUser.ID, domain.LeaveTypeAnnual, startDate, endDate, "Family trip", gomock.Any(), gomock.Any()).
+					Return(&domain.LeaveRequest{
+						UserID: currentUser.ID, LeaveType: domain.LeaveTypeAnnual, StartDate: startDate, EndDate: endDate,
+						Days: 2, Status: domain.LeaveStatusPending,
+					}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"leave_type":"annual","start_date":"2025-03-10","end_date":"2025-03-11","days":2`,
+		},
+		{
+			name:                 "Error - Invalid Start Date",
+			requestBody:          SubmitLeaveRequestRequest{LeaveType: domain.LeaveTypeAnnual, StartDate: "10-03-2025", EndDate: "2025-03-11"},
+			mockService:          func(mockService *mockSvc.MockLeaveServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid start_date format",
+		},
+		{
+			name:        "Error - Insufficient Balance",
+			requestBody: validRequest,
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().SubmitLeaveRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: 2 days requested, 1 annual leave days available", service.ErrInsufficientLeaveBalance)).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "2 days requested, 1 annual leave days available",
+		},
+		{
+			name:        "Error - Overlap",
+			requestBody: validRequest,
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().SubmitLeaveRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrLeaveOverlap).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Leave request overlaps another leave request",
+		},
+		{
+			name:        "Error - Payroll Period Locked",
+			requestBody: validRequest,
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().SubmitLeaveRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrPeriodLocked).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll period is locked",
+		},
+		{
+			name:        "Error - Service Failure",
+			requestBody: validRequest,
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().SubmitLeaveRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to submit leave request",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockLeaveServiceInterface(ctrl)
+			handler := NewLeaveHandler(mockService)
+
+			tc.mockService(mockService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/leave-requests", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.POST("/leave-requests", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.SubmitLeaveRequest)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestLeaveHandler_ReviewLeaveRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "manager"}
+	leaveID := uuid.New()
+
+	testCases := []struct {
+		name                 string
+		leaveID              string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockLeaveServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success - Approve",
+			leaveID:     leaveID.String(),
+			requestBody: ReviewLeaveRequestRequest{Status: domain.LeaveStatusApproved, Note: "Enjoy"},
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().ReviewLeaveRequest(leaveID, true, "Enjoy", currentUser, gomock.Any(), gomock.Any()).
+					Return(&domain.LeaveRequest{
+						BaseModel: domain.BaseModel{ID: leaveID}, Status: domain.LeaveStatusApproved,
+						ReviewedBy: &currentUser.ID, ReviewNote: "Enjoy",
+					}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: fmt.Sprintf(`"status":"approved","reviewed_by":"%s"`, currentUser.ID),
+		},
+		{
+			name:        "Success - Reject",
+			leaveID:     leaveID.String(),
+			requestBody: ReviewLeaveRequestRequest{Status: domain.LeaveStatusRejected},
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().ReviewLeaveRequest(leaveID, false, "", currentUser, gomock.Any(), gomock.Any()).
+					Return(&domain.LeaveRequest{BaseModel: domain.BaseModel{ID: leaveID}, Status: domain.LeaveStatusRejected}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"status":"rejected"`,
+		},
+		{
+			name:                 "Error - Invalid Status",
+			leaveID:              leaveID.String(),
+			requestBody:          ReviewLeaveRequestRequest{Status: domain.LeaveStatusPending},
+			mockService:          func(mockService *mockSvc.MockLeaveServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:                 "Error - Invalid Leave Request ID",
+			leaveID:              "not-a-uuid",
+			requestBody:          ReviewLeaveRequestRequest{Status: domain.LeaveStatusApproved},
+			mockService:          func(mockService *mockSvc.MockLeaveServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid leave request ID format",
+		},
+		{
+			name:        "Error - Not Found",
+			leaveID:     leaveID.String(),
+			requestBody: ReviewLeaveRequestRequest{Status: domain.LeaveStatusApproved},
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().ReviewLeaveRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrLeaveRequestNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Leave request not found",
+		},
+		{
+			name:        "Error - Not The Manager",
+			leaveID:     leaveID.String(),
+			requestBody: ReviewLeaveRequestRequest{Status: domain.LeaveStatusApproved},
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().ReviewLeaveRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrLeaveReviewForbidden).Times(1)
+			},
+			expectedStatus:       http.StatusForbidden,
+			expectedBodyContains: "Not allowed to review this leave request",
+		},
+		{
+			name:        "Error - Already Reviewed",
+			leaveID:     leaveID.String(),
+			requestBody: ReviewLeaveRequestRequest{Status: domain.LeaveStatusApproved},
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().ReviewLeaveRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrLeaveRequestReviewed).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Leave request has already been reviewed",
+		},
+		{
+			name:        "Error - Payroll Period Locked",
+			leaveID:     leaveID.String(),
+			requestBody: ReviewLeaveRequestRequest{Status: domain.LeaveStatusApproved},
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().ReviewLeaveRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrPeriodLocked).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll period is locked",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockLeaveServiceInterface(ctrl)
+			handler := NewLeaveHandler(mockService)
+
+			tc.mockService(mockService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/leave-requests/"+tc.leaveID+"/review", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.POST("/leave-requests/:id/review", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.ReviewLeaveRequest)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestLeaveHandler_GetEmployeeLeaveBalances(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	employeeID := uuid.New()
+
+	testCases := []struct {
+		name                 string
+		query                string
+		mockService          func(mockService *mockSvc.MockLeaveServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:  "Success - Get Leave Balances",
+			query: "?year=2025",
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().GetLeaveBalances(employeeID, 2025, gomock.Any()).Return([]service.LeaveBalance{{
+					LeaveType: domain.LeaveTypeAnnual, Year: 2025, Entitlement: decimal.NewFromInt(12), Accrued: decimal.NewFromInt(3),
+					Used: decimal.NewFromInt(1), Pending: decimal.Zero, Available: decimal.NewFromInt(2),
+				}}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"leave_type":"annual","year":2025,"entitlement":"12","accrued":"3","used":"1","pending":"0","available":"2"`,
+		},
+		{
+			name:                 "Error - Invalid Year",
+			query:                "?year=last",
+			mockService:          func(mockService *mockSvc.MockLeaveServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid year",
+		},
+		{
+			name:  "Error - Employee Not Found",
+			query: "",
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().GetLeaveBalances(employeeID, time.Now().Year(), gomock.Any()).Return(nil, service.ErrEmployeeNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Employee not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockLeaveServiceInterface(ctrl)
+			handler := NewLeaveHandler(mockService)
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/employees/%s/leave-balances%s", employeeID, tc.query), nil)
+
+			router := gin.Default()
+			router.GET("/employees/:id/leave-balances", handler.GetEmployeeLeaveBalances)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestLeaveHandler_SetLeaveEntitlement(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "adminuser"}
+	employeeID := uuid.New()
+
+	testCases := []struct {
+		name                 string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockLeaveServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success - Set Leave Entitlement",
+			requestBody: SetLeaveEntitlementRequest{Year: 2025, LeaveType: domain.LeaveTypeAnnual, Days: decimal.NewFromInt(15)},
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().SetLeaveEntitlement(employeeID, 2025, domain.LeaveTypeAnnual, decimal.NewFromInt(15), currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.LeaveEntitlement{UserID: employeeID, Year: 2025, LeaveType: domain.LeaveTypeAnnual, Days: decimal.NewFromInt(15)}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"year":2025,"leave_type":"annual","days":"15"`,
+		},
+		{
+			name:                 "Error - Missing Year",
+			requestBody:          SetLeaveEntitlementRequest{LeaveType: domain.LeaveTypeAnnual, Days: decimal.NewFromInt(15)},
+			mockService:          func(mockService *mockSvc.MockLeaveServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:        "Error - Leave Type Without Balance",
+			requestBody: SetLeaveEntitlementRequest{Year: 2025, LeaveType: domain.LeaveTypeUnpaid, Days: decimal.NewFromInt(5)},
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().SetLeaveEntitlement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: %q leave has no balance", service.ErrInvalidLeaveEntitlement, "unpaid")).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: `invalid leave entitlement: \"unpaid\" leave has no balance`,
+		},
+		{
+			name:        "Error - Employee Not Found",
+			requestBody: SetLeaveEntitlementRequest{Year: 2025, LeaveType: domain.LeaveTypeAnnual, Days: decimal.NewFromInt(15)},
+			mockService: func(mockService *mockSvc.MockLeaveServiceInterface) {
+				mockService.EXPECT().SetLeaveEntitlement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrEmployeeNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Employee not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockLeaveServiceInterface(ctrl)
+			handler := NewLeaveHandler(mockService)
+
+			tc.mockService(mockService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/employees/%s/leave-entitlements", employeeID), bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.PUT("/employees/:id/leave-entitlements", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.SetLeaveEntitlement)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"payroll-system/api/response"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
 	"payroll-system/internal/domain"
 	"payroll-system/internal/service"
@@ -27,6 +29,12 @@ type SubmitOvertimeRequest struct {
 	Hours float64 `json:"hours" binding:"required,gt=0"`
 }
 
+// ReviewOvertimeRequest represents the request body for approving or rejecting overtime.
+type ReviewOvertimeRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Note   string `json:"note"`
+}
+
 // SubmitOvertime handles the submission of employee overtime.
 func (h *OvertimeHandler) SubmitOvertime(c *gin.Context) {
 	var req SubmitOvertimeRequest
@@ -55,9 +63,105 @@ func (h *OvertimeHandler) SubmitOvertime(c *gin.Context) {
 
 	overtime, err := h.service.SubmitOvertime(currentUser.ID, date, req.Hours, ipAddress, requestID)
 	if err != nil {
+		if errors.Is(err, service.ErrPeriodLocked) {
+			response.Error(c, http.StatusConflict, "Payroll period is locked", err.Error())
+			return
+		}
 		response.Error(c, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
 
 	response.Success(c, "Overtime submitted successfully", response.ToOvertimeResponse(overtime))
 }
+
+// GetOvertimeHistory handles an employee's request to list their overtimes, latest first. It is paginated and can
+// be filtered by date range and payroll period, see bindHistoryFilter.
+func (h *OvertimeHandler) GetOvertimeHistory(c *gin.Context) {
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	filter, ok := bindHistoryFilter(c)
+	if !ok {
+		return
+	}
+
+	overtimes, total, err := h.service.GetOvertimeHistory(currentUser.ID, filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve overtimes", err.Error())
+		return
+	}
+
+	response.Success(c, "Overtimes retrieved successfully", response.ToPageResponse(response.ToOvertimeListResponse(overtimes), filter.Page, filter.PageSize, total))
+}
+
+// GetPendingOvertimes handles listing the pending overtime the current user can review: every
+// pending overtime for an admin, the overtime of their direct reports for a manager.
+func (h *OvertimeHandler) GetPendingOvertimes(c *gin.Context) {
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	overtimes, err := h.service.GetPendingOvertimes(currentUser)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve overtime", err.Error())
+		return
+	}
+
+	response.Success(c, "Overtime retrieved successfully", response.ToOvertimeListResponse(overtimes))
+}
+
+// ReviewOvertime handles approving or rejecting pending overtime.
+func (h *OvertimeHandler) ReviewOvertime(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid overtime ID format", nil)
+		return
+	}
+
+	var req ReviewOvertimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	approve := req.Status == domain.OvertimeStatusApproved
+	overtime, err := h.service.ReviewOvertime(id, approve, req.Note, currentUser, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOvertimeNotFound):
+			response.Error(c, http.StatusNotFound, "Overtime not found", nil)
+		case errors.Is(err, service.ErrOvertimeReviewForbidden):
+			response.Error(c, http.StatusForbidden, "Not allowed to review this overtime", err.Error())
+		case errors.Is(err, service.ErrOvertimeReviewed):
+			response.Error(c, http.StatusConflict, "Overtime has already been reviewed", err.Error())
+		case errors.Is(err, service.ErrPeriodLocked):
+			response.Error(c, http.StatusConflict, "Payroll period is locked", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to review overtime", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Overtime reviewed successfully", response.ToOvertimeResponse(overtime))
+}
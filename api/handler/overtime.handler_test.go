@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -15,6 +16,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
 	mockSvc "payroll-system/tests/mocks/service"
 )
 
@@ -105,6 +107,25 @@ func TestOvertimeHandler_SubmitOvertime(t *testing.T) {
 			expectedStatus:       http.StatusUnauthorized,
 			expectedBodyContains: "User not authenticated",
 		},
+		{
+			name: "Error - Payroll Period Locked",
+			requestBody: SubmitOvertimeRequest{
+				Date:  dateStr,
+				Hours: 1.5,
+			},
+			setupMiddleware: func(r *gin.Engine, h *OvertimeHandler) {
+				r.POST("/overtime", func(c *gin.Context) {
+					c.Set("currentUser", currentUser)
+					c.Next()
+				}, h.SubmitOvertime)
+			},
+			mockService: func(mockService *mockSvc.MockOvertimeServiceInterface) {
+				mockService.EXPECT().SubmitOvertime(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: 2025-08-18 falls in the payroll period 2025-08-01 to 2025-08-31, which has already been processed", service.ErrPeriodLocked)).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll period is locked",
+		},
 		{
 			name: "Error - Service Fails to Submit",
 			requestBody: SubmitOvertimeRequest{
@@ -156,3 +177,242 @@ func TestOvertimeHandler_SubmitOvertime(t *testing.T) {
 		})
 	}
 }
+
+func TestOvertimeHandler_GetPendingOvertimes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "manager"}
+	employee := domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "jdoe"}
+
+	testCases := []struct {
+		name                 string
+		mockService          func(mockService *mockSvc.MockOvertimeServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name: "Success",
+			mockService: func(mockService *mockSvc.MockOvertimeServiceInterface) {
+				mockService.EXPECT().GetPendingOvertimes(currentUser).
+					Return([]domain.Overtime{{UserID: employee.ID, User: employee, Hours: 2, Status: domain.OvertimeStatusPending}}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"username":"jdoe"`,
+		},
+		{
+			name: "Error - Service Fails",
+			mockService: func(mockService *mockSvc.MockOvertimeServiceInterface) {
+				mockService.EXPECT().GetPendingOvertimes(currentUser).Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve overtime",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockOvertimeService := mockSvc.NewMockOvertimeServiceInterface(ctrl)
+			handler := NewOvertimeHandler(mockOvertimeService)
+
+			tc.mockService(mockOvertimeService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/overtimes/pending", nil)
+
+			router := gin.Default()
+			router.GET("/overtimes/pending", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.GetPendingOvertimes)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestOvertimeHandler_GetOvertimeHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "testuser"}
+
+	testCases := []struct {
+		name                 string
+		query                string
+		mockService          func(mockService *mockSvc.MockOvertimeServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:  "Success",
+			query: "?page=2&page_size=1",
+			mockService: func(mockService *mockSvc.MockOvertimeServiceInterface) {
+				mockService.EXPECT().GetOvertimeHistory(currentUser.ID, domain.HistoryFilter{Page: 2, PageSize: 1}).
+					Return([]domain.Overtime{{UserID: currentUser.ID, Hours: 2, Status: domain.OvertimeStatusApproved}}, int64(3), nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"status":"approved"`,
+		},
+		{
+			name:                 "Error - Invalid To Date",
+			query:                "?to=yesterday",
+			mockService:          func(mockService *mockSvc.MockOvertimeServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid to format",
+		},
+		{
+			name: "Error - Service Fails",
+			mockService: func(mockService *mockSvc.MockOvertimeServiceInterface) {
+				mockService.EXPECT().GetOvertimeHistory(gomock.Any(), gomock.Any()).Return(nil, int64(0), errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve overtimes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockOvertimeService := mockSvc.NewMockOvertimeServiceInterface(ctrl)
+			handler := NewOvertimeHandler(mockOvertimeService)
+
+			tc.mockService(mockOvertimeService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/overtimes"+tc.query, nil)
+
+			router := gin.Default()
+			router.GET("/overtimes", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.GetOvertimeHistory)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestOvertimeHandler_ReviewOvertime(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "manager"}
+	overtimeID := uuid.New()
+
+	testCases := []struct {
+		name                 string
+		overtimeID           string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockOvertimeServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success - Approve",
+			overtimeID:  overtimeID.String(),
+			requestBody: ReviewOvertimeRequest{Status: domain.OvertimeStatusApproved, Note: "Thanks"},
+			mockService: func(mockService *mockSvc.MockOvertimeServiceInterface) {
+				mockService.EXPECT().ReviewOvertime(overtimeID, true, "Thanks", currentUser, gomock.Any(), gomock.Any()).
+					Return(&domain.Overtime{
+						BaseModel: domain.BaseModel{ID: overtimeID}, Status: domain.OvertimeStatusApproved,
+						ReviewedBy: &currentUser.ID, ReviewNote: "Thanks",
+					}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: fmt.Sprintf(`"status":"approved","reviewed_by":"%s"`, currentUser.ID),
+		},
+		{
+			name:        "Success - Reject",
+			overtimeID:  overtimeID.String(),
+			requestBody: ReviewOvertimeRequest{Status: domain.OvertimeStatusRejected},
+			mockService: func(mockService *mockSvc.MockOvertimeServiceInterface) {
+				mockService.EXPECT().ReviewOvertime(overtimeID, false, "", currentUser, gomock.Any(), gomock.Any()).
+					Return(&domain.Overtime{BaseModel: domain.BaseModel{ID: overtimeID}, Status: domain.OvertimeStatusRejected}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"status":"rejected"`,
+		},
+		{
+			name:                 "Error - Invalid Status",
+			overtimeID:           overtimeID.String(),
+			requestBody:          ReviewOvertimeRequest{Status: domain.OvertimeStatusPending},
+			mockService:          func(mockService *mockSvc.MockOvertimeServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:                 "Error - Invalid Overtime ID",
+			overtimeID:           "not-a-uuid",
+			requestBody:          ReviewOvertimeRequest{Status: domain.OvertimeStatusApproved},
+			mockService:          func(mockService *mockSvc.MockOvertimeServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid overtime ID format",
+		},
+		{
+			name:        "Error - Not Found",
+			overtimeID:  overtimeID.String(),
+			requestBody: ReviewOvertimeRequest{Status: domain.OvertimeStatusApproved},
+			mockService: func(mockService *mockSvc.MockOvertimeServiceInterface) {
+				mockService.EXPECT().ReviewOvertime(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrOvertimeNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Overtime not found",
+		},
+		{
+			name:        "Error - Not The Manager",
+			overtimeID:  overtimeID.String(),
+			requestBody: ReviewOvertimeRequest{Status: domain.OvertimeStatusApproved},
+			mockService: func(mockService *mockSvc.MockOvertimeServiceInterface) {
+				mockService.EXPECT().ReviewOvertime(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrOvertimeReviewForbidden).Times(1)
+			},
+			expectedStatus:       http.StatusForbidden,
+			expectedBodyContains: "Not allowed to review this overtime",
+		},
+		{
+			name:        "Error - Already Reviewed",
+			overtimeID:  overtimeID.String(),
+			requestBody: ReviewOvertimeRequest{Status: domain.OvertimeStatusApproved},
+			mockService: func(mockService *mockSvc.MockOvertimeServiceInterface) {
+				mockService.EXPECT().ReviewOvertime(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrOvertimeReviewed).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Overtime has already been reviewed",
+		},
+		{
+			name:        "Error - Payroll Period Locked",
+			overtimeID:  overtimeID.String(),
+			requestBody: ReviewOvertimeRequest{Status: domain.OvertimeStatusApproved},
+			mockService: func(mockService *mockSvc.MockOvertimeServiceInterface) {
+				mockService.EXPECT().ReviewOvertime(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrPeriodLocked).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll period is locked",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockOvertimeService := mockSvc.NewMockOvertimeServiceInterface(ctrl)
+			handler := NewOvertimeHandler(mockOvertimeService)
+
+			tc.mockService(mockOvertimeService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/overtimes/"+tc.overtimeID+"/review", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.POST("/overtimes/:id/review", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.ReviewOvertime)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
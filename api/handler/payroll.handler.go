@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"payroll-system/api/response"
 
@@ -26,8 +27,14 @@ type RunPayrollRequest struct {
 	PayrollPeriodID string `json:"payroll_period_id" binding:"required"`
 }
 
-// RunPayroll handles the request to process payroll for a given period.
-func (h *PayrollHandler) RunPayroll(c *gin.Context) {
+// ReversePayrollRequest represents the request body for reversing a processed payroll.
+type ReversePayrollRequest struct {
+	PayrollPeriodID string `json:"payroll_period_id" binding:"required"`
+	Reason          string `json:"reason" binding:"required"`
+}
+
+// PreviewPayroll handles the request to calculate payroll for a given period without persisting it.
+func (h *PayrollHandler) PreviewPayroll(c *gin.Context) {
 	var req RunPayrollRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
@@ -40,6 +47,36 @@ func (h *PayrollHandler) RunPayroll(c *gin.Context) {
 		return
 	}
 
+	preview, err := h.service.PreviewPayroll(periodID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPayrollPeriodNotFound):
+			response.Error(c, http.StatusNotFound, "Payroll period not found", err.Error())
+		case errors.Is(err, service.ErrPayrollAlreadyProcessed):
+			response.Error(c, http.StatusConflict, "Payroll already processed", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to preview payroll", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Payroll preview calculated successfully", response.ToPayrollPreviewResponse(preview))
+}
+
+// ReversePayroll handles the request to reverse a processed payroll and reopen its period.
+func (h *PayrollHandler) ReversePayroll(c *gin.Context) {
+	var req ReversePayrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	periodID, err := uuid.Parse(req.PayrollPeriodID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid payroll_period_id format", nil)
+		return
+	}
+
 	// Get current user from context (set by AuthMiddleware)
 	user, exists := c.Get("currentUser")
 	if !exists {
@@ -52,10 +89,19 @@ func (h *PayrollHandler) RunPayroll(c *gin.Context) {
 	ipAddress := c.ClientIP()
 	requestID := c.GetHeader("X-Request-ID")
 
-	if err := h.service.RunPayroll(periodID, currentUser.ID, ipAddress, requestID); err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to process payroll", err.Error())
+	if err := h.service.ReversePayroll(periodID, req.Reason, currentUser.ID, ipAddress, requestID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrPayrollPeriodNotFound):
+			response.Error(c, http.StatusNotFound, "Payroll period not found", err.Error())
+		case errors.Is(err, service.ErrPayrollNotProcessed):
+			response.Error(c, http.StatusConflict, "Payroll period has not been processed", err.Error())
+		case errors.Is(err, service.ErrPayrollRunInProgress):
+			response.Error(c, http.StatusConflict, "Payroll run already in progress", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to reverse payroll", err.Error())
+		}
 		return
 	}
 
-	response.Success(c, "Payroll processed successfully", nil)
+	response.Success(c, "Payroll reversed successfully", nil)
 }
@@ -10,14 +10,107 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
 	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
 	mockSvc "payroll-system/tests/mocks/service"
 )
 
-func TestPayrollHandler_RunPayroll(t *testing.T) {
+func TestPayrollHandler_PreviewPayroll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	periodID := uuid.New()
+	period := &domain.PayrollPeriod{BaseModel: domain.BaseModel{ID: periodID}}
+
+	testCases := []struct {
+		name                 string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockPayrollServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success - Preview Calculated",
+			requestBody: RunPayrollRequest{PayrollPeriodID: periodID.String()},
+			mockService: func(mockService *mockSvc.MockPayrollServiceInterface) {
+				mockService.EXPECT().PreviewPayroll(periodID).
+					Return(&service.PayrollPreview{
+						Period:           period,
+						Payslips:         []domain.Payslip{{PayrollPeriodID: periodID, PayrollPeriod: *period, TotalTakeHomePay: decimal.NewFromInt(1000)}},
+						TotalTakeHomePay: decimal.NewFromInt(1000),
+					}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"total_take_home_pay":"1000"`,
+		},
+		{
+			name:                 "Error - Invalid Payroll Period ID Format",
+			requestBody:          RunPayrollRequest{PayrollPeriodID: "not-a-uuid"},
+			mockService:          func(mockService *mockSvc.MockPayrollServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid payroll_period_id format",
+		},
+		{
+			name:        "Error - Period Not Found",
+			requestBody: RunPayrollRequest{PayrollPeriodID: periodID.String()},
+			mockService: func(mockService *mockSvc.MockPayrollServiceInterface) {
+				mockService.EXPECT().PreviewPayroll(periodID).
+					Return(nil, service.ErrPayrollPeriodNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Payroll period not found",
+		},
+		{
+			name:        "Error - Payroll Already Processed",
+			requestBody: RunPayrollRequest{PayrollPeriodID: periodID.String()},
+			mockService: func(mockService *mockSvc.MockPayrollServiceInterface) {
+				mockService.EXPECT().PreviewPayroll(periodID).
+					Return(nil, service.ErrPayrollAlreadyProcessed).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll already processed",
+		},
+		{
+			name:        "Error - Service Fails",
+			requestBody: RunPayrollRequest{PayrollPeriodID: periodID.String()},
+			mockService: func(mockService *mockSvc.MockPayrollServiceInterface) {
+				mockService.EXPECT().PreviewPayroll(periodID).
+					Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to preview payroll",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockPayrollService := mockSvc.NewMockPayrollServiceInterface(ctrl)
+			handler := NewPayrollHandler(mockPayrollService)
+
+			tc.mockService(mockPayrollService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/payroll/preview", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.POST("/payroll/preview", handler.PreviewPayroll)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestPayrollHandler_ReversePayroll(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	currentUser := &domain.User{
@@ -30,80 +123,80 @@ func TestPayrollHandler_RunPayroll(t *testing.T) {
 	testCases := []struct {
 		name                 string
 		requestBody          any
-		setupMiddleware      func(r *gin.Engine, h *PayrollHandler)
+		authenticated        bool
 		mockService          func(mockService *mockSvc.MockPayrollServiceInterface)
 		expectedStatus       int
 		expectedBodyContains string
 	}{
 		{
-			name: "Success - Payroll Run Successfully",
-			requestBody: RunPayrollRequest{
-				PayrollPeriodID: periodID.String(),
-			},
-			setupMiddleware: func(r *gin.Engine, h *PayrollHandler) {
-				r.POST("/payroll/run", func(c *gin.Context) {
-					c.Set("currentUser", currentUser)
-					c.Next()
-				}, h.RunPayroll)
-			},
+			name:          "Success - Payroll Reversed",
+			requestBody:   ReversePayrollRequest{PayrollPeriodID: periodID.String(), Reason: "wrong salary"},
+			authenticated: true,
 			mockService: func(mockService *mockSvc.MockPayrollServiceInterface) {
-				mockService.EXPECT().RunPayroll(periodID, currentUser.ID, gomock.Any(), gomock.Any()).
+				mockService.EXPECT().ReversePayroll(periodID, "wrong salary", currentUser.ID, gomock.Any(), gomock.Any()).
 					Return(nil).Times(1)
 			},
 			expectedStatus:       http.StatusOK,
-			expectedBodyContains: "Payroll processed successfully",
+			expectedBodyContains: "Payroll reversed successfully",
 		},
 		{
-			name:        "Error - Invalid JSON Payload",
-			requestBody: `{"payroll_period_id": "invalid}`,
-			setupMiddleware: func(r *gin.Engine, h *PayrollHandler) {
-				r.POST("/payroll/run", h.RunPayroll)
-			},
+			name:                 "Error - Missing Reason",
+			requestBody:          ReversePayrollRequest{PayrollPeriodID: periodID.String()},
+			authenticated:        true,
 			mockService:          func(mockService *mockSvc.MockPayrollServiceInterface) {},
 			expectedStatus:       http.StatusBadRequest,
 			expectedBodyContains: "Invalid request payload",
 		},
 		{
-			name: "Error - Invalid Payroll Period ID Format",
-			requestBody: RunPayrollRequest{
-				PayrollPeriodID: "not-a-uuid",
-			},
-			setupMiddleware: func(r *gin.Engine, h *PayrollHandler) {
-				r.POST("/payroll/run", h.RunPayroll)
-			},
+			name:                 "Error - User Not Authenticated",
+			requestBody:          ReversePayrollRequest{PayrollPeriodID: periodID.String(), Reason: "wrong salary"},
 			mockService:          func(mockService *mockSvc.MockPayrollServiceInterface) {},
-			expectedStatus:       http.StatusBadRequest,
-			expectedBodyContains: "Invalid payroll_period_id format",
+			expectedStatus:       http.StatusUnauthorized,
+			expectedBodyContains: "User not authenticated",
 		},
 		{
-			name: "Error - User Not Authenticated",
-			requestBody: RunPayrollRequest{
-				PayrollPeriodID: periodID.String(),
-			},
-			setupMiddleware: func(r *gin.Engine, h *PayrollHandler) {
-				r.POST("/payroll/run", h.RunPayroll)
+			name:          "Error - Payroll Run In Progress",
+			requestBody:   ReversePayrollRequest{PayrollPeriodID: periodID.String(), Reason: "wrong salary"},
+			authenticated: true,
+			mockService: func(mockService *mockSvc.MockPayrollServiceInterface) {
+				mockService.EXPECT().ReversePayroll(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(service.ErrPayrollRunInProgress).Times(1)
 			},
-			mockService:          func(mockService *mockSvc.MockPayrollServiceInterface) {},
-			expectedStatus:       http.StatusUnauthorized,
-			expectedBodyContains: "User not authenticated",
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll run already in progress",
 		},
 		{
-			name: "Error - Service Fails to Process Payroll",
-			requestBody: RunPayrollRequest{
-				PayrollPeriodID: periodID.String(),
+			name:          "Error - Period Not Found",
+			requestBody:   ReversePayrollRequest{PayrollPeriodID: periodID.String(), Reason: "wrong salary"},
+			authenticated: true,
+			mockService: func(mockService *mockSvc.MockPayrollServiceInterface) {
+				mockService.EXPECT().ReversePayroll(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(service.ErrPayrollPeriodNotFound).Times(1)
 			},
-			setupMiddleware: func(r *gin.Engine, h *PayrollHandler) {
-				r.POST("/payroll/run", func(c *gin.Context) {
-					c.Set("currentUser", currentUser)
-					c.Next()
-				}, h.RunPayroll)
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Payroll period not found",
+		},
+		{
+			name:          "Error - Period Not Processed",
+			requestBody:   ReversePayrollRequest{PayrollPeriodID: periodID.String(), Reason: "wrong salary"},
+			authenticated: true,
+			mockService: func(mockService *mockSvc.MockPayrollServiceInterface) {
+				mockService.EXPECT().ReversePayroll(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(service.ErrPayrollNotProcessed).Times(1)
 			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll period has not been processed",
+		},
+		{
+			name:          "Error - Service Fails",
+			requestBody:   ReversePayrollRequest{PayrollPeriodID: periodID.String(), Reason: "wrong salary"},
+			authenticated: true,
 			mockService: func(mockService *mockSvc.MockPayrollServiceInterface) {
-				mockService.EXPECT().RunPayroll(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-					Return(errors.New("service layer error")).Times(1)
+				mockService.EXPECT().ReversePayroll(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New("db error")).Times(1)
 			},
 			expectedStatus:       http.StatusInternalServerError,
-			expectedBodyContains: "Failed to process payroll",
+			expectedBodyContains: "Failed to reverse payroll",
 		},
 	}
 
@@ -116,20 +209,19 @@ func TestPayrollHandler_RunPayroll(t *testing.T) {
 
 			tc.mockService(mockPayrollService)
 
-			var reqBody []byte
-			if bodyStr, ok := tc.requestBody.(string); ok {
-				reqBody = []byte(bodyStr)
-			} else {
-				reqBody, _ = json.Marshal(tc.requestBody)
-			}
+			reqBody, _ := json.Marshal(tc.requestBody)
 
 			w := httptest.NewRecorder()
-			req, _ := http.NewRequest(http.MethodPost, "/payroll/run", bytes.NewBuffer(reqBody))
+			req, _ := http.NewRequest(http.MethodPost, "/payroll/reverse", bytes.NewBuffer(reqBody))
 			req.Header.Set("Content-Type", "application/json")
 
 			router := gin.Default()
-			tc.setupMiddleware(router, handler)
-
+			router.POST("/payroll/reverse", func(c *gin.Context) {
+				if tc.authenticated {
+					c.Set("currentUser", currentUser)
+				}
+				c.Next()
+			}, handler.ReversePayroll)
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tc.expectedStatus, w.Code)
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payroll-system/api/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+)
+
+// PayrollRunHandler handles payroll run related HTTP requests.
+type PayrollRunHandler struct {
+	service service.PayrollRunServiceInterface
+}
+
+// NewPayrollRunHandler creates a new PayrollRunHandler.
+func NewPayrollRunHandler(service service.PayrollRunServiceInterface) *PayrollRunHandler {
+	return &PayrollRunHandler{service: service}
+}
+
+// StartPayrollRun handles the request to process payroll for a given period in the background.
+func (h *PayrollRunHandler) StartPayrollRun(c *gin.Context) {
+	var req RunPayrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	periodID, err := uuid.Parse(req.PayrollPeriodID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid payroll_period_id format", nil)
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	run, err := h.service.StartPayrollRun(periodID, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPayrollPeriodNotFound):
+			response.Error(c, http.StatusNotFound, "Payroll period not found", err.Error())
+		case errors.Is(err, service.ErrPayrollAlreadyProcessed):
+			response.Error(c, http.StatusConflict, "Payroll already processed", err.Error())
+		case errors.Is(err, service.ErrPayrollPendingItems):
+			response.Error(c, http.StatusConflict, "Payroll period has records pending review", err.Error())
+		case errors.Is(err, service.ErrPayrollRunInProgress):
+			response.Error(c, http.StatusConflict, "Payroll run already in progress", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to start payroll run", err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.APIResponse{
+		Code:    http.StatusAccepted,
+		Message: "Payroll run queued successfully",
+		Data:    response.ToPayrollRunResponse(run),
+	})
+}
+
+// GetPayrollRunByID handles retrieving a payroll run by its ID.
+func (h *PayrollRunHandler) GetPayrollRunByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid payroll run ID format", nil)
+		return
+	}
+
+	run, err := h.service.GetPayrollRunByID(id)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve payroll run", err.Error())
+		return
+	}
+
+	if run == nil {
+		response.Error(c, http.StatusNotFound, "Payroll run not found", nil)
+		return
+	}
+
+	response.Success(c, "Payroll run retrieved successfully", response.ToPayrollRunResponse(run))
+}
+
+// GetAllPayrollRuns handles retrieving past payroll runs, optionally filtered by payroll_period_id.
+func (h *PayrollRunHandler) GetAllPayrollRuns(c *gin.Context) {
+	var periodID *uuid.UUID
+	if v := c.Query("payroll_period_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid payroll_period_id format", nil)
+			return
+		}
+		periodID = &id
+	}
+
+	runs, err := h.service.GetAllPayrollRuns(periodID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve payroll runs", err.Error())
+		return
+	}
+
+	response.Success(c, "Payroll runs retrieved successfully", response.ToPayrollRunListResponse(runs))
+}
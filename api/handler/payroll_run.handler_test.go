@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+	mockSvc "payroll-system/tests/mocks/service"
+)
+
+func TestPayrollRunHandler_StartPayrollRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		Username:  "adminuser",
+		Role:      "admin",
+	}
+	periodID := uuid.New()
+	run := &domain.PayrollRun{
+		BaseModel:       domain.BaseModel{ID: uuid.New()},
+		PayrollPeriodID: periodID,
+		Status:          domain.PayrollRunStatusQueued,
+	}
+
+	withUser := func(r *gin.Engine, h *PayrollRunHandler) {
+		r.POST("/payroll/run", func(c *gin.Context) {
+			c.Set("currentUser", currentUser)
+			c.Next()
+		}, h.StartPayrollRun)
+	}
+	withoutUser := func(r *gin.Engine, h *PayrollRunHandler) {
+		r.POST("/payroll/run", h.StartPayrollRun)
+	}
+
+	testCases := []struct {
+		name                 string
+		requestBody          any
+		setupMiddleware      func(r *gin.Engine, h *PayrollRunHandler)
+		mockService          func(mockService *mockSvc.MockPayrollRunServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:            "Success - Payroll Run Queued",
+			requestBody:     RunPayrollRequest{PayrollPeriodID: periodID.String()},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().StartPayrollRun(periodID, currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(run, nil).Times(1)
+			},
+			expectedStatus:       http.StatusAccepted,
+			expectedBodyContains: `"status":"queued"`,
+		},
+		{
+			name:                 "Error - Invalid JSON Payload",
+			requestBody:          `{"payroll_period_id": "invalid}`,
+			setupMiddleware:      withoutUser,
+			mockService:          func(mockService *mockSvc.MockPayrollRunServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:                 "Error - Invalid Payroll Period ID Format",
+			requestBody:          RunPayrollRequest{PayrollPeriodID: "not-a-uuid"},
+			setupMiddleware:      withoutUser,
+			mockService:          func(mockService *mockSvc.MockPayrollRunServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid payroll_period_id format",
+		},
+		{
+			name:                 "Error - User Not Authenticated",
+			requestBody:          RunPayrollRequest{PayrollPeriodID: periodID.String()},
+			setupMiddleware:      withoutUser,
+			mockService:          func(mockService *mockSvc.MockPayrollRunServiceInterface) {},
+			expectedStatus:       http.StatusUnauthorized,
+			expectedBodyContains: "User not authenticated",
+		},
+		{
+			name:            "Error - Run Already In Progress",
+			requestBody:     RunPayrollRequest{PayrollPeriodID: periodID.String()},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().StartPayrollRun(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrPayrollRunInProgress).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll run already in progress",
+		},
+		{
+			name:            "Error - Payroll Period Not Found",
+			requestBody:     RunPayrollRequest{PayrollPeriodID: periodID.String()},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().StartPayrollRun(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrPayrollPeriodNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Payroll period not found",
+		},
+		{
+			name:            "Error - Payroll Already Processed",
+			requestBody:     RunPayrollRequest{PayrollPeriodID: periodID.String()},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().StartPayrollRun(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrPayrollAlreadyProcessed).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll already processed",
+		},
+		{
+			name:            "Error - Records Pending Review",
+			requestBody:     RunPayrollRequest{PayrollPeriodID: periodID.String()},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().StartPayrollRun(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: overtime 3f2c1d9e-0000-4000-8000-000000000001 on 2025-08-18", service.ErrPayrollPendingItems)).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "overtime 3f2c1d9e-0000-4000-8000-000000000001 on 2025-08-18",
+		},
+		{
+			name:            "Error - Service Fails to Start Run",
+			requestBody:     RunPayrollRequest{PayrollPeriodID: periodID.String()},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().StartPayrollRun(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to start payroll run",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRunService := mockSvc.NewMockPayrollRunServiceInterface(ctrl)
+			handler := NewPayrollRunHandler(mockRunService)
+
+			tc.mockService(mockRunService)
+
+			var reqBody []byte
+			if bodyStr, ok := tc.requestBody.(string); ok {
+				reqBody = []byte(bodyStr)
+			} else {
+				reqBody, _ = json.Marshal(tc.requestBody)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/payroll/run", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			tc.setupMiddleware(router, handler)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestPayrollRunHandler_GetPayrollRunByID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	runID := uuid.New()
+	run := &domain.PayrollRun{
+		BaseModel:          domain.BaseModel{ID: runID},
+		PayrollPeriodID:    uuid.New(),
+		Status:             domain.PayrollRunStatusRunning,
+		TotalEmployees:     4,
+		ProcessedEmployees: 1,
+	}
+
+	testCases := []struct {
+		name                 string
+		id                   string
+		mockService          func(mockService *mockSvc.MockPayrollRunServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name: "Success - Run Found",
+			id:   runID.String(),
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().GetPayrollRunByID(runID).Return(run, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"progress_percent":25`,
+		},
+		{
+			name:                 "Error - Invalid ID",
+			id:                   "not-a-uuid",
+			mockService:          func(mockService *mockSvc.MockPayrollRunServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid payroll run ID format",
+		},
+		{
+			name: "Error - Run Not Found",
+			id:   runID.String(),
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().GetPayrollRunByID(runID).Return(nil, nil).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Payroll run not found",
+		},
+		{
+			name: "Error - Service Fails",
+			id:   runID.String(),
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().GetPayrollRunByID(runID).Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve payroll run",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRunService := mockSvc.NewMockPayrollRunServiceInterface(ctrl)
+			handler := NewPayrollRunHandler(mockRunService)
+
+			tc.mockService(mockRunService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/payroll-runs/"+tc.id, nil)
+
+			router := gin.Default()
+			router.GET("/payroll-runs/:id", handler.GetPayrollRunByID)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestPayrollRunHandler_GetAllPayrollRuns(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	periodID := uuid.New()
+	runs := []domain.PayrollRun{
+		{BaseModel: domain.BaseModel{ID: uuid.New()}, PayrollPeriodID: periodID, Status: domain.PayrollRunStatusSucceeded},
+	}
+
+	testCases := []struct {
+		name                 string
+		query                string
+		mockService          func(mockService *mockSvc.MockPayrollRunServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:  "Success - All Runs",
+			query: "",
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().GetAllPayrollRuns(nil).Return(runs, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"status":"succeeded"`,
+		},
+		{
+			name:  "Success - Filtered By Period",
+			query: "?payroll_period_id=" + periodID.String(),
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().GetAllPayrollRuns(&periodID).Return(runs, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: periodID.String(),
+		},
+		{
+			name:                 "Error - Invalid Period Filter",
+			query:                "?payroll_period_id=bad",
+			mockService:          func(mockService *mockSvc.MockPayrollRunServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid payroll_period_id format",
+		},
+		{
+			name:  "Error - Service Fails",
+			query: "",
+			mockService: func(mockService *mockSvc.MockPayrollRunServiceInterface) {
+				mockService.EXPECT().GetAllPayrollRuns(nil).Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve payroll runs",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRunService := mockSvc.NewMockPayrollRunServiceInterface(ctrl)
+			handler := NewPayrollRunHandler(mockRunService)
+
+			tc.mockService(mockRunService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/payroll-runs"+tc.query, nil)
+
+			router := gin.Default()
+			router.GET("/payroll-runs", handler.GetAllPayrollRuns)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
 	"payroll-system/api/response"
 
@@ -14,11 +18,12 @@ import (
 // PayslipHandler handles payslip related HTTP requests.
 type PayslipHandler struct {
 	service service.PayslipServiceInterface
+	company response.PayslipCompany // Shown in the header of payslip PDFs
 }
 
 // NewPayslipHandler creates a new PayslipHandler.
-func NewPayslipHandler(service service.PayslipServiceInterface) *PayslipHandler {
-	return &PayslipHandler{service: service}
+func NewPayslipHandler(service service.PayslipServiceInterface, company response.PayslipCompany) *PayslipHandler {
+	return &PayslipHandler{service: service, company: company}
 }
 
 // GetEmployeePayslipRequest represents the request for an employee to get their payslip.
@@ -50,7 +55,12 @@ func (h *PayslipHandler) GetEmployeePayslip(c *gin.Context) {
 
 	payslip, err := h.service.GetEmployeePayslip(currentUser.ID, periodID)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to retrieve payslip", err.Error())
+		switch {
+		case errors.Is(err, service.ErrPayslipNotFound):
+			response.Error(c, http.StatusNotFound, "Payslip not found", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to retrieve payslip", err.Error())
+		}
 		return
 	}
 
@@ -62,6 +72,109 @@ func (h *PayslipHandler) GetEmployeePayslip(c *gin.Context) {
 	response.Success(c, "Payslip retrieved successfully", response.ToPayslipResponse(payslip))
 }
 
+// DownloadEmployeePayslip handles an employee's request to download their payslip for a payroll period as a PDF.
+func (h *PayslipHandler) DownloadEmployeePayslip(c *gin.Context) {
+	periodID, err := uuid.Parse(c.Param("period_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid payroll_period_id format", nil)
+		return
+	}
+
+	// Get current user
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	payslip, err := h.service.GetEmployeePayslip(currentUser.ID, periodID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPayslipNotFound):
+			response.Error(c, http.StatusNotFound, "Payslip not found", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to retrieve payslip", err.Error())
+		}
+		return
+	}
+
+	if payslip == nil {
+		response.Error(c, http.StatusNotFound, "Payslip not found", nil)
+		return
+	}
+
+	h.writePayslipPDF(c, payslip, currentUser.Username)
+}
+
+// DownloadPayslip handles an admin's request to download any employee's payslip as a PDF.
+func (h *PayslipHandler) DownloadPayslip(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid payslip ID format", nil)
+		return
+	}
+
+	payslip, err := h.service.GetPayslip(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPayslipNotFound):
+			response.Error(c, http.StatusNotFound, "Payslip not found", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to retrieve payslip", err.Error())
+		}
+		return
+	}
+
+	if payslip == nil {
+		response.Error(c, http.StatusNotFound, "Payslip not found", nil)
+		return
+	}
+
+	h.writePayslipPDF(c, payslip, payslip.User.Username)
+}
+
+// writePayslipPDF responds with a payslip rendered as a PDF attachment, named after the employee and the payroll period.
+func (h *PayslipHandler) writePayslipPDF(c *gin.Context, payslip *domain.Payslip, employee string) {
+	period := response.ToPayrollPeriodResponse(&payslip.PayrollPeriod)
+
+	var buf bytes.Buffer
+	if err := response.WritePayslipPDF(&buf, h.company, employee, period, response.ToPayslipResponse(payslip)); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to render payslip", err.Error())
+		return
+	}
+
+	fileName := fmt.Sprintf("payslip-%s-%s-%s.pdf", employee, period.StartDate, period.EndDate)
+	c.DataFromReader(http.StatusOK, int64(buf.Len()), "application/pdf", &buf, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": fileName}),
+	})
+}
+
+// GetPayslipHistory handles an employee's request to list their payslips, latest first. It is paginated and can
+// be filtered by date range and payroll period, see bindHistoryFilter.
+func (h *PayslipHandler) GetPayslipHistory(c *gin.Context) {
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	filter, ok := bindHistoryFilter(c)
+	if !ok {
+		return
+	}
+
+	payslips, total, err := h.service.GetPayslipHistory(currentUser.ID, filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve payslips", err.Error())
+		return
+	}
+
+	response.Success(c, "Payslips retrieved successfully", response.ToPageResponse(response.ToPayslipHistoryListResponse(payslips), filter.Page, filter.PageSize, total))
+}
+
 // GetPayslipSummary handles an admin's request to view a summary of all payslips for a period.
 func (h *PayslipHandler) GetPayslipSummary(c *gin.Context) {
 	var req GetEmployeePayslipRequest
@@ -76,21 +189,11 @@ func (h *PayslipHandler) GetPayslipSummary(c *gin.Context) {
 		return
 	}
 
-	payslips, totalTakeHomePay, err := h.service.GetPayslipSummaryForPeriod(periodID)
+	summary, err := h.service.GetPayslipSummaryForPeriod(periodID)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to retrieve payslip summary", err.Error())
 		return
 	}
 
-	// Map payslips to response DTOs
-	payslipResponses := make([]response.PayslipResponse, 0)
-	for _, p := range payslips {
-		payslipResponses = append(payslipResponses, response.ToPayslipResponse(&p))
-	}
-
-	response.Success(c, "Payslip summary retrieved successfully", gin.H{
-		"payroll_period_id":                 periodID.String(),
-		"total_take_home_pay_all_employees": totalTakeHomePay,
-		"payslips":                          payslipResponses,
-	})
+	response.Success(c, "Payslip summary retrieved successfully", response.ToPayslipSummaryResponse(periodID.String(), summary))
 }
@@ -4,16 +4,21 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	"payroll-system/api/response"
 	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
 	mockSvc "payroll-system/tests/mocks/service"
 )
 
@@ -49,6 +54,45 @@ func TestPayslipHandler_GetEmployeePayslip(t *testing.T) {
 			expectedStatus:       http.StatusOK,
 			expectedBodyContains: "Payslip retrieved successfully",
 		},
+		{
+			name: "Success - Payslip Lines Are Returned",
+			requestBody: GetEmployeePayslipRequest{
+				PayrollPeriodID: periodID.String(),
+			},
+			setupMiddleware: func(r *gin.Engine, h *PayslipHandler) {
+				r.POST("/payslip", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, h.GetEmployeePayslip)
+			},
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetEmployeePayslip(currentUser.ID, periodID).
+					Return(&domain.Payslip{UserID: currentUser.ID, Items: []domain.PayslipItem{{
+						ComponentType: domain.PayslipComponentEarning,
+						Code:          "BASIC_SALARY",
+						Amount:        decimal.NewFromInt(800000),
+					}}}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"items":[{"component_type":"earning","code":"BASIC_SALARY"`,
+		},
+		{
+			name: "Success - BPJS Contributions Are Returned Separately",
+			requestBody: GetEmployeePayslipRequest{
+				PayrollPeriodID: periodID.String(),
+			},
+			setupMiddleware: func(r *gin.Engine, h *PayslipHandler) {
+				r.POST("/payslip", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, h.GetEmployeePayslip)
+			},
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetEmployeePayslip(currentUser.ID, periodID).
+					Return(&domain.Payslip{UserID: currentUser.ID, Items: []domain.PayslipItem{
+						{ComponentType: domain.PayslipComponentEarning, Code: service.PayslipItemCodeBasicSalary, Amount: decimal.NewFromInt(4000000)},
+						{ComponentType: domain.PayslipComponentDeduction, Code: service.PayslipItemCodeBPJSJHT, Amount: decimal.NewFromInt(80000)},
+						{ComponentType: domain.PayslipComponentEmployerContribution, Code: service.PayslipItemCodeBPJSJHTEmployer, Amount: decimal.NewFromInt(148000)},
+						{ComponentType: domain.PayslipComponentEmployerContribution, Code: service.PayslipItemCodeBPJSJKM, Amount: decimal.NewFromInt(12000)},
+					}}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"total_employee_contributions":"80000","total_employer_contributions":"160000"}`,
+		},
 		{
 			name:        "Error - Invalid JSON",
 			requestBody: `{"payroll_period_id": "invalid}`,
@@ -118,7 +162,7 @@ func TestPayslipHandler_GetEmployeePayslip(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			mockService := mockSvc.NewMockPayslipServiceInterface(ctrl)
-			handler := NewPayslipHandler(mockService)
+			handler := NewPayslipHandler(mockService, response.PayslipCompany{})
 
 			tc.mockService(mockService)
 
@@ -143,6 +187,285 @@ func TestPayslipHandler_GetEmployeePayslip(t *testing.T) {
 	}
 }
 
+// pdfPayslip returns a processed payslip for August 2025 with one attendance a day, so the attendance
+// table of its PDF continues on a second page.
+func pdfPayslip(userID uuid.UUID) *domain.Payslip {
+	period := domain.PayrollPeriod{
+		BaseModel:   domain.BaseModel{ID: uuid.New()},
+		StartDate:   time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC),
+		IsProcessed: true,
+	}
+	payslip := &domain.Payslip{
+		BaseModel:        domain.BaseModel{ID: uuid.New()},
+		UserID:           userID,
+		User:             domain.User{BaseModel: domain.BaseModel{ID: userID}, Username: "employee1"},
+		PayrollPeriodID:  period.ID,
+		PayrollPeriod:    period,
+		GrossEarnings:    decimal.NewFromInt(5000000),
+		TotalDeductions:  decimal.NewFromInt(250000),
+		TotalTakeHomePay: decimal.NewFromInt(4750000),
+		Items: []domain.PayslipItem{
+			{ComponentType: domain.PayslipComponentEarning, Code: "BASIC_SALARY", Name: "Basic salary", Amount: decimal.NewFromInt(5000000)},
+			{ComponentType: domain.PayslipComponentDeduction, Code: "PPH21", Name: "Income tax (PPh 21)", Amount: decimal.NewFromInt(250000)},
+		},
+		Overtimes: []*domain.Overtime{{Date: time.Date(2025, 8, 16, 0, 0, 0, 0, time.UTC), Hours: 2, PayrollPeriodID: &period.ID}},
+	}
+	for day := 1; day <= 31; day++ {
+		date := time.Date(2025, 8, day, 0, 0, 0, 0, time.UTC)
+		checkOut := time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC)
+		payslip.Attendances = append(payslip.Attendances, &domain.Attendance{
+			Date:            date,
+			CheckInTime:     time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			CheckOutTime:    &checkOut,
+			PayrollPeriodID: &period.ID,
+		})
+	}
+	return payslip
+}
+
+func TestPayslipHandler_DownloadEmployeePayslip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "employee1"}
+	periodID := uuid.New()
+
+	testCases := []struct {
+		name                 string
+		periodID             string
+		mockService          func(mockService *mockSvc.MockPayslipServiceInterface)
+		expectedStatus       int
+		expectedBodyContains []string
+	}{
+		{
+			name:     "Success",
+			periodID: periodID.String(),
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetEmployeePayslip(currentUser.ID, periodID).Return(pdfPayslip(currentUser.ID), nil).Times(1)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBodyContains: []string{
+				"%PDF-1.4", "(PT Maju Jaya)", "(Payslip Period 1 Aug 2025 - 31 Aug 2025)", "(employee1)",
+				"(Basic salary)", "(Income tax \\(PPh 21\\))", "(Take-home pay)", "(4,750,000.00)",
+				"(2025-08-31)", "(2025-08-16)", "(Page 2 of 2)",
+			},
+		},
+		{
+			name:                 "Error - Invalid Period ID",
+			periodID:             "invalid",
+			mockService:          func(mockService *mockSvc.MockPayslipServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: []string{"Invalid payroll_period_id format"},
+		},
+		{
+			name:     "Error - Payslip Not Found",
+			periodID: periodID.String(),
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetEmployeePayslip(currentUser.ID, periodID).Return(nil, nil).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: []string{"Payslip not found"},
+		},
+		{
+			name:     "Error - Period Not Processed",
+			periodID: periodID.String(),
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetEmployeePayslip(currentUser.ID, periodID).
+					Return(nil, fmt.Errorf("%w: payslip can only be generated for processed payroll periods", service.ErrPayslipNotFound)).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: []string{"Payslip not found", "processed payroll periods"},
+		},
+		{
+			name:     "Error - Service Failure",
+			periodID: periodID.String(),
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetEmployeePayslip(currentUser.ID, periodID).Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: []string{"Failed to retrieve payslip"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockPayslipServiceInterface(ctrl)
+			handler := NewPayslipHandler(mockService, response.PayslipCompany{Name: "PT Maju Jaya", Address: "Jl. Sudirman 1, Jakarta"})
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/payslips/"+tc.periodID+"/pdf", nil)
+
+			router := gin.Default()
+			router.GET("/payslips/:period_id/pdf", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.DownloadEmployeePayslip)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			for _, expected := range tc.expectedBodyContains {
+				assert.Contains(t, w.Body.String(), expected)
+			}
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+				assert.Equal(t, "attachment; filename=payslip-employee1-2025-08-01-2025-08-31.pdf", w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
+
+func TestPayslipHandler_DownloadPayslip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	payslip := pdfPayslip(uuid.New())
+
+	testCases := []struct {
+		name                 string
+		id                   string
+		mockService          func(mockService *mockSvc.MockPayslipServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name: "Success",
+			id:   payslip.ID.String(),
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetPayslip(payslip.ID).Return(payslip, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: "(employee1)",
+		},
+		{
+			name:                 "Error - Invalid Payslip ID",
+			id:                   "invalid",
+			mockService:          func(mockService *mockSvc.MockPayslipServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid payslip ID format",
+		},
+		{
+			name: "Error - Payslip Not Found",
+			id:   payslip.ID.String(),
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetPayslip(payslip.ID).Return(nil, nil).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Payslip not found",
+		},
+		{
+			name: "Error - Period Not Found",
+			id:   payslip.ID.String(),
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetPayslip(payslip.ID).Return(nil, fmt.Errorf("%w: payroll period not found", service.ErrPayslipNotFound)).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Payslip not found",
+		},
+		{
+			name: "Error - Service Failure",
+			id:   payslip.ID.String(),
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetPayslip(payslip.ID).Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve payslip",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockPayslipServiceInterface(ctrl)
+			handler := NewPayslipHandler(mockService, response.PayslipCompany{Name: "PT Maju Jaya"})
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/payslips/"+tc.id+"/pdf", nil)
+
+			router := gin.Default()
+			router.GET("/payslips/:id/pdf", handler.DownloadPayslip)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, "attachment; filename=payslip-employee1-2025-08-01-2025-08-31.pdf", w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
+
+func TestPayslipHandler_GetPayslipHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "testuser"}
+	processedAt := time.Date(2025, 9, 1, 8, 0, 0, 0, time.UTC)
+	period := domain.PayrollPeriod{
+		BaseModel:   domain.BaseModel{ID: uuid.New()},
+		StartDate:   time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC),
+		IsProcessed: true,
+		ProcessedAt: &processedAt,
+	}
+
+	testCases := []struct {
+		name                 string
+		query                string
+		mockService          func(mockService *mockSvc.MockPayslipServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:  "Success",
+			query: "?from=2025-01-01&to=2025-12-31",
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetPayslipHistory(currentUser.ID, gomock.Any()).
+					Return([]domain.Payslip{{UserID: currentUser.ID, PayrollPeriodID: period.ID, PayrollPeriod: period, TotalTakeHomePay: decimal.NewFromInt(4750000)}}, int64(1), nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"start_date":"2025-08-01","end_date":"2025-08-31","is_processed":true,"processed_at":"2025-09-01T08:00:00Z"},"gross_earnings":"0","total_deductions":"0","total_take_home_pay":"4750000"}]`,
+		},
+		{
+			name:                 "Error - Invalid Page",
+			query:                "?page=-1",
+			mockService:          func(mockService *mockSvc.MockPayslipServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid page",
+		},
+		{
+			name: "Error - Service Fails",
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetPayslipHistory(gomock.Any(), gomock.Any()).Return(nil, int64(0), errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve payslips",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockPayslipServiceInterface(ctrl)
+			handler := NewPayslipHandler(mockService, response.PayslipCompany{})
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/payslips"+tc.query, nil)
+
+			router := gin.Default()
+			router.GET("/payslips", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.GetPayslipHistory)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
 func TestPayslipHandler_GetPayslipSummary(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	periodID := uuid.New()
@@ -161,11 +484,42 @@ func TestPayslipHandler_GetPayslipSummary(t *testing.T) {
 			},
 			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
 				payslips := []domain.Payslip{{}, {}}
-				mockService.EXPECT().GetPayslipSummaryForPeriod(periodID).Return(payslips, 150000.0, nil).Times(1)
+				mockService.EXPECT().GetPayslipSummaryForPeriod(periodID).Return(&service.PayslipSummary{Payslips: payslips, TotalTakeHomePay: decimal.NewFromInt(150000)}, nil).Times(1)
 			},
 			expectedStatus:       http.StatusOK,
 			expectedBodyContains: "Payslip summary retrieved successfully",
 		},
+		{
+			name: "Success - Component Totals Are Returned",
+			requestBody: GetEmployeePayslipRequest{
+				PayrollPeriodID: periodID.String(),
+			},
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetPayslipSummaryForPeriod(periodID).Return(&service.PayslipSummary{
+					TotalDeductions: decimal.NewFromInt(70),
+					Components: []service.PayslipComponentTotal{
+						{ComponentType: domain.PayslipComponentDeduction, Code: service.PayslipItemCodePPh21, Name: "PPh 21 (TER A)", Amount: decimal.NewFromInt(70)},
+					},
+				}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"component_totals":[{"component_type":"deduction","code":"PPH21","name":"PPh 21 (TER A)","amount":"70"}]`,
+		},
+		{
+			name: "Success - Employer Cost Is Returned",
+			requestBody: GetEmployeePayslipRequest{
+				PayrollPeriodID: periodID.String(),
+			},
+			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
+				mockService.EXPECT().GetPayslipSummaryForPeriod(periodID).Return(&service.PayslipSummary{
+					TotalGrossEarnings:         decimal.NewFromInt(1070),
+					TotalEmployerContributions: decimal.NewFromInt(30),
+					TotalEmployerCost:          decimal.NewFromInt(1100),
+				}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"total_employer_cost":"1100"`,
+		},
 		{
 			name:                 "Error - Invalid JSON",
 			requestBody:          `{"payroll_period_id": "invalid}`,
@@ -188,7 +542,7 @@ func TestPayslipHandler_GetPayslipSummary(t *testing.T) {
 				PayrollPeriodID: periodID.String(),
 			},
 			mockService: func(mockService *mockSvc.MockPayslipServiceInterface) {
-				mockService.EXPECT().GetPayslipSummaryForPeriod(periodID).Return(nil, 0.0, errors.New("db error")).Times(1)
+				mockService.EXPECT().GetPayslipSummaryForPeriod(periodID).Return(nil, errors.New("db error")).Times(1)
 			},
 			expectedStatus:       http.StatusInternalServerError,
 			expectedBodyContains: "Failed to retrieve payslip summary",
@@ -200,7 +554,7 @@ func TestPayslipHandler_GetPayslipSummary(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			mockService := mockSvc.NewMockPayslipServiceInterface(ctrl)
-			handler := NewPayslipHandler(mockService)
+			handler := NewPayslipHandler(mockService, response.PayslipCompany{})
 
 			tc.mockService(mockService)
 
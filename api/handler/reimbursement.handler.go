@@ -1,10 +1,18 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"payroll-system/api/response"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 
 	"payroll-system/internal/domain"
 	"payroll-system/internal/service"
@@ -20,20 +28,62 @@ func NewReimbursementHandler(service service.ReimbursementServiceInterface) *Rei
 	return &ReimbursementHandler{service: service}
 }
 
-// SubmitReimbursementRequest represents the request body for submitting a reimbursement.
+// SubmitReimbursementRequest represents the request body for submitting a reimbursement: a multipart form
+// with its receipts, or a JSON body for a claim without receipts.
 type SubmitReimbursementRequest struct {
-	Amount      float64 `json:"amount" binding:"required,gt=0"`
-	Description string  `json:"description"`
+	CategoryID  string                  `form:"category_id" json:"category_id" binding:"required"`
+	ExpenseDate string                  `form:"expense_date" json:"expense_date" binding:"required"` // YYYY-MM-DD
+	Amount      json.Number             `form:"amount" json:"amount" binding:"required"`             // accepts a JSON number or a decimal string
+	Description string                  `form:"description" json:"description"`
+	Receipts    []*multipart.FileHeader `form:"receipts" json:"-"` // receipt images or PDFs, multipart only
 }
 
-// SubmitReimbursement handles the submission of employee reimbursement.
+// ReviewReimbursementRequest represents the request body for reviewing a reimbursement.
+type ReviewReimbursementRequest struct {
+	Status         string           `json:"status" binding:"required,oneof=approved rejected"`
+	ApprovedAmount *decimal.Decimal `json:"approved_amount"` // defaults to the claimed amount
+	Reason         string           `json:"reason"`          // required to reject
+}
+
+// ReimbursementCategoryRequest represents the request body for creating or updating a reimbursement category.
+type ReimbursementCategoryRequest struct {
+	Name          string           `json:"name" binding:"required"`
+	PerClaimLimit *decimal.Decimal `json:"per_claim_limit"` // null for no limit
+	MonthlyLimit  *decimal.Decimal `json:"monthly_limit"`   // null for no limit
+	YearlyLimit   *decimal.Decimal `json:"yearly_limit"`    // null for no limit
+}
+
+// SubmitReimbursement handles the submission of employee reimbursement, as a multipart form with receipts or as JSON
+// without them.
 func (h *ReimbursementHandler) SubmitReimbursement(c *gin.Context) {
 	var req SubmitReimbursementRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var b binding.Binding = binding.FormMultipart
+	if c.ContentType() == binding.MIMEJSON {
+		b = binding.JSON
+	}
+	if err := c.ShouldBindWith(&req, b); err != nil {
 		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
 		return
 	}
 
+	amount, err := decimal.NewFromString(req.Amount.String())
+	if err != nil || !amount.IsPositive() {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", "amount must be greater than 0")
+		return
+	}
+
+	categoryID, err := uuid.Parse(req.CategoryID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid reimbursement category ID format", nil)
+		return
+	}
+
+	expenseDate, err := time.Parse("2006-01-02", req.ExpenseDate)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD", nil)
+		return
+	}
+
 	// Get current user from context (set by AuthMiddleware)
 	user, exists := c.Get("currentUser")
 	if !exists {
@@ -46,11 +96,267 @@ func (h *ReimbursementHandler) SubmitReimbursement(c *gin.Context) {
 	ipAddress := c.ClientIP()
 	requestID := c.GetHeader("X-Request-ID")
 
-	reimbursement, err := h.service.SubmitReimbursement(currentUser.ID, req.Amount, req.Description, ipAddress, requestID)
+	receipts := make([]service.ReceiptUpload, 0, len(req.Receipts))
+	for _, fileHeader := range req.Receipts {
+		file, err := fileHeader.Open()
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid receipt", err.Error())
+			return
+		}
+		defer file.Close()
+		receipts = append(receipts, service.ReceiptUpload{FileName: fileHeader.Filename, Content: file})
+	}
+
+	reimbursement, err := h.service.SubmitReimbursement(currentUser.ID, categoryID, expenseDate, amount, req.Description, receipts, ipAddress, requestID)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to submit reimbursement", err.Error())
+		switch {
+		case errors.Is(err, service.ErrInvalidReimbursement):
+			response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		case errors.Is(err, service.ErrInvalidReceipt):
+			response.Error(c, http.StatusBadRequest, "Invalid receipt", err.Error())
+		case errors.Is(err, service.ErrReimbursementLimitExceeded):
+			response.Error(c, http.StatusBadRequest, "Reimbursement limit exceeded", err.Error())
+		case errors.Is(err, service.ErrReimbursementCategoryNotFound):
+			response.Error(c, http.StatusNotFound, "Reimbursement category not found", nil)
+		case errors.Is(err, service.ErrPeriodLocked):
+			response.Error(c, http.StatusConflict, "Payroll period is locked", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to submit reimbursement", err.Error())
+		}
 		return
 	}
 
 	response.Success(c, "Reimbursement submitted successfully", response.ToReimbursementResponse(reimbursement))
 }
+
+// GetReimbursementHistory handles an employee's request to list their reimbursements, latest first. It is paginated and can
+// be filtered by date range and payroll period, see bindHistoryFilter.
+func (h *ReimbursementHandler) GetReimbursementHistory(c *gin.Context) {
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	filter, ok := bindHistoryFilter(c)
+	if !ok {
+		return
+	}
+
+	reimbursements, total, err := h.service.GetReimbursementHistory(currentUser.ID, filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve reimbursements", err.Error())
+		return
+	}
+
+	response.Success(c, "Reimbursements retrieved successfully", response.ToPageResponse(response.ToReimbursementListResponse(reimbursements), filter.Page, filter.PageSize, total))
+}
+
+// GetPendingReimbursements handles listing the reimbursements waiting for review.
+func (h *ReimbursementHandler) GetPendingReimbursements(c *gin.Context) {
+	reimbursements, err := h.service.GetPendingReimbursements()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve reimbursements", err.Error())
+		return
+	}
+
+	response.Success(c, "Reimbursements retrieved successfully", response.ToReimbursementListResponse(reimbursements))
+}
+
+// ReviewReimbursement handles approving, in full or in part, or rejecting a pending reimbursement.
+func (h *ReimbursementHandler) ReviewReimbursement(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid reimbursement ID format", nil)
+		return
+	}
+
+	var req ReviewReimbursementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	approve := req.Status == domain.ReimbursementStatusApproved
+	reimbursement, err := h.service.ReviewReimbursement(id, approve, req.ApprovedAmount, req.Reason, currentUser, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidApprovedAmount), errors.Is(err, service.ErrRejectionReasonRequired):
+			response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		case errors.Is(err, service.ErrReimbursementNotFound):
+			response.Error(c, http.StatusNotFound, "Reimbursement not found", nil)
+		case errors.Is(err, service.ErrReimbursementReviewForbidden):
+			response.Error(c, http.StatusForbidden, "Not allowed to review this reimbursement", err.Error())
+		case errors.Is(err, service.ErrReimbursementReviewed):
+			response.Error(c, http.StatusConflict, "Reimbursement has already been reviewed", err.Error())
+		case errors.Is(err, service.ErrReceiptRequired):
+			response.Error(c, http.StatusConflict, "Reimbursement has no receipt", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to review reimbursement", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Reimbursement reviewed successfully", response.ToReimbursementResponse(reimbursement))
+}
+
+// CreateReimbursementCategory handles creating a reimbursement category with its limits.
+func (h *ReimbursementHandler) CreateReimbursementCategory(c *gin.Context) {
+	var req ReimbursementCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	newCategory := &domain.ReimbursementCategory{
+		Name:          req.Name,
+		PerClaimLimit: req.PerClaimLimit,
+		MonthlyLimit:  req.MonthlyLimit,
+		YearlyLimit:   req.YearlyLimit,
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	category, err := h.service.CreateReimbursementCategory(newCategory, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidReimbursementCategory):
+			response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		case errors.Is(err, service.ErrDuplicateReimbursementCategory):
+			response.Error(c, http.StatusConflict, "Reimbursement category name is already taken", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to create reimbursement category", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Reimbursement category created successfully", response.ToReimbursementCategoryResponse(category))
+}
+
+// UpdateReimbursementCategory handles changing the name and limits of a reimbursement category.
+func (h *ReimbursementHandler) UpdateReimbursementCategory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid reimbursement category ID format", nil)
+		return
+	}
+
+	var req ReimbursementCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	changes := &domain.ReimbursementCategory{
+		Name:          req.Name,
+		PerClaimLimit: req.PerClaimLimit,
+		MonthlyLimit:  req.MonthlyLimit,
+		YearlyLimit:   req.YearlyLimit,
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	category, err := h.service.UpdateReimbursementCategory(id, changes, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrReimbursementCategoryNotFound):
+			response.Error(c, http.StatusNotFound, "Reimbursement category not found", nil)
+		case errors.Is(err, service.ErrInvalidReimbursementCategory):
+			response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		case errors.Is(err, service.ErrDuplicateReimbursementCategory):
+			response.Error(c, http.StatusConflict, "Reimbursement category name is already taken", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to update reimbursement category", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Reimbursement category updated successfully", response.ToReimbursementCategoryResponse(category))
+}
+
+// GetReimbursementCategories handles listing the reimbursement categories.
+func (h *ReimbursementHandler) GetReimbursementCategories(c *gin.Context) {
+	categories, err := h.service.GetReimbursementCategories()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve reimbursement categories", err.Error())
+		return
+	}
+
+	response.Success(c, "Reimbursement categories retrieved successfully", response.ToReimbursementCategoryListResponse(categories))
+}
+
+// DownloadReceipt handles downloading a receipt of a reimbursement, for the employee who claimed it or an admin.
+func (h *ReimbursementHandler) DownloadReceipt(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid reimbursement ID format", nil)
+		return
+	}
+	receiptID, err := uuid.Parse(c.Param("receipt_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid receipt ID format", nil)
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	receipt, content, err := h.service.GetReceipt(id, receiptID, currentUser)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrReimbursementNotFound):
+			response.Error(c, http.StatusNotFound, "Reimbursement not found", nil)
+		case errors.Is(err, service.ErrReceiptNotFound):
+			response.Error(c, http.StatusNotFound, "Receipt not found", nil)
+		case errors.Is(err, service.ErrReceiptForbidden):
+			response.Error(c, http.StatusForbidden, "Not allowed to download this receipt", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to download receipt", err.Error())
+		}
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, receipt.Size, receipt.ContentType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": receipt.FileName}),
+	})
+}
@@ -4,16 +4,22 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
 	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
 	mockSvc "payroll-system/tests/mocks/service"
 )
 
@@ -24,75 +30,187 @@ func TestReimbursementHandler_SubmitReimbursement(t *testing.T) {
 		BaseModel: domain.BaseModel{ID: uuid.New()},
 		Username:  "testuser",
 	}
+	categoryID := uuid.New()
+	expenseDate := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	claim := func(amount string) map[string]string {
+		return map[string]string{"category_id": categoryID.String(), "expense_date": "2025-08-18", "amount": amount}
+	}
+	withCurrentUser := func(r *gin.Engine, h *ReimbursementHandler) {
+		r.POST("/reimbursements", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, h.SubmitReimbursement)
+	}
+	withoutCurrentUser := func(r *gin.Engine, h *ReimbursementHandler) {
+		r.POST("/reimbursements", h.SubmitReimbursement)
+	}
 
 	testCases := []struct {
 		name                 string
-		requestBody          any
+		fields               map[string]string // Sent as a multipart form with the receipts
+		json                 string            // Sent as JSON when fields is nil
+		receipts             []string
 		setupMiddleware      func(r *gin.Engine, h *ReimbursementHandler)
 		mockService          func(mockService *mockSvc.MockReimbursementServiceInterface)
 		expectedStatus       int
 		expectedBodyContains string
 	}{
 		{
-			name: "Success - Valid Submission",
-			requestBody: SubmitReimbursementRequest{
-				Amount:      150.75,
-				Description: "Team Lunch",
-			},
-			setupMiddleware: func(r *gin.Engine, h *ReimbursementHandler) {
-				r.POST("/reimbursements", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, h.SubmitReimbursement)
-			},
+			name:            "Success - Valid Submission",
+			fields:          map[string]string{"category_id": categoryID.String(), "expense_date": "2025-08-18", "amount": "150.75", "description": "Team Lunch"},
+			receipts:        []string{"lunch.pdf", "lunch-2.pdf"},
+			setupMiddleware: withCurrentUser,
 			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
-				mockService.EXPECT().SubmitReimbursement(currentUser.ID, 150.75, "Team Lunch", gomock.Any(), gomock.Any()).
-					Return(&domain.Reimbursement{UserID: currentUser.ID, Amount: 150.75}, nil).Times(1)
+				mockService.EXPECT().SubmitReimbursement(currentUser.ID, categoryID, expenseDate, decimal.NewFromFloat(150.75), "Team Lunch", gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_, _ uuid.UUID, _ time.Time, _ decimal.Decimal, _ string, receipts []service.ReceiptUpload, _, _ string) (*domain.Reimbursement, error) {
+						assert.Len(t, receipts, 2)
+						assert.Equal(t, "lunch.pdf", receipts[0].FileName)
+						return &domain.Reimbursement{
+							UserID: currentUser.ID, CategoryID: &categoryID, Category: &domain.ReimbursementCategory{Name: "Meals"},
+							ExpenseDate: expenseDate, Amount: decimal.NewFromFloat(150.75),
+							Receipts: []domain.ReimbursementReceipt{{FileName: "lunch.pdf", ContentType: "application/pdf", Size: 11}},
+						}, nil
+					}).Times(1)
 			},
 			expectedStatus:       http.StatusOK,
-			expectedBodyContains: "Reimbursement submitted successfully",
+			expectedBodyContains: `"category":"Meals","expense_date":"2025-08-18"`,
 		},
 		{
-			name:        "Error - Invalid JSON",
-			requestBody: `{"amount": 100,,}`,
-			setupMiddleware: func(r *gin.Engine, h *ReimbursementHandler) {
-				r.POST("/reimbursements", h.SubmitReimbursement)
+			name:            "Success - JSON Without Receipts",
+			json:            fmt.Sprintf(`{"category_id": %q, "expense_date": "2025-08-18", "amount": 150.75, "description": "Team Lunch"}`, categoryID),
+			setupMiddleware: withCurrentUser,
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().SubmitReimbursement(currentUser.ID, categoryID, expenseDate, decimal.NewFromFloat(150.75), "Team Lunch", gomock.Len(0), gomock.Any(), gomock.Any()).
+					Return(&domain.Reimbursement{
+						UserID: currentUser.ID, CategoryID: &categoryID, Category: &domain.ReimbursementCategory{Name: "Meals"},
+						ExpenseDate: expenseDate, Amount: decimal.NewFromFloat(150.75),
+					}, nil).Times(1)
 			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"category":"Meals","expense_date":"2025-08-18"`,
+		},
+		{
+			name:                 "Error - JSON Amount is Zero",
+			json:                 fmt.Sprintf(`{"category_id": %q, "expense_date": "2025-08-18", "amount": "0"}`, categoryID),
+			setupMiddleware:      withoutCurrentUser,
+			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "amount must be greater than 0",
+		},
+		{
+			name:                 "Error - JSON Missing Category",
+			json:                 `{"expense_date": "2025-08-18", "amount": 100}`,
+			setupMiddleware:      withoutCurrentUser,
 			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
 			expectedStatus:       http.StatusBadRequest,
 			expectedBodyContains: "Invalid request payload",
 		},
 		{
-			name: "Error - Amount is Zero",
-			requestBody: SubmitReimbursementRequest{
-				Amount: 0,
-			},
-			setupMiddleware: func(r *gin.Engine, h *ReimbursementHandler) {
-				r.POST("/reimbursements", h.SubmitReimbursement)
-			},
+			name:                 "Error - Amount is Zero",
+			fields:               claim("0"),
+			receipts:             []string{"receipt.pdf"},
+			setupMiddleware:      withoutCurrentUser,
+			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "amount must be greater than 0",
+		},
+		{
+			name:                 "Error - Amount is Not a Number",
+			fields:               claim("a lot"),
+			receipts:             []string{"receipt.pdf"},
+			setupMiddleware:      withoutCurrentUser,
+			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "amount must be greater than 0",
+		},
+		{
+			name:                 "Error - Missing Expense Date",
+			fields:               map[string]string{"category_id": categoryID.String(), "amount": "100"},
+			receipts:             []string{"receipt.pdf"},
+			setupMiddleware:      withoutCurrentUser,
 			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
 			expectedStatus:       http.StatusBadRequest,
 			expectedBodyContains: "Invalid request payload",
 		},
 		{
-			name: "Error - User Not Authenticated",
-			requestBody: SubmitReimbursementRequest{
-				Amount: 100,
-			},
-			setupMiddleware: func(r *gin.Engine, h *ReimbursementHandler) {
-				r.POST("/reimbursements", h.SubmitReimbursement)
-			},
+			name:                 "Error - Invalid Category ID",
+			fields:               map[string]string{"category_id": "not-a-uuid", "expense_date": "2025-08-18", "amount": "100"},
+			receipts:             []string{"receipt.pdf"},
+			setupMiddleware:      withoutCurrentUser,
+			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid reimbursement category ID format",
+		},
+		{
+			name:                 "Error - Invalid Expense Date",
+			fields:               map[string]string{"category_id": categoryID.String(), "expense_date": "18-08-2025", "amount": "100"},
+			receipts:             []string{"receipt.pdf"},
+			setupMiddleware:      withoutCurrentUser,
+			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid date format",
+		},
+		{
+			name:                 "Error - User Not Authenticated",
+			fields:               claim("100"),
+			receipts:             []string{"receipt.pdf"},
+			setupMiddleware:      withoutCurrentUser,
 			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
 			expectedStatus:       http.StatusUnauthorized,
 			expectedBodyContains: "User not authenticated",
 		},
 		{
-			name: "Error - Service Failure",
-			requestBody: SubmitReimbursementRequest{
-				Amount: 50.0,
+			name:            "Error - Invalid Receipt",
+			fields:          claim("100"),
+			receipts:        []string{"receipt.pdf"},
+			setupMiddleware: withCurrentUser,
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().SubmitReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Len(1), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: receipt.pdf is not a JPEG, PNG or WebP image or a PDF", service.ErrInvalidReceipt)).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid receipt",
+		},
+		{
+			name:            "Error - Limit Exceeded",
+			fields:          claim("600000"),
+			receipts:        []string{"receipt.pdf"},
+			setupMiddleware: withCurrentUser,
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().SubmitReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: 600000 is above the Meals limit of 500000 per claim", service.ErrReimbursementLimitExceeded)).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "above the Meals limit of 500000 per claim",
+		},
+		{
+			name:            "Error - Payroll Period Locked",
+			fields:          claim("50"),
+			receipts:        []string{"receipt.pdf"},
+			setupMiddleware: withCurrentUser,
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().SubmitReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: 2025-08-18 falls in the payroll period 2025-08-01 to 2025-08-31, which has already been processed", service.ErrPeriodLocked)).Times(1)
 			},
-			setupMiddleware: func(r *gin.Engine, h *ReimbursementHandler) {
-				r.POST("/reimbursements", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, h.SubmitReimbursement)
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Payroll period is locked",
+		},
+		{
+			name:            "Error - Category Not Found",
+			fields:          claim("50"),
+			receipts:        []string{"receipt.pdf"},
+			setupMiddleware: withCurrentUser,
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().SubmitReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrReimbursementCategoryNotFound).Times(1)
 			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Reimbursement category not found",
+		},
+		{
+			name:            "Error - Service Failure",
+			fields:          claim("50"),
+			receipts:        []string{"receipt.pdf"},
+			setupMiddleware: withCurrentUser,
 			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
-				mockService.EXPECT().SubmitReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				mockService.EXPECT().SubmitReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("service layer error")).Times(1)
 			},
 			expectedStatus:       http.StatusInternalServerError,
@@ -109,6 +227,290 @@ func TestReimbursementHandler_SubmitReimbursement(t *testing.T) {
 
 			tc.mockService(mockService)
 
+			body := bytes.NewBufferString(tc.json)
+			contentType := "application/json"
+			if tc.fields != nil {
+				body = &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				for field, value := range tc.fields {
+					_ = writer.WriteField(field, value)
+				}
+				for _, fileName := range tc.receipts {
+					part, _ := writer.CreateFormFile("receipts", fileName)
+					_, _ = part.Write([]byte("%PDF-1.4\n%%EOF"))
+				}
+				_ = writer.Close()
+				contentType = writer.FormDataContentType()
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/reimbursements", body)
+			req.Header.Set("Content-Type", contentType)
+
+			router := gin.Default()
+			tc.setupMiddleware(router, handler)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestReimbursementHandler_GetPendingReimbursements(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	employee := domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "jdoe"}
+
+	testCases := []struct {
+		name                 string
+		mockService          func(mockService *mockSvc.MockReimbursementServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name: "Success",
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().GetPendingReimbursements().Return([]domain.Reimbursement{
+					{UserID: employee.ID, User: employee, Amount: decimal.NewFromInt(200000), Status: domain.ReimbursementStatusPending},
+				}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"username":"jdoe"`,
+		},
+		{
+			name: "Error - Service Fails",
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().GetPendingReimbursements().Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve reimbursements",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockReimbursementService := mockSvc.NewMockReimbursementServiceInterface(ctrl)
+			handler := NewReimbursementHandler(mockReimbursementService)
+
+			tc.mockService(mockReimbursementService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/reimbursements/pending", nil)
+
+			router := gin.Default()
+			router.GET("/reimbursements/pending", handler.GetPendingReimbursements)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestReimbursementHandler_GetReimbursementHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "testuser"}
+	category := &domain.ReimbursementCategory{BaseModel: domain.BaseModel{ID: uuid.New()}, Name: "Travel"}
+
+	testCases := []struct {
+		name                 string
+		query                string
+		mockService          func(mockService *mockSvc.MockReimbursementServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:  "Success",
+			query: "?from=2025-08-01",
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().GetReimbursementHistory(currentUser.ID, gomock.Any()).
+					Return([]domain.Reimbursement{{UserID: currentUser.ID, CategoryID: &category.ID, Category: category, Amount: decimal.NewFromInt(150000), Status: domain.ReimbursementStatusPending}}, int64(1), nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"category":"Travel"`,
+		},
+		{
+			name:                 "Error - Invalid Page Size",
+			query:                "?page_size=abc",
+			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid page_size",
+		},
+		{
+			name: "Error - Service Fails",
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().GetReimbursementHistory(gomock.Any(), gomock.Any()).Return(nil, int64(0), errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve reimbursements",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockReimbursementServiceInterface(ctrl)
+			handler := NewReimbursementHandler(mockService)
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/reimbursements"+tc.query, nil)
+
+			router := gin.Default()
+			router.GET("/reimbursements", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.GetReimbursementHistory)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestReimbursementHandler_ReviewReimbursement(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "admin", Role: "admin"}
+	reimbursementID := uuid.New()
+	partial := decimal.NewFromInt(150000)
+
+	testCases := []struct {
+		name                 string
+		reimbursementID      string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockReimbursementServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:            "Success - Approve In Part",
+			reimbursementID: reimbursementID.String(),
+			requestBody:     `{"status": "approved", "approved_amount": "150000"}`,
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().ReviewReimbursement(reimbursementID, true, gomock.Any(), "", currentUser, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ uuid.UUID, _ bool, approvedAmount *decimal.Decimal, _ string, _ *domain.User, _, _ string) (*domain.Reimbursement, error) {
+						assert.Equal(t, "150000", approvedAmount.String())
+						return &domain.Reimbursement{
+							BaseModel: domain.BaseModel{ID: reimbursementID}, Amount: decimal.NewFromInt(200000),
+							Status: domain.ReimbursementStatusApproved, ApprovedAmount: &partial, ReviewedBy: &currentUser.ID,
+						}, nil
+					}).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: fmt.Sprintf(`"status":"approved","approved_amount":"150000","reviewed_by":"%s"`, currentUser.ID),
+		},
+		{
+			name:            "Success - Reject",
+			reimbursementID: reimbursementID.String(),
+			requestBody:     ReviewReimbursementRequest{Status: domain.ReimbursementStatusRejected, Reason: "Not a business expense"},
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().ReviewReimbursement(reimbursementID, false, nil, "Not a business expense", currentUser, gomock.Any(), gomock.Any()).
+					Return(&domain.Reimbursement{
+						BaseModel: domain.BaseModel{ID: reimbursementID}, Status: domain.ReimbursementStatusRejected,
+						RejectionReason: "Not a business expense",
+					}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"rejection_reason":"Not a business expense"`,
+		},
+		{
+			name:                 "Error - Invalid Status",
+			reimbursementID:      reimbursementID.String(),
+			requestBody:          ReviewReimbursementRequest{Status: domain.ReimbursementStatusPending},
+			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:                 "Error - Invalid Reimbursement ID",
+			reimbursementID:      "not-a-uuid",
+			requestBody:          ReviewReimbursementRequest{Status: domain.ReimbursementStatusApproved},
+			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid reimbursement ID format",
+		},
+		{
+			name:            "Error - Approved Amount Above The Claim",
+			reimbursementID: reimbursementID.String(),
+			requestBody:     `{"status": "approved", "approved_amount": 250000}`,
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().ReviewReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrInvalidApprovedAmount).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: service.ErrInvalidApprovedAmount.Error(),
+		},
+		{
+			name:            "Error - Rejection Without Reason",
+			reimbursementID: reimbursementID.String(),
+			requestBody:     ReviewReimbursementRequest{Status: domain.ReimbursementStatusRejected},
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().ReviewReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrRejectionReasonRequired).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: service.ErrRejectionReasonRequired.Error(),
+		},
+		{
+			name:            "Error - Not Found",
+			reimbursementID: reimbursementID.String(),
+			requestBody:     ReviewReimbursementRequest{Status: domain.ReimbursementStatusApproved},
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().ReviewReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrReimbursementNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Reimbursement not found",
+		},
+		{
+			name:            "Error - Own Claim",
+			reimbursementID: reimbursementID.String(),
+			requestBody:     ReviewReimbursementRequest{Status: domain.ReimbursementStatusApproved},
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().ReviewReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrReimbursementReviewForbidden).Times(1)
+			},
+			expectedStatus:       http.StatusForbidden,
+			expectedBodyContains: "Not allowed to review this reimbursement",
+		},
+		{
+			name:            "Error - Already Reviewed",
+			reimbursementID: reimbursementID.String(),
+			requestBody:     ReviewReimbursementRequest{Status: domain.ReimbursementStatusApproved},
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().ReviewReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrReimbursementReviewed).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Reimbursement has already been reviewed",
+		},
+		{
+			name:            "Error - No Receipt",
+			reimbursementID: reimbursementID.String(),
+			requestBody:     ReviewReimbursementRequest{Status: domain.ReimbursementStatusApproved},
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().ReviewReimbursement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrReceiptRequired).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Reimbursement has no receipt",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockReimbursementService := mockSvc.NewMockReimbursementServiceInterface(ctrl)
+			handler := NewReimbursementHandler(mockReimbursementService)
+
+			tc.mockService(mockReimbursementService)
+
 			var reqBody []byte
 			if bodyStr, ok := tc.requestBody.(string); ok {
 				reqBody = []byte(bodyStr)
@@ -117,11 +519,99 @@ func TestReimbursementHandler_SubmitReimbursement(t *testing.T) {
 			}
 
 			w := httptest.NewRecorder()
-			req, _ := http.NewRequest(http.MethodPost, "/reimbursements", bytes.NewBuffer(reqBody))
+			req, _ := http.NewRequest(http.MethodPost, "/reimbursements/"+tc.reimbursementID+"/review", bytes.NewBuffer(reqBody))
 			req.Header.Set("Content-Type", "application/json")
 
 			router := gin.Default()
-			tc.setupMiddleware(router, handler)
+			router.POST("/reimbursements/:id/review", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.ReviewReimbursement)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestReimbursementHandler_CreateReimbursementCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "admin", Role: "admin"}
+	monthly := decimal.NewFromInt(1000000)
+
+	testCases := []struct {
+		name                 string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockReimbursementServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"name": "Travel", "monthly_limit": 1000000, "yearly_limit": null}`,
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().CreateReimbursementCategory(gomock.Any(), currentUser.ID, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(category *domain.ReimbursementCategory, _ uuid.UUID, _, _ string) (*domain.ReimbursementCategory, error) {
+						assert.Equal(t, "Travel", category.Name)
+						assert.Nil(t, category.PerClaimLimit)
+						assert.Equal(t, "1000000", category.MonthlyLimit.String())
+						assert.Nil(t, category.YearlyLimit)
+						return &domain.ReimbursementCategory{Name: "Travel", MonthlyLimit: &monthly}, nil
+					}).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"per_claim_limit":null,"monthly_limit":"1000000","yearly_limit":null`,
+		},
+		{
+			name:                 "Error - Missing Name",
+			requestBody:          ReimbursementCategoryRequest{MonthlyLimit: &monthly},
+			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:        "Error - Invalid Limit",
+			requestBody: `{"name": "Travel", "monthly_limit": 0}`,
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().CreateReimbursementCategory(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: limits must be greater than 0", service.ErrInvalidReimbursementCategory)).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "limits must be greater than 0",
+		},
+		{
+			name:        "Error - Duplicate Name",
+			requestBody: ReimbursementCategoryRequest{Name: "Travel"},
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().CreateReimbursementCategory(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrDuplicateReimbursementCategory).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Reimbursement category name is already taken",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockReimbursementService := mockSvc.NewMockReimbursementServiceInterface(ctrl)
+			handler := NewReimbursementHandler(mockReimbursementService)
+
+			tc.mockService(mockReimbursementService)
+
+			var reqBody []byte
+			if bodyStr, ok := tc.requestBody.(string); ok {
+				reqBody = []byte(bodyStr)
+			} else {
+				reqBody, _ = json.Marshal(tc.requestBody)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/reimbursement-categories", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.POST("/reimbursement-categories", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.CreateReimbursementCategory)
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tc.expectedStatus, w.Code)
@@ -129,3 +619,152 @@ func TestReimbursementHandler_SubmitReimbursement(t *testing.T) {
 		})
 	}
 }
+
+func TestReimbursementHandler_UpdateReimbursementCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "admin", Role: "admin"}
+	categoryID := uuid.New()
+
+	testCases := []struct {
+		name                 string
+		categoryID           string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockReimbursementServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success",
+			categoryID:  categoryID.String(),
+			requestBody: ReimbursementCategoryRequest{Name: "Meals"},
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().UpdateReimbursementCategory(categoryID, gomock.Any(), currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.ReimbursementCategory{BaseModel: domain.BaseModel{ID: categoryID}, Name: "Meals"}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: "Reimbursement category updated successfully",
+		},
+		{
+			name:                 "Error - Invalid Category ID",
+			categoryID:           "not-a-uuid",
+			requestBody:          ReimbursementCategoryRequest{Name: "Meals"},
+			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid reimbursement category ID format",
+		},
+		{
+			name:        "Error - Not Found",
+			categoryID:  categoryID.String(),
+			requestBody: ReimbursementCategoryRequest{Name: "Meals"},
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().UpdateReimbursementCategory(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrReimbursementCategoryNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Reimbursement category not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockReimbursementService := mockSvc.NewMockReimbursementServiceInterface(ctrl)
+			handler := NewReimbursementHandler(mockReimbursementService)
+
+			tc.mockService(mockReimbursementService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, "/reimbursement-categories/"+tc.categoryID, bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.PUT("/reimbursement-categories/:id", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.UpdateReimbursementCategory)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestReimbursementHandler_DownloadReceipt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: "testuser", Role: "employee"}
+	reimbursementID, receiptID := uuid.New(), uuid.New()
+
+	testCases := []struct {
+		name                 string
+		receiptID            string
+		mockService          func(mockService *mockSvc.MockReimbursementServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:      "Success",
+			receiptID: receiptID.String(),
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().GetReceipt(reimbursementID, receiptID, currentUser).
+					Return(&domain.ReimbursementReceipt{FileName: "lunch.pdf", ContentType: "application/pdf", Size: 13},
+						io.NopCloser(bytes.NewBufferString("%PDF-1.4 data")), nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: "%PDF-1.4 data",
+		},
+		{
+			name:                 "Error - Invalid Receipt ID",
+			receiptID:            "not-a-uuid",
+			mockService:          func(mockService *mockSvc.MockReimbursementServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid receipt ID format",
+		},
+		{
+			name:      "Error - Another Employee's Claim",
+			receiptID: receiptID.String(),
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().GetReceipt(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, nil, service.ErrReceiptForbidden).Times(1)
+			},
+			expectedStatus:       http.StatusForbidden,
+			expectedBodyContains: "Not allowed to download this receipt",
+		},
+		{
+			name:      "Error - Receipt Not Found",
+			receiptID: receiptID.String(),
+			mockService: func(mockService *mockSvc.MockReimbursementServiceInterface) {
+				mockService.EXPECT().GetReceipt(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, nil, service.ErrReceiptNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Receipt not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockReimbursementServiceInterface(ctrl)
+			handler := NewReimbursementHandler(mockService)
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/reimbursements/"+reimbursementID.String()+"/receipts/"+tc.receiptID, nil)
+
+			router := gin.Default()
+			router.GET("/reimbursements/:id/receipts/:receipt_id", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.DownloadReceipt)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename=lunch.pdf`, w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
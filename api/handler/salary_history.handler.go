@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payroll-system/api/response"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+)
+
+// SalaryHistoryHandler handles salary history related HTTP requests.
+type SalaryHistoryHandler struct {
+	service service.SalaryHistoryServiceInterface
+}
+
+// NewSalaryHistoryHandler creates a new SalaryHistoryHandler.
+func NewSalaryHistoryHandler(service service.SalaryHistoryServiceInterface) *SalaryHistoryHandler {
+	return &SalaryHistoryHandler{service: service}
+}
+
+// CreateSalaryChangeRequest represents the request body for creating a salary change.
+type CreateSalaryChangeRequest struct {
+	Salary        decimal.Decimal `json:"salary"`                            // accepts a JSON number or a decimal string
+	EffectiveFrom string          `json:"effective_from" binding:"required"` // YYYY-MM-DD
+	Reason        string          `json:"reason"`
+}
+
+// CreateSalaryChange handles recording a new salary of an employee.
+func (h *SalaryHistoryHandler) CreateSalaryChange(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid employee ID format", nil)
+		return
+	}
+
+	var req CreateSalaryChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	if !req.Salary.IsPositive() {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", "salary must be greater than 0")
+		return
+	}
+
+	effectiveFrom, err := time.Parse("2006-01-02", req.EffectiveFrom)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid effective_from format. Use YYYY-MM-DD.", nil)
+		return
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	history, err := h.service.CreateSalaryChange(userID, req.Salary, effectiveFrom, req.Reason, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmployeeNotFound):
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+		case errors.Is(err, service.ErrDuplicateSalaryChange):
+			response.Error(c, http.StatusConflict, "Salary change already exists for this date", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to create salary change", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Salary change created successfully", response.ToSalaryHistoryResponse(history))
+}
+
+// GetSalaryChanges handles listing the salary changes of an employee.
+func (h *SalaryHistoryHandler) GetSalaryChanges(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid employee ID format", nil)
+		return
+	}
+
+	histories, err := h.service.GetSalaryChanges(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrEmployeeNotFound) {
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve salary changes", err.Error())
+		return
+	}
+
+	response.Success(c, "Salary changes retrieved successfully", response.ToSalaryHistoryListResponse(histories))
+}
@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+	mockSvc "payroll-system/tests/mocks/service"
+)
+
+func TestSalaryHistoryHandler_CreateSalaryChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		Username:  "adminuser",
+	}
+	employeeID := uuid.New()
+	effectiveFrom, _ := time.Parse("2006-01-02", "2025-08-15")
+	salary := decimal.NewFromInt(6000000)
+	withUser := func(r *gin.Engine, h *SalaryHistoryHandler) {
+		r.POST("/employees/:id/salaries", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, h.CreateSalaryChange)
+	}
+
+	testCases := []struct {
+		name                 string
+		employeeID           string
+		requestBody          any
+		setupMiddleware      func(r *gin.Engine, h *SalaryHistoryHandler)
+		mockService          func(mockService *mockSvc.MockSalaryHistoryServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:            "Success - Create Salary Change",
+			employeeID:      employeeID.String(),
+			requestBody:     CreateSalaryChangeRequest{Salary: salary, EffectiveFrom: "2025-08-15", Reason: "Promotion"},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {
+				mockService.EXPECT().CreateSalaryChange(employeeID, salary, effectiveFrom, "Promotion", currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.SalaryHistory{UserID: employeeID, Salary: salary, EffectiveFrom: effectiveFrom, Reason: "Promotion"}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"effective_from":"2025-08-15","salary":"6000000"`,
+		},
+		{
+			name:                 "Error - Invalid Employee ID",
+			employeeID:           "not-a-uuid",
+			requestBody:          CreateSalaryChangeRequest{Salary: salary, EffectiveFrom: "2025-08-15"},
+			setupMiddleware:      withUser,
+			mockService:          func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid employee ID format",
+		},
+		{
+			name:                 "Error - Invalid JSON",
+			employeeID:           employeeID.String(),
+			requestBody:          `{"salary": 6000000,}`,
+			setupMiddleware:      withUser,
+			mockService:          func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:                 "Error - Salary Not Positive",
+			employeeID:           employeeID.String(),
+			requestBody:          CreateSalaryChangeRequest{Salary: decimal.Zero, EffectiveFrom: "2025-08-15"},
+			setupMiddleware:      withUser,
+			mockService:          func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "salary must be greater than 0",
+		},
+		{
+			name:                 "Error - Invalid Effective Date",
+			employeeID:           employeeID.String(),
+			requestBody:          CreateSalaryChangeRequest{Salary: salary, EffectiveFrom: "15-08-2025"},
+			setupMiddleware:      withUser,
+			mockService:          func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid effective_from format",
+		},
+		{
+			name:        "Error - User Not Authenticated",
+			employeeID:  employeeID.String(),
+			requestBody: CreateSalaryChangeRequest{Salary: salary, EffectiveFrom: "2025-08-15"},
+			setupMiddleware: func(r *gin.Engine, h *SalaryHistoryHandler) {
+				r.POST("/employees/:id/salaries", h.CreateSalaryChange)
+			},
+			mockService:          func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {},
+			expectedStatus:       http.StatusUnauthorized,
+			expectedBodyContains: "User not authenticated",
+		},
+		{
+			name:            "Error - Employee Not Found",
+			employeeID:      employeeID.String(),
+			requestBody:     CreateSalaryChangeRequest{Salary: salary, EffectiveFrom: "2025-08-15"},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {
+				mockService.EXPECT().CreateSalaryChange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrEmployeeNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Employee not found",
+		},
+		{
+			name:            "Error - Duplicate Effective Date",
+			employeeID:      employeeID.String(),
+			requestBody:     CreateSalaryChangeRequest{Salary: salary, EffectiveFrom: "2025-08-15"},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {
+				mockService.EXPECT().CreateSalaryChange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrDuplicateSalaryChange).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Salary change already exists for this date",
+		},
+		{
+			name:            "Error - Service Failure",
+			employeeID:      employeeID.String(),
+			requestBody:     CreateSalaryChangeRequest{Salary: salary, EffectiveFrom: "2025-08-15"},
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {
+				mockService.EXPECT().CreateSalaryChange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to create salary change",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockSalaryHistoryServiceInterface(ctrl)
+			handler := NewSalaryHistoryHandler(mockService)
+
+			tc.mockService(mockService)
+
+			var reqBody []byte
+			if bodyStr, ok := tc.requestBody.(string); ok {
+				reqBody = []byte(bodyStr)
+			} else {
+				reqBody, _ = json.Marshal(tc.requestBody)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/employees/%s/salaries", tc.employeeID), bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			tc.setupMiddleware(router, handler)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestSalaryHistoryHandler_GetSalaryChanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	employeeID := uuid.New()
+
+	testCases := []struct {
+		name                 string
+		employeeID           string
+		mockService          func(mockService *mockSvc.MockSalaryHistoryServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:       "Success - Listed Oldest First",
+			employeeID: employeeID.String(),
+			mockService: func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {
+				mockService.EXPECT().GetSalaryChanges(employeeID).Return([]domain.SalaryHistory{
+					{UserID: employeeID, Salary: decimal.NewFromInt(5000000), EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+					{UserID: employeeID, Salary: decimal.NewFromInt(6000000), EffectiveFrom: time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)},
+				}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"effective_from":"2025-01-01","salary":"5000000"`,
+		},
+		{
+			name:                 "Error - Invalid Employee ID",
+			employeeID:           "not-a-uuid",
+			mockService:          func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid employee ID format",
+		},
+		{
+			name:       "Error - Employee Not Found",
+			employeeID: employeeID.String(),
+			mockService: func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {
+				mockService.EXPECT().GetSalaryChanges(employeeID).Return(nil, service.ErrEmployeeNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Employee not found",
+		},
+		{
+			name:       "Error - Service Failure",
+			employeeID: employeeID.String(),
+			mockService: func(mockService *mockSvc.MockSalaryHistoryServiceInterface) {
+				mockService.EXPECT().GetSalaryChanges(employeeID).Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to retrieve salary changes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockSalaryHistoryServiceInterface(ctrl)
+			handler := NewSalaryHistoryHandler(mockService)
+
+			tc.mockService(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/employees/%s/salaries", tc.employeeID), nil)
+
+			router := gin.Default()
+			router.GET("/employees/:id/salaries", handler.GetSalaryChanges)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
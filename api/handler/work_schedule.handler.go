@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payroll-system/api/response"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+)
+
+// WorkScheduleHandler handles work schedule related HTTP requests.
+type WorkScheduleHandler struct {
+	service service.WorkScheduleServiceInterface
+}
+
+// NewWorkScheduleHandler creates a new WorkScheduleHandler.
+func NewWorkScheduleHandler(service service.WorkScheduleServiceInterface) *WorkScheduleHandler {
+	return &WorkScheduleHandler{service: service}
+}
+
+// WorkScheduleRequest represents the request body for creating or updating a work schedule.
+type WorkScheduleRequest struct {
+	Name         string          `json:"name" binding:"required"`
+	WorkingDays  []string        `json:"working_days" binding:"required"` // e.g. ["mon", "tue", "wed", "thu", "fri", "sat"]
+	ShiftStart   string          `json:"shift_start" binding:"required"`  // HH:MM
+	ShiftEnd     string          `json:"shift_end" binding:"required"`    // HH:MM, before shift_start for a shift that crosses midnight
+	DailyHours   decimal.Decimal `json:"daily_hours"`                     // accepts a JSON number or a decimal string
+	BreakMinutes int             `json:"break_minutes"`
+}
+
+// AssignWorkScheduleRequest represents the request body for assigning a work schedule to an employee.
+type AssignWorkScheduleRequest struct {
+	WorkScheduleID *string `json:"work_schedule_id"` // null for the default schedule
+}
+
+// CreateWorkSchedule handles creating a named work schedule.
+func (h *WorkScheduleHandler) CreateWorkSchedule(c *gin.Context) {
+	var req WorkScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	newSchedule := &domain.WorkSchedule{
+		Name:         req.Name,
+		WorkingDays:  strings.Join(req.WorkingDays, ","),
+		ShiftStart:   req.ShiftStart,
+		ShiftEnd:     req.ShiftEnd,
+		DailyHours:   req.DailyHours,
+		BreakMinutes: req.BreakMinutes,
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	schedule, err := h.service.CreateWorkSchedule(newSchedule, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidWorkSchedule):
+			response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		case errors.Is(err, service.ErrDuplicateWorkSchedule):
+			response.Error(c, http.StatusConflict, "Work schedule name is already taken", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to create work schedule", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Work schedule created successfully", response.ToWorkScheduleResponse(schedule))
+}
+
+// UpdateWorkSchedule handles changing the name, working days, shift and hours of a work schedule.
+func (h *WorkScheduleHandler) UpdateWorkSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid work schedule ID format", nil)
+		return
+	}
+
+	var req WorkScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	changes := &domain.WorkSchedule{
+		Name:         req.Name,
+		WorkingDays:  strings.Join(req.WorkingDays, ","),
+		ShiftStart:   req.ShiftStart,
+		ShiftEnd:     req.ShiftEnd,
+		DailyHours:   req.DailyHours,
+		BreakMinutes: req.BreakMinutes,
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	schedule, err := h.service.UpdateWorkSchedule(id, changes, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWorkScheduleNotFound):
+			response.Error(c, http.StatusNotFound, "Work schedule not found", nil)
+		case errors.Is(err, service.ErrInvalidWorkSchedule):
+			response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		case errors.Is(err, service.ErrDuplicateWorkSchedule):
+			response.Error(c, http.StatusConflict, "Work schedule name is already taken", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to update work schedule", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Work schedule updated successfully", response.ToWorkScheduleResponse(schedule))
+}
+
+// GetWorkSchedule handles retrieving a work schedule by ID.
+func (h *WorkScheduleHandler) GetWorkSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid work schedule ID format", nil)
+		return
+	}
+
+	schedule, err := h.service.GetWorkSchedule(id)
+	if err != nil {
+		if errors.Is(err, service.ErrWorkScheduleNotFound) {
+			response.Error(c, http.StatusNotFound, "Work schedule not found", nil)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve work schedule", err.Error())
+		return
+	}
+
+	response.Success(c, "Work schedule retrieved successfully", response.ToWorkScheduleResponse(schedule))
+}
+
+// GetWorkSchedules handles listing every work schedule.
+func (h *WorkScheduleHandler) GetWorkSchedules(c *gin.Context) {
+	schedules, err := h.service.GetWorkSchedules()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve work schedules", err.Error())
+		return
+	}
+
+	response.Success(c, "Work schedules retrieved successfully", response.ToWorkScheduleListResponse(schedules))
+}
+
+// AssignWorkSchedule handles assigning a work schedule to an employee. A null work_schedule_id
+// puts the employee back on the default schedule.
+func (h *WorkScheduleHandler) AssignWorkSchedule(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid employee ID format", nil)
+		return
+	}
+
+	var req AssignWorkScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+
+	var scheduleID *uuid.UUID
+	if req.WorkScheduleID != nil {
+		id, err := uuid.Parse(*req.WorkScheduleID)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid work schedule ID format", nil)
+			return
+		}
+		scheduleID = &id
+	}
+
+	// Get current user from context (set by AuthMiddleware)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	currentUser := user.(*domain.User)
+
+	// Get IP address from request
+	ipAddress := c.ClientIP()
+	requestID := c.GetHeader("X-Request-ID")
+
+	profile, err := h.service.AssignWorkSchedule(userID, scheduleID, currentUser.ID, ipAddress, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmployeeNotFound):
+			response.Error(c, http.StatusNotFound, "Employee not found", nil)
+		case errors.Is(err, service.ErrWorkScheduleNotFound):
+			response.Error(c, http.StatusNotFound, "Work schedule not found", nil)
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to assign work schedule", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, "Work schedule assigned successfully", response.ToEmployeeResponse(profile))
+}
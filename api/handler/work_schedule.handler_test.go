@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+	mockSvc "payroll-system/tests/mocks/service"
+)
+
+func TestWorkScheduleHandler_CreateWorkSchedule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		Username:  "adminuser",
+	}
+	withUser := func(r *gin.Engine, h *WorkScheduleHandler) {
+		r.POST("/work-schedules", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, h.CreateWorkSchedule)
+	}
+	nightShift := WorkScheduleRequest{
+		Name:         "Night Shift",
+		WorkingDays:  []string{"mon", "tue", "wed", "thu", "fri", "sat"},
+		ShiftStart:   "22:00",
+		ShiftEnd:     "06:00",
+		DailyHours:   decimal.NewFromInt(7),
+		BreakMinutes: 60,
+	}
+
+	testCases := []struct {
+		name                 string
+		requestBody          any
+		setupMiddleware      func(r *gin.Engine, h *WorkScheduleHandler)
+		mockService          func(mockService *mockSvc.MockWorkScheduleServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:            "Success - Create Work Schedule",
+			requestBody:     nightShift,
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockWorkScheduleServiceInterface) {
+				mockService.EXPECT().CreateWorkSchedule(gomock.Any(), currentUser.ID, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(s *domain.WorkSchedule, _ uuid.UUID, _, _ string) (*domain.WorkSchedule, error) {
+						assert.Equal(t, "mon,tue,wed,thu,fri,sat", s.WorkingDays)
+						return s, nil
+					}).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"working_days":["mon","tue","wed","thu","fri","sat"],"shift_start":"22:00","shift_end":"06:00","overnight":true,"daily_hours":"7","break_minutes":60`,
+		},
+		{
+			name:                 "Error - Missing Working Days",
+			requestBody:          WorkScheduleRequest{Name: "Night Shift", ShiftStart: "22:00", ShiftEnd: "06:00"},
+			setupMiddleware:      withUser,
+			mockService:          func(mockService *mockSvc.MockWorkScheduleServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid request payload",
+		},
+		{
+			name:        "Error - User Not Authenticated",
+			requestBody: nightShift,
+			setupMiddleware: func(r *gin.Engine, h *WorkScheduleHandler) {
+				r.POST("/work-schedules", h.CreateWorkSchedule)
+			},
+			mockService:          func(mockService *mockSvc.MockWorkScheduleServiceInterface) {},
+			expectedStatus:       http.StatusUnauthorized,
+			expectedBodyContains: "User not authenticated",
+		},
+		{
+			name:            "Error - Invalid Schedule",
+			requestBody:     nightShift,
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockWorkScheduleServiceInterface) {
+				mockService.EXPECT().CreateWorkSchedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: unknown weekday %q", service.ErrInvalidWorkSchedule, "thur")).Times(1)
+			},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: `invalid work schedule: unknown weekday \"thur\"`,
+		},
+		{
+			name:            "Error - Name Already Taken",
+			requestBody:     nightShift,
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockWorkScheduleServiceInterface) {
+				mockService.EXPECT().CreateWorkSchedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrDuplicateWorkSchedule).Times(1)
+			},
+			expectedStatus:       http.StatusConflict,
+			expectedBodyContains: "Work schedule name is already taken",
+		},
+		{
+			name:            "Error - Service Failure",
+			requestBody:     nightShift,
+			setupMiddleware: withUser,
+			mockService: func(mockService *mockSvc.MockWorkScheduleServiceInterface) {
+				mockService.EXPECT().CreateWorkSchedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBodyContains: "Failed to create work schedule",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockWorkScheduleServiceInterface(ctrl)
+			handler := NewWorkScheduleHandler(mockService)
+
+			tc.mockService(mockService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/work-schedules", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			tc.setupMiddleware(router, handler)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestWorkScheduleHandler_AssignWorkSchedule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	currentUser := &domain.User{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		Username:  "adminuser",
+	}
+	userID := uuid.New()
+	scheduleID := uuid.New()
+	scheduleIDString := scheduleID.String()
+	invalidID := "not-a-uuid"
+
+	testCases := []struct {
+		name                 string
+		employeeID           string
+		requestBody          any
+		mockService          func(mockService *mockSvc.MockWorkScheduleServiceInterface)
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{
+			name:        "Success - Assign Work Schedule",
+			employeeID:  userID.String(),
+			requestBody: AssignWorkScheduleRequest{WorkScheduleID: &scheduleIDString},
+			mockService: func(mockService *mockSvc.MockWorkScheduleServiceInterface) {
+				mockService.EXPECT().AssignWorkSchedule(userID, &scheduleID, currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.EmployeeProfile{UserID: userID, WorkScheduleID: &scheduleID}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: fmt.Sprintf(`"work_schedule_id":"%s"`, scheduleID),
+		},
+		{
+			name:        "Success - Back To Default Schedule",
+			employeeID:  userID.String(),
+			requestBody: AssignWorkScheduleRequest{},
+			mockService: func(mockService *mockSvc.MockWorkScheduleServiceInterface) {
+				mockService.EXPECT().AssignWorkSchedule(userID, nil, currentUser.ID, gomock.Any(), gomock.Any()).
+					Return(&domain.EmployeeProfile{UserID: userID}, nil).Times(1)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `"work_schedule_id":null`,
+		},
+		{
+			name:                 "Error - Invalid Employee ID",
+			employeeID:           invalidID,
+			requestBody:          AssignWorkScheduleRequest{},
+			mockService:          func(mockService *mockSvc.MockWorkScheduleServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid employee ID format",
+		},
+		{
+			name:                 "Error - Invalid Work Schedule ID",
+			employeeID:           userID.String(),
+			requestBody:          AssignWorkScheduleRequest{WorkScheduleID: &invalidID},
+			mockService:          func(mockService *mockSvc.MockWorkScheduleServiceInterface) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBodyContains: "Invalid work schedule ID format",
+		},
+		{
+			name:        "Error - Employee Not Found",
+			employeeID:  userID.String(),
+			requestBody: AssignWorkScheduleRequest{WorkScheduleID: &scheduleIDString},
+			mockService: func(mockService *mockSvc.MockWorkScheduleServiceInterface) {
+				mockService.EXPECT().AssignWorkSchedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrEmployeeNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Employee not found",
+		},
+		{
+			name:        "Error - Work Schedule Not Found",
+			employeeID:  userID.String(),
+			requestBody: AssignWorkScheduleRequest{WorkScheduleID: &scheduleIDString},
+			mockService: func(mockService *mockSvc.MockWorkScheduleServiceInterface) {
+				mockService.EXPECT().AssignWorkSchedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, service.ErrWorkScheduleNotFound).Times(1)
+			},
+			expectedStatus:       http.StatusNotFound,
+			expectedBodyContains: "Work schedule not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := mockSvc.NewMockWorkScheduleServiceInterface(ctrl)
+			handler := NewWorkScheduleHandler(mockService)
+
+			tc.mockService(mockService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, "/employees/"+tc.employeeID+"/work-schedule", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router := gin.Default()
+			router.PUT("/employees/:id/work-schedule", func(c *gin.Context) { c.Set("currentUser", currentUser); c.Next() }, handler.AssignWorkSchedule)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
@@ -74,6 +74,11 @@ func AuthMiddleware(userRepo repository.UserRepository) gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if !user.IsActive() {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User is deactivated"})
+			c.Abort()
+			return
+		}
 
 		// Set user in context
 		c.Set("currentUser", user)
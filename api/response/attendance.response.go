@@ -2,6 +2,7 @@ package response
 
 import (
 	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
 )
 
 // AttendanceResponse defines how attendance data is returned to the client.
@@ -9,7 +10,7 @@ type AttendanceResponse struct {
 	ID              string  `json:"id"`
 	Date            string  `json:"date"`           // formatted YYYY-MM-DD
 	CheckInTime     string  `json:"check_in_time"`  // formatted HH:MM:SS
-	CheckOutTime    string  `json:"check_out_time"` // formatted HH:MM:SS
+	CheckOutTime    *string `json:"check_out_time"` // formatted HH:MM:SS, null while clocked in
 	HoursWorked     float64 `json:"hours_worked"`
 	PayrollPeriodID *string `json:"payroll_period_id,omitempty"`
 }
@@ -22,20 +23,45 @@ func ToAttendanceResponse(a *domain.Attendance) AttendanceResponse {
 		payrollPeriodID = &id
 	}
 
-	hours := a.CheckOutTime.Sub(a.CheckInTime).Hours()
-
-	if hours > 8 {
-		hours = 8
-	} else if hours < 0 {
-		hours = 0
+	// Check-in and check-out times are loaded from the database without their date
+	var checkOutTime *string
+	if checkOut := a.CheckOutAt(); checkOut != nil {
+		s := checkOut.Format("2006-01-02 15:04:05")
+		checkOutTime = &s
 	}
 
+	// Hours worked under the schedule the attendance was submitted under, less its break
+	hours := a.WorkedHours(domain.WorkScheduleOrDefault(a.WorkSchedule))
+
 	return AttendanceResponse{
 		ID:              a.ID.String(),
 		Date:            a.Date.Format("2006-01-02"),
-		CheckInTime:     a.CheckInTime.Format("2006-01-02 15:04:05"),
-		CheckOutTime:    a.CheckOutTime.Format("2006-01-02 15:04:05"),
+		CheckInTime:     a.CheckInAt().Format("2006-01-02 15:04:05"),
+		CheckOutTime:    checkOutTime,
 		HoursWorked:     hours,
 		PayrollPeriodID: payrollPeriodID,
 	}
 }
+
+// ToAttendanceListResponse maps []domain.Attendance -> []AttendanceResponse
+func ToAttendanceListResponse(attendances []domain.Attendance) []AttendanceResponse {
+	res := make([]AttendanceResponse, 0, len(attendances))
+	for i := range attendances {
+		res = append(res, ToAttendanceResponse(&attendances[i]))
+	}
+	return res
+}
+
+// AttendancePolicyResponse defines how the attendance policy in force is returned to the client.
+type AttendancePolicyResponse struct {
+	OpenAttendance string `json:"open_attendance"` // unpaid or shift_end, how attendances left open are closed
+	PayMode        string `json:"pay_mode"`        // full_day, hourly or half_day, configured at startup
+}
+
+// ToAttendancePolicyResponse maps service.AttendancePolicy -> AttendancePolicyResponse
+func ToAttendancePolicyResponse(p service.AttendancePolicy) AttendancePolicyResponse {
+	return AttendancePolicyResponse{
+		OpenAttendance: string(p.OpenAttendance),
+		PayMode:        string(p.PayMode),
+	}
+}
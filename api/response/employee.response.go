@@ -0,0 +1,78 @@
+package response
+
+import (
+	"time"
+
+	"payroll-system/internal/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+// EmployeeResponse defines how an employee is returned to the client.
+type EmployeeResponse struct {
+	ID              string          `json:"id"` // ID of the employee's user
+	ProfileID       string          `json:"profile_id"`
+	Username        string          `json:"username"`
+	Salary          decimal.Decimal `json:"salary"`
+	PTKPStatus      string          `json:"ptkp_status"`
+	HireDate        *string         `json:"hire_date"`        // formatted YYYY-MM-DD
+	TerminationDate *string         `json:"termination_date"` // formatted YYYY-MM-DD
+	WorkScheduleID  *string         `json:"work_schedule_id"` // nil for the default schedule
+	ManagerID       *string         `json:"manager_id"`       // ID of the manager's user, nil when the employee reports to nobody
+	IsActive        bool            `json:"is_active"`
+	DeactivatedAt   *string         `json:"deactivated_at,omitempty"`
+}
+
+// ToEmployeeResponse maps domain.EmployeeProfile (with its User) -> EmployeeResponse
+func ToEmployeeResponse(p *domain.EmployeeProfile) EmployeeResponse {
+	var deactivatedAt *string
+	if p.User.DeactivatedAt != nil {
+		s := p.User.DeactivatedAt.Format(time.RFC3339)
+		deactivatedAt = &s
+	}
+
+	var workScheduleID *string
+	if p.WorkScheduleID != nil {
+		s := p.WorkScheduleID.String()
+		workScheduleID = &s
+	}
+
+	var managerID *string
+	if p.ManagerID != nil {
+		s := p.ManagerID.String()
+		managerID = &s
+	}
+
+	var hireDate, terminationDate *string
+	if p.HireDate != nil {
+		s := p.HireDate.Format("2006-01-02")
+		hireDate = &s
+	}
+	if p.TerminationDate != nil {
+		s := p.TerminationDate.Format("2006-01-02")
+		terminationDate = &s
+	}
+
+	return EmployeeResponse{
+		ID:              p.UserID.String(),
+		ProfileID:       p.ID.String(),
+		Username:        p.User.Username,
+		Salary:          p.Salary,
+		PTKPStatus:      p.PTKPStatus,
+		HireDate:        hireDate,
+		TerminationDate: terminationDate,
+		WorkScheduleID:  workScheduleID,
+		ManagerID:       managerID,
+		IsActive:        p.User.IsActive(),
+		DeactivatedAt:   deactivatedAt,
+	}
+}
+
+// ToEmployeeListResponse maps []domain.EmployeeProfile -> []EmployeeResponse
+func ToEmployeeListResponse(profiles []domain.EmployeeProfile) []EmployeeResponse {
+	res := make([]EmployeeResponse, 0, len(profiles))
+	for i := range profiles {
+		res = append(res, ToEmployeeResponse(&profiles[i]))
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package response
+
+import (
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+)
+
+// HolidayResponse defines how a holiday is returned to the client.
+type HolidayResponse struct {
+	ID        string `json:"id"`
+	Date      string `json:"date"` // formatted YYYY-MM-DD
+	Name      string `json:"name"`
+	Type      string `json:"type"` // national or collective_leave
+	CreatedBy string `json:"created_by"`
+}
+
+// ToHolidayResponse maps domain.Holiday -> HolidayResponse
+func ToHolidayResponse(h *domain.Holiday) HolidayResponse {
+	return HolidayResponse{
+		ID:        h.ID.String(),
+		Date:      h.Date.Format("2006-01-02"),
+		Name:      h.Name,
+		Type:      h.Type,
+		CreatedBy: h.CreatedBy.String(),
+	}
+}
+
+// ToHolidayListResponse maps []domain.Holiday -> []HolidayResponse
+func ToHolidayListResponse(holidays []domain.Holiday) []HolidayResponse {
+	res := make([]HolidayResponse, 0, len(holidays))
+	for i := range holidays {
+		res = append(res, ToHolidayResponse(&holidays[i]))
+	}
+	return res
+}
+
+// HolidayImportResponse defines how the outcome of an iCalendar import is returned to the client.
+type HolidayImportResponse struct {
+	CreatedCount int               `json:"created_count"`
+	SkippedCount int               `json:"skipped_count"`
+	Created      []HolidayResponse `json:"created"`
+	Skipped      []HolidayResponse `json:"skipped"` // Events on a date that was already on the calendar
+}
+
+// ToHolidayImportResponse maps service.HolidayImport -> HolidayImportResponse
+func ToHolidayImportResponse(i *service.HolidayImport) HolidayImportResponse {
+	skipped := make([]HolidayResponse, 0, len(i.Skipped))
+	for _, h := range i.Skipped {
+		skipped = append(skipped, HolidayResponse{
+			Date: h.Date.Format("2006-01-02"),
+			Name: h.Name,
+			Type: h.Type,
+		})
+	}
+
+	return HolidayImportResponse{
+		CreatedCount: len(i.Created),
+		SkippedCount: len(i.Skipped),
+		Created:      ToHolidayListResponse(i.Created),
+		Skipped:      skipped,
+	}
+}
@@ -0,0 +1,111 @@
+package response
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+)
+
+// LeaveRequestResponse defines how a leave request is returned to the client.
+type LeaveRequestResponse struct {
+	ID         string  `json:"id"`
+	UserID     string  `json:"user_id"`
+	Username   string  `json:"username,omitempty"` // Set on the requests listed for review
+	LeaveType  string  `json:"leave_type"`
+	StartDate  string  `json:"start_date"` // formatted YYYY-MM-DD
+	EndDate    string  `json:"end_date"`   // formatted YYYY-MM-DD
+	Days       int     `json:"days"`       // Working days taken as leave
+	Reason     string  `json:"reason"`
+	Status     string  `json:"status"` // pending, approved or rejected
+	ReviewedBy *string `json:"reviewed_by"`
+	ReviewedAt *string `json:"reviewed_at"`
+	ReviewNote string  `json:"review_note"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+// ToLeaveRequestResponse maps domain.LeaveRequest -> LeaveRequestResponse
+func ToLeaveRequestResponse(l *domain.LeaveRequest) LeaveRequestResponse {
+	var reviewedBy, reviewedAt *string
+	if l.ReviewedBy != nil {
+		s := l.ReviewedBy.String()
+		reviewedBy = &s
+	}
+	if l.ReviewedAt != nil {
+		s := l.ReviewedAt.Format(time.RFC3339)
+		reviewedAt = &s
+	}
+
+	return LeaveRequestResponse{
+		ID:         l.ID.String(),
+		UserID:     l.UserID.String(),
+		Username:   l.User.Username,
+		LeaveType:  l.LeaveType,
+		StartDate:  l.StartDate.Format("2006-01-02"),
+		EndDate:    l.EndDate.Format("2006-01-02"),
+		Days:       l.Days,
+		Reason:     l.Reason,
+		Status:     l.Status,
+		ReviewedBy: reviewedBy,
+		ReviewedAt: reviewedAt,
+		ReviewNote: l.ReviewNote,
+		CreatedAt:  l.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToLeaveRequestListResponse maps []domain.LeaveRequest -> []LeaveRequestResponse
+func ToLeaveRequestListResponse(leaves []domain.LeaveRequest) []LeaveRequestResponse {
+	res := make([]LeaveRequestResponse, 0, len(leaves))
+	for i := range leaves {
+		res = append(res, ToLeaveRequestResponse(&leaves[i]))
+	}
+	return res
+}
+
+// LeaveBalanceResponse defines how a leave balance is returned to the client, in working days.
+type LeaveBalanceResponse struct {
+	LeaveType   string          `json:"leave_type"`
+	Year        int             `json:"year"`
+	Entitlement decimal.Decimal `json:"entitlement"`
+	Accrued     decimal.Decimal `json:"accrued"`
+	Used        decimal.Decimal `json:"used"`
+	Pending     decimal.Decimal `json:"pending"`
+	Available   decimal.Decimal `json:"available"`
+}
+
+// ToLeaveBalanceListResponse maps []service.LeaveBalance -> []LeaveBalanceResponse
+func ToLeaveBalanceListResponse(balances []service.LeaveBalance) []LeaveBalanceResponse {
+	res := make([]LeaveBalanceResponse, 0, len(balances))
+	for _, b := range balances {
+		res = append(res, LeaveBalanceResponse{
+			LeaveType:   b.LeaveType,
+			Year:        b.Year,
+			Entitlement: b.Entitlement,
+			Accrued:     b.Accrued,
+			Used:        b.Used,
+			Pending:     b.Pending,
+			Available:   b.Available,
+		})
+	}
+	return res
+}
+
+// LeaveEntitlementResponse defines how a leave entitlement is returned to the client.
+type LeaveEntitlementResponse struct {
+	UserID    string          `json:"user_id"`
+	Year      int             `json:"year"`
+	LeaveType string          `json:"leave_type"`
+	Days      decimal.Decimal `json:"days"`
+}
+
+// ToLeaveEntitlementResponse maps domain.LeaveEntitlement -> LeaveEntitlementResponse
+func ToLeaveEntitlementResponse(e *domain.LeaveEntitlement) LeaveEntitlementResponse {
+	return LeaveEntitlementResponse{
+		UserID:    e.UserID.String(),
+		Year:      e.Year,
+		LeaveType: e.LeaveType,
+		Days:      e.Days,
+	}
+}
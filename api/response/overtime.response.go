@@ -1,29 +1,60 @@
 package response
 
 import (
+	"time"
+
 	"payroll-system/internal/domain"
 )
 
 // OvertimeResponse defines how overtime data is returned to the client.
 type OvertimeResponse struct {
 	ID              string  `json:"id"`
-	Date            string  `json:"date"` // formatted YYYY-MM-DD
+	UserID          string  `json:"user_id"`
+	Username        string  `json:"username,omitempty"` // Set on the overtime listed for review
+	Date            string  `json:"date"`               // formatted YYYY-MM-DD
 	Hours           float64 `json:"hours"`
+	Status          string  `json:"status"` // pending, approved or rejected
+	ReviewedBy      *string `json:"reviewed_by"`
+	ReviewedAt      *string `json:"reviewed_at"`
+	ReviewNote      string  `json:"review_note"`
 	PayrollPeriodID *string `json:"payroll_period_id,omitempty"`
 }
 
 // ToOvertimeResponse maps domain.Overtime -> OvertimeResponse
 func ToOvertimeResponse(o *domain.Overtime) OvertimeResponse {
-	var payrollPeriodID *string
+	var payrollPeriodID, reviewedBy, reviewedAt *string
 	if o.PayrollPeriodID != nil {
 		id := o.PayrollPeriodID.String()
 		payrollPeriodID = &id
 	}
+	if o.ReviewedBy != nil {
+		id := o.ReviewedBy.String()
+		reviewedBy = &id
+	}
+	if o.ReviewedAt != nil {
+		at := o.ReviewedAt.Format(time.RFC3339)
+		reviewedAt = &at
+	}
 
 	return OvertimeResponse{
 		ID:              o.ID.String(),
+		UserID:          o.UserID.String(),
+		Username:        o.User.Username,
 		Date:            o.Date.Format("2006-01-02"),
 		Hours:           o.Hours,
+		Status:          o.Status,
+		ReviewedBy:      reviewedBy,
+		ReviewedAt:      reviewedAt,
+		ReviewNote:      o.ReviewNote,
 		PayrollPeriodID: payrollPeriodID,
 	}
 }
+
+// ToOvertimeListResponse maps []domain.Overtime -> []OvertimeResponse
+func ToOvertimeListResponse(overtimes []domain.Overtime) []OvertimeResponse {
+	res := make([]OvertimeResponse, 0, len(overtimes))
+	for i := range overtimes {
+		res = append(res, ToOvertimeResponse(&overtimes[i]))
+	}
+	return res
+}
@@ -0,0 +1,46 @@
+package response
+
+import (
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/service"
+)
+
+// PayrollPreviewResponse defines how a payroll dry-run is returned to the client.
+type PayrollPreviewResponse struct {
+	PayrollPeriod              PayrollPeriodResponse `json:"payroll_period"`
+	EmployeeCount              int                   `json:"employee_count"`
+	TotalBaseSalary            decimal.Decimal       `json:"total_base_salary"`
+	TotalProratedSalary        decimal.Decimal       `json:"total_prorated_salary"`
+	TotalOvertimePay           decimal.Decimal       `json:"total_overtime_pay"`
+	TotalReimbursement         decimal.Decimal       `json:"total_reimbursement"`
+	TotalGrossEarnings         decimal.Decimal       `json:"total_gross_earnings"`
+	TotalDeductions            decimal.Decimal       `json:"total_deductions"`
+	TotalTakeHomePay           decimal.Decimal       `json:"total_take_home_pay"`
+	TotalEmployerContributions decimal.Decimal       `json:"total_employer_contributions"`
+	TotalEmployerCost          decimal.Decimal       `json:"total_employer_cost"` // Gross earnings plus employer contributions
+	Payslips                   []PayslipResponse     `json:"payslips"`
+}
+
+// ToPayrollPreviewResponse maps service.PayrollPreview -> PayrollPreviewResponse
+func ToPayrollPreviewResponse(p *service.PayrollPreview) PayrollPreviewResponse {
+	payslips := make([]PayslipResponse, 0, len(p.Payslips))
+	for i := range p.Payslips {
+		payslips = append(payslips, ToPayslipResponse(&p.Payslips[i]))
+	}
+
+	return PayrollPreviewResponse{
+		PayrollPeriod:              ToPayrollPeriodResponse(p.Period),
+		EmployeeCount:              len(p.Payslips),
+		TotalBaseSalary:            p.TotalBaseSalary,
+		TotalProratedSalary:        p.TotalProratedSalary,
+		TotalOvertimePay:           p.TotalOvertimePay,
+		TotalReimbursement:         p.TotalReimbursement,
+		TotalGrossEarnings:         p.TotalGrossEarnings,
+		TotalDeductions:            p.TotalDeductions,
+		TotalTakeHomePay:           p.TotalTakeHomePay,
+		TotalEmployerContributions: p.TotalEmployerContributions,
+		TotalEmployerCost:          p.TotalEmployerCost,
+		Payslips:                   payslips,
+	}
+}
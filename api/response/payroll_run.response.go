@@ -0,0 +1,63 @@
+package response
+
+import (
+	"time"
+
+	"payroll-system/internal/domain"
+)
+
+// PayrollRunResponse defines how a payroll run is returned to the client.
+type PayrollRunResponse struct {
+	ID                 string  `json:"id"`
+	PayrollPeriodID    string  `json:"payroll_period_id"`
+	Status             string  `json:"status"`
+	TotalEmployees     int     `json:"total_employees"`
+	ProcessedEmployees int     `json:"processed_employees"`
+	ProgressPercent    float64 `json:"progress_percent"`
+	CreatedAt          string  `json:"created_at"`
+	StartedAt          *string `json:"started_at,omitempty"`
+	FinishedAt         *string `json:"finished_at,omitempty"`
+	Error              string  `json:"error,omitempty"`
+}
+
+// ToPayrollRunResponse maps domain.PayrollRun -> PayrollRunResponse
+func ToPayrollRunResponse(r *domain.PayrollRun) PayrollRunResponse {
+	var startedAt, finishedAt *string
+	if r.StartedAt != nil {
+		s := r.StartedAt.Format(time.RFC3339)
+		startedAt = &s
+	}
+	if r.FinishedAt != nil {
+		s := r.FinishedAt.Format(time.RFC3339)
+		finishedAt = &s
+	}
+
+	var progress float64
+	if r.Status == domain.PayrollRunStatusSucceeded {
+		progress = 100
+	} else if r.TotalEmployees > 0 {
+		progress = float64(r.ProcessedEmployees) * 100 / float64(r.TotalEmployees)
+	}
+
+	return PayrollRunResponse{
+		ID:                 r.ID.String(),
+		PayrollPeriodID:    r.PayrollPeriodID.String(),
+		Status:             r.Status,
+		TotalEmployees:     r.TotalEmployees,
+		ProcessedEmployees: r.ProcessedEmployees,
+		ProgressPercent:    progress,
+		CreatedAt:          r.CreatedAt.Format(time.RFC3339),
+		StartedAt:          startedAt,
+		FinishedAt:         finishedAt,
+		Error:              r.Error,
+	}
+}
+
+// ToPayrollRunListResponse converts []domain.PayrollRun -> []PayrollRunResponse
+func ToPayrollRunListResponse(runs []domain.PayrollRun) []PayrollRunResponse {
+	res := make([]PayrollRunResponse, len(runs))
+	for i, r := range runs {
+		res[i] = ToPayrollRunResponse(&r)
+	}
+	return res
+}
@@ -2,20 +2,19 @@ package response
 
 import (
 	"payroll-system/internal/domain"
-	"time"
-)
+	"payroll-system/internal/service"
 
-const RegularWorkingHoursPerDay = 8
-const OvertimeMultiplier = 2.0
+	"github.com/shopspring/decimal"
+)
 
 // AttendancePayslipResponse defines how attendance data is returned to the client.
 type AttendancePayslipResponse struct {
 	ID              string  `json:"id"`
 	Date            string  `json:"date"`           // formatted YYYY-MM-DD
 	CheckInTime     string  `json:"check_in_time"`  // formatted HH:MM:SS
-	CheckOutTime    string  `json:"check_out_time"` // formatted HH:MM:SS
+	CheckOutTime    *string `json:"check_out_time"` // formatted HH:MM:SS, null while clocked in
 	HoursWorked     float64 `json:"hours_worked"`
-	BasePay         float64 `json:"base_pay"`
+	PaidHours       float64 `json:"paid_hours"` // Hours worked as paid by the payslip's attendance pay mode
 	PayrollPeriodID *string `json:"payroll_period_id,omitempty"`
 }
 
@@ -24,92 +23,217 @@ type OvertimePayslipResponse struct {
 	ID              string  `json:"id"`
 	Date            string  `json:"date"` // formatted YYYY-MM-DD
 	Hours           float64 `json:"hours"`
-	BasePay         float64 `json:"base_pay"`
 	PayrollPeriodID *string `json:"payroll_period_id,omitempty"`
 }
 
+// PayslipItemResponse defines how a payslip line is returned to the client.
+type PayslipItemResponse struct {
+	ComponentType string          `json:"component_type"`
+	Code          string          `json:"code"`
+	Name          string          `json:"name"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	Rate          decimal.Decimal `json:"rate"`
+	Amount        decimal.Decimal `json:"amount"`
+	Taxable       bool            `json:"taxable"`
+}
+
+// PayslipBPJSResponse defines how the BPJS contributions of a payslip are returned to the client.
+type PayslipBPJSResponse struct {
+	EmployeeContributions      []PayslipItemResponse `json:"employee_contributions"` // Deducted from take-home pay
+	EmployerContributions      []PayslipItemResponse `json:"employer_contributions"` // Paid by the employer on top of gross earnings
+	TotalEmployeeContributions decimal.Decimal       `json:"total_employee_contributions"`
+	TotalEmployerContributions decimal.Decimal       `json:"total_employer_contributions"`
+}
+
 // PayslipResponse defines the structure returned to the client.
 type PayslipResponse struct {
-	ID                 string      `json:"id"`
-	UserID             string      `json:"user_id"`
-	PayrollPeriodID    string      `json:"payroll_period_id"`
-	BaseSalary         float64     `json:"base_salary"`
-	ProratedSalary     float64     `json:"prorated_salary"`
-	OvertimePay        float64     `json:"overtime_pay"`
-	TotalReimbursement float64     `json:"total_reimbursement"`
-	TotalTakeHomePay   float64     `json:"total_take_home_pay"`
-	Overtimes          interface{} `json:"overtimes"`
-	Attendances        interface{} `json:"attendances"`
+	ID                         string                `json:"id"`
+	UserID                     string                `json:"user_id"`
+	PayrollPeriodID            string                `json:"payroll_period_id"`
+	AttendancePayMode          string                `json:"attendance_pay_mode"` // full_day, hourly or half_day
+	BaseSalary                 decimal.Decimal       `json:"base_salary"`
+	ProratedSalary             decimal.Decimal       `json:"prorated_salary"`
+	OvertimePay                decimal.Decimal       `json:"overtime_pay"`
+	TotalReimbursement         decimal.Decimal       `json:"total_reimbursement"`
+	GrossEarnings              decimal.Decimal       `json:"gross_earnings"`
+	TotalDeductions            decimal.Decimal       `json:"total_deductions"`
+	TotalEmployerContributions decimal.Decimal       `json:"total_employer_contributions"`
+	TotalTakeHomePay           decimal.Decimal       `json:"total_take_home_pay"`
+	TotalEmployerCost          decimal.Decimal       `json:"total_employer_cost"` // Gross earnings plus employer contributions
+	BPJS                       PayslipBPJSResponse   `json:"bpjs"`
+	Items                      []PayslipItemResponse `json:"items"`
+	Overtimes                  interface{}           `json:"overtimes"`
+	Attendances                interface{}           `json:"attendances"`
 }
 
-// ToPayslipResponse maps domain.Payslip -> PayslipResponse
+// ToPayslipResponse maps domain.Payslip -> PayslipResponse. The attendances and overtimes are the
+// records the payslip paid; what they earned is in the items, one line per salary in force during the
+// period, as calculated and rounded by the payroll run.
 func ToPayslipResponse(p *domain.Payslip) PayslipResponse {
-	// Calculate hourly pay
-	totalPossibleWorkingHours := 0.0
-	for d := p.PayrollPeriod.StartDate; !d.After(p.PayrollPeriod.EndDate); d = d.Add(24 * time.Hour) {
-		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
-			totalPossibleWorkingHours += RegularWorkingHoursPerDay
-		}
-	}
-
-	var hourlyRate float64
-
-	if totalPossibleWorkingHours > 0 {
-		hourlyRate = p.BaseSalary / totalPossibleWorkingHours
-	}
-
 	overtimes := make([]OvertimePayslipResponse, 0)
 
 	for _, o := range p.Overtimes {
 		id := o.PayrollPeriodID.String()
 		payrollPeriodID := &id
 
-		basePay := o.Hours * hourlyRate * OvertimeMultiplier
-
 		overtimes = append(overtimes, OvertimePayslipResponse{
 			ID:              o.ID.String(),
 			Date:            o.Date.Format("2006-01-02"),
 			Hours:           o.Hours,
-			BasePay:         basePay,
 			PayrollPeriodID: payrollPeriodID,
 		})
 	}
 
 	attendances := make([]AttendancePayslipResponse, 0)
+	schedule := p.WorkCalendar.Schedule()
+	dailyHours := schedule.DailyHours.InexactFloat64()
 
 	for _, a := range p.Attendances {
-		hours := a.CheckOutTime.Sub(a.CheckInTime).Hours()
-
-		if hours > 8 {
-			hours = 8
-		} else if hours < 0 {
-			hours = 0
-		}
+		// Paid the same way as the basic salary line of the payslip
+		hours := a.WorkedHours(schedule)
+		paidHours := p.AttendancePayMode.PaidHours(hours, dailyHours)
 
 		id := a.PayrollPeriodID.String()
 		payrollPeriodID := &id
 
+		var checkOutTime *string
+		if checkOut := a.CheckOutAt(); checkOut != nil {
+			s := checkOut.Format("2006-01-02 15:04:05")
+			checkOutTime = &s
+		}
+
 		attendances = append(attendances, AttendancePayslipResponse{
 			ID:              a.ID.String(),
 			Date:            a.Date.Format("2006-01-02"),
-			CheckInTime:     a.CheckInTime.Format("2006-01-02 15:04:05"),
-			CheckOutTime:    a.CheckOutTime.Format("2006-01-02 15:04:05"),
+			CheckInTime:     a.CheckInAt().Format("2006-01-02 15:04:05"),
+			CheckOutTime:    checkOutTime,
 			HoursWorked:     hours,
-			BasePay:         hourlyRate,
+			PaidHours:       paidHours,
 			PayrollPeriodID: payrollPeriodID,
 		})
 	}
 
+	items := make([]PayslipItemResponse, 0, len(p.Items))
+	bpjs := PayslipBPJSResponse{
+		EmployeeContributions:      make([]PayslipItemResponse, 0),
+		EmployerContributions:      make([]PayslipItemResponse, 0),
+		TotalEmployeeContributions: decimal.Zero,
+		TotalEmployerContributions: decimal.Zero,
+	}
+	for _, item := range p.Items {
+		itemResponse := PayslipItemResponse{
+			ComponentType: item.ComponentType,
+			Code:          item.Code,
+			Name:          item.Name,
+			Quantity:      item.Quantity,
+			Rate:          item.Rate,
+			Amount:        item.Amount,
+			Taxable:       item.Taxable,
+		}
+		items = append(items, itemResponse)
+
+		if !service.IsBPJSItemCode(item.Code) {
+			continue
+		}
+		if item.ComponentType == domain.PayslipComponentEmployerContribution {
+			bpjs.EmployerContributions = append(bpjs.EmployerContributions, itemResponse)
+			bpjs.TotalEmployerContributions = bpjs.TotalEmployerContributions.Add(item.Amount)
+		} else {
+			bpjs.EmployeeContributions = append(bpjs.EmployeeContributions, itemResponse)
+			bpjs.TotalEmployeeContributions = bpjs.TotalEmployeeContributions.Add(item.Amount)
+		}
+	}
+
 	return PayslipResponse{
-		ID:                 p.ID.String(),
-		UserID:             p.UserID.String(),
-		PayrollPeriodID:    p.PayrollPeriodID.String(),
-		BaseSalary:         p.BaseSalary,
-		ProratedSalary:     p.ProratedSalary,
-		OvertimePay:        p.OvertimePay,
-		TotalReimbursement: p.TotalReimbursement,
-		TotalTakeHomePay:   p.TotalTakeHomePay,
-		Overtimes:          overtimes,
-		Attendances:        attendances,
+		ID:                         p.ID.String(),
+		UserID:                     p.UserID.String(),
+		PayrollPeriodID:            p.PayrollPeriodID.String(),
+		AttendancePayMode:          string(p.AttendancePayMode),
+		BaseSalary:                 p.BaseSalary,
+		ProratedSalary:             p.ProratedSalary,
+		OvertimePay:                p.OvertimePay,
+		TotalReimbursement:         p.TotalReimbursement,
+		GrossEarnings:              p.GrossEarnings,
+		TotalDeductions:            p.TotalDeductions,
+		TotalEmployerContributions: p.TotalEmployerContributions,
+		TotalTakeHomePay:           p.TotalTakeHomePay,
+		TotalEmployerCost:          p.GrossEarnings.Add(p.TotalEmployerContributions),
+		BPJS:                       bpjs,
+		Items:                      items,
+		Overtimes:                  overtimes,
+		Attendances:                attendances,
+	}
+}
+
+// PayslipComponentTotalResponse defines how the period total of a pay component is returned to the client.
+type PayslipComponentTotalResponse struct {
+	ComponentType string          `json:"component_type"`
+	Code          string          `json:"code"`
+	Name          string          `json:"name"`
+	Amount        decimal.Decimal `json:"amount"`
+}
+
+// PayslipSummaryResponse defines how the payslip summary of a payroll period is returned to the client.
+type PayslipSummaryResponse struct {
+	PayrollPeriodID            string                          `json:"payroll_period_id"`
+	TotalGrossEarnings         decimal.Decimal                 `json:"total_gross_earnings"`
+	TotalDeductions            decimal.Decimal                 `json:"total_deductions"`
+	TotalEmployerContributions decimal.Decimal                 `json:"total_employer_contributions"`
+	TotalTakeHomePay           decimal.Decimal                 `json:"total_take_home_pay_all_employees"`
+	TotalEmployerCost          decimal.Decimal                 `json:"total_employer_cost"` // Gross earnings plus employer contributions
+	ComponentTotals            []PayslipComponentTotalResponse `json:"component_totals"`
+	Payslips                   []PayslipResponse               `json:"payslips"`
+}
+
+// ToPayslipSummaryResponse maps service.PayslipSummary -> PayslipSummaryResponse
+func ToPayslipSummaryResponse(periodID string, s *service.PayslipSummary) PayslipSummaryResponse {
+	components := make([]PayslipComponentTotalResponse, 0, len(s.Components))
+	for _, c := range s.Components {
+		components = append(components, PayslipComponentTotalResponse{
+			ComponentType: c.ComponentType,
+			Code:          c.Code,
+			Name:          c.Name,
+			Amount:        c.Amount,
+		})
+	}
+
+	payslips := make([]PayslipResponse, 0, len(s.Payslips))
+	for i := range s.Payslips {
+		payslips = append(payslips, ToPayslipResponse(&s.Payslips[i]))
+	}
+
+	return PayslipSummaryResponse{
+		PayrollPeriodID:            periodID,
+		TotalGrossEarnings:         s.TotalGrossEarnings,
+		TotalDeductions:            s.TotalDeductions,
+		TotalEmployerContributions: s.TotalEmployerContributions,
+		TotalTakeHomePay:           s.TotalTakeHomePay,
+		TotalEmployerCost:          s.TotalEmployerCost,
+		ComponentTotals:            components,
+		Payslips:                   payslips,
+	}
+}
+
+// PayslipHistoryResponse defines how a payslip is listed in an employee's payslip history.
+type PayslipHistoryResponse struct {
+	PayslipID        string                `json:"payslip_id"`
+	PayrollPeriod    PayrollPeriodResponse `json:"payroll_period"`
+	GrossEarnings    decimal.Decimal       `json:"gross_earnings"`
+	TotalDeductions  decimal.Decimal       `json:"total_deductions"`
+	TotalTakeHomePay decimal.Decimal       `json:"total_take_home_pay"`
+}
+
+// ToPayslipHistoryListResponse maps []domain.Payslip -> []PayslipHistoryResponse
+func ToPayslipHistoryListResponse(payslips []domain.Payslip) []PayslipHistoryResponse {
+	res := make([]PayslipHistoryResponse, 0, len(payslips))
+	for i := range payslips {
+		res = append(res, PayslipHistoryResponse{
+			PayslipID:        payslips[i].ID.String(),
+			PayrollPeriod:    ToPayrollPeriodResponse(&payslips[i].PayrollPeriod),
+			GrossEarnings:    payslips[i].GrossEarnings,
+			TotalDeductions:  payslips[i].TotalDeductions,
+			TotalTakeHomePay: payslips[i].TotalTakeHomePay,
+		})
 	}
+	return res
 }
@@ -0,0 +1,291 @@
+package response
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/pdf"
+)
+
+// PayslipCompany is the employer shown in the header of payslip PDFs.
+type PayslipCompany struct {
+	Name    string
+	Address string
+}
+
+// Layout of payslip PDFs, in points.
+const (
+	payslipMargin    = 40.0
+	payslipRight     = pdf.PageWidth - payslipMargin
+	payslipWidth     = payslipRight - payslipMargin
+	payslipRowHeight = 16.0
+	payslipFontSize  = 9.0
+	payslipBottom    = pdf.PageHeight - 60 // Rows end above the footer
+)
+
+// payslipColumn is a column of a table on a payslip PDF.
+type payslipColumn struct {
+	title string
+	width float64
+	right bool // Right-aligned, for numbers
+}
+
+// payslipItemColumns are the columns of the earnings, deductions and employer contributions tables.
+var payslipItemColumns = []payslipColumn{
+	{title: "Description", width: payslipWidth - 290},
+	{title: "Quantity", width: 70, right: true},
+	{title: "Rate", width: 110, right: true},
+	{title: "Amount", width: 110, right: true},
+}
+
+// payslipPDF draws a payslip on a PDF document from top to bottom, adding pages as needed.
+type payslipPDF struct {
+	doc *pdf.Document
+	y   float64 // Top of the next row
+}
+
+// WritePayslipPDF writes a payslip, as returned by ToPayslipResponse, to w as a printable A4 PDF: the
+// company header and payroll period, the earnings, deductions and take-home pay, the employer
+// contributions, and the hours of the attendance and overtime tables, whose pay is in the earnings.
+// Tables that do not fit on a page continue on the next one under their header row, and every page is
+// numbered in the footer.
+func WritePayslipPDF(w io.Writer, company PayslipCompany, employee string, period PayrollPeriodResponse, p PayslipResponse) error {
+	doc := pdf.New(period.Name + " - " + employee)
+	doc.AddPage()
+	r := &payslipPDF{doc: doc}
+
+	doc.Text(payslipMargin, 56, pdf.HelveticaBold, 16, company.Name)
+	doc.Text(payslipMargin, 70, pdf.Helvetica, payslipFontSize, company.Address)
+	doc.TextRight(payslipRight, 56, pdf.HelveticaBold, 16, "PAYSLIP")
+	doc.TextRight(payslipRight, 70, pdf.Helvetica, payslipFontSize, period.Name)
+	doc.Line(payslipMargin, 82, payslipRight, 82, 1)
+	r.y = 92
+
+	r.field(payslipMargin, "Employee", employee)
+	r.field(payslipMargin+payslipWidth/2, "Payslip ID", p.ID)
+	r.y += payslipRowHeight
+	r.field(payslipMargin, "Period", period.StartDate+" to "+period.EndDate)
+	r.field(payslipMargin+payslipWidth/2, "Attendance pay", payModeLabel(p.AttendancePayMode))
+	r.y += payslipRowHeight * 2
+
+	r.section("Earnings")
+	r.table(payslipItemColumns, payslipItemRows(p.Items, domain.PayslipComponentEarning),
+		[]string{"Gross earnings", "", "", formatAmount(p.GrossEarnings)}, "No earnings.")
+
+	r.section("Deductions")
+	r.table(payslipItemColumns, payslipItemRows(p.Items, domain.PayslipComponentDeduction),
+		[]string{"Total deductions", "", "", formatAmount(p.TotalDeductions)}, "No deductions.")
+
+	r.ensure(payslipRowHeight * 2)
+	doc.FillRect(payslipMargin, r.y, payslipWidth, payslipRowHeight*1.5, 0.85)
+	doc.Text(payslipMargin+4, r.y+16, pdf.HelveticaBold, 12, "Take-home pay")
+	doc.TextRight(payslipRight-4, r.y+16, pdf.HelveticaBold, 12, formatAmount(p.TotalTakeHomePay))
+	r.y += payslipRowHeight * 3
+
+	if contributions := payslipItemRows(p.Items, domain.PayslipComponentEmployerContribution); len(contributions) > 0 {
+		r.section("Employer contributions (paid by the employer, not deducted from take-home pay)")
+		r.table(payslipItemColumns, contributions,
+			[]string{"Total employer contributions", "", "", formatAmount(p.TotalEmployerContributions)}, "")
+	}
+
+	// The pay of the attendances and overtime is in the earnings lines, per salary in force
+	attendances, _ := p.Attendances.([]AttendancePayslipResponse)
+	var hoursWorked, paidHours float64
+	attendanceRows := make([][]string, 0, len(attendances))
+	for _, a := range attendances {
+		hoursWorked += a.HoursWorked
+		paidHours += a.PaidHours
+		attendanceRows = append(attendanceRows, []string{
+			a.Date, clockTime(a.Date, &a.CheckInTime), clockTime(a.Date, a.CheckOutTime),
+			formatHours(a.HoursWorked), formatHours(a.PaidHours),
+		})
+	}
+	r.section("Attendance")
+	r.table([]payslipColumn{
+		{title: "Date", width: 75},
+		{title: "Check in", width: 60},
+		{title: "Check out", width: payslipWidth - 280},
+		{title: "Hours worked", width: 75, right: true},
+		{title: "Paid hours", width: 70, right: true},
+	}, attendanceRows, []string{"Total", "", "", formatHours(hoursWorked), formatHours(paidHours)},
+		"No attendance in this period.")
+
+	overtimes, _ := p.Overtimes.([]OvertimePayslipResponse)
+	var overtimeHours float64
+	overtimeRows := make([][]string, 0, len(overtimes))
+	for _, o := range overtimes {
+		overtimeHours += o.Hours
+		overtimeRows = append(overtimeRows, []string{o.Date, formatHours(o.Hours)})
+	}
+	r.section("Overtime")
+	r.table([]payslipColumn{
+		{title: "Date", width: payslipWidth - 80},
+		{title: "Hours", width: 80, right: true},
+	}, overtimeRows, []string{"Total", formatHours(overtimeHours)},
+		"No overtime in this period.")
+
+	pages := doc.PageCount()
+	for page := 1; page <= pages; page++ {
+		doc.SetPage(page)
+		doc.Line(payslipMargin, pdf.PageHeight-48, payslipRight, pdf.PageHeight-48, 0.5)
+		doc.Text(payslipMargin, pdf.PageHeight-36, pdf.Helvetica, 8, company.Name+" - "+period.Name+" - "+employee)
+		doc.TextRight(payslipRight, pdf.PageHeight-36, pdf.Helvetica, 8, "Page "+strconv.Itoa(page)+" of "+strconv.Itoa(pages))
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// ensure starts a new page unless height fits on the current one, and reports whether it did.
+func (r *payslipPDF) ensure(height float64) bool {
+	if r.y+height <= payslipBottom {
+		return false
+	}
+	r.doc.AddPage()
+	r.y = payslipMargin
+	return true
+}
+
+// field draws a label with its value at x on the current row.
+func (r *payslipPDF) field(x float64, label, value string) {
+	r.doc.Text(x, r.y+11, pdf.Helvetica, payslipFontSize, label)
+	r.doc.Text(x+75, r.y+11, pdf.HelveticaBold, payslipFontSize, value)
+}
+
+// section draws the title of a section, on a new page if its table would start without a row.
+func (r *payslipPDF) section(title string) {
+	r.ensure(20 + payslipRowHeight*2)
+	r.doc.Text(payslipMargin, r.y+12, pdf.HelveticaBold, 11, title)
+	r.y += 20
+}
+
+// table draws a table with a header row, the rows, and a total row unless total is nil. The header row
+// is repeated on every page the table continues on; empty is shown when there are no rows.
+func (r *payslipPDF) table(columns []payslipColumn, rows [][]string, total []string, empty string) {
+	r.header(columns)
+	if len(rows) == 0 {
+		r.doc.Text(payslipMargin+4, r.y+11, pdf.Helvetica, payslipFontSize, empty)
+		r.y += payslipRowHeight
+	}
+	for _, row := range rows {
+		if r.ensure(payslipRowHeight) {
+			r.header(columns)
+		}
+		r.row(columns, row, pdf.Helvetica)
+	}
+	if total != nil {
+		if r.ensure(payslipRowHeight) {
+			r.header(columns)
+		}
+		r.doc.Line(payslipMargin, r.y, payslipRight, r.y, 0.5)
+		r.row(columns, total, pdf.HelveticaBold)
+	}
+	r.y += payslipRowHeight
+}
+
+// header draws the header row of a table.
+func (r *payslipPDF) header(columns []payslipColumn) {
+	r.doc.FillRect(payslipMargin, r.y, payslipWidth, payslipRowHeight, 0.9)
+	titles := make([]string, len(columns))
+	for i, column := range columns {
+		titles[i] = column.title
+	}
+	r.row(columns, titles, pdf.HelveticaBold)
+}
+
+// row draws a row of a table, cutting cells short that do not fit their column.
+func (r *payslipPDF) row(columns []payslipColumn, cells []string, font pdf.Font) {
+	x := payslipMargin
+	for i, column := range columns {
+		text := fitText(font, cells[i], column.width-8)
+		if column.right {
+			r.doc.TextRight(x+column.width-4, r.y+11, font, payslipFontSize, text)
+		} else {
+			r.doc.Text(x+4, r.y+11, font, payslipFontSize, text)
+		}
+		x += column.width
+	}
+	r.y += payslipRowHeight
+}
+
+// fitText shortens s with an ellipsis until it is at most width wide.
+func fitText(font pdf.Font, s string, width float64) string {
+	if pdf.TextWidth(font, payslipFontSize, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(font, payslipFontSize, string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// payslipItemRows returns the table rows of the payslip items of a component type: the name, the
+// quantity and rate when the item has them, and the amount.
+func payslipItemRows(items []PayslipItemResponse, componentType string) [][]string {
+	var rows [][]string
+	for _, item := range items {
+		if item.ComponentType != componentType {
+			continue
+		}
+		quantity, rate := "", ""
+		if !item.Quantity.IsZero() {
+			quantity = item.Quantity.String()
+		}
+		if !item.Rate.IsZero() {
+			rate = formatAmount(item.Rate)
+		}
+		rows = append(rows, []string{item.Name, quantity, rate, formatAmount(item.Amount)})
+	}
+	return rows
+}
+
+// payModeLabel returns an attendance pay mode as shown on payslips, e.g. "Full day".
+func payModeLabel(mode string) string {
+	label := strings.ReplaceAll(mode, "_", " ")
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// clockTime returns the HH:MM of a time formatted as "2006-01-02 15:04:05", marked as the next day
+// when it is not on date, or "-" when it is not set.
+func clockTime(date string, t *string) string {
+	if t == nil || len(*t) < 16 {
+		return "-"
+	}
+	clock := (*t)[11:16]
+	if !strings.HasPrefix(*t, date) {
+		clock += " (next day)"
+	}
+	return clock
+}
+
+// formatHours formats a number of hours with two decimals.
+func formatHours(hours float64) string {
+	return strconv.FormatFloat(hours, 'f', 2, 64)
+}
+
+// formatAmount formats an amount with two decimals and thousands separators, e.g. "4,750,000.00".
+func formatAmount(d decimal.Decimal) string {
+	s := d.Abs().StringFixed(2)
+	whole, cents := s[:len(s)-3], s[len(s)-3:]
+
+	var b strings.Builder
+	if d.IsNegative() {
+		b.WriteByte('-')
+	}
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	b.WriteString(cents)
+	return b.String()
+}
@@ -1,31 +1,128 @@
 package response
 
 import (
+	"time"
+
 	"payroll-system/internal/domain"
+
+	"github.com/shopspring/decimal"
 )
 
 // ReimbursementResponse defines the structure returned to the client.
 type ReimbursementResponse struct {
-	ID              string  `json:"id"`
-	UserID          string  `json:"user_id"`
-	Amount          float64 `json:"amount"`
-	Description     string  `json:"description"`
-	PayrollPeriodID *string `json:"payroll_period_id,omitempty"`
+	ID              string            `json:"id"`
+	UserID          string            `json:"user_id"`
+	Username        string            `json:"username,omitempty"` // Set on the reimbursements listed for review
+	CategoryID      *string           `json:"category_id"`
+	Category        string            `json:"category,omitempty"` // Name of the category
+	ExpenseDate     string            `json:"expense_date"`       // formatted YYYY-MM-DD
+	Amount          decimal.Decimal   `json:"amount"`
+	Description     string            `json:"description"`
+	Status          string            `json:"status"` // pending, approved or rejected
+	ApprovedAmount  *decimal.Decimal  `json:"approved_amount"`
+	ReviewedBy      *string           `json:"reviewed_by"`
+	ReviewedAt      *string           `json:"reviewed_at"`
+	RejectionReason string            `json:"rejection_reason"`
+	PayrollPeriodID *string           `json:"payroll_period_id,omitempty"`
+	Receipts        []ReceiptResponse `json:"receipts,omitempty"`
+}
+
+// ReceiptResponse defines how a receipt attached to a reimbursement is returned to the client.
+type ReceiptResponse struct {
+	ID          string `json:"id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"` // in bytes
+	SHA256      string `json:"sha256"`
 }
 
 // ToReimbursementResponse maps domain.Reimbursement -> ReimbursementResponse
 func ToReimbursementResponse(r *domain.Reimbursement) ReimbursementResponse {
-	var periodID *string
+	var categoryID, periodID, reviewedBy, reviewedAt *string
+	var category string
+	if r.CategoryID != nil {
+		id := r.CategoryID.String()
+		categoryID = &id
+	}
+	if r.Category != nil {
+		category = r.Category.Name
+	}
 	if r.PayrollPeriodID != nil {
 		id := r.PayrollPeriodID.String()
 		periodID = &id
 	}
+	if r.ReviewedBy != nil {
+		id := r.ReviewedBy.String()
+		reviewedBy = &id
+	}
+	if r.ReviewedAt != nil {
+		at := r.ReviewedAt.Format(time.RFC3339)
+		reviewedAt = &at
+	}
+	var receipts []ReceiptResponse
+	for _, receipt := range r.Receipts {
+		receipts = append(receipts, ReceiptResponse{
+			ID:          receipt.ID.String(),
+			FileName:    receipt.FileName,
+			ContentType: receipt.ContentType,
+			Size:        receipt.Size,
+			SHA256:      receipt.SHA256,
+		})
+	}
 
 	return ReimbursementResponse{
 		ID:              r.ID.String(),
 		UserID:          r.UserID.String(),
+		Username:        r.User.Username,
+		CategoryID:      categoryID,
+		Category:        category,
+		ExpenseDate:     r.ExpenseDate.Format("2006-01-02"),
 		Amount:          r.Amount,
 		Description:     r.Description,
+		Status:          r.Status,
+		ApprovedAmount:  r.ApprovedAmount,
+		ReviewedBy:      reviewedBy,
+		ReviewedAt:      reviewedAt,
+		RejectionReason: r.RejectionReason,
 		PayrollPeriodID: periodID,
+		Receipts:        receipts,
+	}
+}
+
+// ToReimbursementListResponse maps []domain.Reimbursement -> []ReimbursementResponse
+func ToReimbursementListResponse(reimbursements []domain.Reimbursement) []ReimbursementResponse {
+	res := make([]ReimbursementResponse, 0, len(reimbursements))
+	for i := range reimbursements {
+		res = append(res, ToReimbursementResponse(&reimbursements[i]))
+	}
+	return res
+}
+
+// ReimbursementCategoryResponse defines how a reimbursement category is returned to the client.
+type ReimbursementCategoryResponse struct {
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	PerClaimLimit *decimal.Decimal `json:"per_claim_limit"` // null for no limit
+	MonthlyLimit  *decimal.Decimal `json:"monthly_limit"`   // null for no limit
+	YearlyLimit   *decimal.Decimal `json:"yearly_limit"`    // null for no limit
+}
+
+// ToReimbursementCategoryResponse maps domain.ReimbursementCategory -> ReimbursementCategoryResponse
+func ToReimbursementCategoryResponse(c *domain.ReimbursementCategory) ReimbursementCategoryResponse {
+	return ReimbursementCategoryResponse{
+		ID:            c.ID.String(),
+		Name:          c.Name,
+		PerClaimLimit: c.PerClaimLimit,
+		MonthlyLimit:  c.MonthlyLimit,
+		YearlyLimit:   c.YearlyLimit,
+	}
+}
+
+// ToReimbursementCategoryListResponse maps []domain.ReimbursementCategory -> []ReimbursementCategoryResponse
+func ToReimbursementCategoryListResponse(categories []domain.ReimbursementCategory) []ReimbursementCategoryResponse {
+	res := make([]ReimbursementCategoryResponse, 0, len(categories))
+	for i := range categories {
+		res = append(res, ToReimbursementCategoryResponse(&categories[i]))
 	}
+	return res
 }
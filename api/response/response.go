@@ -35,3 +35,23 @@ func Error(c *gin.Context, code int, message string, data interface{}) {
 		Data:    data,
 	})
 }
+
+// PageResponse is the structure of one page of a paginated list
+type PageResponse struct {
+	Items      interface{} `json:"items"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"page_size"`
+	TotalItems int64       `json:"total_items"`
+	TotalPages int64       `json:"total_pages"`
+}
+
+// ToPageResponse wraps the items of a page of a list of total items
+func ToPageResponse(items interface{}, page, pageSize int, total int64) PageResponse {
+	return PageResponse{
+		Items:      items,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: (total + int64(pageSize) - 1) / int64(pageSize),
+	}
+}
@@ -0,0 +1,38 @@
+package response
+
+import (
+	"payroll-system/internal/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+// SalaryHistoryResponse defines how a salary change is returned to the client.
+type SalaryHistoryResponse struct {
+	ID            string          `json:"id"`
+	UserID        string          `json:"user_id"`
+	EffectiveFrom string          `json:"effective_from"` // formatted YYYY-MM-DD
+	Salary        decimal.Decimal `json:"salary"`
+	Reason        string          `json:"reason"`
+	CreatedBy     string          `json:"created_by"`
+}
+
+// ToSalaryHistoryResponse maps domain.SalaryHistory -> SalaryHistoryResponse
+func ToSalaryHistoryResponse(h *domain.SalaryHistory) SalaryHistoryResponse {
+	return SalaryHistoryResponse{
+		ID:            h.ID.String(),
+		UserID:        h.UserID.String(),
+		EffectiveFrom: h.EffectiveFrom.Format("2006-01-02"),
+		Salary:        h.Salary,
+		Reason:        h.Reason,
+		CreatedBy:     h.CreatedBy.String(),
+	}
+}
+
+// ToSalaryHistoryListResponse maps []domain.SalaryHistory -> []SalaryHistoryResponse
+func ToSalaryHistoryListResponse(histories []domain.SalaryHistory) []SalaryHistoryResponse {
+	res := make([]SalaryHistoryResponse, 0, len(histories))
+	for i := range histories {
+		res = append(res, ToSalaryHistoryResponse(&histories[i]))
+	}
+	return res
+}
@@ -0,0 +1,44 @@
+package response
+
+import (
+	"strings"
+
+	"payroll-system/internal/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+// WorkScheduleResponse defines how a work schedule is returned to the client.
+type WorkScheduleResponse struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	WorkingDays  []string        `json:"working_days"` // e.g. ["mon", "tue", "wed", "thu", "fri"]
+	ShiftStart   string          `json:"shift_start"`  // formatted HH:MM
+	ShiftEnd     string          `json:"shift_end"`    // formatted HH:MM
+	Overnight    bool            `json:"overnight"`    // The shift crosses midnight
+	DailyHours   decimal.Decimal `json:"daily_hours"`
+	BreakMinutes int             `json:"break_minutes"`
+}
+
+// ToWorkScheduleResponse maps domain.WorkSchedule -> WorkScheduleResponse
+func ToWorkScheduleResponse(s *domain.WorkSchedule) WorkScheduleResponse {
+	return WorkScheduleResponse{
+		ID:           s.ID.String(),
+		Name:         s.Name,
+		WorkingDays:  strings.Split(s.WorkingDays, ","),
+		ShiftStart:   s.ShiftStart,
+		ShiftEnd:     s.ShiftEnd,
+		Overnight:    s.IsOvernight(),
+		DailyHours:   s.DailyHours,
+		BreakMinutes: s.BreakMinutes,
+	}
+}
+
+// ToWorkScheduleListResponse maps []domain.WorkSchedule -> []WorkScheduleResponse
+func ToWorkScheduleListResponse(schedules []domain.WorkSchedule) []WorkScheduleResponse {
+	res := make([]WorkScheduleResponse, 0, len(schedules))
+	for i := range schedules {
+		res = append(res, ToWorkScheduleResponse(&schedules[i]))
+	}
+	return res
+}
@@ -4,11 +4,14 @@ import (
 	"log"
 	"os"
 	"payroll-system/api/middleware"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv" // For loading environment variables from .env file
 
 	"payroll-system/api/handler" // Import the handler package
+	"payroll-system/api/response"
 	"payroll-system/internal/service"
 
 	"payroll-system/db"
@@ -35,6 +38,9 @@ func main() {
 	// Initialize Gin router
 	router := gin.Default()
 
+	// --- Dependency Injection for Unit of Work ---
+	unitOfWork := repository.NewGormUnitOfWork(db)
+
 	// --- Dependency Injection for Audit Log ---
 	auditRepo := repository.NewAuditLogGormRepository(db) // GORM implementation of UserRepository
 
@@ -53,48 +59,142 @@ func main() {
 	payrollPeriodService := service.NewPayrollPeriodService(payrollPeriodRepo, auditRepo)
 	payrollPeriodHandler := handler.NewPayrollPeriodHandler(payrollPeriodService)
 
+	// --- Dependency Injection for Period Lock ---
+	// Attendance, overtime and reimbursements dated in a processed payroll period cannot be changed.
+	periodLock := service.NewPeriodLock(unitOfWork)
+
+	// --- Dependency Injection for Holiday Calendar ---
+	holidayRepo := repository.NewHolidayGormRepository(db)
+	holidayService := service.NewHolidayService(holidayRepo, unitOfWork)
+	holidayHandler := handler.NewHolidayHandler(holidayService)
+
+	// --- Dependency Injection for Work Schedule ---
+	workScheduleRepo := repository.NewWorkScheduleGormRepository(db)
+	workScheduleService := service.NewWorkScheduleService(workScheduleRepo, unitOfWork)
+	workScheduleHandler := handler.NewWorkScheduleHandler(workScheduleService)
+
 	// --- Dependency Injection for Attendance ---
 	attendanceRepo := repository.NewAttendanceGormRepository(db)
-	attendanceService := service.NewAttendanceService(attendanceRepo, auditRepo)
+	attendanceSettingRepo := repository.NewAttendanceSettingGormRepository(db)
+	attendancePolicy, err := service.ParseAttendancePolicy(os.Getenv("ATTENDANCE_OPEN_POLICY"), os.Getenv("ATTENDANCE_PAY_MODE"))
+	if err != nil {
+		log.Fatalf("Invalid attendance policy configuration: %v", err)
+	}
+	attendanceService := service.NewAttendanceService(attendanceRepo, holidayRepo, workScheduleRepo, attendanceSettingRepo, auditRepo, periodLock, attendancePolicy)
 	attendanceHandler := handler.NewAttendanceHandler(attendanceService)
+	// Attendances left open at the end of their day are closed hourly, following the attendance policy.
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for now := time.Now(); ; now = <-ticker.C {
+			if closed, err := attendanceService.CloseOpenAttendances(now, uuid.Nil, "", ""); err != nil {
+				log.Printf("Failed to close open attendances (%d closed): %v", closed, err)
+			} else if closed > 0 {
+				log.Printf("Closed %d attendance(s) left open", closed)
+			}
+		}
+	}()
 
 	// --- Dependency Injection for Overtime ---
 	overtimeRepo := repository.NewOvertimeGormRepository(db)
-	overtimeService := service.NewOvertimeService(overtimeRepo, auditRepo)
+	overtimeService := service.NewOvertimeService(overtimeRepo, periodLock, unitOfWork)
 	overtimeHandler := handler.NewOvertimeHandler(overtimeService)
 
 	// --- Dependency Injection for Reimbursement ---
 	reimbursementRepo := repository.NewReimbursementGormRepository(db)
-	reimbursementService := service.NewReimbursementService(reimbursementRepo, auditRepo)
+	reimbursementCategoryRepo := repository.NewReimbursementCategoryGormRepository(db)
+	receiptStorageDir := os.Getenv("RECEIPT_STORAGE_DIR")
+	if receiptStorageDir == "" {
+		receiptStorageDir = "storage/receipts" // Default directory
+	}
+	receiptStorage, err := repository.NewLocalBlobStorage(receiptStorageDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize receipt storage: %v", err)
+	}
+	reimbursementService := service.NewReimbursementService(reimbursementRepo, reimbursementCategoryRepo, receiptStorage, periodLock, unitOfWork)
 	reimbursementHandler := handler.NewReimbursementHandler(reimbursementService)
 
 	// --- Dependency Injection for Employee Profile ---
 	employeeProfileRepo := repository.NewEmployeeProfileGormRepository(db)
+	employeeService := service.NewEmployeeService(userRepo, employeeProfileRepo, unitOfWork)
+	employeeHandler := handler.NewEmployeeHandler(employeeService)
+
+	// --- Dependency Injection for Salary History ---
+	salaryHistoryRepo := repository.NewSalaryHistoryGormRepository(db)
+	salaryHistoryService := service.NewSalaryHistoryService(salaryHistoryRepo, employeeProfileRepo, unitOfWork)
+	salaryHistoryHandler := handler.NewSalaryHistoryHandler(salaryHistoryService)
+
+	// --- Dependency Injection for Leave ---
+	leaveRepo := repository.NewLeaveGormRepository(db)
+	leavePolicy, err := service.ParseLeavePolicy(os.Getenv)
+	if err != nil {
+		log.Fatalf("Invalid leave policy configuration: %v", err)
+	}
+	leaveService := service.NewLeaveService(leaveRepo, employeeProfileRepo, holidayRepo, workScheduleRepo, periodLock, unitOfWork, leavePolicy)
+	leaveHandler := handler.NewLeaveHandler(leaveService)
 
 	// --- Dependency Injection for Payslip ---
 	payslipRepo := repository.NewPayslipGormRepository(db)
 
 	// --- Dependency Injection for Payroll Service ---
+	roundingPolicy, err := service.ParseRoundingPolicy(
+		os.Getenv("PAYROLL_ROUNDING_MODE"),
+		os.Getenv("PAYROLL_ROUNDING_SCOPE"),
+		os.Getenv("PAYROLL_ROUNDING_PLACES"),
+	)
+	if err != nil {
+		log.Fatalf("Invalid payroll rounding configuration: %v", err)
+	}
+	bpjsRates, err := service.ParseBPJSRates(os.Getenv)
+	if err != nil {
+		log.Fatalf("Invalid BPJS configuration: %v", err)
+	}
 	payrollService := service.NewPayrollService(
 		payslipRepo,
 		payrollPeriodRepo,
 		employeeProfileRepo,
+		salaryHistoryRepo,
 		attendanceRepo,
 		overtimeRepo,
 		reimbursementRepo,
+		holidayRepo,
+		workScheduleRepo,
+		leaveRepo,
 		auditRepo,
-		db,
+		unitOfWork,
+		roundingPolicy,
+		attendancePolicy,
+		service.DefaultPayslipComponentRegistry(bpjsRates),
 	)
 	payrollHandler := handler.NewPayrollHandler(payrollService)
 
+	// --- Dependency Injection for Payroll Run ---
+	payrollRunRepo := repository.NewPayrollRunGormRepository(db)
+	// Runs left queued or running by a previous process can no longer finish, mark them as failed.
+	if stale, err := payrollRunRepo.FailStalePayrollRuns("interrupted by server restart"); err != nil {
+		log.Fatalf("Failed to recover stale payroll runs: %v", err)
+	} else if stale > 0 {
+		log.Printf("Marked %d interrupted payroll run(s) as failed", stale)
+	}
+	payrollRunService := service.NewPayrollRunService(payrollRunRepo, payrollPeriodRepo, payrollService, auditRepo)
+	payrollRunHandler := handler.NewPayrollRunHandler(payrollRunService)
+
 	// --- Dependency Injection for Payslip Service ---
-	payslipService := service.NewPayslipService(payslipRepo, payrollPeriodRepo, attendanceRepo, overtimeRepo)
-	payslipHandler := handler.NewPayslipHandler(payslipService)
+	payslipService := service.NewPayslipService(payslipRepo, payrollPeriodRepo, attendanceRepo, overtimeRepo, holidayRepo, workScheduleRepo)
+	companyName := os.Getenv("COMPANY_NAME")
+	if companyName == "" {
+		companyName = "Payroll System" // Default name in the header of payslip PDFs
+	}
+	payslipHandler := handler.NewPayslipHandler(payslipService, response.PayslipCompany{
+		Name:    companyName,
+		Address: os.Getenv("COMPANY_ADDRESS"),
+	})
 
 	// --- Register API Routes ---
 	authRoutes := router.Group("/auth")
 	{
-		authRoutes.POST("/register", authHandler.Register)
+		// Only an admin registers admins; the first admin is created by the seeder
+		authRoutes.POST("/register", middleware.AuthMiddleware(userRepo), middleware.AuthorizeMiddleware("admin"), authHandler.Register)
 		authRoutes.POST("/login", authHandler.Login)
 	}
 
@@ -114,15 +214,33 @@ func main() {
 		{
 			// Attendance Routes (Employee only)
 			employeeRoutes.POST("/attendances", attendanceHandler.SubmitAttendance)
+			employeeRoutes.GET("/attendances", attendanceHandler.GetAttendanceHistory)
+			employeeRoutes.POST("/attendances/clock-in", attendanceHandler.ClockIn)
+			employeeRoutes.POST("/attendances/clock-out", attendanceHandler.ClockOut)
 
-			// Overtime Routes (Employee only)
+			// Overtime Routes (Employee only); managers review the overtime of their direct reports
 			employeeRoutes.POST("/overtimes", overtimeHandler.SubmitOvertime)
+			employeeRoutes.GET("/overtimes", overtimeHandler.GetOvertimeHistory)
+			employeeRoutes.GET("/overtimes/pending", overtimeHandler.GetPendingOvertimes)
+			employeeRoutes.POST("/overtimes/:id/review", overtimeHandler.ReviewOvertime)
 
 			// Reimbursement Routes (Employee only)
 			employeeRoutes.POST("/reimbursements", reimbursementHandler.SubmitReimbursement)
+			employeeRoutes.GET("/reimbursements", reimbursementHandler.GetReimbursementHistory)
+			employeeRoutes.GET("/reimbursements/:id/receipts/:receipt_id", reimbursementHandler.DownloadReceipt)
+			employeeRoutes.GET("/reimbursement-categories", reimbursementHandler.GetReimbursementCategories)
+
+			// Leave Routes (Employee only); managers review the leave of their direct reports
+			employeeRoutes.POST("/leave-requests", leaveHandler.SubmitLeaveRequest)
+			employeeRoutes.GET("/leave-requests", leaveHandler.GetLeaveRequests)
+			employeeRoutes.GET("/leave-requests/pending", leaveHandler.GetPendingLeaveRequests)
+			employeeRoutes.POST("/leave-requests/:id/review", leaveHandler.ReviewLeaveRequest)
+			employeeRoutes.GET("/leave-balances", leaveHandler.GetLeaveBalances)
 
 			// Payslip Routes (Employee only)
 			employeeRoutes.POST("/payslips", payslipHandler.GetEmployeePayslip)
+			employeeRoutes.GET("/payslips", payslipHandler.GetPayslipHistory)
+			employeeRoutes.GET("/payslips/:period_id/pdf", payslipHandler.DownloadEmployeePayslip)
 
 			// Payroll Period Routes (Employee only)
 			employeeRoutes.GET("/payroll-periods", payrollPeriodHandler.GetAllPayrollPeriods)
@@ -143,10 +261,65 @@ func main() {
 			adminRoutes.GET("/payroll-periods/:id", payrollPeriodHandler.GetPayrollPeriodByID)
 
 			// Payroll Processing Routes (Admin only)
-			adminRoutes.POST("/run-payroll", payrollHandler.RunPayroll)
+			adminRoutes.POST("/run-payroll", payrollRunHandler.StartPayrollRun)
+			adminRoutes.GET("/payroll-runs", payrollRunHandler.GetAllPayrollRuns)
+			adminRoutes.GET("/payroll-runs/:id", payrollRunHandler.GetPayrollRunByID)
+			adminRoutes.POST("/payroll-preview", payrollHandler.PreviewPayroll)
+			adminRoutes.POST("/reverse-payroll", payrollHandler.ReversePayroll)
 
 			// Payslip Summary Routes (Admin only)
 			adminRoutes.POST("/payslip-summary", payslipHandler.GetPayslipSummary)
+			adminRoutes.GET("/payslips/:id/pdf", payslipHandler.DownloadPayslip)
+
+			// Employee Management Routes (Admin only)
+			adminRoutes.POST("/employees", employeeHandler.CreateEmployee)
+			adminRoutes.GET("/employees", employeeHandler.GetEmployees)
+			adminRoutes.GET("/employees/:id", employeeHandler.GetEmployee)
+			adminRoutes.PUT("/employees/:id", employeeHandler.UpdateEmployee)
+			adminRoutes.POST("/employees/:id/deactivate", employeeHandler.DeactivateEmployee)
+			adminRoutes.POST("/employees/:id/terminate", employeeHandler.TerminateEmployee)
+			adminRoutes.PUT("/employees/:id/manager", employeeHandler.AssignManager)
+
+			// Salary History Routes (Admin only)
+			adminRoutes.POST("/employees/:id/salaries", salaryHistoryHandler.CreateSalaryChange)
+			adminRoutes.GET("/employees/:id/salaries", salaryHistoryHandler.GetSalaryChanges)
+
+			// Holiday Calendar Routes (Admin only)
+			adminRoutes.POST("/holidays", holidayHandler.CreateHoliday)
+			adminRoutes.GET("/holidays", holidayHandler.GetHolidays)
+			adminRoutes.PUT("/holidays/:id", holidayHandler.UpdateHoliday)
+			adminRoutes.DELETE("/holidays/:id", holidayHandler.DeleteHoliday)
+			adminRoutes.POST("/holidays/import", holidayHandler.ImportHolidays)
+
+			// Leave Routes (Admin only)
+			adminRoutes.GET("/leave-requests/pending", leaveHandler.GetPendingLeaveRequests)
+			adminRoutes.POST("/leave-requests/:id/review", leaveHandler.ReviewLeaveRequest)
+			adminRoutes.GET("/employees/:id/leave-balances", leaveHandler.GetEmployeeLeaveBalances)
+			adminRoutes.PUT("/employees/:id/leave-entitlements", leaveHandler.SetLeaveEntitlement)
+
+			// Overtime Routes (Admin only)
+			adminRoutes.GET("/overtimes/pending", overtimeHandler.GetPendingOvertimes)
+			adminRoutes.POST("/overtimes/:id/review", overtimeHandler.ReviewOvertime)
+
+			// Reimbursement Routes (Admin only)
+			adminRoutes.GET("/reimbursements/pending", reimbursementHandler.GetPendingReimbursements)
+			adminRoutes.POST("/reimbursements/:id/review", reimbursementHandler.ReviewReimbursement)
+			adminRoutes.GET("/reimbursements/:id/receipts/:receipt_id", reimbursementHandler.DownloadReceipt)
+			adminRoutes.POST("/reimbursement-categories", reimbursementHandler.CreateReimbursementCategory)
+			adminRoutes.GET("/reimbursement-categories", reimbursementHandler.GetReimbursementCategories)
+			adminRoutes.PUT("/reimbursement-categories/:id", reimbursementHandler.UpdateReimbursementCategory)
+
+			// Attendance Routes (Admin only)
+			adminRoutes.POST("/attendances/close-open", attendanceHandler.CloseOpenAttendances)
+			adminRoutes.GET("/attendance-policy", attendanceHandler.GetAttendancePolicy)
+			adminRoutes.PUT("/attendance-policy", attendanceHandler.UpdateAttendancePolicy)
+
+			// Work Schedule Routes (Admin only)
+			adminRoutes.POST("/work-schedules", workScheduleHandler.CreateWorkSchedule)
+			adminRoutes.GET("/work-schedules", workScheduleHandler.GetWorkSchedules)
+			adminRoutes.GET("/work-schedules/:id", workScheduleHandler.GetWorkSchedule)
+			adminRoutes.PUT("/work-schedules/:id", workScheduleHandler.UpdateWorkSchedule)
+			adminRoutes.PUT("/employees/:id/work-schedule", workScheduleHandler.AssignWorkSchedule)
 		}
 	}
 
@@ -32,15 +32,31 @@ func InitDB() *gorm.DB {
 		&domain.EmployeeProfile{},
 		&domain.PayrollPeriod{},
 		&domain.Attendance{},
+		&domain.AttendanceSetting{},
 		&domain.Overtime{},
+		&domain.ReimbursementCategory{},
 		&domain.Reimbursement{},
+		&domain.ReimbursementReceipt{},
 		&domain.Payslip{},
+		&domain.PayslipItem{},
 		&domain.AuditLog{},
+		&domain.PayrollRun{},
+		&domain.SalaryHistory{},
+		&domain.Holiday{},
+		&domain.WorkSchedule{},
+		&domain.LeaveRequest{},
+		&domain.LeaveEntitlement{},
 	)
 	if err != nil {
 		log.Fatalf("Failed to auto-migrate database schema: %v", err)
 	}
 
+	// Reimbursements claimed before expense dates were introduced are dated the day they were claimed
+	err = db.Exec("UPDATE reimbursements SET expense_date = created_at::date WHERE expense_date IS NULL").Error
+	if err != nil {
+		log.Fatalf("Failed to backfill reimbursement expense dates: %v", err)
+	}
+
 	log.Println("Database connection established and schema migrated successfully.")
 	return db
 }
@@ -6,10 +6,11 @@ import (
 	"os"
 
 	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
 	"golang.org/x/crypto/bcrypt"
 
+	"payroll-system/db"
 	"payroll-system/internal/domain"
-	"payroll-system/internal/infrastructure/database"
 )
 
 func main() {
@@ -19,18 +20,18 @@ func main() {
 		log.Println("No .env file found, relying on environment variables.")
 	}
 
-	db := database.InitDB() // Initialize DB connection and run migrations
+	conn := db.InitDB() // Initialize DB connection and run migrations
 
 	// Clear existing data (optional, for fresh seeding)
 	log.Println("Clearing existing data...")
-	db.Exec("DELETE FROM audit_logs")
-	db.Exec("DELETE FROM payslips")
-	db.Exec("DELETE FROM reimbursements")
-	db.Exec("DELETE FROM overtimes")
-	db.Exec("DELETE FROM attendances")
-	db.Exec("DELETE FROM employee_profiles")
-	db.Exec("DELETE FROM payroll_periods")
-	db.Exec("DELETE FROM users")
+	conn.Exec("DELETE FROM audit_logs")
+	conn.Exec("DELETE FROM payslips")
+	conn.Exec("DELETE FROM reimbursements")
+	conn.Exec("DELETE FROM overtimes")
+	conn.Exec("DELETE FROM attendances")
+	conn.Exec("DELETE FROM employee_profiles")
+	conn.Exec("DELETE FROM payroll_periods")
+	conn.Exec("DELETE FROM users")
 	log.Println("Existing data cleared.")
 
 	// Seed Admin User
@@ -46,17 +47,21 @@ func main() {
 		Password: string(hashedAdminPassword),
 		Role:     "admin",
 	}
-	if err := db.Create(adminUser).Error; err != nil {
+	if err := conn.Create(adminUser).Error; err != nil {
 		log.Fatalf("Failed to seed admin user: %v", err)
 	}
 	log.Println("Admin user seeded.")
 
 	// Seed 100 Fake Employees
 	log.Println("Seeding 100 fake employees...")
+	ptkpStatuses := []string{
+		domain.PTKPStatusTK0, domain.PTKPStatusTK1, domain.PTKPStatusTK2, domain.PTKPStatusTK3,
+		domain.PTKPStatusK0, domain.PTKPStatusK1, domain.PTKPStatusK2, domain.PTKPStatusK3,
+	}
 	for i := 1; i <= 100; i++ {
 		username := fmt.Sprintf("employee%d", i)
 		password := fmt.Sprintf("password%d", i)
-		salary := float64(5000000 + i*10000) // Example salary range
+		salary := decimal.NewFromInt(int64(5000000 + i*10000)) // Example salary range
 
 		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 
@@ -65,15 +70,16 @@ func main() {
 			Password: string(hashedPassword),
 			Role:     "employee",
 		}
-		if err := db.Create(employeeUser).Error; err != nil {
+		if err := conn.Create(employeeUser).Error; err != nil {
 			log.Fatalf("Failed to seed employee %d: %v", i, err)
 		}
 
 		employeeProfile := &domain.EmployeeProfile{
-			UserID: employeeUser.ID,
-			Salary: salary,
+			UserID:     employeeUser.ID,
+			Salary:     salary,
+			PTKPStatus: ptkpStatuses[i%len(ptkpStatuses)],
 		}
-		if err := db.Create(employeeProfile).Error; err != nil {
+		if err := conn.Create(employeeProfile).Error; err != nil {
 			log.Fatalf("Failed to seed employee profile %d: %v", i, err)
 		}
 	}
@@ -6,13 +6,71 @@ import (
 	"github.com/google/uuid"
 )
 
+// AttendancePayMode defines how the hours worked on a day are turned into paid hours.
+type AttendancePayMode string
+
+const (
+	AttendancePayFullDay AttendancePayMode = "full_day" // a day is paid once the daily hours are worked, otherwise not at all
+	AttendancePayHourly  AttendancePayMode = "hourly"   // every hour worked is paid
+	AttendancePayHalfDay AttendancePayMode = "half_day" // a full day from the daily hours, half a day from half of them
+)
+
+// PaidHours returns the paid hours of a day on which worked hours were worked, with daily hours
+// for a full working day. An empty mode pays full days, as payslips did before the pay modes.
+func (m AttendancePayMode) PaidHours(worked, daily float64) float64 {
+	switch {
+	case worked >= daily:
+		return daily
+	case m == AttendancePayHourly:
+		return worked
+	case m == AttendancePayHalfDay && worked >= daily/2:
+		return daily / 2
+	default:
+		return 0
+	}
+}
+
 // Attendance records an employee's daily attendance.
 type Attendance struct {
 	BaseModel
 	UserID          uuid.UUID      `gorm:"type:uuid;not null" json:"user_id"`
 	Date            time.Time      `gorm:"type:date;uniqueIndex:idx_user_date;not null" json:"date"`
 	CheckInTime     time.Time      `gorm:"type:time;not null" json:"check_in_time"`
-	CheckOutTime    time.Time      `gorm:"type:time;not null" json:"check_out_time"`
+	CheckOutTime    *time.Time     `gorm:"type:time" json:"check_out_time"`              // Nil while the employee is clocked in
 	PayrollPeriodID *uuid.UUID     `gorm:"type:uuid" json:"payroll_period_id,omitempty"` // Nullable, set after payroll run
 	PayrollPeriod   *PayrollPeriod `gorm:"foreignKey:PayrollPeriodID" json:"payroll_period,omitempty"`
+	WorkSchedule    *WorkSchedule  `gorm:"-" json:"-"` // Schedule the attendance was submitted under, nil for the default schedule
+}
+
+// IsOpen reports whether the employee clocked in and has not clocked out yet.
+func (a Attendance) IsOpen() bool {
+	return a.CheckOutTime == nil
+}
+
+// CheckInAt returns the check-in on the attendance date. Check-in times are stored without their date.
+func (a Attendance) CheckInAt() time.Time {
+	return time.Date(a.Date.Year(), a.Date.Month(), a.Date.Day(),
+		a.CheckInTime.Hour(), a.CheckInTime.Minute(), a.CheckInTime.Second(), 0, a.Date.Location())
+}
+
+// CheckOutAt returns the check-out on the attendance date, or on the next day when it is before the
+// check-in, as on an overnight shift. It returns nil while the attendance is open.
+func (a Attendance) CheckOutAt() *time.Time {
+	if a.IsOpen() {
+		return nil
+	}
+	checkOut := time.Date(a.Date.Year(), a.Date.Month(), a.Date.Day(),
+		a.CheckOutTime.Hour(), a.CheckOutTime.Minute(), a.CheckOutTime.Second(), 0, a.Date.Location())
+	if checkOut.Before(a.CheckInAt()) {
+		checkOut = checkOut.AddDate(0, 0, 1)
+	}
+	return &checkOut
+}
+
+// WorkedHours returns the hours worked under schedule, or 0 while the attendance is open.
+func (a Attendance) WorkedHours(schedule WorkSchedule) float64 {
+	if a.IsOpen() {
+		return 0
+	}
+	return schedule.WorkedHours(a.CheckInTime, *a.CheckOutTime)
 }
@@ -0,0 +1,8 @@
+package domain
+
+// AttendanceSetting holds the attendance settings an admin changes at runtime. There is at most one
+// row; until an admin saves it, the settings configured at startup apply.
+type AttendanceSetting struct {
+	BaseModel
+	OpenAttendance string `gorm:"type:varchar(20);not null" json:"open_attendance"` // How attendances left open are closed: "unpaid" or "shift_end"
+}
@@ -1,13 +1,58 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PTKP (Penghasilan Tidak Kena Pajak) statuses: "TK" is single and "K" is married, followed by
+// the number of dependants (at most 3 count for tax purposes).
+const (
+	PTKPStatusTK0 = "TK/0"
+	PTKPStatusTK1 = "TK/1"
+	PTKPStatusTK2 = "TK/2"
+	PTKPStatusTK3 = "TK/3"
+	PTKPStatusK0  = "K/0"
+	PTKPStatusK1  = "K/1"
+	PTKPStatusK2  = "K/2"
+	PTKPStatusK3  = "K/3"
 )
 
+// IsValidPTKPStatus reports whether status is one of the PTKP statuses.
+func IsValidPTKPStatus(status string) bool {
+	switch status {
+	case PTKPStatusTK0, PTKPStatusTK1, PTKPStatusTK2, PTKPStatusTK3,
+		PTKPStatusK0, PTKPStatusK1, PTKPStatusK2, PTKPStatusK3:
+		return true
+	}
+	return false
+}
+
 // EmployeeProfile stores additional details for an employee.
 type EmployeeProfile struct {
 	BaseModel
-	UserID uuid.UUID `gorm:"type:uuid;uniqueIndex;not null" json:"user_id"`
-	User   User      `gorm:"foreignKey:UserID" json:"user"`
-	Salary float64   `gorm:"type:numeric;not null" json:"salary"`
+	UserID          uuid.UUID       `gorm:"type:uuid;uniqueIndex;not null" json:"user_id"`
+	User            User            `gorm:"foreignKey:UserID" json:"user"`
+	Salary          decimal.Decimal `gorm:"type:numeric;not null" json:"salary"`                        // Used for the dates before the first SalaryHistory change
+	PTKPStatus      string          `gorm:"type:varchar(5);not null;default:'TK/0'" json:"ptkp_status"` // Tax status used for PPh 21 withholding
+	HireDate        *time.Time      `gorm:"type:date" json:"hire_date"`                                 // First day of employment, nil when employed since before it was recorded
+	TerminationDate *time.Time      `gorm:"type:date" json:"termination_date"`                          // Last day of employment, nil while employed
+	WorkScheduleID  *uuid.UUID      `gorm:"type:uuid" json:"work_schedule_id"`                          // Assigned work schedule, nil for the default schedule
+	ManagerID       *uuid.UUID      `gorm:"type:uuid;index" json:"manager_id"`                          // User the employee reports to, who reviews their leave requests
+}
+
+// EmploymentWindow returns the part of the date range from startDate to endDate in which the
+// employee is employed, from the hire date through the termination date. ok is false when the
+// employee is not employed on any day of the range.
+func (p *EmployeeProfile) EmploymentWindow(startDate, endDate time.Time) (from, to time.Time, ok bool) {
+	from, to = startDate, endDate
+	if p.HireDate != nil && p.HireDate.After(from) {
+		from = *p.HireDate
+	}
+	if p.TerminationDate != nil && p.TerminationDate.Before(to) {
+		to = *p.TerminationDate
+	}
+	return from, to, !from.After(to)
 }
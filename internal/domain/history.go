@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HistoryFilter selects one page of the records an employee lists in their history.
+type HistoryFilter struct {
+	From            *time.Time // Records dated on or after, if set
+	To              *time.Time // Records dated on or before, if set
+	PayrollPeriodID *uuid.UUID // Records paid in the payroll period, if set
+	Page            int        // Counted from 1
+	PageSize        int
+}
+
+// Offset returns the number of records on the pages before the page of the filter.
+func (f HistoryFilter) Offset() int {
+	return (f.Page - 1) * f.PageSize
+}
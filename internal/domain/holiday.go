@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Holiday types: national holidays are set by the government, collective leave (cuti bersama)
+// days are company-wide days off taken from the employees' annual leave.
+const (
+	HolidayTypeNational        = "national"
+	HolidayTypeCollectiveLeave = "collective_leave"
+)
+
+// IsValidHolidayType reports whether holidayType is one of the holiday types.
+func IsValidHolidayType(holidayType string) bool {
+	return holidayType == HolidayTypeNational || holidayType == HolidayTypeCollectiveLeave
+}
+
+// Holiday is a day off on the holiday calendar. A date is on the calendar at most once.
+type Holiday struct {
+	BaseModel
+	Date time.Time `gorm:"type:date;not null;uniqueIndex:idx_holidays_date,where:deleted_at IS NULL" json:"date"`
+	Name string    `gorm:"type:varchar(255);not null" json:"name"`
+	Type string    `gorm:"type:varchar(20);not null;default:'national'" json:"type"`
+}
+
+// WorkCalendar tells the working days of a work schedule apart from its rest days and holidays.
+// The zero value has no holidays and follows the default work schedule: every Monday to Friday is
+// a working day of 8 hours.
+type WorkCalendar struct {
+	holidays map[string]Holiday
+	schedule *WorkSchedule
+}
+
+// NewWorkCalendar creates a work calendar with the given holidays.
+func NewWorkCalendar(holidays []Holiday) WorkCalendar {
+	calendar := WorkCalendar{holidays: make(map[string]Holiday, len(holidays))}
+	for _, h := range holidays {
+		calendar.holidays[h.Date.Format("2006-01-02")] = h
+	}
+	return calendar
+}
+
+// WithSchedule returns a copy of the calendar that follows schedule, or the default work
+// schedule when schedule is nil.
+func (c WorkCalendar) WithSchedule(schedule *WorkSchedule) WorkCalendar {
+	c.schedule = schedule
+	return c
+}
+
+// Schedule returns the work schedule the calendar follows.
+func (c WorkCalendar) Schedule() WorkSchedule {
+	return WorkScheduleOrDefault(c.schedule)
+}
+
+// Holiday returns the holiday on date, if there is one.
+func (c WorkCalendar) Holiday(date time.Time) (Holiday, bool) {
+	h, ok := c.holidays[date.Format("2006-01-02")]
+	return h, ok
+}
+
+// IsWorkingDay reports whether date is a working day of the schedule that is not a holiday.
+func (c WorkCalendar) IsWorkingDay(date time.Time) bool {
+	if !c.Schedule().WorksOn(date.Weekday()) {
+		return false
+	}
+	_, holiday := c.Holiday(date)
+	return !holiday
+}
+
+// WorkingDays returns the number of working days from one date through another.
+func (c WorkCalendar) WorkingDays(from, to time.Time) int {
+	days := 0
+	for d := from; !d.After(to); d = d.Add(24 * time.Hour) {
+		if c.IsWorkingDay(d) {
+			days++
+		}
+	}
+	return days
+}
+
+// WorkingHours returns the scheduled working hours from one date through another: the schedule's
+// daily hours for every working day.
+func (c WorkCalendar) WorkingHours(from, to time.Time) decimal.Decimal {
+	return c.Schedule().DailyHours.Mul(decimal.NewFromInt(int64(c.WorkingDays(from, to))))
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Leave types. Annual, sick and maternity leave are paid and taken from a yearly balance; unpaid
+// leave has no balance and is deducted from the salary.
+const (
+	LeaveTypeAnnual    = "annual"
+	LeaveTypeSick      = "sick"
+	LeaveTypeMaternity = "maternity"
+	LeaveTypeUnpaid    = "unpaid"
+)
+
+// Leave request statuses.
+const (
+	LeaveStatusPending  = "pending"
+	LeaveStatusApproved = "approved"
+	LeaveStatusRejected = "rejected"
+)
+
+// IsValidLeaveType reports whether leaveType is one of the leave types.
+func IsValidLeaveType(leaveType string) bool {
+	switch leaveType {
+	case LeaveTypeAnnual, LeaveTypeSick, LeaveTypeMaternity, LeaveTypeUnpaid:
+		return true
+	}
+	return false
+}
+
+// IsPaidLeaveType reports whether a day of leaveType leave is paid as a worked day.
+func IsPaidLeaveType(leaveType string) bool {
+	return leaveType != LeaveTypeUnpaid
+}
+
+// LeaveRequest is an employee's request for leave from one date through another. Only the working
+// days of the employee's schedule in the range are taken as leave.
+type LeaveRequest struct {
+	BaseModel
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	User       User       `gorm:"foreignKey:UserID" json:"user"`
+	LeaveType  string     `gorm:"type:varchar(20);not null" json:"leave_type"`
+	StartDate  time.Time  `gorm:"type:date;not null" json:"start_date"`
+	EndDate    time.Time  `gorm:"type:date;not null" json:"end_date"`
+	Days       int        `gorm:"not null" json:"days"` // Working days taken as leave
+	Reason     string     `gorm:"type:text" json:"reason"`
+	Status     string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	ReviewedBy *uuid.UUID `gorm:"type:uuid" json:"reviewed_by"` // Manager or admin who approved or rejected the request
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	ReviewNote string     `gorm:"type:text" json:"review_note"`
+}
+
+// LeaveEntitlement overrides the yearly entitlement of the leave policy for one employee, leave
+// type and year, e.g. for annual leave carried over from the year before.
+type LeaveEntitlement struct {
+	BaseModel
+	UserID    uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_leave_entitlements_user_year_type" json:"user_id"`
+	Year      int             `gorm:"not null;uniqueIndex:idx_leave_entitlements_user_year_type" json:"year"`
+	LeaveType string          `gorm:"type:varchar(20);not null;uniqueIndex:idx_leave_entitlements_user_year_type" json:"leave_type"`
+	Days      decimal.Decimal `gorm:"type:numeric;not null" json:"days"`
+}
@@ -6,6 +6,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// Overtime statuses. Only approved overtime is paid.
+const (
+	OvertimeStatusPending  = "pending"
+	OvertimeStatusApproved = "approved"
+	OvertimeStatusRejected = "rejected"
+)
+
 // Overtime records an employee's overtime hours.
 type Overtime struct {
 	BaseModel
@@ -13,6 +20,10 @@ type Overtime struct {
 	User            User           `gorm:"foreignKey:UserID" json:"user"`
 	Date            time.Time      `gorm:"type:date;not null" json:"date"`
 	Hours           float64        `gorm:"type:numeric;not null" json:"hours"`
+	Status          string         `gorm:"type:varchar(20);not null;default:'approved';index" json:"status"` // Overtime recorded before approvals were introduced stays approved
+	ReviewedBy      *uuid.UUID     `gorm:"type:uuid" json:"reviewed_by"`                                     // Manager or admin who approved or rejected the overtime
+	ReviewedAt      *time.Time     `json:"reviewed_at"`
+	ReviewNote      string         `gorm:"type:text" json:"review_note"`
 	PayrollPeriodID *uuid.UUID     `gorm:"type:uuid" json:"payroll_period_id,omitempty"` // Nullable, set after payroll run
 	PayrollPeriod   *PayrollPeriod `gorm:"foreignKey:PayrollPeriodID" json:"payroll_period,omitempty"`
 }
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payroll run statuses.
+const (
+	PayrollRunStatusQueued    = "queued"
+	PayrollRunStatusRunning   = "running"
+	PayrollRunStatusSucceeded = "succeeded"
+	PayrollRunStatusFailed    = "failed"
+)
+
+// PayrollRun tracks a background payroll processing job for a payroll period. A period has at most
+// one queued or running payroll run at a time.
+type PayrollRun struct {
+	BaseModel
+	PayrollPeriodID    uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_payroll_runs_active_period,where:(status = 'queued' OR status = 'running') AND deleted_at IS NULL" json:"payroll_period_id"`
+	Status             string     `gorm:"type:varchar(20);not null;index" json:"status"` // "queued", "running", "succeeded" or "failed"
+	TotalEmployees     int        `gorm:"not null;default:0" json:"total_employees"`
+	ProcessedEmployees int        `gorm:"not null;default:0" json:"processed_employees"`
+	StartedAt          *time.Time `json:"started_at,omitempty"`  // Nullable, set when a worker picks the run up
+	FinishedAt         *time.Time `json:"finished_at,omitempty"` // Nullable, set when the run succeeds or fails
+	Error              string     `gorm:"type:text" json:"error,omitempty"`
+	RequestID          string     `gorm:"type:varchar(255)" json:"request_id"`
+}
@@ -2,20 +2,29 @@ package domain
 
 import (
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
-// Payslip stores the calculated payslip details for an employee.
+// Payslip stores the calculated payslip details for an employee. An employee has at most one
+// active payslip per payroll period; payslips voided by a reversal are soft-deleted and do not count.
 type Payslip struct {
 	BaseModel
-	UserID             uuid.UUID     `gorm:"type:uuid;not null" json:"user_id"`
-	User               User          `gorm:"foreignKey:UserID" json:"user"`
-	PayrollPeriodID    uuid.UUID     `gorm:"type:uuid;not null" json:"payroll_period_id"`
-	PayrollPeriod      PayrollPeriod `gorm:"foreignKey:PayrollPeriodID" json:"payroll_period"`
-	Overtimes          []*Overtime   `gorm:"-" json:"overtimes"`
-	Attendances        []*Attendance `gorm:"-" json:"attendances"`
-	BaseSalary         float64       `gorm:"type:numeric;not null" json:"base_salary"`
-	ProratedSalary     float64       `gorm:"type:numeric;not null" json:"prorated_salary"`
-	OvertimePay        float64       `gorm:"type:numeric;not null" json:"overtime_pay"`
-	TotalReimbursement float64       `gorm:"type:numeric;not null" json:"total_reimbursement"`
-	TotalTakeHomePay   float64       `gorm:"type:numeric;not null" json:"total_take_home_pay"`
+	UserID                     uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_payslips_user_period,where:deleted_at IS NULL" json:"user_id"`
+	User                       User              `gorm:"foreignKey:UserID" json:"user"`
+	PayrollPeriodID            uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_payslips_user_period" json:"payroll_period_id"`
+	PayrollPeriod              PayrollPeriod     `gorm:"foreignKey:PayrollPeriodID" json:"payroll_period"`
+	Overtimes                  []*Overtime       `gorm:"-" json:"overtimes"`
+	Attendances                []*Attendance     `gorm:"-" json:"attendances"`
+	WorkCalendar               WorkCalendar      `gorm:"-" json:"-"`                                                              // Holidays of the payroll period and the employee's work schedule, attached with the attendances and overtimes
+	AttendancePayMode          AttendancePayMode `gorm:"type:varchar(10);not null;default:'full_day'" json:"attendance_pay_mode"` // How the attendance hours were paid
+	BaseSalary                 decimal.Decimal   `gorm:"type:numeric;not null" json:"base_salary"`
+	ProratedSalary             decimal.Decimal   `gorm:"type:numeric;not null" json:"prorated_salary"`
+	OvertimePay                decimal.Decimal   `gorm:"type:numeric;not null" json:"overtime_pay"`
+	TotalReimbursement         decimal.Decimal   `gorm:"type:numeric;not null" json:"total_reimbursement"`
+	GrossEarnings              decimal.Decimal   `gorm:"type:numeric;not null;default:0" json:"gross_earnings"`
+	TotalDeductions            decimal.Decimal   `gorm:"type:numeric;not null;default:0" json:"total_deductions"`
+	TotalEmployerContributions decimal.Decimal   `gorm:"type:numeric;not null;default:0" json:"total_employer_contributions"`
+	TotalTakeHomePay           decimal.Decimal   `gorm:"type:numeric;not null" json:"total_take_home_pay"`
+	Items                      []PayslipItem     `gorm:"foreignKey:PayslipID" json:"items"`      // Earning, deduction and employer contribution lines
+	VoidReason                 string            `gorm:"type:text" json:"void_reason,omitempty"` // Set when the payroll run is reversed
 }
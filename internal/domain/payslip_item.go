@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Payslip component types.
+const (
+	PayslipComponentEarning              = "earning"
+	PayslipComponentDeduction            = "deduction"
+	PayslipComponentEmployerContribution = "employer_contribution"
+)
+
+// PayslipItem is a single earning, deduction or employer contribution line of a payslip.
+// Employer contributions are reported on the payslip but do not change take-home pay.
+type PayslipItem struct {
+	BaseModel
+	PayslipID     uuid.UUID       `gorm:"type:uuid;not null;index" json:"payslip_id"`
+	ComponentType string          `gorm:"type:varchar(30);not null" json:"component_type"` // "earning", "deduction" or "employer_contribution"
+	Code          string          `gorm:"type:varchar(50);not null" json:"code"`           // e.g. "BASIC_SALARY", "OVERTIME"
+	Name          string          `gorm:"type:varchar(255);not null" json:"name"`
+	Quantity      decimal.Decimal `gorm:"type:numeric;not null" json:"quantity"`
+	Rate          decimal.Decimal `gorm:"type:numeric;not null" json:"rate"`
+	Amount        decimal.Decimal `gorm:"type:numeric;not null" json:"amount"`
+	Taxable       bool            `gorm:"not null;default:false" json:"taxable"`
+	Sequence      int             `gorm:"not null;default:0" json:"sequence"` // Order in which the line was calculated
+}
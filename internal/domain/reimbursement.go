@@ -1,16 +1,67 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Reimbursement statuses. Only approved reimbursements are paid.
+const (
+	ReimbursementStatusPending  = "pending"
+	ReimbursementStatusApproved = "approved"
+	ReimbursementStatusRejected = "rejected"
 )
 
-// Reimbursement records an employee's reimbursement request.
+// Reimbursement records an employee's reimbursement request. An approved reimbursement is paid by
+// the first payroll run whose period ends on or after its expense date.
 type Reimbursement struct {
 	BaseModel
-	UserID        uuid.UUID      `gorm:"type:uuid;not null" json:"user_id"`
-	User          User           `gorm:"foreignKey:UserID" json:"user"`
-	Amount        float64        `gorm:"type:numeric;not null" json:"amount"`
-	Description   string         `gorm:"type:text" json:"description"`
-	PayrollPeriodID *uuid.UUID     `gorm:"type:uuid" json:"payroll_period_id,omitempty"` // Nullable, set after payroll run
-	PayrollPeriod   *PayrollPeriod `gorm:"foreignKey:PayrollPeriodID" json:"payroll_period,omitempty"`
+	UserID          uuid.UUID              `gorm:"type:uuid;not null" json:"user_id"`
+	User            User                   `gorm:"foreignKey:UserID" json:"user"`
+	CategoryID      *uuid.UUID             `gorm:"type:uuid;index" json:"category_id"` // Nil for claims made before categories were introduced
+	Category        *ReimbursementCategory `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	ExpenseDate     time.Time              `gorm:"type:date;index" json:"expense_date"` // Day the expense was incurred
+	Amount          decimal.Decimal        `gorm:"type:numeric;not null" json:"amount"`
+	Description     string                 `gorm:"type:text" json:"description"`
+	Status          string                 `gorm:"type:varchar(20);not null;default:'approved';index" json:"status"` // Reimbursements claimed before approvals were introduced stay approved
+	ApprovedAmount  *decimal.Decimal       `gorm:"type:numeric" json:"approved_amount"`                              // At most the claimed amount; nil until approved
+	ReviewedBy      *uuid.UUID             `gorm:"type:uuid" json:"reviewed_by"`                                     // Admin who approved or rejected the claim
+	ReviewedAt      *time.Time             `json:"reviewed_at"`
+	RejectionReason string                 `gorm:"type:text" json:"rejection_reason"`
+	PayrollPeriodID *uuid.UUID             `gorm:"type:uuid" json:"payroll_period_id,omitempty"` // Nullable, set after payroll run
+	PayrollPeriod   *PayrollPeriod         `gorm:"foreignKey:PayrollPeriodID" json:"payroll_period,omitempty"`
+	Receipts        []ReimbursementReceipt `gorm:"foreignKey:ReimbursementID" json:"receipts,omitempty"`
+}
+
+// PaidAmount returns the amount paid for an approved reimbursement: the approved amount, or the
+// claimed amount for reimbursements approved before partial approval was introduced.
+func (r Reimbursement) PaidAmount() decimal.Decimal {
+	if r.ApprovedAmount != nil {
+		return *r.ApprovedAmount
+	}
+	return r.Amount
+}
+
+// ReimbursementCategory is an admin-defined kind of expense, such as medical, travel or meals, with
+// the most an employee can claim for it. A nil limit is unlimited.
+type ReimbursementCategory struct {
+	BaseModel
+	Name          string           `gorm:"type:varchar(100);not null;uniqueIndex:idx_reimbursement_categories_name,where:deleted_at IS NULL" json:"name"`
+	PerClaimLimit *decimal.Decimal `gorm:"type:numeric" json:"per_claim_limit"` // Most a single claim can be for
+	MonthlyLimit  *decimal.Decimal `gorm:"type:numeric" json:"monthly_limit"`   // Most claimed for expenses in a calendar month
+	YearlyLimit   *decimal.Decimal `gorm:"type:numeric" json:"yearly_limit"`    // Most claimed for expenses in a calendar year
+}
+
+// ReimbursementReceipt is a receipt image or PDF attached to a reimbursement claim. The file is kept
+// in blob storage under its SHA-256 checksum, so a file uploaded more than once is stored once.
+type ReimbursementReceipt struct {
+	BaseModel
+	ReimbursementID uuid.UUID `gorm:"type:uuid;not null;index" json:"reimbursement_id"`
+	FileName        string    `gorm:"type:varchar(255);not null" json:"file_name"` // Name of the uploaded file
+	ContentType     string    `gorm:"type:varchar(100);not null" json:"content_type"`
+	Size            int64     `gorm:"not null" json:"size"` // In bytes
+	SHA256          string    `gorm:"column:sha256;type:char(64);not null;index" json:"sha256"`
+	StorageKey      string    `gorm:"type:varchar(255);not null" json:"-"` // Key of the file in blob storage
 }
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// SalaryHistory records a salary change of an employee. The salary is in force from EffectiveFrom
+// until the day before the next change; an employee has at most one change per effective date.
+type SalaryHistory struct {
+	BaseModel
+	UserID        uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_salary_histories_user_effective,where:deleted_at IS NULL" json:"user_id"`
+	User          User            `gorm:"foreignKey:UserID" json:"user"`
+	EffectiveFrom time.Time       `gorm:"type:date;not null;uniqueIndex:idx_salary_histories_user_effective" json:"effective_from"`
+	Salary        decimal.Decimal `gorm:"type:numeric;not null" json:"salary"`
+	Reason        string          `gorm:"type:text" json:"reason"`
+}
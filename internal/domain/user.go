@@ -1,9 +1,17 @@
 package domain
 
+import "time"
+
 // User represents a user in the system, either an employee or an admin.
 type User struct {
 	BaseModel
-	Username string `gorm:"type:varchar(255);uniqueIndex;not null" json:"username"`
-	Password string `gorm:"type:varchar(255);not null" json:"-"`   // Stored hashed
-	Role     string `gorm:"type:varchar(50);not null" json:"role"` // "employee" or "admin"
+	Username      string     `gorm:"type:varchar(255);uniqueIndex;not null" json:"username"`
+	Password      string     `gorm:"type:varchar(255);not null" json:"-"`   // Stored hashed
+	Role          string     `gorm:"type:varchar(50);not null" json:"role"` // "employee" or "admin"
+	DeactivatedAt *time.Time `gorm:"index" json:"deactivated_at"`           // Set when the user is deactivated, nil while active
+}
+
+// IsActive reports whether the user has not been deactivated.
+func (u *User) IsActive() bool {
+	return u.DeactivatedAt == nil
 }
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultDailyWorkingHours is the daily working hours of the default work schedule.
+const DefaultDailyWorkingHours = 8
+
+// weekdayNames are the names of the weekdays in WorkSchedule.WorkingDays, Sunday first.
+var weekdayNames = [...]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// WorkSchedule is a named schedule of working weekdays and a daily shift, assigned to employees.
+// A shift whose end is not after its start crosses midnight and ends the next day.
+type WorkSchedule struct {
+	BaseModel
+	Name         string          `gorm:"type:varchar(100);not null;uniqueIndex:idx_work_schedules_name,where:deleted_at IS NULL" json:"name"`
+	WorkingDays  string          `gorm:"type:varchar(27);not null" json:"working_days"` // Comma separated weekdays, e.g. "mon,tue,wed,thu,fri"
+	ShiftStart   string          `gorm:"type:varchar(5);not null" json:"shift_start"`   // HH:MM
+	ShiftEnd     string          `gorm:"type:varchar(5);not null" json:"shift_end"`     // HH:MM
+	DailyHours   decimal.Decimal `gorm:"type:numeric;not null" json:"daily_hours"`      // Paid hours of a full working day
+	BreakMinutes int             `gorm:"not null;default:0" json:"break_minutes"`       // Unpaid break within the shift
+}
+
+// DefaultWorkSchedule returns the schedule of employees without an assigned schedule:
+// Monday to Friday, 09:00 to 17:00, 8 hours a day without a break.
+func DefaultWorkSchedule() WorkSchedule {
+	return WorkSchedule{
+		Name:        "Standard",
+		WorkingDays: "mon,tue,wed,thu,fri",
+		ShiftStart:  "09:00",
+		ShiftEnd:    "17:00",
+		DailyHours:  decimal.NewFromInt(DefaultDailyWorkingHours),
+	}
+}
+
+// WorkScheduleOrDefault returns schedule, or the default work schedule when schedule is nil.
+func WorkScheduleOrDefault(schedule *WorkSchedule) WorkSchedule {
+	if schedule == nil {
+		return DefaultWorkSchedule()
+	}
+	return *schedule
+}
+
+// ParseWeekdays parses comma separated weekday names ("mon" to "sun", in any case) and returns
+// them in the canonical order, Monday first, without duplicates.
+func ParseWeekdays(names string) (string, error) {
+	var days [len(weekdayNames)]bool
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		found := false
+		for i, weekday := range weekdayNames {
+			if weekday == name {
+				days[i] = true
+				found = true
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("unknown weekday %q", name)
+		}
+	}
+
+	var canonical []string
+	for i := range weekdayNames {
+		weekday := (i + 1) % len(weekdayNames) // Monday first, Sunday last
+		if days[weekday] {
+			canonical = append(canonical, weekdayNames[weekday])
+		}
+	}
+	if len(canonical) == 0 {
+		return "", fmt.Errorf("at least one working day is required")
+	}
+	return strings.Join(canonical, ","), nil
+}
+
+// WorksOn reports whether weekday is a working day of the schedule.
+func (s WorkSchedule) WorksOn(weekday time.Weekday) bool {
+	for _, name := range strings.Split(s.WorkingDays, ",") {
+		if name == weekdayNames[weekday] {
+			return true
+		}
+	}
+	return false
+}
+
+// ShiftDuration returns the length of the shift from its start to its end, break included.
+func (s WorkSchedule) ShiftDuration() (time.Duration, error) {
+	start, err := time.Parse("15:04", s.ShiftStart)
+	if err != nil {
+		return 0, fmt.Errorf("invalid shift start %q", s.ShiftStart)
+	}
+	end, err := time.Parse("15:04", s.ShiftEnd)
+	if err != nil {
+		return 0, fmt.Errorf("invalid shift end %q", s.ShiftEnd)
+	}
+	duration := end.Sub(start)
+	if duration <= 0 {
+		duration += 24 * time.Hour
+	}
+	return duration, nil
+}
+
+// IsOvernight reports whether the shift crosses midnight.
+func (s WorkSchedule) IsOvernight() bool {
+	return s.ShiftEnd <= s.ShiftStart
+}
+
+// ShiftEndsAt returns the end of the shift that starts on date, on the next day for an overnight
+// shift. Shift times are validated when the schedule is saved.
+func (s WorkSchedule) ShiftEndsAt(date time.Time) time.Time {
+	end, _ := time.Parse("15:04", s.ShiftEnd)
+	endsAt := time.Date(date.Year(), date.Month(), date.Day(), end.Hour(), end.Minute(), 0, 0, date.Location())
+	if s.IsOvernight() {
+		endsAt = endsAt.AddDate(0, 0, 1)
+	}
+	return endsAt
+}
+
+// AttendanceClosesAt returns the end of the day on which the shift that starts on date ends. An
+// attendance of date still open at that time was left open: the employee did not clock out.
+func (s WorkSchedule) AttendanceClosesAt(date time.Time) time.Time {
+	endsAt := s.ShiftEndsAt(date)
+	return time.Date(endsAt.Year(), endsAt.Month(), endsAt.Day()+1, 0, 0, 0, 0, endsAt.Location())
+}
+
+// WorkedHours returns the hours worked from checkIn to checkOut less the break, capped at the
+// schedule's daily hours. On an overnight shift a check-out before the check-in is on the next
+// day: attendance times are stored without their date.
+func (s WorkSchedule) WorkedHours(checkIn, checkOut time.Time) float64 {
+	worked := checkOut.Sub(checkIn)
+	if worked < 0 && s.IsOvernight() {
+		worked += 24 * time.Hour
+	}
+	hours := worked.Hours() - float64(s.BreakMinutes)/60
+	if daily := s.DailyHours.InexactFloat64(); hours > daily {
+		hours = daily
+	}
+	if hours < 0 {
+		hours = 0
+	}
+	return hours
+}
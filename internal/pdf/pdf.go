@@ -0,0 +1,189 @@
+// Package pdf writes simple PDF documents: text in the standard Helvetica fonts, lines and filled
+// rectangles on A4 pages. Every PDF reader ships the standard fonts, so no font is embedded.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points. Positions are given in points from the top left corner of the page.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font is one of the standard fonts a document writes text in.
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+// fontNames are the PDF names of the fonts, in the order of the Font constants.
+var fontNames = []string{"Helvetica", "Helvetica-Bold"}
+
+// Document is a PDF document being drawn page by page.
+type Document struct {
+	title string
+	pages []*bytes.Buffer // Content stream of every page
+	page  *bytes.Buffer   // Page being drawn on
+}
+
+// New creates a document without pages. title is shown by PDF readers in place of the file name.
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+// AddPage adds an empty page at the end of the document and draws on it from then on.
+func (d *Document) AddPage() {
+	d.page = new(bytes.Buffer)
+	d.pages = append(d.pages, d.page)
+}
+
+// PageCount returns the number of pages of the document.
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage draws on page n, counted from 1, from then on.
+func (d *Document) SetPage(n int) {
+	d.page = d.pages[n-1]
+}
+
+// Text draws s at x with its baseline at y. Characters outside of Latin-1 are drawn as '?'.
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(d.page, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n", font+1, num(size), num(x), num(PageHeight-y), escape(encode(s)))
+}
+
+// TextRight draws s ending at x with its baseline at y.
+func (d *Document) TextRight(x, y float64, font Font, size float64, s string) {
+	d.Text(x-TextWidth(font, size, s), y, font, size, s)
+}
+
+// Line draws a black line from (x1, y1) to (x2, y2).
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.page, "%s w %s %s m %s %s l S\n", num(width), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// FillRect fills the rectangle with its top left corner at (x, y) in gray, from 0 for black to 1
+// for white.
+func (d *Document) FillRect(x, y, width, height, gray float64) {
+	fmt.Fprintf(d.page, "q %s g %s %s %s %s re f Q\n", num(gray), num(x), num(PageHeight-y-height), num(width), num(height))
+}
+
+// TextWidth returns the width of s drawn in font at size, in points.
+func TextWidth(font Font, size float64, s string) float64 {
+	widths := helveticaWidths
+	if font == HelveticaBold {
+		widths = helveticaBoldWidths
+	}
+
+	var units int
+	for _, c := range encode(s) {
+		if c >= 32 && c <= 126 {
+			units += widths[c-32]
+		} else {
+			units += 556 // Latin-1 letters are about as wide as the digits
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// WriteTo writes the document to w as a PDF file.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int // Offset of every object, object n at index n-1
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1 to 4 are the catalog, the page tree, the document information and the fonts; every
+	// page is followed by its content stream.
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object(fmt.Sprintf("<< /Title (%s) >>", escape(encode(d.title))))
+	object(fmt.Sprintf("<< /F1 << /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >> /F2 << /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >> >>",
+		fontNames[Helvetica], fontNames[HelveticaBold]))
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font 4 0 R >> /Contents %d 0 R >>",
+			num(PageWidth), num(PageHeight), 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+// num formats a number of points with at most two decimals.
+func num(v float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", v), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// encode converts s to the WinAnsi encoding of the fonts, which matches Latin-1 for the printable
+// characters. Control characters become spaces and other characters '?'.
+func encode(s string) []byte {
+	encoded := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 32 || r == 127:
+			encoded = append(encoded, ' ')
+		case r < 127 || (r >= 160 && r <= 255):
+			encoded = append(encoded, byte(r))
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+// escape escapes the characters with a special meaning in a PDF string literal.
+func escape(s []byte) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// Character widths of the printable ASCII characters, space to tilde, in thousandths of the font
+// size, from the Adobe font metrics of the standard fonts.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
@@ -0,0 +1,62 @@
+package pdf_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"payroll-system/internal/pdf"
+)
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc := pdf.New("Payslip (August)")
+	doc.AddPage()
+	doc.Text(40, 56, pdf.HelveticaBold, 16, `PT Maju (Jakarta) \ Café`)
+	doc.TextRight(555.28, 70, pdf.Helvetica, 9, "Payslip Period 1 Aug 2025 - 31 Aug 2025")
+	doc.Line(40, 82, 555.28, 82, 1)
+	doc.AddPage()
+	doc.FillRect(40, 40, 515.28, 16, 0.9)
+	doc.SetPage(1)
+	doc.Text(40, 800, pdf.Helvetica, 8, "Page 1 of 2 – 日本")
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(out, "%%EOF\n"))
+	assert.Contains(t, out, "<< /Type /Pages /Kids [5 0 R 7 0 R] /Count 2 >>")
+	assert.Contains(t, out, "<< /Title (Payslip \\(August\\)) >>")
+	assert.Contains(t, out, "BT /F2 16 Tf 40 785.89 Td (PT Maju \\(Jakarta\\) \\\\ Caf\xe9) Tj ET\n")
+	assert.Contains(t, out, "BT /F1 8 Tf 40 41.89 Td (Page 1 of 2 ? ??) Tj ET\n")
+	assert.Contains(t, out, "1 w 40 759.89 m 555.28 759.89 l S\n")
+	assert.Contains(t, out, "q 0.9 g 40 785.89 515.28 16 re f Q\n")
+
+	// Every object is where the cross-reference table says it is
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(startxref[1])
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out[xref:], "xref\n0 9\n0000000000 65535 f \n"))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllStringSubmatch(out[xref:], -1)
+	require.Len(t, entries, 8)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(entry[1])
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(out[offset:], fmt.Sprintf("%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.InDelta(t, 25.02, pdf.TextWidth(pdf.Helvetica, 10, "0000\x00"), 0.001)
+	assert.InDelta(t, 6.11, pdf.TextWidth(pdf.HelveticaBold, 10, "b"), 0.001)
+	assert.Greater(t, pdf.TextWidth(pdf.HelveticaBold, 9, "Total"), pdf.TextWidth(pdf.Helvetica, 9, "Total"))
+}
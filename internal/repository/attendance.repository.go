@@ -16,10 +16,17 @@ type AttendanceRepository interface {
 	CreateAttendance(attendance *domain.Attendance) error
 	GetAttendanceByID(id uuid.UUID) (*domain.Attendance, error)
 	GetAttendanceByUserIDAndDate(userID uuid.UUID, date time.Time) (*domain.Attendance, error)
+	GetOpenAttendanceByUserID(userID uuid.UUID) (*domain.Attendance, error)
+	GetOpenAttendances() ([]domain.Attendance, error)
 	GetAttendancesByUserIDAndPayrollPeriodID(userID uuid.UUID, payrollPeriodID uuid.UUID) ([]*domain.Attendance, error)
 	GetAttendancesByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.Attendance, error)
+	GetAttendanceHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Attendance, int64, error)
 	UpdateAttendance(attendance *domain.Attendance) error
-	UpdateAttendancesTx(tx *gorm.DB, attendances []domain.Attendance) error
+	GetAttendancesByPayrollPeriodID(payrollPeriodID uuid.UUID) ([]domain.Attendance, error)
+	DetachAttendancesFromPayrollPeriod(payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error
+	GetAttendancesByPeriodGroupedByUser(startDate, endDate time.Time) (map[uuid.UUID][]domain.Attendance, error)
+	GetAttendancesByPayrollPeriodIDGroupedByUser(payrollPeriodID uuid.UUID) (map[uuid.UUID][]*domain.Attendance, error)
+	AttachAttendancesToPayrollPeriod(ids []uuid.UUID, payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error
 }
 
 // AttendanceGormRepository implements repository.AttendanceRepository using GORM.
@@ -57,6 +64,23 @@ func (r *AttendanceGormRepository) GetAttendanceByUserIDAndDate(userID uuid.UUID
 	return &attendance, err
 }
 
+// GetOpenAttendanceByUserID retrieves the latest attendance record of a user without a check-out.
+func (r *AttendanceGormRepository) GetOpenAttendanceByUserID(userID uuid.UUID) (*domain.Attendance, error) {
+	var attendance domain.Attendance
+	err := r.db.Where("user_id = ? AND check_out_time IS NULL", userID).Order("date DESC").First(&attendance).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &attendance, err
+}
+
+// GetOpenAttendances retrieves every attendance record without a check-out.
+func (r *AttendanceGormRepository) GetOpenAttendances() ([]domain.Attendance, error) {
+	var attendances []domain.Attendance
+	err := r.db.Where("check_out_time IS NULL").Order("user_id, date").Find(&attendances).Error
+	return attendances, err
+}
+
 // GetAttendancesByUserIDAndPeriod retrieves attendance records for a user within a date range.
 func (r *AttendanceGormRepository) GetAttendancesByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.Attendance, error) {
 	var attendances []domain.Attendance
@@ -71,18 +95,74 @@ func (r *AttendanceGormRepository) GetAttendancesByUserIDAndPayrollPeriodID(user
 	return attendances, err
 }
 
+// GetAttendanceHistory retrieves a page of the attendance records of a user, latest first, together
+// with the number of records matching the filter.
+func (r *AttendanceGormRepository) GetAttendanceHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Attendance, int64, error) {
+	return findHistoryPage[domain.Attendance](filterHistory(r.db, userID, filter, "date"), filter, "date DESC")
+}
+
 // UpdateAttendance updates an existing attendance record in the database.
 func (r *AttendanceGormRepository) UpdateAttendance(attendance *domain.Attendance) error {
 	return r.db.Save(attendance).Error
 }
 
-// UpdateAttendancesTx updates multiple attendance records within the given transaction.
-func (r *AttendanceGormRepository) UpdateAttendancesTx(tx *gorm.DB, attendances []domain.Attendance) error {
-	if tx == nil {
-		return gorm.ErrInvalidDB
+// GetAttendancesByPayrollPeriodID retrieves all attendance records attached to a payroll period.
+func (r *AttendanceGormRepository) GetAttendancesByPayrollPeriodID(payrollPeriodID uuid.UUID) ([]domain.Attendance, error) {
+	var attendances []domain.Attendance
+	err := r.db.Where("payroll_period_id = ?", payrollPeriodID).Find(&attendances).Error
+	return attendances, err
+}
+
+// DetachAttendancesFromPayrollPeriod clears the payroll period of every attendance record attached to it.
+func (r *AttendanceGormRepository) DetachAttendancesFromPayrollPeriod(payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error {
+	return r.db.Model(&domain.Attendance{}).
+		Where("payroll_period_id = ?", payrollPeriodID).
+		Updates(map[string]interface{}{
+			"payroll_period_id": nil,
+			"updated_by":        updatedBy,
+			"ip_address":        ipAddress,
+		}).Error
+}
+
+// GetAttendancesByPeriodGroupedByUser retrieves the attendance records of every user within a date range
+// in a single query, grouped by user ID.
+func (r *AttendanceGormRepository) GetAttendancesByPeriodGroupedByUser(startDate, endDate time.Time) (map[uuid.UUID][]domain.Attendance, error) {
+	var attendances []domain.Attendance
+	err := r.db.
+		Where("date >= ? AND date <= ?", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Order("user_id, date").
+		Find(&attendances).Error
+	if err != nil {
+		return nil, err
 	}
-	for _, attendance := range attendances {
-		if err := tx.Save(&attendance).Error; err != nil {
+	return groupByUserID(attendances, func(a domain.Attendance) uuid.UUID { return a.UserID }), nil
+}
+
+// GetAttendancesByPayrollPeriodIDGroupedByUser retrieves every attendance record attached to a payroll period
+// in a single query, grouped by user ID.
+func (r *AttendanceGormRepository) GetAttendancesByPayrollPeriodIDGroupedByUser(payrollPeriodID uuid.UUID) (map[uuid.UUID][]*domain.Attendance, error) {
+	attendances := make([]*domain.Attendance, 0)
+	err := r.db.
+		Where("payroll_period_id = ?", payrollPeriodID).
+		Order("user_id, date").
+		Find(&attendances).Error
+	if err != nil {
+		return nil, err
+	}
+	return groupByUserID(attendances, func(a *domain.Attendance) uuid.UUID { return a.UserID }), nil
+}
+
+// AttachAttendancesToPayrollPeriod attaches the given attendance records to a payroll period,
+// using one UPDATE per BatchSize records.
+func (r *AttendanceGormRepository) AttachAttendancesToPayrollPeriod(ids []uuid.UUID, payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error {
+	for _, chunk := range chunkIDs(ids, BatchSize) {
+		if err := r.db.Model(&domain.Attendance{}).
+			Where("id IN ?", chunk).
+			Updates(map[string]interface{}{
+				"payroll_period_id": payrollPeriodID,
+				"updated_by":        updatedBy,
+				"ip_address":        ipAddress,
+			}).Error; err != nil {
 			return err
 		}
 	}
@@ -246,6 +246,40 @@ func (s *AttendanceRepositorySuite) TestGetAttendanceByUserIDAndDate() {
 	}
 }
 
+func (s *AttendanceRepositorySuite) TestGetOpenAttendanceByUserID() {
+	userID := uuid.New()
+	query := `SELECT * FROM "attendances" WHERE (user_id = $1 AND check_out_time IS NULL) AND "attendances"."deleted_at" IS NULL ORDER BY date DESC,"attendances"."id" LIMIT $2`
+
+	s.T().Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "date"}).AddRow(uuid.New(), userID, time.Now())
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID, 1).WillReturnRows(rows)
+
+		attendance, err := s.repo.GetOpenAttendanceByUserID(userID)
+		assert.NoError(t, err)
+		assert.True(t, attendance.IsOpen())
+	})
+
+	s.T().Run("Not Clocked In", func(t *testing.T) {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID, 1).WillReturnError(gorm.ErrRecordNotFound)
+
+		attendance, err := s.repo.GetOpenAttendanceByUserID(userID)
+		assert.NoError(t, err)
+		assert.Nil(t, attendance)
+	})
+}
+
+func (s *AttendanceRepositorySuite) TestGetOpenAttendances() {
+	rows := sqlmock.NewRows([]string{"id", "user_id", "date"}).
+		AddRow(uuid.New(), uuid.New(), time.Now()).
+		AddRow(uuid.New(), uuid.New(), time.Now())
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "attendances" WHERE check_out_time IS NULL AND "attendances"."deleted_at" IS NULL ORDER BY user_id, date`)).
+		WillReturnRows(rows)
+
+	attendances, err := s.repo.GetOpenAttendances()
+	s.NoError(err)
+	s.Len(attendances, 2)
+}
+
 func (s *AttendanceRepositorySuite) TestGetAttendancesByUserIDAndPeriod() {
 	userID := uuid.New()
 	startDate := time.Now().Add(-5 * 24 * time.Hour)
@@ -409,77 +443,272 @@ func (s *AttendanceRepositorySuite) TestUpdateAttendance() {
 	}
 }
 
-//func (s *AttendanceRepositorySuite) TestUpdateAttendancesTx() {
-//	att1 := domain.Attendance{BaseModel: domain.BaseModel{ID: uuid.New()}}
-//	att2 := domain.Attendance{BaseModel: domain.BaseModel{ID: uuid.New()}}
-//	attendances := []domain.Attendance{att1, att2}
-//
-//	testCases := []struct {
-//		name        string
-//		attendances []domain.Attendance
-//		mock        func()
-//		wantErr     bool
-//		useNilTx    bool
-//	}{
-//		{
-//			name:        "Success",
-//			attendances: attendances,
-//			mock: func() {
-//				// GORM's Save on an existing record is a simple UPDATE, which is an Exec.
-//				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "attendances" SET`)).
-//					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), att1.ID).
-//					WillReturnResult(driver.ResultNoRows)
-//				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "attendances" SET`)).
-//					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), att2.ID).
-//					WillReturnResult(driver.ResultNoRows)
-//			},
-//			wantErr: false,
-//		},
-//		{
-//			name:        "DB Error on second update",
-//			attendances: attendances,
-//			mock: func() {
-//				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "attendances" SET`)).
-//					WillReturnResult(driver.ResultNoRows)
-//				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "attendances" SET`)).
-//					WillReturnError(errors.New("db error"))
-//			},
-//			wantErr: true,
-//		},
-//		{
-//			name:     "Error with nil transaction",
-//			useNilTx: true,
-//			mock:     func() {},
-//			wantErr:  true,
-//		},
-//	}
-//
-//	for _, tc := range testCases {
-//		s.T().Run(tc.name, func(t *testing.T) {
-//			if tc.useNilTx {
-//				err := s.repo.UpdateAttendancesTx(nil, tc.attendances)
-//				assert.Error(t, err)
-//				assert.Equal(t, gorm.ErrInvalidDB, err)
-//				return
-//			}
-//
-//			s.mock.ExpectBegin()
-//			tc.mock()
-//			if tc.wantErr {
-//				s.mock.ExpectRollback()
-//			} else {
-//				s.mock.ExpectCommit()
-//			}
-//
-//			err := s.db.Transaction(func(tx *gorm.DB) error {
-//				return s.repo.UpdateAttendancesTx(tx, tc.attendances)
-//			})
-//
-//			if tc.wantErr {
-//				assert.Error(t, err)
-//			} else {
-//				assert.NoError(t, err)
-//			}
-//		})
-//	}
-//}
+func (s *AttendanceRepositorySuite) TestGetAttendancesByPayrollPeriodID() {
+	periodID := uuid.New()
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+		wantLen int
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "payroll_period_id"}).AddRow(uuid.New(), periodID).AddRow(uuid.New(), periodID)
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "attendances" WHERE payroll_period_id = $1 AND "attendances"."deleted_at" IS NULL`)).
+					WithArgs(periodID).
+					WillReturnRows(rows)
+			},
+			wantErr: false,
+			wantLen: 2,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "attendances" WHERE payroll_period_id = $1`)).
+					WithArgs(periodID).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			records, err := s.repo.GetAttendancesByPayrollPeriodID(periodID)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, records, tc.wantLen)
+			}
+		})
+	}
+}
+
+func (s *AttendanceRepositorySuite) TestDetachAttendancesFromPayrollPeriod() {
+	periodID := uuid.New()
+	updatedBy := uuid.New()
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "attendances" SET "ip_address"=$1,"payroll_period_id"=$2,"updated_by"=$3,"updated_at"=$4 WHERE payroll_period_id = $5 AND "attendances"."deleted_at" IS NULL`)).
+					WithArgs("127.0.0.1", nil, updatedBy, sqlmock.AnyArg(), periodID).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			wantErr: false,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "attendances" SET`)).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.mock.ExpectBegin()
+			tc.mock()
+			if tc.wantErr {
+				s.mock.ExpectRollback()
+			} else {
+				s.mock.ExpectCommit()
+			}
+
+			err := s.db.Transaction(func(tx *gorm.DB) error {
+				return NewAttendanceGormRepository(tx).DetachAttendancesFromPayrollPeriod(periodID, updatedBy, "127.0.0.1")
+			})
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func (s *AttendanceRepositorySuite) TestGetAttendancesByPeriodGroupedByUser() {
+	userA := uuid.New()
+	userB := uuid.New()
+	startDate := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+
+	s.T().Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "date"}).
+			AddRow(uuid.New(), userA, startDate).
+			AddRow(uuid.New(), userA, endDate).
+			AddRow(uuid.New(), userB, startDate)
+		s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "attendances" WHERE (date >= $1 AND date <= $2) AND "attendances"."deleted_at" IS NULL ORDER BY user_id, date`)).
+			WithArgs("2025-08-01", "2025-08-31").
+			WillReturnRows(rows)
+
+		grouped, err := s.repo.GetAttendancesByPeriodGroupedByUser(startDate, endDate)
+		assert.NoError(t, err)
+		assert.Len(t, grouped, 2)
+		assert.Len(t, grouped[userA], 2)
+		assert.Len(t, grouped[userB], 1)
+	})
+
+	s.T().Run("DB Error", func(t *testing.T) {
+		s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "attendances"`)).
+			WillReturnError(errors.New("db error"))
+
+		grouped, err := s.repo.GetAttendancesByPeriodGroupedByUser(startDate, endDate)
+		assert.Error(t, err)
+		assert.Nil(t, grouped)
+	})
+}
+
+func (s *AttendanceRepositorySuite) TestGetAttendancesByPayrollPeriodIDGroupedByUser() {
+	periodID := uuid.New()
+	userID := uuid.New()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "payroll_period_id"}).
+		AddRow(uuid.New(), userID, periodID).
+		AddRow(uuid.New(), userID, periodID)
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "attendances" WHERE payroll_period_id = $1 AND "attendances"."deleted_at" IS NULL ORDER BY user_id, date`)).
+		WithArgs(periodID).
+		WillReturnRows(rows)
+
+	grouped, err := s.repo.GetAttendancesByPayrollPeriodIDGroupedByUser(periodID)
+	s.NoError(err)
+	s.Len(grouped[userID], 2)
+}
+
+func (s *AttendanceRepositorySuite) TestAttachAttendancesToPayrollPeriod() {
+	periodID := uuid.New()
+	updatedBy := uuid.New()
+
+	// One more ID than fits in a single batch, so two UPDATE statements are expected
+	ids := make([]uuid.UUID, BatchSize+1)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "attendances" SET "ip_address"=$1,"payroll_period_id"=$2,"updated_by"=$3,"updated_at"=$4 WHERE id IN (`)).
+					WillReturnResult(sqlmock.NewResult(0, BatchSize))
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "attendances" SET "ip_address"=$1,"payroll_period_id"=$2,"updated_by"=$3,"updated_at"=$4 WHERE id IN ($5) AND "attendances"."deleted_at" IS NULL`)).
+					WithArgs("127.0.0.1", periodID, updatedBy, sqlmock.AnyArg(), ids[BatchSize]).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: false,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "attendances" SET`)).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.mock.ExpectBegin()
+			tc.mock()
+			if tc.wantErr {
+				s.mock.ExpectRollback()
+			} else {
+				s.mock.ExpectCommit()
+			}
+
+			err := s.db.Transaction(func(tx *gorm.DB) error {
+				return NewAttendanceGormRepository(tx).AttachAttendancesToPayrollPeriod(ids, periodID, updatedBy, "127.0.0.1")
+			})
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func (s *AttendanceRepositorySuite) TestGetAttendanceHistory() {
+	userID := uuid.New()
+	periodID := uuid.New()
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+	countQuery := `SELECT count(*) FROM "attendances" WHERE user_id = $1 AND date >= $2 AND date <= $3 AND payroll_period_id = $4 AND "attendances"."deleted_at" IS NULL`
+	pageQuery := `SELECT * FROM "attendances" WHERE user_id = $1 AND date >= $2 AND date <= $3 AND payroll_period_id = $4 AND "attendances"."deleted_at" IS NULL ORDER BY date DESC LIMIT $5 OFFSET $6`
+
+	testCases := []struct {
+		name      string
+		mock      func()
+		wantLen   int
+		wantTotal int64
+		wantErr   bool
+	}{
+		{
+			name: "Success - Second Page",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(countQuery)).
+					WithArgs(userID, "2025-08-01", "2025-08-31", periodID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+				rows := sqlmock.NewRows([]string{"id", "user_id"}).
+					AddRow(uuid.New(), userID)
+				s.mock.ExpectQuery(regexp.QuoteMeta(pageQuery)).
+					WithArgs(userID, "2025-08-01", "2025-08-31", periodID, 2, 2).
+					WillReturnRows(rows)
+			},
+			wantLen:   1,
+			wantTotal: 3,
+		},
+		{
+			name: "Success - Page Past The Last Record",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(countQuery)).
+					WithArgs(userID, "2025-08-01", "2025-08-31", periodID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+			},
+			wantLen:   0,
+			wantTotal: 2,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(countQuery)).
+					WithArgs(userID, "2025-08-01", "2025-08-31", periodID).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			filter := domain.HistoryFilter{From: &from, To: &to, PayrollPeriodID: &periodID, Page: 2, PageSize: 2}
+			results, total, err := s.repo.GetAttendanceHistory(userID, filter)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, results, tc.wantLen)
+				assert.Equal(t, tc.wantTotal, total)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// AttendanceSettingRepository defines the interface for attendance setting data operations.
+//
+//go:generate mockgen -source=attendance_setting.repository.go -destination=../../tests/mocks/repository/mock_attendance_setting_repository.go -package=mocks
+type AttendanceSettingRepository interface {
+	GetAttendanceSetting() (*domain.AttendanceSetting, error)
+	SaveAttendanceSetting(setting *domain.AttendanceSetting) error
+}
+
+// AttendanceSettingGormRepository implements repository.AttendanceSettingRepository using GORM.
+type AttendanceSettingGormRepository struct {
+	db *gorm.DB
+}
+
+// NewAttendanceSettingGormRepository creates a new AttendanceSettingGormRepository.
+func NewAttendanceSettingGormRepository(db *gorm.DB) AttendanceSettingRepository {
+	return &AttendanceSettingGormRepository{db: db}
+}
+
+// GetAttendanceSetting retrieves the attendance setting. It returns nil when no admin has saved one yet.
+func (r *AttendanceSettingGormRepository) GetAttendanceSetting() (*domain.AttendanceSetting, error) {
+	var setting domain.AttendanceSetting
+	err := r.db.First(&setting).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &setting, err
+}
+
+// SaveAttendanceSetting creates the attendance setting when it has no ID yet and updates it otherwise.
+func (r *AttendanceSettingGormRepository) SaveAttendanceSetting(setting *domain.AttendanceSetting) error {
+	return r.db.Save(setting).Error
+}
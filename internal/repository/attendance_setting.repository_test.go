@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// --- Test Suite Setup for AttendanceSettingRepository ---
+
+type AttendanceSettingRepositorySuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	repo AttendanceSettingRepository
+}
+
+// SetupSuite runs before the tests in the suite are run.
+func (s *AttendanceSettingRepositorySuite) SetupSuite() {
+	sqlDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	s.Require().NoError(err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = NewAttendanceSettingGormRepository(db)
+}
+
+// TearDownTest runs after each test in the suite.
+func (s *AttendanceSettingRepositorySuite) TearDownTest() {
+	s.Require().NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestAttendanceSettingRepository runs the test suite.
+func TestAttendanceSettingRepository(t *testing.T) {
+	suite.Run(t, new(AttendanceSettingRepositorySuite))
+}
+
+// --- Test Cases ---
+
+func (s *AttendanceSettingRepositorySuite) TestGetAttendanceSetting() {
+	query := `SELECT * FROM "attendance_settings" WHERE "attendance_settings"."deleted_at" IS NULL ORDER BY "attendance_settings"."id" LIMIT $1`
+
+	s.Run("Success", func() {
+		rows := sqlmock.NewRows([]string{"id", "open_attendance"}).AddRow(uuid.New(), "shift_end")
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).WillReturnRows(rows)
+
+		setting, err := s.repo.GetAttendanceSetting()
+		s.NoError(err)
+		s.Equal("shift_end", setting.OpenAttendance)
+	})
+
+	s.Run("Not Saved Yet", func() {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).WillReturnError(gorm.ErrRecordNotFound)
+
+		setting, err := s.repo.GetAttendanceSetting()
+		s.NoError(err)
+		s.Nil(setting)
+	})
+
+	s.Run("DB Error", func() {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).WillReturnError(errors.New("db error"))
+
+		_, err := s.repo.GetAttendanceSetting()
+		s.Error(err)
+	})
+}
+
+func (s *AttendanceSettingRepositorySuite) TestSaveAttendanceSetting() {
+	s.Run("Create", func() {
+		setting := &domain.AttendanceSetting{OpenAttendance: "shift_end"}
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "attendance_settings"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+		s.mock.ExpectCommit()
+
+		s.NoError(s.repo.SaveAttendanceSetting(setting))
+		s.NotEqual(uuid.Nil, setting.ID)
+	})
+
+	s.Run("Update", func() {
+		setting := &domain.AttendanceSetting{BaseModel: domain.BaseModel{ID: uuid.New()}, OpenAttendance: "unpaid"}
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "attendance_settings" SET`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+
+		s.NoError(s.repo.SaveAttendanceSetting(setting))
+	})
+}
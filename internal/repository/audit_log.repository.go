@@ -14,6 +14,7 @@ import (
 //go:generate mockgen -source=audit_log.repository.go -destination=../../tests/mocks/repository/mock_audit_log_repository.go -package=mocks
 type AuditLogRepository interface {
 	Create(audit *domain.AuditLog) error
+	CreateBatch(audits []*domain.AuditLog) error
 	GetByID(id uuid.UUID) (*domain.AuditLog, error)
 	GetAllByUser(userID uuid.UUID, limit int) ([]domain.AuditLog, error)
 }
@@ -56,3 +57,17 @@ func (r *AuditLogGormRepository) GetAllByUser(userID uuid.UUID, limit int) ([]do
 	err := query.Find(&logs).Error
 	return logs, err
 }
+
+// CreateBatch inserts multiple audit log records, using one INSERT per BatchSize records.
+func (r *AuditLogGormRepository) CreateBatch(audits []*domain.AuditLog) error {
+	if len(audits) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for _, audit := range audits {
+		if audit.Timestamp.IsZero() {
+			audit.Timestamp = now
+		}
+	}
+	return r.db.CreateInBatches(audits, BatchSize).Error
+}
@@ -258,3 +258,30 @@ func (s *AuditLogRepositorySuite) TestGetAllByUser() {
 		})
 	}
 }
+
+func (s *AuditLogRepositorySuite) TestCreateBatch() {
+	userID := uuid.New()
+	entityID := uuid.New()
+
+	s.T().Run("Success", func(t *testing.T) {
+		audits := make([]*domain.AuditLog, 2)
+		for i := range audits {
+			audit, err := NewAuditLog(&userID, "CREATE", "Payslip", &entityID, nil, map[string]int{"n": i}, "127.0.0.1", "req-1")
+			s.Require().NoError(err)
+			audits[i] = audit
+		}
+
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_logs"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New()))
+		s.mock.ExpectCommit()
+
+		err := s.repo.CreateBatch(audits)
+		assert.NoError(t, err)
+	})
+
+	s.T().Run("Empty", func(t *testing.T) {
+		err := s.repo.CreateBatch(nil)
+		assert.NoError(t, err)
+	})
+}
@@ -15,13 +15,24 @@ var CreateAuditLogFunc = CreateAuditLog
 // - userID can be nil for system actions
 // - oldValue and newValue can be any struct, will be marshaled to JSON
 func CreateAuditLog(repo AuditLogRepository, userID *uuid.UUID, action, entityName string, entityID *uuid.UUID, oldValue, newValue any, ipAddress string, requestID string) error {
-	oldJSON, err := json.Marshal(oldValue)
+	audit, err := NewAuditLog(userID, action, entityName, entityID, oldValue, newValue, ipAddress, requestID)
 	if err != nil {
 		return err
 	}
+
+	return repo.Create(audit)
+}
+
+// NewAuditLog builds an audit log without inserting it, so that many entries can be
+// written at once with AuditLogRepository.CreateBatch.
+func NewAuditLog(userID *uuid.UUID, action, entityName string, entityID *uuid.UUID, oldValue, newValue any, ipAddress string, requestID string) (*domain.AuditLog, error) {
+	oldJSON, err := json.Marshal(oldValue)
+	if err != nil {
+		return nil, err
+	}
 	newJSON, err := json.Marshal(newValue)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	audit := &domain.AuditLog{
@@ -42,5 +53,5 @@ func CreateAuditLog(repo AuditLogRepository, userID *uuid.UUID, action, entityNa
 		},
 	}
 
-	return repo.Create(audit)
+	return audit, nil
 }
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+)
+
+// BatchSize is the maximum number of rows written, or IDs bound, by a single set-based statement.
+// It keeps statements well below PostgreSQL's limit of 65535 bind parameters.
+const BatchSize = 1000
+
+// chunkIDs splits ids into consecutive chunks of at most size elements.
+func chunkIDs(ids []uuid.UUID, size int) [][]uuid.UUID {
+	chunks := make([][]uuid.UUID, 0, (len(ids)+size-1)/size)
+	for start := 0; start < len(ids); start += size {
+		end := min(start+size, len(ids))
+		chunks = append(chunks, ids[start:end])
+	}
+	return chunks
+}
+
+// groupByUserID groups records by the user they belong to, preserving their order.
+func groupByUserID[T any](records []T, userID func(T) uuid.UUID) map[uuid.UUID][]T {
+	grouped := make(map[uuid.UUID][]T)
+	for _, record := range records {
+		id := userID(record)
+		grouped[id] = append(grouped[id], record)
+	}
+	return grouped
+}
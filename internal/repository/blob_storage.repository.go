@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ErrBlobNotFound is returned when no blob is stored under a key.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStorage defines the interface for storing file contents, such as reimbursement receipts,
+// under a slash-separated key.
+//
+//go:generate mockgen -source=blob_storage.repository.go -destination=../../tests/mocks/repository/mock_blob_storage_repository.go -package=mocks
+type BlobStorage interface {
+	// Put stores the contents of r under key, replacing any blob already stored under it.
+	Put(key string, r io.Reader) error
+	// Get opens the blob stored under key. It returns ErrBlobNotFound if there is none.
+	Get(key string) (io.ReadCloser, error)
+	// Exists reports whether a blob is stored under key.
+	Exists(key string) (bool, error)
+}
+
+// LocalBlobStorage implements repository.BlobStorage with files in a directory of the local filesystem.
+type LocalBlobStorage struct {
+	root string
+}
+
+// NewLocalBlobStorage creates a LocalBlobStorage keeping its blobs under root, creating the
+// directory if it does not exist.
+func NewLocalBlobStorage(root string) (BlobStorage, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob storage directory: %w", err)
+	}
+	return &LocalBlobStorage{root: root}, nil
+}
+
+// path returns the file path of the blob stored under key. Keys must stay inside the root directory.
+func (s *LocalBlobStorage) path(key string) (string, error) {
+	name := filepath.FromSlash(key)
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.root, name), nil
+}
+
+// Put writes the blob to a temporary file and renames it into place, so a blob is never read half written.
+func (s *LocalBlobStorage) Put(key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens the file of the blob stored under key.
+func (s *LocalBlobStorage) Get(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return f, err
+}
+
+// Exists reports whether the file of the blob stored under key exists.
+func (s *LocalBlobStorage) Exists(key string) (bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
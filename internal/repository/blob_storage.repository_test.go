@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStorage(t *testing.T) {
+	storage, err := NewLocalBlobStorage(t.TempDir())
+	require.NoError(t, err)
+
+	exists, err := storage.Exists("receipts/ab/abc")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = storage.Get("receipts/ab/abc")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+
+	require.NoError(t, storage.Put("receipts/ab/abc", strings.NewReader("first")))
+	require.NoError(t, storage.Put("receipts/ab/abc", strings.NewReader("second")))
+
+	exists, err = storage.Exists("receipts/ab/abc")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	blob, err := storage.Get("receipts/ab/abc")
+	require.NoError(t, err)
+	defer blob.Close()
+	content, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+
+	// Keys cannot leave the storage directory
+	assert.Error(t, storage.Put("../outside", strings.NewReader("x")))
+	_, err = storage.Get("/etc/passwd")
+	assert.Error(t, err)
+}
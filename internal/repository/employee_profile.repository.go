@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
@@ -14,6 +16,9 @@ type EmployeeProfileRepository interface {
 	CreateEmployeeProfile(profile *domain.EmployeeProfile) error
 	GetEmployeeProfileByUserID(userID uuid.UUID) (*domain.EmployeeProfile, error)
 	GetAllEmployeeProfiles() ([]domain.EmployeeProfile, error)
+	GetEmployeeProfilesForPeriod(startDate, endDate time.Time) ([]domain.EmployeeProfile, error)
+	SearchEmployeeProfiles(search string) ([]domain.EmployeeProfile, error)
+	UpdateEmployeeProfile(profile *domain.EmployeeProfile) error
 }
 
 // EmployeeProfileGormRepository implements repository.EmployeeProfileRepository using GORM.
@@ -41,9 +46,43 @@ func (r *EmployeeProfileGormRepository) GetEmployeeProfileByUserID(userID uuid.U
 	return &profile, err
 }
 
-// GetAllEmployeeProfiles retrieves all employee profiles.
+// GetAllEmployeeProfiles retrieves the employee profiles of every active user. Profiles of
+// deactivated users are left out, so they are not paid by later payroll runs.
 func (r *EmployeeProfileGormRepository) GetAllEmployeeProfiles() ([]domain.EmployeeProfile, error) {
 	var profiles []domain.EmployeeProfile
-	err := r.db.Find(&profiles).Error
+	err := r.db.
+		Where("user_id IN (SELECT id FROM users WHERE deactivated_at IS NULL AND deleted_at IS NULL)").
+		Find(&profiles).Error
+	return profiles, err
+}
+
+// GetEmployeeProfilesForPeriod retrieves the employee profiles of every active user employed on at
+// least one day of a date range: hired on or before its end date and not terminated before its
+// start date. Employees who left before the range are not paid by its payroll run.
+func (r *EmployeeProfileGormRepository) GetEmployeeProfilesForPeriod(startDate, endDate time.Time) ([]domain.EmployeeProfile, error) {
+	var profiles []domain.EmployeeProfile
+	err := r.db.
+		Where("user_id IN (SELECT id FROM users WHERE deactivated_at IS NULL AND deleted_at IS NULL)").
+		Where("hire_date IS NULL OR hire_date <= ?", endDate).
+		Where("termination_date IS NULL OR termination_date >= ?", startDate).
+		Find(&profiles).Error
+	return profiles, err
+}
+
+// SearchEmployeeProfiles retrieves the employee profiles, with their user, whose username contains
+// search, ignoring case, ordered by username. An empty search returns every profile. Profiles of
+// deactivated users are included.
+func (r *EmployeeProfileGormRepository) SearchEmployeeProfiles(search string) ([]domain.EmployeeProfile, error) {
+	var profiles []domain.EmployeeProfile
+	query := r.db.Joins("User")
+	if search != "" {
+		query = query.Where(`"User"."username" ILIKE ?`, "%"+search+"%")
+	}
+	err := query.Order(`"User"."username" ASC`).Find(&profiles).Error
 	return profiles, err
 }
+
+// UpdateEmployeeProfile updates an existing employee profile in the database.
+func (r *EmployeeProfileGormRepository) UpdateEmployeeProfile(profile *domain.EmployeeProfile) error {
+	return r.db.Omit("User").Save(profile).Error
+}
@@ -4,9 +4,11 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/postgres"
@@ -56,6 +58,7 @@ func TestEmployeeProfileRepository(t *testing.T) {
 func (s *EmployeeProfileRepositorySuite) TestCreateEmployeeProfile() {
 	profileID := uuid.New()
 	userID := uuid.New()
+	hireDate := time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)
 
 	testCases := []struct {
 		name    string
@@ -66,15 +69,16 @@ func (s *EmployeeProfileRepositorySuite) TestCreateEmployeeProfile() {
 		{
 			name: "Success",
 			profile: &domain.EmployeeProfile{
-				BaseModel: domain.BaseModel{ID: profileID},
-				UserID:    userID,
-				Salary:    60000,
+				BaseModel:  domain.BaseModel{ID: profileID},
+				UserID:     userID,
+				Salary:     decimal.NewFromInt(60000),
+				PTKPStatus: domain.PTKPStatusK1,
+				HireDate:   &hireDate,
 			},
 			mock: func() {
 				s.mock.ExpectBegin()
-				// Corrected the SQL query and argument type for salary to float64.
-				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "employee_profiles" ("created_at","updated_at","deleted_at","created_by","updated_by","ip_address","user_id","salary","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`)).
-					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), userID, float64(60000), profileID).
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "employee_profiles" ("created_at","updated_at","deleted_at","created_by","updated_by","ip_address","user_id","salary","ptkp_status","hire_date","termination_date","work_schedule_id","manager_id","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "id"`)).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), userID, decimal.NewFromInt(60000), domain.PTKPStatusK1, hireDate, nil, nil, nil, profileID).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(profileID))
 				s.mock.ExpectCommit()
 			},
@@ -192,7 +196,7 @@ func (s *EmployeeProfileRepositorySuite) TestGetAllEmployeeProfiles() {
 					AddRow(uuid.New(), uuid.New()).
 					AddRow(uuid.New(), uuid.New())
 				// Corrected the expected SQL query.
-				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "employee_profiles" WHERE "employee_profiles"."deleted_at" IS NULL`)).
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "employee_profiles" WHERE (user_id IN (SELECT id FROM users WHERE deactivated_at IS NULL AND deleted_at IS NULL)) AND "employee_profiles"."deleted_at" IS NULL`)).
 					WillReturnRows(rows)
 			},
 			wantLen: 2,
@@ -202,7 +206,7 @@ func (s *EmployeeProfileRepositorySuite) TestGetAllEmployeeProfiles() {
 			name: "Success with no profiles",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id", "user_id"})
-				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "employee_profiles" WHERE "employee_profiles"."deleted_at" IS NULL`)).
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "employee_profiles" WHERE (user_id IN (SELECT id FROM users WHERE deactivated_at IS NULL AND deleted_at IS NULL)) AND "employee_profiles"."deleted_at" IS NULL`)).
 					WillReturnRows(rows)
 			},
 			wantLen: 0,
@@ -211,7 +215,7 @@ func (s *EmployeeProfileRepositorySuite) TestGetAllEmployeeProfiles() {
 		{
 			name: "DB Error",
 			mock: func() {
-				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "employee_profiles" WHERE "employee_profiles"."deleted_at" IS NULL`)).
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "employee_profiles" WHERE (user_id IN (SELECT id FROM users WHERE deactivated_at IS NULL AND deleted_at IS NULL)) AND "employee_profiles"."deleted_at" IS NULL`)).
 					WillReturnError(errors.New("db error"))
 			},
 			wantLen: 0,
@@ -233,3 +237,161 @@ func (s *EmployeeProfileRepositorySuite) TestGetAllEmployeeProfiles() {
 		})
 	}
 }
+
+func (s *EmployeeProfileRepositorySuite) TestGetEmployeeProfilesForPeriod() {
+	startDate := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	const query = `SELECT * FROM "employee_profiles" WHERE (user_id IN (SELECT id FROM users WHERE deactivated_at IS NULL AND deleted_at IS NULL)) AND (hire_date IS NULL OR hire_date <= $1) AND (termination_date IS NULL OR termination_date >= $2) AND "employee_profiles"."deleted_at" IS NULL`
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantLen int
+		wantErr bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id"}).
+					AddRow(uuid.New(), uuid.New())
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(endDate, startDate).
+					WillReturnRows(rows)
+			},
+			wantLen: 1,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(endDate, startDate).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			profiles, err := s.repo.GetEmployeeProfilesForPeriod(startDate, endDate)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, profiles, tc.wantLen)
+			}
+		})
+	}
+}
+
+func (s *EmployeeProfileRepositorySuite) TestSearchEmployeeProfiles() {
+	userID := uuid.New()
+	// The selected columns are left out, only the join, filter and order are matched.
+	const searchQuery = `FROM "employee_profiles" LEFT JOIN "users" "User" ON "employee_profiles"."user_id" = "User"."id" AND "User"."deleted_at" IS NULL WHERE "User"."username" ILIKE $1 AND "employee_profiles"."deleted_at" IS NULL ORDER BY "User"."username" ASC`
+	const listQuery = `FROM "employee_profiles" LEFT JOIN "users" "User" ON "employee_profiles"."user_id" = "User"."id" AND "User"."deleted_at" IS NULL WHERE "employee_profiles"."deleted_at" IS NULL ORDER BY "User"."username" ASC`
+
+	testCases := []struct {
+		name    string
+		search  string
+		mock    func()
+		wantLen int
+		wantErr bool
+	}{
+		{
+			name:   "Filters by username",
+			search: "ali",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "User__id", "User__username"}).
+					AddRow(uuid.New(), userID, userID, "alice")
+				s.mock.ExpectQuery(regexp.QuoteMeta(searchQuery)).
+					WithArgs("%ali%").
+					WillReturnRows(rows)
+			},
+			wantLen: 1,
+		},
+		{
+			name:   "Empty search lists every profile",
+			search: "",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "User__id", "User__username"}).
+					AddRow(uuid.New(), userID, userID, "alice").
+					AddRow(uuid.New(), uuid.New(), uuid.New(), "bob")
+				s.mock.ExpectQuery(regexp.QuoteMeta(listQuery)).
+					WillReturnRows(rows)
+			},
+			wantLen: 2,
+		},
+		{
+			name:   "DB Error",
+			search: "ali",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(searchQuery)).
+					WithArgs("%ali%").
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			profiles, err := s.repo.SearchEmployeeProfiles(tc.search)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, profiles, tc.wantLen)
+				assert.Equal(t, "alice", profiles[0].User.Username)
+			}
+		})
+	}
+}
+
+func (s *EmployeeProfileRepositorySuite) TestUpdateEmployeeProfile() {
+	profile := &domain.EmployeeProfile{
+		BaseModel:  domain.BaseModel{ID: uuid.New()},
+		UserID:     uuid.New(),
+		Salary:     decimal.NewFromInt(60000),
+		PTKPStatus: domain.PTKPStatusK0,
+	}
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "employee_profiles" SET`)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				s.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "employee_profiles" SET`)).
+					WillReturnError(errors.New("db error"))
+				s.mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			err := s.repo.UpdateEmployeeProfile(profile)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes translated by the repositories.
+const (
+	pgUniqueViolation  = "23505"
+	pgLockNotAvailable = "55P03"
+)
+
+var (
+	// ErrDuplicateRecord is returned when a write violates a unique constraint.
+	ErrDuplicateRecord = errors.New("record already exists")
+	// ErrRecordLocked is returned when a row lock cannot be acquired because another transaction holds it.
+	ErrRecordLocked = errors.New("record is locked by another transaction")
+)
+
+// translateError maps PostgreSQL constraint and lock errors to repository errors. Any other
+// error is returned unchanged.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return ErrDuplicateRecord
+	case pgLockNotAvailable:
+		return ErrRecordLocked
+	}
+	return err
+}
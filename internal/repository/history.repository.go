@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// filterHistory applies the date range and payroll period of filter to a query on the records of a
+// user, with the date of a record in dateColumn.
+func filterHistory(db *gorm.DB, userID uuid.UUID, filter domain.HistoryFilter, dateColumn string) *gorm.DB {
+	query := db.Where("user_id = ?", userID)
+	if filter.From != nil {
+		query = query.Where(dateColumn+" >= ?", filter.From.Format("2006-01-02"))
+	}
+	if filter.To != nil {
+		query = query.Where(dateColumn+" <= ?", filter.To.Format("2006-01-02"))
+	}
+	if filter.PayrollPeriodID != nil {
+		query = query.Where("payroll_period_id = ?", *filter.PayrollPeriodID)
+	}
+	return query
+}
+
+// findHistoryPage counts the records matched by query and loads the page of them selected by filter,
+// sorted by order. The query must not be sorted or paged yet.
+func findHistoryPage[T any](query *gorm.DB, filter domain.HistoryFilter, order string) ([]T, int64, error) {
+	query = query.Session(&gorm.Session{}) // Counting must not change the query of the page
+
+	var total int64
+	if err := query.Model(new(T)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	records := make([]T, 0, filter.PageSize)
+	if total <= int64(filter.Offset()) {
+		return records, total, nil
+	}
+	err := query.Order(order).Offset(filter.Offset()).Limit(filter.PageSize).Find(&records).Error
+	return records, total, err
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// HolidayRepository defines the interface for holiday calendar data operations.
+//
+//go:generate mockgen -source=holiday.repository.go -destination=../../tests/mocks/repository/mock_holiday_repository.go -package=mocks
+type HolidayRepository interface {
+	CreateHoliday(holiday *domain.Holiday) error
+	CreateHolidays(holidays []domain.Holiday) error
+	GetHolidayByID(id uuid.UUID) (*domain.Holiday, error)
+	GetHolidayByDate(date time.Time) (*domain.Holiday, error)
+	GetHolidaysBetween(startDate, endDate time.Time) ([]domain.Holiday, error)
+	UpdateHoliday(holiday *domain.Holiday) error
+	DeleteHoliday(holiday *domain.Holiday) error
+}
+
+// HolidayGormRepository implements repository.HolidayRepository using GORM.
+type HolidayGormRepository struct {
+	db *gorm.DB
+}
+
+// NewHolidayGormRepository creates a new HolidayGormRepository.
+func NewHolidayGormRepository(db *gorm.DB) HolidayRepository {
+	return &HolidayGormRepository{db: db}
+}
+
+// CreateHoliday creates a new holiday in the database. It returns ErrDuplicateRecord when the
+// date is already on the calendar.
+func (r *HolidayGormRepository) CreateHoliday(holiday *domain.Holiday) error {
+	return translateError(r.db.Create(holiday).Error)
+}
+
+// CreateHolidays inserts multiple holidays, using one INSERT per BatchSize holidays. It returns
+// ErrDuplicateRecord when one of the dates is already on the calendar.
+func (r *HolidayGormRepository) CreateHolidays(holidays []domain.Holiday) error {
+	if len(holidays) == 0 {
+		return nil
+	}
+	return translateError(r.db.CreateInBatches(holidays, BatchSize).Error)
+}
+
+// GetHolidayByID retrieves a holiday by its ID.
+func (r *HolidayGormRepository) GetHolidayByID(id uuid.UUID) (*domain.Holiday, error) {
+	var holiday domain.Holiday
+	err := r.db.First(&holiday, "id = ?", id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &holiday, err
+}
+
+// GetHolidayByDate retrieves the holiday on a date.
+func (r *HolidayGormRepository) GetHolidayByDate(date time.Time) (*domain.Holiday, error) {
+	var holiday domain.Holiday
+	err := r.db.Where("date = ?", date.Format("2006-01-02")).First(&holiday).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &holiday, err
+}
+
+// GetHolidaysBetween retrieves the holidays from one date through another, ordered by date.
+func (r *HolidayGormRepository) GetHolidaysBetween(startDate, endDate time.Time) ([]domain.Holiday, error) {
+	var holidays []domain.Holiday
+	err := r.db.
+		Where("date BETWEEN ? AND ?", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Order("date ASC").
+		Find(&holidays).Error
+	return holidays, err
+}
+
+// UpdateHoliday updates an existing holiday. It returns ErrDuplicateRecord when the new date is
+// already on the calendar.
+func (r *HolidayGormRepository) UpdateHoliday(holiday *domain.Holiday) error {
+	return translateError(r.db.Save(holiday).Error)
+}
+
+// DeleteHoliday soft-deletes a holiday, which frees its date for a new holiday.
+func (r *HolidayGormRepository) DeleteHoliday(holiday *domain.Holiday) error {
+	return r.db.Delete(holiday).Error
+}
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// --- Test Suite Setup for HolidayRepository ---
+
+type HolidayRepositorySuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	repo HolidayRepository
+}
+
+// SetupSuite runs before the tests in the suite are run.
+func (s *HolidayRepositorySuite) SetupSuite() {
+	sqlDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	s.Require().NoError(err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = NewHolidayGormRepository(db)
+}
+
+// TearDownTest runs after each test in the suite.
+func (s *HolidayRepositorySuite) TearDownTest() {
+	s.Require().NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestHolidayRepository runs the test suite.
+func TestHolidayRepository(t *testing.T) {
+	suite.Run(t, new(HolidayRepositorySuite))
+}
+
+// --- Test Cases ---
+
+func (s *HolidayRepositorySuite) TestCreateHoliday() {
+	holidayID := uuid.New()
+	holiday := &domain.Holiday{
+		BaseModel: domain.BaseModel{ID: holidayID},
+		Date:      time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC),
+		Name:      "Hari Kemerdekaan",
+		Type:      domain.HolidayTypeNational,
+	}
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "holidays"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(holidayID))
+				s.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Duplicate date",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "holidays"`)).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_holidays_date"})
+				s.mock.ExpectRollback()
+			},
+			wantErr: ErrDuplicateRecord,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			err := s.repo.CreateHoliday(holiday)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func (s *HolidayRepositorySuite) TestCreateHolidays() {
+	holidays := []domain.Holiday{
+		{BaseModel: domain.BaseModel{ID: uuid.New()}, Date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), Name: "Idul Fitri", Type: domain.HolidayTypeNational},
+		{BaseModel: domain.BaseModel{ID: uuid.New()}, Date: time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC), Name: "Cuti Bersama Idul Fitri", Type: domain.HolidayTypeCollectiveLeave},
+	}
+
+	s.Run("Empty", func() {
+		s.NoError(s.repo.CreateHolidays(nil))
+	})
+
+	s.Run("Success", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "holidays"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(holidays[0].ID).AddRow(holidays[1].ID))
+		s.mock.ExpectCommit()
+
+		s.NoError(s.repo.CreateHolidays(holidays))
+	})
+
+	s.Run("Duplicate date", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "holidays"`)).
+			WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_holidays_date"})
+		s.mock.ExpectRollback()
+
+		s.ErrorIs(s.repo.CreateHolidays(holidays), ErrDuplicateRecord)
+	})
+}
+
+func (s *HolidayRepositorySuite) TestGetHolidayByID() {
+	holidayID := uuid.New()
+	query := `SELECT * FROM "holidays" WHERE id = $1 AND "holidays"."deleted_at" IS NULL ORDER BY "holidays"."id" LIMIT $2`
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+		wantNil bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(holidayID, "Hari Kemerdekaan")
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(holidayID, 1).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(holidayID, 1).WillReturnError(gorm.ErrRecordNotFound)
+			},
+			wantNil: true,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(holidayID, 1).WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			holiday, err := s.repo.GetHolidayByID(holidayID)
+			switch {
+			case tc.wantErr:
+				assert.Error(t, err)
+			case tc.wantNil:
+				assert.NoError(t, err)
+				assert.Nil(t, holiday)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, holidayID, holiday.ID)
+			}
+		})
+	}
+}
+
+func (s *HolidayRepositorySuite) TestGetHolidayByDate() {
+	date := time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)
+	query := `SELECT * FROM "holidays" WHERE date = $1 AND "holidays"."deleted_at" IS NULL ORDER BY "holidays"."id" LIMIT $2`
+
+	s.Run("Success", func() {
+		rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(uuid.New(), "Hari Kemerdekaan")
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("2025-08-17", 1).WillReturnRows(rows)
+
+		holiday, err := s.repo.GetHolidayByDate(date)
+		s.NoError(err)
+		s.Equal("Hari Kemerdekaan", holiday.Name)
+	})
+
+	s.Run("Not Found", func() {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("2025-08-17", 1).WillReturnError(gorm.ErrRecordNotFound)
+
+		holiday, err := s.repo.GetHolidayByDate(date)
+		s.NoError(err)
+		s.Nil(holiday)
+	})
+}
+
+func (s *HolidayRepositorySuite) TestGetHolidaysBetween() {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	query := `SELECT * FROM "holidays" WHERE (date BETWEEN $1 AND $2) AND "holidays"."deleted_at" IS NULL ORDER BY date ASC`
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+		wantLen int
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(uuid.New(), "Tahun Baru").AddRow(uuid.New(), "Hari Kemerdekaan")
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("2025-01-01", "2025-12-31").WillReturnRows(rows)
+			},
+			wantLen: 2,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("2025-01-01", "2025-12-31").WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			holidays, err := s.repo.GetHolidaysBetween(start, end)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, holidays, tc.wantLen)
+			}
+		})
+	}
+}
+
+func (s *HolidayRepositorySuite) TestUpdateHoliday() {
+	holiday := &domain.Holiday{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		Date:      time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC),
+		Name:      "Cuti Bersama Hari Kemerdekaan",
+		Type:      domain.HolidayTypeCollectiveLeave,
+	}
+
+	s.Run("Success", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "holidays"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+
+		s.NoError(s.repo.UpdateHoliday(holiday))
+	})
+
+	s.Run("Duplicate date", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "holidays"`)).
+			WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_holidays_date"})
+		s.mock.ExpectRollback()
+
+		s.ErrorIs(s.repo.UpdateHoliday(holiday), ErrDuplicateRecord)
+	})
+}
+
+func (s *HolidayRepositorySuite) TestDeleteHoliday() {
+	holiday := &domain.Holiday{BaseModel: domain.BaseModel{ID: uuid.New()}}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "holidays" SET "deleted_at"=$1 WHERE "holidays"."id" = $2 AND "holidays"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), holiday.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.repo.DeleteHoliday(holiday))
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// LeaveRepository defines the interface for leave request and leave entitlement data operations.
+//
+//go:generate mockgen -source=leave.repository.go -destination=../../tests/mocks/repository/mock_leave_repository.go -package=mocks
+type LeaveRepository interface {
+	CreateLeaveRequest(leave *domain.LeaveRequest) error
+	GetLeaveRequestByID(id uuid.UUID) (*domain.LeaveRequest, error)
+	GetLeaveRequestsByUserID(userID uuid.UUID) ([]domain.LeaveRequest, error)
+	GetLeaveRequestsByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.LeaveRequest, error)
+	GetOverlappingLeaveRequests(userID uuid.UUID, startDate, endDate time.Time) ([]domain.LeaveRequest, error)
+	GetPendingLeaveRequests() ([]domain.LeaveRequest, error)
+	GetPendingLeaveRequestsByManagerID(managerID uuid.UUID) ([]domain.LeaveRequest, error)
+	GetPendingLeaveRequestsByPeriod(startDate, endDate time.Time) ([]domain.LeaveRequest, error)
+	UpdateLeaveRequest(leave *domain.LeaveRequest) error
+	GetApprovedLeaveRequestsByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.LeaveRequest, error)
+	GetApprovedLeaveRequestsByPeriodGroupedByUser(startDate, endDate time.Time) (map[uuid.UUID][]domain.LeaveRequest, error)
+	GetLeaveEntitlement(userID uuid.UUID, year int, leaveType string) (*domain.LeaveEntitlement, error)
+	GetLeaveEntitlementsByUserIDAndYear(userID uuid.UUID, year int) ([]domain.LeaveEntitlement, error)
+	SaveLeaveEntitlement(entitlement *domain.LeaveEntitlement) error
+}
+
+// LeaveGormRepository implements repository.LeaveRepository using GORM.
+type LeaveGormRepository struct {
+	db *gorm.DB
+}
+
+// NewLeaveGormRepository creates a new LeaveGormRepository.
+func NewLeaveGormRepository(db *gorm.DB) LeaveRepository {
+	return &LeaveGormRepository{db: db}
+}
+
+// CreateLeaveRequest creates a new leave request in the database.
+func (r *LeaveGormRepository) CreateLeaveRequest(leave *domain.LeaveRequest) error {
+	return r.db.Create(leave).Error
+}
+
+// GetLeaveRequestByID retrieves a leave request by its ID.
+func (r *LeaveGormRepository) GetLeaveRequestByID(id uuid.UUID) (*domain.LeaveRequest, error) {
+	var leave domain.LeaveRequest
+	err := r.db.First(&leave, "id = ?", id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &leave, err
+}
+
+// GetLeaveRequestsByUserID retrieves every leave request of a user, the latest first.
+func (r *LeaveGormRepository) GetLeaveRequestsByUserID(userID uuid.UUID) ([]domain.LeaveRequest, error) {
+	var leaves []domain.LeaveRequest
+	err := r.db.
+		Where("user_id = ?", userID).
+		Order("start_date DESC").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// GetLeaveRequestsByUserIDAndPeriod retrieves the leave requests of a user starting within a date
+// range, of any status.
+func (r *LeaveGormRepository) GetLeaveRequestsByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.LeaveRequest, error) {
+	var leaves []domain.LeaveRequest
+	err := r.db.
+		Where("user_id = ? AND start_date BETWEEN ? AND ?", userID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Order("start_date").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// GetOverlappingLeaveRequests retrieves the pending and approved leave requests of a user with at
+// least one day from startDate through endDate.
+func (r *LeaveGormRepository) GetOverlappingLeaveRequests(userID uuid.UUID, startDate, endDate time.Time) ([]domain.LeaveRequest, error) {
+	var leaves []domain.LeaveRequest
+	err := r.db.
+		Where("user_id = ? AND status IN ? AND start_date <= ? AND end_date >= ?",
+			userID, []string{domain.LeaveStatusPending, domain.LeaveStatusApproved},
+			endDate.Format("2006-01-02"), startDate.Format("2006-01-02")).
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// GetPendingLeaveRequests retrieves every pending leave request with its user, oldest first.
+func (r *LeaveGormRepository) GetPendingLeaveRequests() ([]domain.LeaveRequest, error) {
+	var leaves []domain.LeaveRequest
+	err := r.db.
+		Preload("User").
+		Where("status = ?", domain.LeaveStatusPending).
+		Order("start_date").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// GetPendingLeaveRequestsByManagerID retrieves the pending leave requests of the employees who
+// report to a manager, with their users, oldest first.
+func (r *LeaveGormRepository) GetPendingLeaveRequestsByManagerID(managerID uuid.UUID) ([]domain.LeaveRequest, error) {
+	var leaves []domain.LeaveRequest
+	err := r.db.
+		Preload("User").
+		Where("status = ?", domain.LeaveStatusPending).
+		Where("user_id IN (SELECT user_id FROM employee_profiles WHERE manager_id = ? AND deleted_at IS NULL)", managerID).
+		Order("start_date").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// GetPendingLeaveRequestsByPeriod retrieves the pending leave requests with a day within a date range,
+// oldest first.
+func (r *LeaveGormRepository) GetPendingLeaveRequestsByPeriod(startDate, endDate time.Time) ([]domain.LeaveRequest, error) {
+	var leaves []domain.LeaveRequest
+	err := r.db.
+		Where("status = ? AND start_date <= ? AND end_date >= ?", domain.LeaveStatusPending, endDate.Format("2006-01-02"), startDate.Format("2006-01-02")).
+		Order("start_date").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// UpdateLeaveRequest updates an existing leave request.
+func (r *LeaveGormRepository) UpdateLeaveRequest(leave *domain.LeaveRequest) error {
+	return r.db.Omit("User").Save(leave).Error
+}
+
+// GetApprovedLeaveRequestsByUserIDAndPeriod retrieves the approved leave requests of a user with at
+// least one day within a date range.
+func (r *LeaveGormRepository) GetApprovedLeaveRequestsByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.LeaveRequest, error) {
+	var leaves []domain.LeaveRequest
+	err := r.db.
+		Where("user_id = ? AND status = ? AND start_date <= ? AND end_date >= ?",
+			userID, domain.LeaveStatusApproved, endDate.Format("2006-01-02"), startDate.Format("2006-01-02")).
+		Order("start_date").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// GetApprovedLeaveRequestsByPeriodGroupedByUser retrieves the approved leave requests of every user
+// with at least one day within a date range in a single query, grouped by user ID.
+func (r *LeaveGormRepository) GetApprovedLeaveRequestsByPeriodGroupedByUser(startDate, endDate time.Time) (map[uuid.UUID][]domain.LeaveRequest, error) {
+	var leaves []domain.LeaveRequest
+	err := r.db.
+		Where("status = ? AND start_date <= ? AND end_date >= ?",
+			domain.LeaveStatusApproved, endDate.Format("2006-01-02"), startDate.Format("2006-01-02")).
+		Order("user_id, start_date").
+		Find(&leaves).Error
+	if err != nil {
+		return nil, err
+	}
+	return groupByUserID(leaves, func(l domain.LeaveRequest) uuid.UUID { return l.UserID }), nil
+}
+
+// GetLeaveEntitlement retrieves the entitlement of a user for a leave type and year.
+func (r *LeaveGormRepository) GetLeaveEntitlement(userID uuid.UUID, year int, leaveType string) (*domain.LeaveEntitlement, error) {
+	var entitlement domain.LeaveEntitlement
+	err := r.db.Where("user_id = ? AND year = ? AND leave_type = ?", userID, year, leaveType).First(&entitlement).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &entitlement, err
+}
+
+// GetLeaveEntitlementsByUserIDAndYear retrieves the entitlements of a user for a year.
+func (r *LeaveGormRepository) GetLeaveEntitlementsByUserIDAndYear(userID uuid.UUID, year int) ([]domain.LeaveEntitlement, error) {
+	var entitlements []domain.LeaveEntitlement
+	err := r.db.Where("user_id = ? AND year = ?", userID, year).Find(&entitlements).Error
+	return entitlements, err
+}
+
+// SaveLeaveEntitlement creates or updates an entitlement. It returns ErrDuplicateRecord when a new
+// entitlement is for a user, year and leave type that already has one.
+func (r *LeaveGormRepository) SaveLeaveEntitlement(entitlement *domain.LeaveEntitlement) error {
+	return translateError(r.db.Save(entitlement).Error)
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// --- Test Suite Setup for LeaveRepository ---
+
+type LeaveRepositorySuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	repo LeaveRepository
+}
+
+// SetupSuite runs before the tests in the suite are run.
+func (s *LeaveRepositorySuite) SetupSuite() {
+	sqlDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	s.Require().NoError(err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = NewLeaveGormRepository(db)
+}
+
+// TearDownTest runs after each test in the suite.
+func (s *LeaveRepositorySuite) TearDownTest() {
+	s.Require().NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestLeaveRepository runs the test suite.
+func TestLeaveRepository(t *testing.T) {
+	suite.Run(t, new(LeaveRepositorySuite))
+}
+
+// --- Test Cases ---
+
+func (s *LeaveRepositorySuite) TestCreateLeaveRequest() {
+	leaveID := uuid.New()
+	leave := &domain.LeaveRequest{
+		BaseModel: domain.BaseModel{ID: leaveID},
+		UserID:    uuid.New(),
+		LeaveType: domain.LeaveTypeAnnual,
+		StartDate: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC),
+		Days:      3,
+		Status:    domain.LeaveStatusPending,
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "leave_requests"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(leaveID))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.repo.CreateLeaveRequest(leave))
+}
+
+func (s *LeaveRepositorySuite) TestGetLeaveRequestByID() {
+	leaveID := uuid.New()
+	query := `SELECT * FROM "leave_requests" WHERE id = $1 AND "leave_requests"."deleted_at" IS NULL ORDER BY "leave_requests"."id" LIMIT $2`
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+		wantNil bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "status"}).AddRow(leaveID, domain.LeaveStatusPending)
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(leaveID, 1).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(leaveID, 1).WillReturnError(gorm.ErrRecordNotFound)
+			},
+			wantNil: true,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(leaveID, 1).WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			leave, err := s.repo.GetLeaveRequestByID(leaveID)
+			switch {
+			case tc.wantErr:
+				assert.Error(t, err)
+			case tc.wantNil:
+				assert.NoError(t, err)
+				assert.Nil(t, leave)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, leaveID, leave.ID)
+			}
+		})
+	}
+}
+
+func (s *LeaveRepositorySuite) TestGetOverlappingLeaveRequests() {
+	userID := uuid.New()
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	query := `SELECT * FROM "leave_requests" WHERE (user_id = $1 AND status IN ($2,$3) AND start_date <= $4 AND end_date >= $5) AND "leave_requests"."deleted_at" IS NULL`
+
+	rows := sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uuid.New(), userID)
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(userID, domain.LeaveStatusPending, domain.LeaveStatusApproved, "2025-03-14", "2025-03-10").
+		WillReturnRows(rows)
+
+	leaves, err := s.repo.GetOverlappingLeaveRequests(userID, start, end)
+	s.NoError(err)
+	s.Len(leaves, 1)
+}
+
+func (s *LeaveRepositorySuite) TestGetPendingLeaveRequestsByManagerID() {
+	managerID, employeeID := uuid.New(), uuid.New()
+	query := `SELECT * FROM "leave_requests" WHERE status = $1 AND (user_id IN (SELECT user_id FROM employee_profiles WHERE manager_id = $2 AND deleted_at IS NULL)) AND "leave_requests"."deleted_at" IS NULL ORDER BY start_date`
+	usersQuery := `SELECT * FROM "users" WHERE "users"."id" = $1`
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(domain.LeaveStatusPending, managerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uuid.New(), employeeID))
+	s.mock.ExpectQuery(regexp.QuoteMeta(usersQuery)).
+		WithArgs(employeeID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(employeeID, "jdoe"))
+
+	leaves, err := s.repo.GetPendingLeaveRequestsByManagerID(managerID)
+	s.NoError(err)
+	s.Require().Len(leaves, 1)
+	s.Equal("jdoe", leaves[0].User.Username)
+}
+
+func (s *LeaveRepositorySuite) TestGetPendingLeaveRequestsByPeriod() {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	query := `SELECT * FROM "leave_requests" WHERE (status = $1 AND start_date <= $2 AND end_date >= $3) AND "leave_requests"."deleted_at" IS NULL ORDER BY start_date`
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(domain.LeaveStatusPending, "2025-03-31", "2025-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uuid.New(), uuid.New()))
+
+	leaves, err := s.repo.GetPendingLeaveRequestsByPeriod(start, end)
+	s.NoError(err)
+	s.Len(leaves, 1)
+}
+
+func (s *LeaveRepositorySuite) TestGetApprovedLeaveRequestsByPeriodGroupedByUser() {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	query := `SELECT * FROM "leave_requests" WHERE (status = $1 AND start_date <= $2 AND end_date >= $3) AND "leave_requests"."deleted_at" IS NULL ORDER BY user_id, start_date`
+
+	s.Run("Success", func() {
+		alice, bob := uuid.New(), uuid.New()
+		rows := sqlmock.NewRows([]string{"id", "user_id"}).
+			AddRow(uuid.New(), alice).
+			AddRow(uuid.New(), alice).
+			AddRow(uuid.New(), bob)
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs(domain.LeaveStatusApproved, "2025-03-31", "2025-03-01").
+			WillReturnRows(rows)
+
+		grouped, err := s.repo.GetApprovedLeaveRequestsByPeriodGroupedByUser(start, end)
+		s.NoError(err)
+		s.Len(grouped[alice], 2)
+		s.Len(grouped[bob], 1)
+	})
+
+	s.Run("DB Error", func() {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(errors.New("db error"))
+
+		_, err := s.repo.GetApprovedLeaveRequestsByPeriodGroupedByUser(start, end)
+		s.Error(err)
+	})
+}
+
+func (s *LeaveRepositorySuite) TestGetLeaveEntitlement() {
+	userID := uuid.New()
+	query := `SELECT * FROM "leave_entitlements" WHERE (user_id = $1 AND year = $2 AND leave_type = $3) AND "leave_entitlements"."deleted_at" IS NULL ORDER BY "leave_entitlements"."id" LIMIT $4`
+
+	s.Run("Success", func() {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "year", "leave_type", "days"}).
+			AddRow(uuid.New(), userID, 2025, domain.LeaveTypeAnnual, "15")
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID, 2025, domain.LeaveTypeAnnual, 1).WillReturnRows(rows)
+
+		entitlement, err := s.repo.GetLeaveEntitlement(userID, 2025, domain.LeaveTypeAnnual)
+		s.NoError(err)
+		s.True(entitlement.Days.Equal(decimal.NewFromInt(15)))
+	})
+
+	s.Run("Not Found", func() {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID, 2025, domain.LeaveTypeSick, 1).WillReturnError(gorm.ErrRecordNotFound)
+
+		entitlement, err := s.repo.GetLeaveEntitlement(userID, 2025, domain.LeaveTypeSick)
+		s.NoError(err)
+		s.Nil(entitlement)
+	})
+}
+
+func (s *LeaveRepositorySuite) TestSaveLeaveEntitlement() {
+	s.Run("Duplicate entitlement", func() {
+		entitlement := &domain.LeaveEntitlement{
+			UserID:    uuid.New(),
+			Year:      2025,
+			LeaveType: domain.LeaveTypeAnnual,
+			Days:      decimal.NewFromInt(15),
+		}
+
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "leave_entitlements"`)).
+			WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_leave_entitlements_user_year_type"})
+		s.mock.ExpectRollback()
+
+		s.ErrorIs(s.repo.SaveLeaveEntitlement(entitlement), ErrDuplicateRecord)
+	})
+}
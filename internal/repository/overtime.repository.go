@@ -16,10 +16,18 @@ type OvertimeRepository interface {
 	CreateOvertime(overtime *domain.Overtime) (*domain.Overtime, error)
 	GetOvertimeByID(id uuid.UUID) (*domain.Overtime, error)
 	GetOvertimeByUserIDAndDate(userID uuid.UUID, date time.Time) ([]domain.Overtime, error)
-	GetOvertimesByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.Overtime, error)
+	GetApprovedOvertimesByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.Overtime, error)
 	GetOvertimesByUserIDAndPayrollPeriodID(userID uuid.UUID, payrollPeriodID uuid.UUID) ([]*domain.Overtime, error)
+	GetOvertimeHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Overtime, int64, error)
 	UpdateOvertime(overtime *domain.Overtime) error
-	UpdateOvertimesTx(tx *gorm.DB, overtimes []domain.Overtime) error
+	GetPendingOvertimes() ([]domain.Overtime, error)
+	GetPendingOvertimesByManagerID(managerID uuid.UUID) ([]domain.Overtime, error)
+	GetPendingOvertimesByPeriod(startDate, endDate time.Time) ([]domain.Overtime, error)
+	GetOvertimesByPayrollPeriodID(payrollPeriodID uuid.UUID) ([]domain.Overtime, error)
+	DetachOvertimesFromPayrollPeriod(payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error
+	GetApprovedOvertimesByPeriodGroupedByUser(startDate, endDate time.Time) (map[uuid.UUID][]domain.Overtime, error)
+	GetOvertimesByPayrollPeriodIDGroupedByUser(payrollPeriodID uuid.UUID) (map[uuid.UUID][]*domain.Overtime, error)
+	AttachOvertimesToPayrollPeriod(ids []uuid.UUID, payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error
 }
 
 // OvertimeGormRepository implements repository.OvertimeRepository using GORM.
@@ -54,10 +62,11 @@ func (r *OvertimeGormRepository) GetOvertimeByUserIDAndDate(userID uuid.UUID, da
 	return overtimes, err
 }
 
-// GetOvertimesByUserIDAndPeriod retrieves overtime records for a user within a date range.
-func (r *OvertimeGormRepository) GetOvertimesByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.Overtime, error) {
+// GetApprovedOvertimesByUserIDAndPeriod retrieves the approved overtime records for a user within a date range.
+func (r *OvertimeGormRepository) GetApprovedOvertimesByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.Overtime, error) {
 	var overtimes []domain.Overtime
-	err := r.db.Where("user_id = ? AND date >= ? AND date <= ?", userID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).Find(&overtimes).Error
+	err := r.db.Where("user_id = ? AND status = ? AND date >= ? AND date <= ?",
+		userID, domain.OvertimeStatusApproved, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).Find(&overtimes).Error
 	return overtimes, err
 }
 
@@ -68,18 +77,108 @@ func (r *OvertimeGormRepository) GetOvertimesByUserIDAndPayrollPeriodID(userID u
 	return overtimes, err
 }
 
+// GetOvertimeHistory retrieves a page of the overtime records of a user, latest first, together with
+// the number of records matching the filter.
+func (r *OvertimeGormRepository) GetOvertimeHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Overtime, int64, error) {
+	return findHistoryPage[domain.Overtime](filterHistory(r.db, userID, filter, "date"), filter, "date DESC, created_at DESC")
+}
+
 // UpdateOvertime updates an existing overtime record in the database.
 func (r *OvertimeGormRepository) UpdateOvertime(overtime *domain.Overtime) error {
 	return r.db.Save(overtime).Error
 }
 
-// UpdateOvertimesTx updates multiple overtime records within the given transaction.
-func (r *OvertimeGormRepository) UpdateOvertimesTx(tx *gorm.DB, overtimes []domain.Overtime) error {
-	if tx == nil {
-		return gorm.ErrInvalidDB
+// GetPendingOvertimes retrieves every pending overtime record with its user, oldest first.
+func (r *OvertimeGormRepository) GetPendingOvertimes() ([]domain.Overtime, error) {
+	var overtimes []domain.Overtime
+	err := r.db.
+		Preload("User").
+		Where("status = ?", domain.OvertimeStatusPending).
+		Order("date").
+		Find(&overtimes).Error
+	return overtimes, err
+}
+
+// GetPendingOvertimesByManagerID retrieves the pending overtime records of the employees who
+// report to a manager, with their users, oldest first.
+func (r *OvertimeGormRepository) GetPendingOvertimesByManagerID(managerID uuid.UUID) ([]domain.Overtime, error) {
+	var overtimes []domain.Overtime
+	err := r.db.
+		Preload("User").
+		Where("status = ?", domain.OvertimeStatusPending).
+		Where("user_id IN (SELECT user_id FROM employee_profiles WHERE manager_id = ? AND deleted_at IS NULL)", managerID).
+		Order("date").
+		Find(&overtimes).Error
+	return overtimes, err
+}
+
+// GetPendingOvertimesByPeriod retrieves the pending overtime records dated within a date range, oldest first.
+func (r *OvertimeGormRepository) GetPendingOvertimesByPeriod(startDate, endDate time.Time) ([]domain.Overtime, error) {
+	var overtimes []domain.Overtime
+	err := r.db.
+		Where("status = ? AND date >= ? AND date <= ?", domain.OvertimeStatusPending, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Order("date").
+		Find(&overtimes).Error
+	return overtimes, err
+}
+
+// GetOvertimesByPayrollPeriodID retrieves all overtime records attached to a payroll period.
+func (r *OvertimeGormRepository) GetOvertimesByPayrollPeriodID(payrollPeriodID uuid.UUID) ([]domain.Overtime, error) {
+	var overtimes []domain.Overtime
+	err := r.db.Where("payroll_period_id = ?", payrollPeriodID).Find(&overtimes).Error
+	return overtimes, err
+}
+
+// DetachOvertimesFromPayrollPeriod clears the payroll period of every overtime record attached to it.
+func (r *OvertimeGormRepository) DetachOvertimesFromPayrollPeriod(payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error {
+	return r.db.Model(&domain.Overtime{}).
+		Where("payroll_period_id = ?", payrollPeriodID).
+		Updates(map[string]interface{}{
+			"payroll_period_id": nil,
+			"updated_by":        updatedBy,
+			"ip_address":        ipAddress,
+		}).Error
+}
+
+// GetApprovedOvertimesByPeriodGroupedByUser retrieves the approved overtime records of every user
+// within a date range in a single query, grouped by user ID.
+func (r *OvertimeGormRepository) GetApprovedOvertimesByPeriodGroupedByUser(startDate, endDate time.Time) (map[uuid.UUID][]domain.Overtime, error) {
+	var overtimes []domain.Overtime
+	err := r.db.
+		Where("status = ? AND date >= ? AND date <= ?", domain.OvertimeStatusApproved, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Order("user_id, date").
+		Find(&overtimes).Error
+	if err != nil {
+		return nil, err
+	}
+	return groupByUserID(overtimes, func(o domain.Overtime) uuid.UUID { return o.UserID }), nil
+}
+
+// GetOvertimesByPayrollPeriodIDGroupedByUser retrieves every overtime record attached to a payroll period
+// in a single query, grouped by user ID.
+func (r *OvertimeGormRepository) GetOvertimesByPayrollPeriodIDGroupedByUser(payrollPeriodID uuid.UUID) (map[uuid.UUID][]*domain.Overtime, error) {
+	overtimes := make([]*domain.Overtime, 0)
+	err := r.db.
+		Where("payroll_period_id = ?", payrollPeriodID).
+		Order("user_id, date").
+		Find(&overtimes).Error
+	if err != nil {
+		return nil, err
 	}
-	for _, overtime := range overtimes {
-		if err := tx.Save(&overtime).Error; err != nil {
+	return groupByUserID(overtimes, func(o *domain.Overtime) uuid.UUID { return o.UserID }), nil
+}
+
+// AttachOvertimesToPayrollPeriod attaches the given overtime records to a payroll period,
+// using one UPDATE per BatchSize records.
+func (r *OvertimeGormRepository) AttachOvertimesToPayrollPeriod(ids []uuid.UUID, payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error {
+	for _, chunk := range chunkIDs(ids, BatchSize) {
+		if err := r.db.Model(&domain.Overtime{}).
+			Where("id IN ?", chunk).
+			Updates(map[string]interface{}{
+				"payroll_period_id": payrollPeriodID,
+				"updated_by":        updatedBy,
+				"ip_address":        ipAddress,
+			}).Error; err != nil {
 			return err
 		}
 	}
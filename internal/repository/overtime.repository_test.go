@@ -72,11 +72,12 @@ func (s *OvertimeRepositorySuite) TestCreateOvertime() {
 				UserID:    userID,
 				Date:      now,
 				Hours:     2.5,
+				Status:    domain.OvertimeStatusPending,
 			},
 			mock: func() {
 				s.mock.ExpectBegin()
-				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "overtimes" ("created_at","updated_at","deleted_at","created_by","updated_by","ip_address","user_id","date","hours","payroll_period_id","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11) RETURNING "id"`)).
-					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), userID, now, 2.5, nil, overtimeID).
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "overtimes" ("created_at","updated_at","deleted_at","created_by","updated_by","ip_address","user_id","date","hours","status","reviewed_by","reviewed_at","review_note","payroll_period_id","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15) RETURNING "id"`)).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), userID, now, 2.5, domain.OvertimeStatusPending, nil, nil, "", nil, overtimeID).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(overtimeID))
 				s.mock.ExpectCommit()
 			},
@@ -226,7 +227,7 @@ func (s *OvertimeRepositorySuite) TestGetOvertimeByUserIDAndDate() {
 	}
 }
 
-func (s *OvertimeRepositorySuite) TestGetOvertimesByUserIDAndPeriod() {
+func (s *OvertimeRepositorySuite) TestGetApprovedOvertimesByUserIDAndPeriod() {
 	userID := uuid.New()
 	startDate := time.Now().Add(-5 * 24 * time.Hour)
 	endDate := time.Now()
@@ -244,8 +245,8 @@ func (s *OvertimeRepositorySuite) TestGetOvertimesByUserIDAndPeriod() {
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id", "user_id"}).
 					AddRow(uuid.New(), userID)
-				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "overtimes" WHERE (user_id = $1 AND date >= $2 AND date <= $3) AND "overtimes"."deleted_at" IS NULL`)).
-					WithArgs(userID, startDateStr, endDateStr).
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "overtimes" WHERE (user_id = $1 AND status = $2 AND date >= $3 AND date <= $4) AND "overtimes"."deleted_at" IS NULL`)).
+					WithArgs(userID, domain.OvertimeStatusApproved, startDateStr, endDateStr).
 					WillReturnRows(rows)
 			},
 			wantLen: 1,
@@ -254,8 +255,8 @@ func (s *OvertimeRepositorySuite) TestGetOvertimesByUserIDAndPeriod() {
 		{
 			name: "DB Error",
 			mock: func() {
-				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "overtimes" WHERE (user_id = $1 AND date >= $2 AND date <= $3) AND "overtimes"."deleted_at" IS NULL`)).
-					WithArgs(userID, startDateStr, endDateStr).
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "overtimes" WHERE (user_id = $1 AND status = $2 AND date >= $3 AND date <= $4) AND "overtimes"."deleted_at" IS NULL`)).
+					WithArgs(userID, domain.OvertimeStatusApproved, startDateStr, endDateStr).
 					WillReturnError(errors.New("db error"))
 			},
 			wantLen: 0,
@@ -266,7 +267,7 @@ func (s *OvertimeRepositorySuite) TestGetOvertimesByUserIDAndPeriod() {
 	for _, tc := range testCases {
 		s.T().Run(tc.name, func(t *testing.T) {
 			tc.mock()
-			overtimes, err := s.repo.GetOvertimesByUserIDAndPeriod(userID, startDate, endDate)
+			overtimes, err := s.repo.GetApprovedOvertimesByUserIDAndPeriod(userID, startDate, endDate)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -370,49 +371,131 @@ func (s *OvertimeRepositorySuite) TestUpdateOvertime() {
 	}
 }
 
-func (s *OvertimeRepositorySuite) TestUpdateOvertimesTx() {
-	overtimes := []domain.Overtime{
-		{BaseModel: domain.BaseModel{ID: uuid.New()}},
-	}
+func (s *OvertimeRepositorySuite) TestGetPendingOvertimes() {
+	employeeID := uuid.New()
+	query := `SELECT * FROM "overtimes" WHERE status = $1 AND "overtimes"."deleted_at" IS NULL ORDER BY date`
+	usersQuery := `SELECT * FROM "users" WHERE "users"."id" = $1`
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(domain.OvertimeStatusPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uuid.New(), employeeID))
+	s.mock.ExpectQuery(regexp.QuoteMeta(usersQuery)).
+		WithArgs(employeeID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(employeeID, "jdoe"))
+
+	overtimes, err := s.repo.GetPendingOvertimes()
+	s.NoError(err)
+	s.Require().Len(overtimes, 1)
+	s.Equal("jdoe", overtimes[0].User.Username)
+}
+
+func (s *OvertimeRepositorySuite) TestGetPendingOvertimesByManagerID() {
+	managerID, employeeID := uuid.New(), uuid.New()
+	query := `SELECT * FROM "overtimes" WHERE status = $1 AND (user_id IN (SELECT user_id FROM employee_profiles WHERE manager_id = $2 AND deleted_at IS NULL)) AND "overtimes"."deleted_at" IS NULL ORDER BY date`
+	usersQuery := `SELECT * FROM "users" WHERE "users"."id" = $1`
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(domain.OvertimeStatusPending, managerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uuid.New(), employeeID))
+	s.mock.ExpectQuery(regexp.QuoteMeta(usersQuery)).
+		WithArgs(employeeID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(employeeID, "jdoe"))
+
+	overtimes, err := s.repo.GetPendingOvertimesByManagerID(managerID)
+	s.NoError(err)
+	s.Require().Len(overtimes, 1)
+	s.Equal("jdoe", overtimes[0].User.Username)
+}
+
+func (s *OvertimeRepositorySuite) TestGetPendingOvertimesByPeriod() {
+	startDate := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+	query := `SELECT * FROM "overtimes" WHERE (status = $1 AND date >= $2 AND date <= $3) AND "overtimes"."deleted_at" IS NULL ORDER BY date`
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(domain.OvertimeStatusPending, "2025-08-01", "2025-08-31").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uuid.New(), uuid.New()))
+
+	overtimes, err := s.repo.GetPendingOvertimesByPeriod(startDate, endDate)
+	s.NoError(err)
+	s.Len(overtimes, 1)
+}
+
+func (s *OvertimeRepositorySuite) TestGetOvertimesByPayrollPeriodID() {
+	periodID := uuid.New()
 
 	testCases := []struct {
-		name     string
-		mock     func()
-		wantErr  bool
-		useNilTx bool
+		name    string
+		mock    func()
+		wantErr bool
+		wantLen int
 	}{
 		{
 			name: "Success",
 			mock: func() {
-				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "overtimes" SET`)).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+				rows := sqlmock.NewRows([]string{"id", "payroll_period_id"}).AddRow(uuid.New(), periodID).AddRow(uuid.New(), periodID)
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "overtimes" WHERE payroll_period_id = $1 AND "overtimes"."deleted_at" IS NULL`)).
+					WithArgs(periodID).
+					WillReturnRows(rows)
 			},
 			wantErr: false,
+			wantLen: 2,
 		},
 		{
 			name: "DB Error",
 			mock: func() {
-				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "overtimes" SET`)).
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "overtimes" WHERE payroll_period_id = $1`)).
+					WithArgs(periodID).
 					WillReturnError(errors.New("db error"))
 			},
 			wantErr: true,
 		},
-		{
-			name:     "Nil Transaction",
-			mock:     func() {},
-			wantErr:  true,
-			useNilTx: true,
-		},
 	}
 
 	for _, tc := range testCases {
 		s.T().Run(tc.name, func(t *testing.T) {
-			if tc.useNilTx {
-				err := s.repo.UpdateOvertimesTx(nil, overtimes)
+			tc.mock()
+			records, err := s.repo.GetOvertimesByPayrollPeriodID(periodID)
+			if tc.wantErr {
 				assert.Error(t, err)
-				return
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, records, tc.wantLen)
 			}
+		})
+	}
+}
+
+func (s *OvertimeRepositorySuite) TestDetachOvertimesFromPayrollPeriod() {
+	periodID := uuid.New()
+	updatedBy := uuid.New()
 
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "overtimes" SET "ip_address"=$1,"payroll_period_id"=$2,"updated_by"=$3,"updated_at"=$4 WHERE payroll_period_id = $5 AND "overtimes"."deleted_at" IS NULL`)).
+					WithArgs("127.0.0.1", nil, updatedBy, sqlmock.AnyArg(), periodID).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			wantErr: false,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "overtimes" SET`)).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
 			s.mock.ExpectBegin()
 			tc.mock()
 			if tc.wantErr {
@@ -422,7 +505,7 @@ func (s *OvertimeRepositorySuite) TestUpdateOvertimesTx() {
 			}
 
 			err := s.db.Transaction(func(tx *gorm.DB) error {
-				return s.repo.UpdateOvertimesTx(tx, overtimes)
+				return NewOvertimeGormRepository(tx).DetachOvertimesFromPayrollPeriod(periodID, updatedBy, "127.0.0.1")
 			})
 
 			if tc.wantErr {
@@ -433,3 +516,83 @@ func (s *OvertimeRepositorySuite) TestUpdateOvertimesTx() {
 		})
 	}
 }
+
+func (s *OvertimeRepositorySuite) TestGetApprovedOvertimesByPeriodGroupedByUser() {
+	userA := uuid.New()
+	userB := uuid.New()
+	startDate := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "hours"}).
+		AddRow(uuid.New(), userA, 2.0).
+		AddRow(uuid.New(), userB, 1.5)
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "overtimes" WHERE (status = $1 AND date >= $2 AND date <= $3) AND "overtimes"."deleted_at" IS NULL ORDER BY user_id, date`)).
+		WithArgs(domain.OvertimeStatusApproved, "2025-08-01", "2025-08-31").
+		WillReturnRows(rows)
+
+	grouped, err := s.repo.GetApprovedOvertimesByPeriodGroupedByUser(startDate, endDate)
+	s.NoError(err)
+	s.Len(grouped, 2)
+	s.Equal(1.5, grouped[userB][0].Hours)
+}
+
+func (s *OvertimeRepositorySuite) TestGetOvertimesByPayrollPeriodIDGroupedByUser() {
+	periodID := uuid.New()
+	userID := uuid.New()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "overtimes" WHERE payroll_period_id = $1 AND "overtimes"."deleted_at" IS NULL ORDER BY user_id, date`)).
+		WithArgs(periodID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uuid.New(), userID))
+
+	grouped, err := s.repo.GetOvertimesByPayrollPeriodIDGroupedByUser(periodID)
+	s.NoError(err)
+	s.Len(grouped[userID], 1)
+}
+
+func (s *OvertimeRepositorySuite) TestAttachOvertimesToPayrollPeriod() {
+	periodID := uuid.New()
+	updatedBy := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	s.T().Run("Success", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "overtimes" SET "ip_address"=$1,"payroll_period_id"=$2,"updated_by"=$3,"updated_at"=$4 WHERE id IN ($5,$6) AND "overtimes"."deleted_at" IS NULL`)).
+			WithArgs("127.0.0.1", periodID, updatedBy, sqlmock.AnyArg(), ids[0], ids[1]).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		s.mock.ExpectCommit()
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			return NewOvertimeGormRepository(tx).AttachOvertimesToPayrollPeriod(ids, periodID, updatedBy, "127.0.0.1")
+		})
+		assert.NoError(t, err)
+	})
+
+	s.T().Run("No records", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectCommit()
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			return NewOvertimeGormRepository(tx).AttachOvertimesToPayrollPeriod(nil, periodID, updatedBy, "127.0.0.1")
+		})
+		assert.NoError(t, err)
+	})
+
+}
+
+func (s *OvertimeRepositorySuite) TestGetOvertimeHistory() {
+	userID := uuid.New()
+	countQuery := `SELECT count(*) FROM "overtimes" WHERE user_id = $1 AND "overtimes"."deleted_at" IS NULL`
+	pageQuery := `SELECT * FROM "overtimes" WHERE user_id = $1 AND "overtimes"."deleted_at" IS NULL ORDER BY date DESC, created_at DESC LIMIT $2`
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(countQuery)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(regexp.QuoteMeta(pageQuery)).
+		WithArgs(userID, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "hours"}).AddRow(uuid.New(), userID, 2.0).AddRow(uuid.New(), userID, 1.5))
+
+	overtimes, total, err := s.repo.GetOvertimeHistory(userID, domain.HistoryFilter{Page: 1, PageSize: 20})
+	s.NoError(err)
+	s.Len(overtimes, 2)
+	s.Equal(int64(2), total)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"payroll-system/internal/domain"
 )
@@ -16,12 +17,14 @@ import (
 type PayrollPeriodRepository interface {
 	CreatePayrollPeriod(period *domain.PayrollPeriod) error
 	GetPayrollPeriodByID(id uuid.UUID) (*domain.PayrollPeriod, error)
+	LockPayrollPeriodByID(id uuid.UUID) (*domain.PayrollPeriod, error)
 	GetActivePayrollPeriod() (*domain.PayrollPeriod, error)
-	MarkPayrollPeriodAsProcessed(id uuid.UUID) error
 	GetAllPayrollPeriods() ([]domain.PayrollPeriod, error)
 	GetPayrollPeriodByDates(startDate, endDate time.Time) (*domain.PayrollPeriod, error)
-	MarkPayrollPeriodAsProcessedTx(tx *gorm.DB, periodID uuid.UUID) error
+	MarkPayrollPeriodAsProcessed(periodID uuid.UUID) error
+	MarkPayrollPeriodAsUnprocessed(periodID uuid.UUID) error
 	GetOverlappingPayrollPeriods(startDate, endDate time.Time) ([]domain.PayrollPeriod, error)
+	LockOverlappingPayrollPeriods(startDate, endDate time.Time) ([]domain.PayrollPeriod, error)
 }
 
 // PayrollPeriodGormRepository implements repository.PayrollPeriodRepository using GORM.
@@ -49,6 +52,22 @@ func (r *PayrollPeriodGormRepository) GetPayrollPeriodByID(id uuid.UUID) (*domai
 	return &period, err
 }
 
+// LockPayrollPeriodByID retrieves a payroll period by its ID and locks its row until the end of the
+// current transaction (SELECT ... FOR UPDATE NOWAIT). It must be called inside a transaction.
+// If another transaction already holds the lock it fails immediately with ErrRecordLocked
+// instead of waiting for it.
+func (r *PayrollPeriodGormRepository) LockPayrollPeriodByID(id uuid.UUID) (*domain.PayrollPeriod, error) {
+	var period domain.PayrollPeriod
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).First(&period, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &period, nil
+}
+
 // GetActivePayrollPeriod retrieves the currently active (not processed) payroll period.
 func (r *PayrollPeriodGormRepository) GetActivePayrollPeriod() (*domain.PayrollPeriod, error) {
 	var period domain.PayrollPeriod
@@ -59,15 +78,6 @@ func (r *PayrollPeriodGormRepository) GetActivePayrollPeriod() (*domain.PayrollP
 	return &period, err
 }
 
-// MarkPayrollPeriodAsProcessed updates a payroll period's status to processed.
-func (r *PayrollPeriodGormRepository) MarkPayrollPeriodAsProcessed(id uuid.UUID) error {
-	now := time.Now()
-	return r.db.Model(&domain.PayrollPeriod{}).Where("id = ?", id).Updates(map[string]interface{}{
-		"is_processed": true,
-		"processed_at": &now,
-	}).Error
-}
-
 // GetAllPayrollPeriods retrieves all payroll periods.
 func (r *PayrollPeriodGormRepository) GetAllPayrollPeriods() ([]domain.PayrollPeriod, error) {
 	var periods []domain.PayrollPeriod
@@ -85,9 +95,9 @@ func (r *PayrollPeriodGormRepository) GetPayrollPeriodByDates(startDate, endDate
 	return &period, err
 }
 
-// MarkPayrollPeriodAsProcessedTx marks a payroll period as processed within a transaction.
-func (r *PayrollPeriodGormRepository) MarkPayrollPeriodAsProcessedTx(tx *gorm.DB, periodID uuid.UUID) error {
-	result := tx.Model(&domain.PayrollPeriod{}).
+// MarkPayrollPeriodAsProcessed marks a payroll period as processed.
+func (r *PayrollPeriodGormRepository) MarkPayrollPeriodAsProcessed(periodID uuid.UUID) error {
+	result := r.db.Model(&domain.PayrollPeriod{}).
 		Where("id = ? AND is_processed = ?", periodID, false).
 		Updates(map[string]interface{}{
 			"is_processed": true,
@@ -104,6 +114,23 @@ func (r *PayrollPeriodGormRepository) MarkPayrollPeriodAsProcessedTx(tx *gorm.DB
 	return nil
 }
 
+// MarkPayrollPeriodAsUnprocessed reopens a processed payroll period.
+func (r *PayrollPeriodGormRepository) MarkPayrollPeriodAsUnprocessed(periodID uuid.UUID) error {
+	result := r.db.Model(&domain.PayrollPeriod{}).
+		Where("id = ? AND is_processed = ?", periodID, true).
+		Updates(map[string]interface{}{
+			"is_processed": false,
+			"processed_at": nil,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark payroll period as unprocessed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no payroll period updated, maybe not processed or not found")
+	}
+	return nil
+}
+
 // GetOverlappingPayrollPeriods retrieves payroll periods that overlap with the given date range.
 // Overlap means: (period.StartDate <= endDate) AND (period.EndDate >= startDate).
 func (r *PayrollPeriodGormRepository) GetOverlappingPayrollPeriods(startDate, endDate time.Time) ([]domain.PayrollPeriod, error) {
@@ -119,3 +146,21 @@ func (r *PayrollPeriodGormRepository) GetOverlappingPayrollPeriods(startDate, en
 
 	return periods, nil
 }
+
+// LockOverlappingPayrollPeriods retrieves the payroll periods with a day from startDate through
+// endDate, ordered by start date, and takes a shared lock on their rows until the end of the current
+// transaction (SELECT ... FOR SHARE). It must be called inside a transaction. The calendar days of the
+// dates are compared, in their location. A transaction holding the lock waits for a payroll run that
+// locked one of the periods for update to finish, and a run started meanwhile fails with
+// ErrRecordLocked, so a change checked against a period never lands while the period is processed.
+func (r *PayrollPeriodGormRepository) LockOverlappingPayrollPeriods(startDate, endDate time.Time) ([]domain.PayrollPeriod, error) {
+	var periods []domain.PayrollPeriod
+	err := r.db.Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("start_date <= ? AND end_date >= ?", endDate.Format("2006-01-02"), startDate.Format("2006-01-02")).
+		Order("start_date").
+		Find(&periods).Error
+	if err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
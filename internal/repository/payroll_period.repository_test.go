@@ -8,6 +8,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/postgres"
@@ -162,32 +163,41 @@ func (s *PayrollPeriodRepositorySuite) TestGetPayrollPeriodByID() {
 	}
 }
 
-func (s *PayrollPeriodRepositorySuite) TestGetActivePayrollPeriod() {
+func (s *PayrollPeriodRepositorySuite) TestLockPayrollPeriodByID() {
+	periodID := uuid.New()
+	query := regexp.QuoteMeta(`SELECT * FROM "payroll_periods" WHERE "payroll_periods"."id" = $1 AND "payroll_periods"."deleted_at" IS NULL ORDER BY "payroll_periods"."id" LIMIT $2 FOR UPDATE NOWAIT`)
+
 	testCases := []struct {
 		name    string
 		mock    func()
-		wantErr bool
+		wantErr error
 		wantNil bool
 	}{
 		{
 			name: "Success",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id"}).AddRow(uuid.New())
-				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payroll_periods" WHERE is_processed = $1 AND "payroll_periods"."deleted_at" IS NULL ORDER BY start_date ASC,"payroll_periods"."id" LIMIT $2`)).
-					WithArgs(false, 1).
-					WillReturnRows(rows)
+				s.mock.ExpectQuery(query).
+					WithArgs(periodID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(periodID))
 			},
-			wantErr: false,
-			wantNil: false,
 		},
 		{
 			name: "Not Found",
 			mock: func() {
-				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payroll_periods" WHERE is_processed = $1 AND "payroll_periods"."deleted_at" IS NULL ORDER BY start_date ASC,"payroll_periods"."id" LIMIT $2`)).
-					WithArgs(false, 1).
+				s.mock.ExpectQuery(query).
+					WithArgs(periodID, 1).
 					WillReturnError(gorm.ErrRecordNotFound)
 			},
-			wantErr: false,
+			wantNil: true,
+		},
+		{
+			name: "Locked By Another Transaction",
+			mock: func() {
+				s.mock.ExpectQuery(query).
+					WithArgs(periodID, 1).
+					WillReturnError(&pgconn.PgError{Code: "55P03", Message: "could not obtain lock on row in relation \"payroll_periods\""})
+			},
+			wantErr: ErrRecordLocked,
 			wantNil: true,
 		},
 	}
@@ -195,61 +205,65 @@ func (s *PayrollPeriodRepositorySuite) TestGetActivePayrollPeriod() {
 	for _, tc := range testCases {
 		s.T().Run(tc.name, func(t *testing.T) {
 			tc.mock()
-			period, err := s.repo.GetActivePayrollPeriod()
-			if tc.wantErr {
-				assert.Error(t, err)
+			period, err := s.repo.LockPayrollPeriodByID(periodID)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
 			} else {
 				assert.NoError(t, err)
 			}
 			if tc.wantNil {
 				assert.Nil(t, period)
 			} else {
-				assert.NotNil(t, period)
+				assert.Equal(t, periodID, period.ID)
 			}
 		})
 	}
 }
 
-func (s *PayrollPeriodRepositorySuite) TestMarkPayrollPeriodAsProcessed() {
-	periodID := uuid.New()
-
+func (s *PayrollPeriodRepositorySuite) TestGetActivePayrollPeriod() {
 	testCases := []struct {
 		name    string
 		mock    func()
 		wantErr bool
+		wantNil bool
 	}{
 		{
 			name: "Success",
 			mock: func() {
-				s.mock.ExpectBegin()
-				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payroll_periods" SET "is_processed"=$1,"processed_at"=$2,"updated_at"=$3 WHERE id = $4 AND "payroll_periods"."deleted_at" IS NULL`)).
-					WithArgs(true, sqlmock.AnyArg(), sqlmock.AnyArg(), periodID).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-				s.mock.ExpectCommit()
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(uuid.New())
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payroll_periods" WHERE is_processed = $1 AND "payroll_periods"."deleted_at" IS NULL ORDER BY start_date ASC,"payroll_periods"."id" LIMIT $2`)).
+					WithArgs(false, 1).
+					WillReturnRows(rows)
 			},
 			wantErr: false,
+			wantNil: false,
 		},
 		{
-			name: "DB Error",
+			name: "Not Found",
 			mock: func() {
-				s.mock.ExpectBegin()
-				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payroll_periods" SET`)).
-					WillReturnError(errors.New("db error"))
-				s.mock.ExpectRollback()
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payroll_periods" WHERE is_processed = $1 AND "payroll_periods"."deleted_at" IS NULL ORDER BY start_date ASC,"payroll_periods"."id" LIMIT $2`)).
+					WithArgs(false, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
 			},
-			wantErr: true,
+			wantErr: false,
+			wantNil: true,
 		},
 	}
 
 	for _, tc := range testCases {
 		s.T().Run(tc.name, func(t *testing.T) {
 			tc.mock()
-			err := s.repo.MarkPayrollPeriodAsProcessed(periodID)
+			period, err := s.repo.GetActivePayrollPeriod()
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			if tc.wantNil {
+				assert.Nil(t, period)
+			} else {
+				assert.NotNil(t, period)
+			}
 		})
 	}
 }
@@ -347,7 +361,7 @@ func (s *PayrollPeriodRepositorySuite) TestGetPayrollPeriodByDates() {
 	}
 }
 
-func (s *PayrollPeriodRepositorySuite) TestMarkPayrollPeriodAsProcessedTx() {
+func (s *PayrollPeriodRepositorySuite) TestMarkPayrollPeriodAsProcessed() {
 	periodID := uuid.New()
 
 	testCases := []struct {
@@ -398,7 +412,7 @@ func (s *PayrollPeriodRepositorySuite) TestMarkPayrollPeriodAsProcessedTx() {
 			}
 
 			err := s.db.Transaction(func(tx *gorm.DB) error {
-				return s.repo.MarkPayrollPeriodAsProcessedTx(tx, periodID)
+				return NewPayrollPeriodGormRepository(tx).MarkPayrollPeriodAsProcessed(periodID)
 			})
 
 			if tc.wantErr {
@@ -413,6 +427,68 @@ func (s *PayrollPeriodRepositorySuite) TestMarkPayrollPeriodAsProcessedTx() {
 	}
 }
 
+func (s *PayrollPeriodRepositorySuite) TestMarkPayrollPeriodAsUnprocessed() {
+	periodID := uuid.New()
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payroll_periods" SET "is_processed"=$1,"processed_at"=$2,"updated_at"=$3 WHERE (id = $4 AND is_processed = $5) AND "payroll_periods"."deleted_at" IS NULL`)).
+					WithArgs(false, nil, sqlmock.AnyArg(), periodID, true).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			wantErr: false,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payroll_periods" SET "is_processed"=$1`)).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+			errMsg:  "failed to mark payroll period as unprocessed",
+		},
+		{
+			name: "No Rows Affected",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payroll_periods" SET "is_processed"=$1`)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+			errMsg:  "no payroll period updated",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.mock.ExpectBegin()
+			tc.mock()
+			if tc.wantErr {
+				s.mock.ExpectRollback()
+			} else {
+				s.mock.ExpectCommit()
+			}
+
+			err := s.db.Transaction(func(tx *gorm.DB) error {
+				return NewPayrollPeriodGormRepository(tx).MarkPayrollPeriodAsUnprocessed(periodID)
+			})
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func (s *PayrollPeriodRepositorySuite) TestGetOverlappingPayrollPeriods() {
 	startDate := time.Now()
 	endDate := startDate.Add(14 * 24 * time.Hour)
@@ -459,3 +535,32 @@ func (s *PayrollPeriodRepositorySuite) TestGetOverlappingPayrollPeriods() {
 		})
 	}
 }
+
+func (s *PayrollPeriodRepositorySuite) TestLockOverlappingPayrollPeriods() {
+	query := `SELECT * FROM "payroll_periods" WHERE (start_date <= $1 AND end_date >= $2) AND "payroll_periods"."deleted_at" IS NULL ORDER BY start_date FOR SHARE`
+	// Late in the evening of August 31 in Jakarta is still August 31
+	startDate := time.Date(2025, 8, 31, 23, 30, 0, 0, time.FixedZone("WIB", 7*60*60))
+	endDate := time.Date(2025, 9, 2, 0, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+	s.T().Run("Success", func(t *testing.T) {
+		august, september := uuid.New(), uuid.New()
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs("2025-09-02", "2025-08-31").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "is_processed"}).AddRow(august, true).AddRow(september, false))
+
+		periods, err := s.repo.LockOverlappingPayrollPeriods(startDate, endDate)
+		assert.NoError(t, err)
+		assert.Len(t, periods, 2)
+		assert.Equal(t, august, periods[0].ID)
+	})
+
+	s.T().Run("Database Error", func(t *testing.T) {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs("2025-09-02", "2025-08-31").
+			WillReturnError(errors.New("db error"))
+
+		periods, err := s.repo.LockOverlappingPayrollPeriods(startDate, endDate)
+		assert.Error(t, err)
+		assert.Nil(t, periods)
+	})
+}
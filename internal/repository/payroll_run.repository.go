@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// PayrollRunRepository defines the interface for payroll run data operations.
+//
+//go:generate mockgen -source=payroll_run.repository.go -destination=../../tests/mocks/repository/mock_payroll_run_repository.go -package=mocks
+type PayrollRunRepository interface {
+	CreatePayrollRun(run *domain.PayrollRun) error
+	GetPayrollRunByID(id uuid.UUID) (*domain.PayrollRun, error)
+	GetAllPayrollRuns(periodID *uuid.UUID) ([]domain.PayrollRun, error)
+	GetActivePayrollRunByPeriodID(periodID uuid.UUID) (*domain.PayrollRun, error)
+	UpdatePayrollRun(run *domain.PayrollRun) error
+	UpdatePayrollRunProgress(id uuid.UUID, processed, total int) error
+	FailStalePayrollRuns(reason string) (int64, error)
+}
+
+// PayrollRunGormRepository implements repository.PayrollRunRepository using GORM.
+type PayrollRunGormRepository struct {
+	db *gorm.DB
+}
+
+// NewPayrollRunGormRepository creates a new PayrollRunGormRepository.
+func NewPayrollRunGormRepository(db *gorm.DB) PayrollRunRepository {
+	return &PayrollRunGormRepository{db: db}
+}
+
+// CreatePayrollRun creates a new payroll run in the database. It returns ErrDuplicateRecord when
+// the period already has a queued or running payroll run.
+func (r *PayrollRunGormRepository) CreatePayrollRun(run *domain.PayrollRun) error {
+	return translateError(r.db.Create(run).Error)
+}
+
+// GetPayrollRunByID retrieves a payroll run by its ID.
+func (r *PayrollRunGormRepository) GetPayrollRunByID(id uuid.UUID) (*domain.PayrollRun, error) {
+	var run domain.PayrollRun
+	err := r.db.First(&run, "id = ?", id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &run, err
+}
+
+// GetAllPayrollRuns retrieves payroll runs, newest first, optionally filtered by payroll period.
+func (r *PayrollRunGormRepository) GetAllPayrollRuns(periodID *uuid.UUID) ([]domain.PayrollRun, error) {
+	var runs []domain.PayrollRun
+	query := r.db.Order("created_at DESC")
+	if periodID != nil {
+		query = query.Where("payroll_period_id = ?", *periodID)
+	}
+	err := query.Find(&runs).Error
+	return runs, err
+}
+
+// GetActivePayrollRunByPeriodID retrieves the queued or running payroll run of a payroll period.
+func (r *PayrollRunGormRepository) GetActivePayrollRunByPeriodID(periodID uuid.UUID) (*domain.PayrollRun, error) {
+	var run domain.PayrollRun
+	err := r.db.
+		Where("payroll_period_id = ? AND status IN ?", periodID, []string{domain.PayrollRunStatusQueued, domain.PayrollRunStatusRunning}).
+		First(&run).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &run, err
+}
+
+// UpdatePayrollRun updates an existing payroll run in the database.
+func (r *PayrollRunGormRepository) UpdatePayrollRun(run *domain.PayrollRun) error {
+	return r.db.Save(run).Error
+}
+
+// UpdatePayrollRunProgress updates the progress counters of a payroll run.
+func (r *PayrollRunGormRepository) UpdatePayrollRunProgress(id uuid.UUID, processed, total int) error {
+	return r.db.Model(&domain.PayrollRun{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"processed_employees": processed,
+		"total_employees":     total,
+	}).Error
+}
+
+// FailStalePayrollRuns marks every queued or running payroll run as failed.
+// It is meant to be called on startup, when no worker can still own such a run.
+func (r *PayrollRunGormRepository) FailStalePayrollRuns(reason string) (int64, error) {
+	result := r.db.Model(&domain.PayrollRun{}).
+		Where("status IN ?", []string{domain.PayrollRunStatusQueued, domain.PayrollRunStatusRunning}).
+		Updates(map[string]interface{}{
+			"status":      domain.PayrollRunStatusFailed,
+			"error":       reason,
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
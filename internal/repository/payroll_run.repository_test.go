@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// --- Test Suite Setup for PayrollRunRepository ---
+
+type PayrollRunRepositorySuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	repo PayrollRunRepository
+}
+
+// SetupSuite runs before the tests in the suite are run.
+func (s *PayrollRunRepositorySuite) SetupSuite() {
+	sqlDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	s.Require().NoError(err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = NewPayrollRunGormRepository(db)
+}
+
+// TearDownTest runs after each test in the suite.
+func (s *PayrollRunRepositorySuite) TearDownTest() {
+	s.Require().NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestPayrollRunRepository runs the test suite.
+func TestPayrollRunRepository(t *testing.T) {
+	suite.Run(t, new(PayrollRunRepositorySuite))
+}
+
+// --- Test Cases ---
+
+func (s *PayrollRunRepositorySuite) TestCreatePayrollRun() {
+	run := &domain.PayrollRun{
+		BaseModel:       domain.BaseModel{ID: uuid.New()},
+		PayrollPeriodID: uuid.New(),
+		Status:          domain.PayrollRunStatusQueued,
+	}
+
+	s.T().Run("Success", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "payroll_runs"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(run.ID))
+		s.mock.ExpectCommit()
+
+		assert.NoError(t, s.repo.CreatePayrollRun(run))
+	})
+
+	s.T().Run("Active Run Already Exists", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "payroll_runs"`)).
+			WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_payroll_runs_active_period"})
+		s.mock.ExpectRollback()
+
+		assert.ErrorIs(t, s.repo.CreatePayrollRun(run), ErrDuplicateRecord)
+	})
+}
+
+func (s *PayrollRunRepositorySuite) TestGetPayrollRunByID() {
+	runID := uuid.New()
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+		wantNil bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "status"}).AddRow(runID, domain.PayrollRunStatusRunning)
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payroll_runs" WHERE id = $1 AND "payroll_runs"."deleted_at" IS NULL ORDER BY "payroll_runs"."id" LIMIT $2`)).
+					WithArgs(runID, 1).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payroll_runs"`)).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			wantNil: true,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payroll_runs"`)).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			run, err := s.repo.GetPayrollRunByID(runID)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tc.wantNil {
+				assert.Nil(t, run)
+			} else {
+				assert.Equal(t, runID, run.ID)
+			}
+		})
+	}
+}
+
+func (s *PayrollRunRepositorySuite) TestGetAllPayrollRuns() {
+	periodID := uuid.New()
+
+	s.T().Run("All", func(t *testing.T) {
+		s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payroll_runs" WHERE "payroll_runs"."deleted_at" IS NULL ORDER BY created_at DESC`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New()))
+		runs, err := s.repo.GetAllPayrollRuns(nil)
+		assert.NoError(t, err)
+		assert.Len(t, runs, 2)
+	})
+
+	s.T().Run("Filtered By Period", func(t *testing.T) {
+		s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payroll_runs" WHERE payroll_period_id = $1 AND "payroll_runs"."deleted_at" IS NULL ORDER BY created_at DESC`)).
+			WithArgs(periodID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+		runs, err := s.repo.GetAllPayrollRuns(&periodID)
+		assert.NoError(t, err)
+		assert.Len(t, runs, 1)
+	})
+}
+
+func (s *PayrollRunRepositorySuite) TestGetActivePayrollRunByPeriodID() {
+	periodID := uuid.New()
+	query := regexp.QuoteMeta(`SELECT * FROM "payroll_runs" WHERE (payroll_period_id = $1 AND status IN ($2,$3)) AND "payroll_runs"."deleted_at" IS NULL ORDER BY "payroll_runs"."id" LIMIT $4`)
+
+	s.T().Run("Active Run", func(t *testing.T) {
+		s.mock.ExpectQuery(query).
+			WithArgs(periodID, domain.PayrollRunStatusQueued, domain.PayrollRunStatusRunning, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(uuid.New(), domain.PayrollRunStatusQueued))
+		run, err := s.repo.GetActivePayrollRunByPeriodID(periodID)
+		assert.NoError(t, err)
+		assert.NotNil(t, run)
+	})
+
+	s.T().Run("No Active Run", func(t *testing.T) {
+		s.mock.ExpectQuery(query).
+			WithArgs(periodID, domain.PayrollRunStatusQueued, domain.PayrollRunStatusRunning, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+		run, err := s.repo.GetActivePayrollRunByPeriodID(periodID)
+		assert.NoError(t, err)
+		assert.Nil(t, run)
+	})
+}
+
+func (s *PayrollRunRepositorySuite) TestUpdatePayrollRunProgress() {
+	runID := uuid.New()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payroll_runs" SET "processed_employees"=$1,"total_employees"=$2,"updated_at"=$3 WHERE id = $4 AND "payroll_runs"."deleted_at" IS NULL`)).
+		WithArgs(50, 120, sqlmock.AnyArg(), runID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.UpdatePayrollRunProgress(runID, 50, 120)
+	s.NoError(err)
+}
+
+func (s *PayrollRunRepositorySuite) TestFailStalePayrollRuns() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payroll_runs" SET "error"=$1,"finished_at"=$2,"status"=$3,"updated_at"=$4 WHERE status IN ($5,$6) AND "payroll_runs"."deleted_at" IS NULL`)).
+		WithArgs("interrupted", sqlmock.AnyArg(), domain.PayrollRunStatusFailed, sqlmock.AnyArg(), domain.PayrollRunStatusQueued, domain.PayrollRunStatusRunning).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectCommit()
+
+	affected, err := s.repo.FailStalePayrollRuns("interrupted")
+	s.NoError(err)
+	s.Equal(int64(2), affected)
+}
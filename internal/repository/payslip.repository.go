@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
@@ -15,7 +17,10 @@ type PayslipRepository interface {
 	GetPayslipByID(id uuid.UUID) (*domain.Payslip, error)
 	GetPayslipByUserIDAndPeriodID(userID, periodID uuid.UUID) (*domain.Payslip, error)
 	GetAllPayslipsByPeriodID(periodID uuid.UUID) ([]domain.Payslip, error)
-	CreatePayslipTx(tx *gorm.DB, payslip *domain.Payslip) error
+	GetPayslipHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Payslip, int64, error)
+	GetYearToDatePayslipsGroupedByUser(year int, before time.Time) (map[uuid.UUID][]domain.Payslip, error)
+	CreatePayslips(payslips []domain.Payslip) error
+	VoidPayslipsByPeriodID(periodID uuid.UUID, reason string, voidedBy uuid.UUID) error
 }
 
 // PayslipGormRepository implements repository.PayslipRepository using GORM.
@@ -28,15 +33,23 @@ func NewPayslipGormRepository(db *gorm.DB) PayslipRepository {
 	return &PayslipGormRepository{db: db}
 }
 
-// CreatePayslip creates a new payslip record in the database.
+// orderPayslipItems returns payslip items in the order in which they were calculated.
+func orderPayslipItems(db *gorm.DB) *gorm.DB {
+	return db.Order("sequence ASC")
+}
+
+// CreatePayslip creates a new payslip record, together with its items, in the database.
 func (r *PayslipGormRepository) CreatePayslip(payslip *domain.Payslip) error {
-	return r.db.Create(payslip).Error
+	return translateError(r.db.Create(payslip).Error)
 }
 
-// GetPayslipByID retrieves a payslip record by its ID.
+// GetPayslipByID retrieves a payslip record by its ID, with its items and user.
 func (r *PayslipGormRepository) GetPayslipByID(id uuid.UUID) (*domain.Payslip, error) {
 	var payslip domain.Payslip
-	err := r.db.First(&payslip, id).Error
+	err := r.db.
+		Preload("Items", orderPayslipItems).
+		Preload("User").
+		First(&payslip, id).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -47,6 +60,7 @@ func (r *PayslipGormRepository) GetPayslipByID(id uuid.UUID) (*domain.Payslip, e
 func (r *PayslipGormRepository) GetPayslipByUserIDAndPeriodID(userID, periodID uuid.UUID) (*domain.Payslip, error) {
 	var payslip domain.Payslip
 	err := r.db.
+		Preload("Items", orderPayslipItems).
 		Where("user_id = ? AND payroll_period_id = ?", userID, periodID).
 		First(&payslip).Error
 	if err == gorm.ErrRecordNotFound {
@@ -59,15 +73,75 @@ func (r *PayslipGormRepository) GetPayslipByUserIDAndPeriodID(userID, periodID u
 func (r *PayslipGormRepository) GetAllPayslipsByPeriodID(periodID uuid.UUID) ([]domain.Payslip, error) {
 	var payslips []domain.Payslip
 	err := r.db.
+		Preload("Items", orderPayslipItems).
 		Where("payroll_period_id = ?", periodID).
 		Find(&payslips).Error
 	return payslips, err
 }
 
-// CreatePayslipTx inserts a new payslip record within the given transaction.
-func (r *PayslipGormRepository) CreatePayslipTx(tx *gorm.DB, payslip *domain.Payslip) error {
-	if tx == nil {
-		return gorm.ErrInvalidDB
+// GetPayslipHistory retrieves a page of the payslips of a user with their payroll period, latest
+// period first, together with the number of payslips matching the filter. The date range of the
+// filter selects the payslips of the periods overlapping it. Items are not loaded.
+func (r *PayslipGormRepository) GetPayslipHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Payslip, int64, error) {
+	query := r.db.Joins("PayrollPeriod").Where("user_id = ?", userID)
+	if filter.From != nil {
+		query = query.Where(`"PayrollPeriod".end_date >= ?`, filter.From.Format("2006-01-02"))
+	}
+	if filter.To != nil {
+		query = query.Where(`"PayrollPeriod".start_date <= ?`, filter.To.Format("2006-01-02"))
+	}
+	if filter.PayrollPeriodID != nil {
+		query = query.Where("payroll_period_id = ?", *filter.PayrollPeriodID)
+	}
+	return findHistoryPage[domain.Payslip](query, filter, `"PayrollPeriod".start_date DESC`)
+}
+
+// GetYearToDatePayslipsGroupedByUser retrieves, with their items, the payslips of every user for the
+// payroll periods of a calendar year that end before the given date, grouped by user ID. Voided
+// payslips are not returned.
+func (r *PayslipGormRepository) GetYearToDatePayslipsGroupedByUser(year int, before time.Time) (map[uuid.UUID][]domain.Payslip, error) {
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, before.Location())
+	periodIDs := r.db.Model(&domain.PayrollPeriod{}).
+		Select("id").
+		Where("end_date >= ? AND end_date < ?", yearStart, before)
+
+	var payslips []domain.Payslip
+	err := r.db.
+		Preload("Items", orderPayslipItems).
+		Where("payroll_period_id IN (?)", periodIDs).
+		Order("user_id, created_at").
+		Find(&payslips).Error
+	if err != nil {
+		return nil, err
+	}
+	return groupByUserID(payslips, func(p domain.Payslip) uuid.UUID { return p.UserID }), nil
+}
+
+// VoidPayslipsByPeriodID voids every payslip of a payroll period.
+// The void reason is recorded on the payslips before they and their items are soft-deleted, so they
+// remain available for auditing but are no longer returned by regular queries.
+// All statements should run in the same transaction, see UnitOfWork.
+func (r *PayslipGormRepository) VoidPayslipsByPeriodID(periodID uuid.UUID, reason string, voidedBy uuid.UUID) error {
+	if err := r.db.Model(&domain.Payslip{}).
+		Where("payroll_period_id = ?", periodID).
+		Updates(map[string]interface{}{
+			"void_reason": reason,
+			"updated_by":  voidedBy,
+		}).Error; err != nil {
+		return err
+	}
+	payslipIDs := r.db.Model(&domain.Payslip{}).Select("id").Where("payroll_period_id = ?", periodID)
+	if err := r.db.Where("payslip_id IN (?)", payslipIDs).Delete(&domain.PayslipItem{}).Error; err != nil {
+		return err
+	}
+	return r.db.Where("payroll_period_id = ?", periodID).Delete(&domain.Payslip{}).Error
+}
+
+// CreatePayslips inserts multiple payslip records and their items, using one INSERT per BatchSize payslips.
+// It returns ErrDuplicateRecord when an employee already has a payslip for the period.
+func (r *PayslipGormRepository) CreatePayslips(payslips []domain.Payslip) error {
+	if len(payslips) == 0 {
+		return nil
 	}
-	return tx.Create(payslip).Error
+	return translateError(r.db.CreateInBatches(payslips, BatchSize).Error)
 }
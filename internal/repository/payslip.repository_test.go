@@ -4,9 +4,11 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/postgres"
@@ -109,6 +111,7 @@ func (s *PayslipRepositorySuite) TestCreatePayslip() {
 
 func (s *PayslipRepositorySuite) TestGetPayslipByID() {
 	payslipID := uuid.New()
+	userID := uuid.New()
 
 	testCases := []struct {
 		name    string
@@ -121,10 +124,16 @@ func (s *PayslipRepositorySuite) TestGetPayslipByID() {
 			name: "Success",
 			id:   payslipID,
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id"}).AddRow(payslipID)
+				rows := sqlmock.NewRows([]string{"id", "user_id"}).AddRow(payslipID, userID)
 				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payslips" WHERE "payslips"."id" = $1 AND "payslips"."deleted_at" IS NULL ORDER BY "payslips"."id" LIMIT $2`)).
 					WithArgs(payslipID, 1).
 					WillReturnRows(rows)
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payslip_items" WHERE "payslip_items"."payslip_id" = $1 AND "payslip_items"."deleted_at" IS NULL ORDER BY sequence ASC`)).
+					WithArgs(payslipID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "payslip_id", "code"}).AddRow(uuid.New(), payslipID, "BASIC_SALARY"))
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE "users"."id" = $1 AND "users"."deleted_at" IS NULL`)).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(userID, "employee1"))
 			},
 			wantErr: false,
 			wantNil: false,
@@ -173,10 +182,14 @@ func (s *PayslipRepositorySuite) TestGetPayslipByUserIDAndPeriodID() {
 		{
 			name: "Success",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id"}).AddRow(uuid.New())
+				payslipID := uuid.New()
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(payslipID)
 				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payslips" WHERE (user_id = $1 AND payroll_period_id = $2) AND "payslips"."deleted_at" IS NULL ORDER BY "payslips"."id" LIMIT $3`)).
 					WithArgs(userID, periodID, 1).
 					WillReturnRows(rows)
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payslip_items" WHERE "payslip_items"."payslip_id" = $1 AND "payslip_items"."deleted_at" IS NULL ORDER BY sequence ASC`)).
+					WithArgs(payslipID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
 			},
 			wantErr: false,
 			wantNil: false,
@@ -223,10 +236,15 @@ func (s *PayslipRepositorySuite) TestGetAllPayslipsByPeriodID() {
 		{
 			name: "Success",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New())
+				firstID, secondID := uuid.New(), uuid.New()
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(firstID).AddRow(secondID)
 				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payslips" WHERE payroll_period_id = $1 AND "payslips"."deleted_at" IS NULL`)).
 					WithArgs(periodID).
 					WillReturnRows(rows)
+				// Items of every payslip are preloaded with a single query
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payslip_items" WHERE "payslip_items"."payslip_id" IN ($1,$2) AND "payslip_items"."deleted_at" IS NULL ORDER BY sequence ASC`)).
+					WithArgs(firstID, secondID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "payslip_id"}).AddRow(uuid.New(), firstID).AddRow(uuid.New(), secondID))
 			},
 			wantErr: false,
 			wantLen: 2,
@@ -257,47 +275,110 @@ func (s *PayslipRepositorySuite) TestGetAllPayslipsByPeriodID() {
 	}
 }
 
-func (s *PayslipRepositorySuite) TestCreatePayslipTx() {
-	payslip := &domain.Payslip{BaseModel: domain.BaseModel{ID: uuid.New()}}
+func (s *PayslipRepositorySuite) TestGetYearToDatePayslipsGroupedByUser() {
+	before := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	yearStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := `SELECT * FROM "payslips" WHERE payroll_period_id IN (SELECT "id" FROM "payroll_periods" WHERE (end_date >= $1 AND end_date < $2) AND "payroll_periods"."deleted_at" IS NULL) AND "payslips"."deleted_at" IS NULL ORDER BY user_id, created_at`
+	firstUser, secondUser := uuid.New(), uuid.New()
 
 	testCases := []struct {
-		name     string
-		mock     func()
-		wantErr  bool
-		useNilTx bool
+		name    string
+		mock    func()
+		wantErr bool
+		wantLen map[uuid.UUID]int
 	}{
 		{
 			name: "Success",
 			mock: func() {
-				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "payslips"`)).
-					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(payslip.ID))
+				first, second, third := uuid.New(), uuid.New(), uuid.New()
+				rows := sqlmock.NewRows([]string{"id", "user_id"}).
+					AddRow(first, firstUser).
+					AddRow(second, firstUser).
+					AddRow(third, secondUser)
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(yearStart, before).
+					WillReturnRows(rows)
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payslip_items" WHERE "payslip_items"."payslip_id" IN ($1,$2,$3) AND "payslip_items"."deleted_at" IS NULL ORDER BY sequence ASC`)).
+					WithArgs(first, second, third).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "payslip_id"}))
 			},
 			wantErr: false,
+			wantLen: map[uuid.UUID]int{firstUser: 2, secondUser: 1},
 		},
 		{
 			name: "DB Error",
 			mock: func() {
-				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "payslips"`)).
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(yearStart, before).
 					WillReturnError(errors.New("db error"))
 			},
 			wantErr: true,
 		},
-		{
-			name:     "Nil Transaction",
-			mock:     func() {},
-			wantErr:  true,
-			useNilTx: true,
-		},
 	}
 
 	for _, tc := range testCases {
 		s.T().Run(tc.name, func(t *testing.T) {
-			if tc.useNilTx {
-				err := s.repo.CreatePayslipTx(nil, payslip)
+			tc.mock()
+			grouped, err := s.repo.GetYearToDatePayslipsGroupedByUser(2025, before)
+			if tc.wantErr {
 				assert.Error(t, err)
 				return
 			}
+			assert.NoError(t, err)
+			assert.Len(t, grouped, len(tc.wantLen))
+			for userID, n := range tc.wantLen {
+				assert.Len(t, grouped[userID], n)
+			}
+		})
+	}
+}
+func (s *PayslipRepositorySuite) TestVoidPayslipsByPeriodID() {
+	periodID := uuid.New()
+	voidedBy := uuid.New()
+	reason := "wrong salary for employee42"
 
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payslips" SET "updated_by"=$1,"void_reason"=$2,"updated_at"=$3 WHERE payroll_period_id = $4 AND "payslips"."deleted_at" IS NULL`)).
+					WithArgs(voidedBy, reason, sqlmock.AnyArg(), periodID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payslip_items" SET "deleted_at"=$1 WHERE payslip_id IN (SELECT "id" FROM "payslips" WHERE payroll_period_id = $2 AND "payslips"."deleted_at" IS NULL) AND "payslip_items"."deleted_at" IS NULL`)).
+					WithArgs(sqlmock.AnyArg(), periodID).
+					WillReturnResult(sqlmock.NewResult(0, 12))
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payslips" SET "deleted_at"=$1 WHERE payroll_period_id = $2 AND "payslips"."deleted_at" IS NULL`)).
+					WithArgs(sqlmock.AnyArg(), periodID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			wantErr: false,
+		},
+		{
+			name: "DB Error on void reason",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payslips" SET "updated_by"=$1,"void_reason"=$2`)).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "DB Error on payslip items",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payslips" SET "updated_by"=$1,"void_reason"=$2`)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payslip_items" SET "deleted_at"=$1`)).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
 			s.mock.ExpectBegin()
 			tc.mock()
 			if tc.wantErr {
@@ -307,7 +388,7 @@ func (s *PayslipRepositorySuite) TestCreatePayslipTx() {
 			}
 
 			err := s.db.Transaction(func(tx *gorm.DB) error {
-				return s.repo.CreatePayslipTx(tx, payslip)
+				return NewPayslipGormRepository(tx).VoidPayslipsByPeriodID(periodID, reason, voidedBy)
 			})
 
 			if tc.wantErr {
@@ -318,3 +399,105 @@ func (s *PayslipRepositorySuite) TestCreatePayslipTx() {
 		})
 	}
 }
+
+func (s *PayslipRepositorySuite) TestCreatePayslips() {
+	payslips := []domain.Payslip{
+		{BaseModel: domain.BaseModel{ID: uuid.New()}, UserID: uuid.New()},
+		{BaseModel: domain.BaseModel{ID: uuid.New()}, UserID: uuid.New()},
+	}
+
+	s.T().Run("Success", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "payslips"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(payslips[0].ID).AddRow(payslips[1].ID))
+		s.mock.ExpectCommit()
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			return NewPayslipGormRepository(tx).CreatePayslips(payslips)
+		})
+		assert.NoError(t, err)
+	})
+
+	s.T().Run("DB Error", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "payslips"`)).
+			WillReturnError(errors.New("db error"))
+		s.mock.ExpectRollback()
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			return NewPayslipGormRepository(tx).CreatePayslips(payslips)
+		})
+		assert.Error(t, err)
+	})
+
+	s.T().Run("Success with items", func(t *testing.T) {
+		withItems := []domain.Payslip{{
+			BaseModel: domain.BaseModel{ID: uuid.New()},
+			UserID:    uuid.New(),
+			Items: []domain.PayslipItem{
+				{BaseModel: domain.BaseModel{ID: uuid.New()}, Code: "BASIC_SALARY", Sequence: 1},
+				{BaseModel: domain.BaseModel{ID: uuid.New()}, Code: "OVERTIME", Sequence: 2},
+			},
+		}}
+
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "payslips"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(withItems[0].ID))
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "payslip_items"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(withItems[0].Items[0].ID).AddRow(withItems[0].Items[1].ID))
+		s.mock.ExpectCommit()
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			return NewPayslipGormRepository(tx).CreatePayslips(withItems)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, withItems[0].ID, withItems[0].Items[0].PayslipID)
+	})
+
+	s.T().Run("Duplicate payslip", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "payslips"`)).
+			WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_payslips_user_period"})
+		s.mock.ExpectRollback()
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			return NewPayslipGormRepository(tx).CreatePayslips(payslips)
+		})
+		assert.ErrorIs(t, err, ErrDuplicateRecord)
+	})
+
+	s.T().Run("No payslips", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectCommit()
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			return NewPayslipGormRepository(tx).CreatePayslips(nil)
+		})
+		assert.NoError(t, err)
+	})
+
+}
+
+func (s *PayslipRepositorySuite) TestGetPayslipHistory() {
+	userID, periodID := uuid.New(), uuid.New()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	countQuery := `SELECT count(*) FROM "payslips" LEFT JOIN "payroll_periods" "PayrollPeriod" ON "payslips"."payroll_period_id" = "PayrollPeriod"."id" AND "PayrollPeriod"."deleted_at" IS NULL WHERE user_id = $1 AND "PayrollPeriod".end_date >= $2 AND "PayrollPeriod".start_date <= $3 AND "payslips"."deleted_at" IS NULL`
+	pageQuery := `FROM "payslips" LEFT JOIN "payroll_periods" "PayrollPeriod" ON "payslips"."payroll_period_id" = "PayrollPeriod"."id" AND "PayrollPeriod"."deleted_at" IS NULL WHERE user_id = $1 AND "PayrollPeriod".end_date >= $2 AND "PayrollPeriod".start_date <= $3 AND "payslips"."deleted_at" IS NULL ORDER BY "PayrollPeriod".start_date DESC LIMIT $4`
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(countQuery)).
+		WithArgs(userID, "2025-01-01", "2025-12-31").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(pageQuery)).
+		WithArgs(userID, "2025-01-01", "2025-12-31", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "payroll_period_id", "total_take_home_pay", "PayrollPeriod__id", "PayrollPeriod__is_processed"}).
+			AddRow(uuid.New(), userID, periodID, "5000000", periodID, true))
+
+	payslips, total, err := s.repo.GetPayslipHistory(userID, domain.HistoryFilter{From: &from, To: &to, Page: 1, PageSize: 20})
+	s.NoError(err)
+	s.Equal(int64(1), total)
+	s.Require().Len(payslips, 1)
+	s.Equal(periodID, payslips[0].PayrollPeriod.ID)
+	s.True(payslips[0].PayrollPeriod.IsProcessed)
+	s.Equal("5000000", payslips[0].TotalTakeHomePay.String())
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"payroll-system/internal/domain"
 )
@@ -15,9 +16,17 @@ import (
 type ReimbursementRepository interface {
 	CreateReimbursement(reimbursement *domain.Reimbursement) error
 	GetReimbursementByID(id uuid.UUID) (*domain.Reimbursement, error)
-	GetReimbursementsByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.Reimbursement, error)
+	GetPayableReimbursementsByUserID(userID uuid.UUID, endDate time.Time) ([]domain.Reimbursement, error)
+	GetClaimedReimbursementsByUserIDAndCategory(userID, categoryID uuid.UUID, startDate, endDate time.Time) ([]domain.Reimbursement, error)
+	GetReimbursementHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Reimbursement, int64, error)
 	UpdateReimbursement(reimbursement *domain.Reimbursement) error
-	UpdateReimbursementsTx(tx *gorm.DB, reimbursements []domain.Reimbursement) error
+	GetPendingReimbursements() ([]domain.Reimbursement, error)
+	GetPendingReimbursementsByPeriod(startDate, endDate time.Time) ([]domain.Reimbursement, error)
+	GetReimbursementsByPayrollPeriodID(payrollPeriodID uuid.UUID) ([]domain.Reimbursement, error)
+	DetachReimbursementsFromPayrollPeriod(payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error
+	GetPayableReimbursementsGroupedByUser(endDate time.Time) (map[uuid.UUID][]domain.Reimbursement, error)
+	AttachReimbursementsToPayrollPeriod(ids []uuid.UUID, payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error
+	GetReimbursementReceipt(reimbursementID, receiptID uuid.UUID) (*domain.ReimbursementReceipt, error)
 }
 
 // ReimbursementGormRepository implements repository.ReimbursementRepository using GORM.
@@ -30,42 +39,140 @@ func NewReimbursementGormRepository(db *gorm.DB) ReimbursementRepository {
 	return &ReimbursementGormRepository{db: db}
 }
 
-// CreateReimbursement creates a new reimbursement record in the database.
+// CreateReimbursement creates a new reimbursement record in the database, together with its receipts.
 func (r *ReimbursementGormRepository) CreateReimbursement(reimbursement *domain.Reimbursement) error {
 	return r.db.Create(reimbursement).Error
 }
 
-// GetReimbursementByID retrieves a reimbursement record by its ID.
+// GetReimbursementByID retrieves a reimbursement record by its ID, with its receipts.
 func (r *ReimbursementGormRepository) GetReimbursementByID(id uuid.UUID) (*domain.Reimbursement, error) {
 	var reimbursement domain.Reimbursement
-	err := r.db.First(&reimbursement, id).Error
+	err := r.db.Preload("Receipts").First(&reimbursement, id).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
 	return &reimbursement, err
 }
 
-// GetReimbursementsByUserIDAndPeriod retrieves reimbursement records for a user within a date range.
-func (r *ReimbursementGormRepository) GetReimbursementsByUserIDAndPeriod(userID uuid.UUID, startDate, endDate time.Time) ([]domain.Reimbursement, error) {
+// payable selects the approved reimbursements not yet paid by a payroll run with an expense date on
+// or before a date. A claim approved after the period of its expense date was processed is paid by
+// the next run.
+const payable = "status = ? AND payroll_period_id IS NULL AND expense_date <= ?"
+
+// GetPayableReimbursementsByUserID retrieves the approved, unpaid reimbursement records of a user with
+// an expense date on or before endDate.
+func (r *ReimbursementGormRepository) GetPayableReimbursementsByUserID(userID uuid.UUID, endDate time.Time) ([]domain.Reimbursement, error) {
+	var reimbursements []domain.Reimbursement
+	err := r.db.
+		Where("user_id = ?", userID).
+		Where(payable, domain.ReimbursementStatusApproved, endDate.Format("2006-01-02")).
+		Find(&reimbursements).Error
+	return reimbursements, err
+}
+
+// GetClaimedReimbursementsByUserIDAndCategory retrieves the pending and approved reimbursement records
+// of a user in a category with an expense date within a date range.
+func (r *ReimbursementGormRepository) GetClaimedReimbursementsByUserIDAndCategory(userID, categoryID uuid.UUID, startDate, endDate time.Time) ([]domain.Reimbursement, error) {
 	var reimbursements []domain.Reimbursement
-	err := r.db.Where("user_id = ? AND created_at >= ? AND created_at <= ?", userID, startDate, endDate).Find(&reimbursements).Error
+	err := r.db.
+		Where("user_id = ? AND category_id = ? AND status <> ? AND expense_date >= ? AND expense_date <= ?",
+			userID, categoryID, domain.ReimbursementStatusRejected, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Find(&reimbursements).Error
 	return reimbursements, err
 }
 
+// GetReimbursementHistory retrieves a page of the reimbursement records of a user with their category
+// and receipts, latest expense first, together with the number of records matching the filter.
+func (r *ReimbursementGormRepository) GetReimbursementHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Reimbursement, int64, error) {
+	query := filterHistory(r.db.Preload("Category").Preload("Receipts"), userID, filter, "expense_date")
+	return findHistoryPage[domain.Reimbursement](query, filter, "expense_date DESC, created_at DESC")
+}
+
 // UpdateReimbursement updates an existing reimbursement record in the database.
 func (r *ReimbursementGormRepository) UpdateReimbursement(reimbursement *domain.Reimbursement) error {
-	return r.db.Save(reimbursement).Error
+	return r.db.Omit(clause.Associations).Save(reimbursement).Error
+}
+
+// GetPendingReimbursements retrieves every pending reimbursement record with its user, category and
+// receipts, oldest first.
+func (r *ReimbursementGormRepository) GetPendingReimbursements() ([]domain.Reimbursement, error) {
+	var reimbursements []domain.Reimbursement
+	err := r.db.
+		Preload("User").
+		Preload("Category").
+		Preload("Receipts").
+		Where("status = ?", domain.ReimbursementStatusPending).
+		Order("created_at").
+		Find(&reimbursements).Error
+	return reimbursements, err
+}
+
+// GetPendingReimbursementsByPeriod retrieves the pending reimbursement records with an expense date
+// within a date range, oldest expense first.
+func (r *ReimbursementGormRepository) GetPendingReimbursementsByPeriod(startDate, endDate time.Time) ([]domain.Reimbursement, error) {
+	var reimbursements []domain.Reimbursement
+	err := r.db.
+		Where("status = ? AND expense_date >= ? AND expense_date <= ?", domain.ReimbursementStatusPending, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Order("expense_date").
+		Find(&reimbursements).Error
+	return reimbursements, err
 }
 
-// UpdateReimbursementsTx updates multiple reimbursement records within the given transaction.
-func (r *ReimbursementGormRepository) UpdateReimbursementsTx(tx *gorm.DB, reimbursements []domain.Reimbursement) error {
-	if tx == nil {
-		return gorm.ErrInvalidDB
+// GetReimbursementsByPayrollPeriodID retrieves all reimbursement records attached to a payroll period.
+func (r *ReimbursementGormRepository) GetReimbursementsByPayrollPeriodID(payrollPeriodID uuid.UUID) ([]domain.Reimbursement, error) {
+	var reimbursements []domain.Reimbursement
+	err := r.db.Where("payroll_period_id = ?", payrollPeriodID).Find(&reimbursements).Error
+	return reimbursements, err
+}
+
+// DetachReimbursementsFromPayrollPeriod clears the payroll period of every reimbursement record attached to it.
+func (r *ReimbursementGormRepository) DetachReimbursementsFromPayrollPeriod(payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error {
+	return r.db.Model(&domain.Reimbursement{}).
+		Where("payroll_period_id = ?", payrollPeriodID).
+		Updates(map[string]interface{}{
+			"payroll_period_id": nil,
+			"updated_by":        updatedBy,
+			"ip_address":        ipAddress,
+		}).Error
+}
+
+// GetPayableReimbursementsGroupedByUser retrieves the approved, unpaid reimbursement records of every
+// user with an expense date on or before endDate in a single query, grouped by user ID.
+func (r *ReimbursementGormRepository) GetPayableReimbursementsGroupedByUser(endDate time.Time) (map[uuid.UUID][]domain.Reimbursement, error) {
+	var reimbursements []domain.Reimbursement
+	err := r.db.
+		Where(payable, domain.ReimbursementStatusApproved, endDate.Format("2006-01-02")).
+		Order("user_id, expense_date").
+		Find(&reimbursements).Error
+	if err != nil {
+		return nil, err
 	}
-	for _, reimbursement := range reimbursements {
-		if err := tx.Save(&reimbursement).Error; err != nil {
+	return groupByUserID(reimbursements, func(r domain.Reimbursement) uuid.UUID { return r.UserID }), nil
+}
+
+// AttachReimbursementsToPayrollPeriod attaches the given reimbursement records to a payroll period,
+// using one UPDATE per BatchSize records.
+func (r *ReimbursementGormRepository) AttachReimbursementsToPayrollPeriod(ids []uuid.UUID, payrollPeriodID uuid.UUID, updatedBy uuid.UUID, ipAddress string) error {
+	for _, chunk := range chunkIDs(ids, BatchSize) {
+		if err := r.db.Model(&domain.Reimbursement{}).
+			Where("id IN ?", chunk).
+			Updates(map[string]interface{}{
+				"payroll_period_id": payrollPeriodID,
+				"updated_by":        updatedBy,
+				"ip_address":        ipAddress,
+			}).Error; err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// GetReimbursementReceipt retrieves a receipt attached to a reimbursement.
+func (r *ReimbursementGormRepository) GetReimbursementReceipt(reimbursementID, receiptID uuid.UUID) (*domain.ReimbursementReceipt, error) {
+	var receipt domain.ReimbursementReceipt
+	err := r.db.Where("id = ? AND reimbursement_id = ?", receiptID, reimbursementID).First(&receipt).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &receipt, err
+}
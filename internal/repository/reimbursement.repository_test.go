@@ -8,6 +8,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/postgres"
@@ -69,7 +70,7 @@ func (s *ReimbursementRepositorySuite) TestCreateReimbursement() {
 			reimbursement: &domain.Reimbursement{
 				BaseModel: domain.BaseModel{ID: reimbursementID},
 				UserID:    userID,
-				Amount:    100.50,
+				Amount:    decimal.NewFromFloat(100.50),
 			},
 			mock: func() {
 				s.mock.ExpectBegin()
@@ -79,6 +80,26 @@ func (s *ReimbursementRepositorySuite) TestCreateReimbursement() {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Success With Receipts",
+			reimbursement: &domain.Reimbursement{
+				BaseModel: domain.BaseModel{ID: reimbursementID},
+				UserID:    userID,
+				Amount:    decimal.NewFromFloat(100.50),
+				Receipts: []domain.ReimbursementReceipt{
+					{FileName: "receipt.pdf", ContentType: "application/pdf", Size: 1024, SHA256: "abc", StorageKey: "receipts/ab/abc"},
+				},
+			},
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reimbursements"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(reimbursementID))
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reimbursement_receipts"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				s.mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
 		{
 			name: "DB Error",
 			reimbursement: &domain.Reimbursement{
@@ -125,6 +146,9 @@ func (s *ReimbursementRepositorySuite) TestGetReimbursementByID() {
 				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reimbursements" WHERE "reimbursements"."id" = $1 AND "reimbursements"."deleted_at" IS NULL ORDER BY "reimbursements"."id" LIMIT $2`)).
 					WithArgs(reimbursementID, 1).
 					WillReturnRows(rows)
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reimbursement_receipts" WHERE "reimbursement_receipts"."reimbursement_id" = $1 AND "reimbursement_receipts"."deleted_at" IS NULL`)).
+					WithArgs(reimbursementID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "reimbursement_id", "file_name"}).AddRow(uuid.New(), reimbursementID, "receipt.pdf"))
 			},
 			wantErr: false,
 			wantNil: false,
@@ -160,10 +184,9 @@ func (s *ReimbursementRepositorySuite) TestGetReimbursementByID() {
 	}
 }
 
-func (s *ReimbursementRepositorySuite) TestGetReimbursementsByUserIDAndPeriod() {
+func (s *ReimbursementRepositorySuite) TestGetPayableReimbursementsByUserID() {
 	userID := uuid.New()
-	startDate := time.Now().Add(-30 * 24 * time.Hour)
-	endDate := time.Now()
+	endDate := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
 
 	testCases := []struct {
 		name    string
@@ -177,8 +200,8 @@ func (s *ReimbursementRepositorySuite) TestGetReimbursementsByUserIDAndPeriod()
 				rows := sqlmock.NewRows([]string{"id", "user_id"}).
 					AddRow(uuid.New(), userID).
 					AddRow(uuid.New(), userID)
-				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reimbursements" WHERE (user_id = $1 AND created_at >= $2 AND created_at <= $3) AND "reimbursements"."deleted_at" IS NULL`)).
-					WithArgs(userID, startDate, endDate).
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reimbursements" WHERE user_id = $1 AND (status = $2 AND payroll_period_id IS NULL AND expense_date <= $3) AND "reimbursements"."deleted_at" IS NULL`)).
+					WithArgs(userID, domain.ReimbursementStatusApproved, "2025-08-31").
 					WillReturnRows(rows)
 			},
 			wantErr: false,
@@ -187,8 +210,8 @@ func (s *ReimbursementRepositorySuite) TestGetReimbursementsByUserIDAndPeriod()
 		{
 			name: "DB Error",
 			mock: func() {
-				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reimbursements" WHERE (user_id = $1 AND created_at >= $2 AND created_at <= $3) AND "reimbursements"."deleted_at" IS NULL`)).
-					WithArgs(userID, startDate, endDate).
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reimbursements" WHERE user_id = $1 AND (status = $2 AND payroll_period_id IS NULL AND expense_date <= $3) AND "reimbursements"."deleted_at" IS NULL`)).
+					WithArgs(userID, domain.ReimbursementStatusApproved, "2025-08-31").
 					WillReturnError(errors.New("db error"))
 			},
 			wantErr: true,
@@ -199,7 +222,7 @@ func (s *ReimbursementRepositorySuite) TestGetReimbursementsByUserIDAndPeriod()
 	for _, tc := range testCases {
 		s.T().Run(tc.name, func(t *testing.T) {
 			tc.mock()
-			reimbursements, err := s.repo.GetReimbursementsByUserIDAndPeriod(userID, startDate, endDate)
+			reimbursements, err := s.repo.GetPayableReimbursementsByUserID(userID, endDate)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -255,49 +278,125 @@ func (s *ReimbursementRepositorySuite) TestUpdateReimbursement() {
 	}
 }
 
-func (s *ReimbursementRepositorySuite) TestUpdateReimbursementsTx() {
-	reimbursements := []domain.Reimbursement{
-		{BaseModel: domain.BaseModel{ID: uuid.New()}},
-	}
+func (s *ReimbursementRepositorySuite) TestGetPendingReimbursements() {
+	employeeID, categoryID := uuid.New(), uuid.New()
+	query := `SELECT * FROM "reimbursements" WHERE status = $1 AND "reimbursements"."deleted_at" IS NULL ORDER BY created_at`
+	categoriesQuery := `SELECT * FROM "reimbursement_categories" WHERE "reimbursement_categories"."id" = $1 AND "reimbursement_categories"."deleted_at" IS NULL`
+	receiptsQuery := `SELECT * FROM "reimbursement_receipts" WHERE "reimbursement_receipts"."reimbursement_id" = $1 AND "reimbursement_receipts"."deleted_at" IS NULL`
+	usersQuery := `SELECT * FROM "users" WHERE "users"."id" = $1`
+	reimbursementID := uuid.New()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(domain.ReimbursementStatusPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "category_id"}).AddRow(reimbursementID, employeeID, categoryID))
+	s.mock.ExpectQuery(regexp.QuoteMeta(categoriesQuery)).
+		WithArgs(categoryID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(categoryID, "Travel"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(receiptsQuery)).
+		WithArgs(reimbursementID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reimbursement_id", "file_name"}).AddRow(uuid.New(), reimbursementID, "receipt.pdf"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(usersQuery)).
+		WithArgs(employeeID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(employeeID, "jdoe"))
+
+	reimbursements, err := s.repo.GetPendingReimbursements()
+	s.NoError(err)
+	s.Require().Len(reimbursements, 1)
+	s.Equal("jdoe", reimbursements[0].User.Username)
+	s.Equal("Travel", reimbursements[0].Category.Name)
+	s.Require().Len(reimbursements[0].Receipts, 1)
+	s.Equal("receipt.pdf", reimbursements[0].Receipts[0].FileName)
+}
+
+func (s *ReimbursementRepositorySuite) TestGetPendingReimbursementsByPeriod() {
+	startDate := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+	query := `SELECT * FROM "reimbursements" WHERE (status = $1 AND expense_date >= $2 AND expense_date <= $3) AND "reimbursements"."deleted_at" IS NULL ORDER BY expense_date`
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(domain.ReimbursementStatusPending, "2025-08-01", "2025-08-31").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uuid.New(), uuid.New()))
+
+	reimbursements, err := s.repo.GetPendingReimbursementsByPeriod(startDate, endDate)
+	s.NoError(err)
+	s.Len(reimbursements, 1)
+}
+
+func (s *ReimbursementRepositorySuite) TestGetReimbursementsByPayrollPeriodID() {
+	periodID := uuid.New()
 
 	testCases := []struct {
-		name     string
-		mock     func()
-		wantErr  bool
-		useNilTx bool
+		name    string
+		mock    func()
+		wantErr bool
+		wantLen int
 	}{
 		{
 			name: "Success",
 			mock: func() {
-				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reimbursements" SET`)).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+				rows := sqlmock.NewRows([]string{"id", "payroll_period_id"}).AddRow(uuid.New(), periodID).AddRow(uuid.New(), periodID)
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reimbursements" WHERE payroll_period_id = $1 AND "reimbursements"."deleted_at" IS NULL`)).
+					WithArgs(periodID).
+					WillReturnRows(rows)
 			},
 			wantErr: false,
+			wantLen: 2,
 		},
 		{
 			name: "DB Error",
 			mock: func() {
-				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reimbursements" SET`)).
+				s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reimbursements" WHERE payroll_period_id = $1`)).
+					WithArgs(periodID).
 					WillReturnError(errors.New("db error"))
 			},
 			wantErr: true,
 		},
-		{
-			name:     "Nil Transaction",
-			mock:     func() {},
-			wantErr:  true,
-			useNilTx: true,
-		},
 	}
 
 	for _, tc := range testCases {
 		s.T().Run(tc.name, func(t *testing.T) {
-			if tc.useNilTx {
-				err := s.repo.UpdateReimbursementsTx(nil, reimbursements)
+			tc.mock()
+			records, err := s.repo.GetReimbursementsByPayrollPeriodID(periodID)
+			if tc.wantErr {
 				assert.Error(t, err)
-				return
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, records, tc.wantLen)
 			}
+		})
+	}
+}
+
+func (s *ReimbursementRepositorySuite) TestDetachReimbursementsFromPayrollPeriod() {
+	periodID := uuid.New()
+	updatedBy := uuid.New()
 
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reimbursements" SET "ip_address"=$1,"payroll_period_id"=$2,"updated_by"=$3,"updated_at"=$4 WHERE payroll_period_id = $5 AND "reimbursements"."deleted_at" IS NULL`)).
+					WithArgs("127.0.0.1", nil, updatedBy, sqlmock.AnyArg(), periodID).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			wantErr: false,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reimbursements" SET`)).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
 			s.mock.ExpectBegin()
 			tc.mock()
 			if tc.wantErr {
@@ -307,7 +406,7 @@ func (s *ReimbursementRepositorySuite) TestUpdateReimbursementsTx() {
 			}
 
 			err := s.db.Transaction(func(tx *gorm.DB) error {
-				return s.repo.UpdateReimbursementsTx(tx, reimbursements)
+				return NewReimbursementGormRepository(tx).DetachReimbursementsFromPayrollPeriod(periodID, updatedBy, "127.0.0.1")
 			})
 
 			if tc.wantErr {
@@ -318,3 +417,107 @@ func (s *ReimbursementRepositorySuite) TestUpdateReimbursementsTx() {
 		})
 	}
 }
+
+func (s *ReimbursementRepositorySuite) TestGetPayableReimbursementsGroupedByUser() {
+	userID := uuid.New()
+	endDate := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "amount"}).
+		AddRow(uuid.New(), userID, "150000").
+		AddRow(uuid.New(), userID, "50000")
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reimbursements" WHERE (status = $1 AND payroll_period_id IS NULL AND expense_date <= $2) AND "reimbursements"."deleted_at" IS NULL ORDER BY user_id, expense_date`)).
+		WithArgs(domain.ReimbursementStatusApproved, "2025-08-31").
+		WillReturnRows(rows)
+
+	grouped, err := s.repo.GetPayableReimbursementsGroupedByUser(endDate)
+	s.NoError(err)
+	s.Len(grouped[userID], 2)
+}
+
+func (s *ReimbursementRepositorySuite) TestGetClaimedReimbursementsByUserIDAndCategory() {
+	userID, categoryID := uuid.New(), uuid.New()
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reimbursements" WHERE (user_id = $1 AND category_id = $2 AND status <> $3 AND expense_date >= $4 AND expense_date <= $5) AND "reimbursements"."deleted_at" IS NULL`)).
+		WithArgs(userID, categoryID, domain.ReimbursementStatusRejected, "2025-01-01", "2025-12-31").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount"}).AddRow(uuid.New(), userID, "150000"))
+
+	reimbursements, err := s.repo.GetClaimedReimbursementsByUserIDAndCategory(userID, categoryID, startDate, endDate)
+	s.NoError(err)
+	s.Len(reimbursements, 1)
+}
+
+func (s *ReimbursementRepositorySuite) TestAttachReimbursementsToPayrollPeriod() {
+	periodID := uuid.New()
+	updatedBy := uuid.New()
+	ids := []uuid.UUID{uuid.New()}
+
+	s.T().Run("Success", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reimbursements" SET "ip_address"=$1,"payroll_period_id"=$2,"updated_by"=$3,"updated_at"=$4 WHERE id IN ($5) AND "reimbursements"."deleted_at" IS NULL`)).
+			WithArgs("127.0.0.1", periodID, updatedBy, sqlmock.AnyArg(), ids[0]).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			return NewReimbursementGormRepository(tx).AttachReimbursementsToPayrollPeriod(ids, periodID, updatedBy, "127.0.0.1")
+		})
+		assert.NoError(t, err)
+	})
+
+}
+
+func (s *ReimbursementRepositorySuite) TestGetReimbursementReceipt() {
+	reimbursementID, receiptID := uuid.New(), uuid.New()
+	query := `SELECT * FROM "reimbursement_receipts" WHERE (id = $1 AND reimbursement_id = $2) AND "reimbursement_receipts"."deleted_at" IS NULL ORDER BY "reimbursement_receipts"."id" LIMIT $3`
+
+	s.T().Run("Success", func(t *testing.T) {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs(receiptID, reimbursementID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "reimbursement_id", "storage_key"}).AddRow(receiptID, reimbursementID, "receipts/ab/abc"))
+
+		receipt, err := s.repo.GetReimbursementReceipt(reimbursementID, receiptID)
+		assert.NoError(t, err)
+		assert.Equal(t, "receipts/ab/abc", receipt.StorageKey)
+	})
+
+	s.T().Run("Not Found", func(t *testing.T) {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs(receiptID, reimbursementID, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		receipt, err := s.repo.GetReimbursementReceipt(reimbursementID, receiptID)
+		assert.NoError(t, err)
+		assert.Nil(t, receipt)
+	})
+}
+
+func (s *ReimbursementRepositorySuite) TestGetReimbursementHistory() {
+	userID, categoryID, reimbursementID := uuid.New(), uuid.New(), uuid.New()
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	countQuery := `SELECT count(*) FROM "reimbursements" WHERE user_id = $1 AND expense_date >= $2 AND "reimbursements"."deleted_at" IS NULL`
+	pageQuery := `SELECT * FROM "reimbursements" WHERE user_id = $1 AND expense_date >= $2 AND "reimbursements"."deleted_at" IS NULL ORDER BY expense_date DESC, created_at DESC LIMIT $3`
+	categoriesQuery := `SELECT * FROM "reimbursement_categories" WHERE "reimbursement_categories"."id" = $1 AND "reimbursement_categories"."deleted_at" IS NULL`
+	receiptsQuery := `SELECT * FROM "reimbursement_receipts" WHERE "reimbursement_receipts"."reimbursement_id" = $1 AND "reimbursement_receipts"."deleted_at" IS NULL`
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(countQuery)).
+		WithArgs(userID, "2025-08-01").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(pageQuery)).
+		WithArgs(userID, "2025-08-01", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "category_id"}).AddRow(reimbursementID, userID, categoryID))
+	s.mock.ExpectQuery(regexp.QuoteMeta(categoriesQuery)).
+		WithArgs(categoryID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(categoryID, "Travel"))
+	s.mock.ExpectQuery(regexp.QuoteMeta(receiptsQuery)).
+		WithArgs(reimbursementID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reimbursement_id", "file_name"}).AddRow(uuid.New(), reimbursementID, "receipt.pdf"))
+
+	reimbursements, total, err := s.repo.GetReimbursementHistory(userID, domain.HistoryFilter{From: &from, Page: 1, PageSize: 10})
+	s.NoError(err)
+	s.Equal(int64(1), total)
+	s.Require().Len(reimbursements, 1)
+	s.Equal("Travel", reimbursements[0].Category.Name)
+	s.Len(reimbursements[0].Receipts, 1)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// ReimbursementCategoryRepository defines the interface for reimbursement category data operations.
+//
+//go:generate mockgen -source=reimbursement_category.repository.go -destination=../../tests/mocks/repository/mock_reimbursement_category_repository.go -package=mocks
+type ReimbursementCategoryRepository interface {
+	CreateReimbursementCategory(category *domain.ReimbursementCategory) error
+	GetReimbursementCategoryByID(id uuid.UUID) (*domain.ReimbursementCategory, error)
+	GetAllReimbursementCategories() ([]domain.ReimbursementCategory, error)
+	UpdateReimbursementCategory(category *domain.ReimbursementCategory) error
+}
+
+// ReimbursementCategoryGormRepository implements repository.ReimbursementCategoryRepository using GORM.
+type ReimbursementCategoryGormRepository struct {
+	db *gorm.DB
+}
+
+// NewReimbursementCategoryGormRepository creates a new ReimbursementCategoryGormRepository.
+func NewReimbursementCategoryGormRepository(db *gorm.DB) ReimbursementCategoryRepository {
+	return &ReimbursementCategoryGormRepository{db: db}
+}
+
+// CreateReimbursementCategory creates a new reimbursement category in the database. It returns
+// ErrDuplicateRecord when another category has the same name.
+func (r *ReimbursementCategoryGormRepository) CreateReimbursementCategory(category *domain.ReimbursementCategory) error {
+	return translateError(r.db.Create(category).Error)
+}
+
+// GetReimbursementCategoryByID retrieves a reimbursement category by its ID.
+func (r *ReimbursementCategoryGormRepository) GetReimbursementCategoryByID(id uuid.UUID) (*domain.ReimbursementCategory, error) {
+	var category domain.ReimbursementCategory
+	err := r.db.First(&category, "id = ?", id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &category, err
+}
+
+// GetAllReimbursementCategories retrieves every reimbursement category, ordered by name.
+func (r *ReimbursementCategoryGormRepository) GetAllReimbursementCategories() ([]domain.ReimbursementCategory, error) {
+	var categories []domain.ReimbursementCategory
+	err := r.db.Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+// UpdateReimbursementCategory updates an existing reimbursement category in the database. It returns
+// ErrDuplicateRecord when another category has the same name.
+func (r *ReimbursementCategoryGormRepository) UpdateReimbursementCategory(category *domain.ReimbursementCategory) error {
+	return translateError(r.db.Save(category).Error)
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// --- Test Suite Setup for ReimbursementCategoryRepository ---
+
+type ReimbursementCategoryRepositorySuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	repo ReimbursementCategoryRepository
+}
+
+// SetupSuite runs before the tests in the suite are run.
+func (s *ReimbursementCategoryRepositorySuite) SetupSuite() {
+	sqlDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	s.Require().NoError(err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = NewReimbursementCategoryGormRepository(db)
+}
+
+// TearDownTest runs after each test in the suite.
+func (s *ReimbursementCategoryRepositorySuite) TearDownTest() {
+	s.Require().NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestReimbursementCategoryRepository runs the test suite.
+func TestReimbursementCategoryRepository(t *testing.T) {
+	suite.Run(t, new(ReimbursementCategoryRepositorySuite))
+}
+
+// --- Test Cases ---
+
+func (s *ReimbursementCategoryRepositorySuite) TestCreateReimbursementCategory() {
+	categoryID := uuid.New()
+	monthly := decimal.NewFromInt(1000000)
+	category := &domain.ReimbursementCategory{
+		BaseModel:    domain.BaseModel{ID: categoryID},
+		Name:         "Travel",
+		MonthlyLimit: &monthly,
+	}
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reimbursement_categories"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(categoryID))
+				s.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Duplicate name",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reimbursement_categories"`)).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_reimbursement_categories_name"})
+				s.mock.ExpectRollback()
+			},
+			wantErr: ErrDuplicateRecord,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			err := s.repo.CreateReimbursementCategory(category)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func (s *ReimbursementCategoryRepositorySuite) TestGetReimbursementCategoryByID() {
+	categoryID := uuid.New()
+	query := `SELECT * FROM "reimbursement_categories" WHERE id = $1 AND "reimbursement_categories"."deleted_at" IS NULL ORDER BY "reimbursement_categories"."id" LIMIT $2`
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+		wantNil bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(categoryID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "per_claim_limit"}).AddRow(categoryID, "Travel", "500000"))
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(categoryID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			wantNil: true,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(categoryID, 1).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			category, err := s.repo.GetReimbursementCategoryByID(categoryID)
+			switch {
+			case tc.wantErr:
+				assert.Error(t, err)
+			case tc.wantNil:
+				assert.NoError(t, err)
+				assert.Nil(t, category)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, "Travel", category.Name)
+				assert.Equal(t, "500000", category.PerClaimLimit.String())
+				assert.Nil(t, category.YearlyLimit)
+			}
+		})
+	}
+}
+
+func (s *ReimbursementCategoryRepositorySuite) TestGetAllReimbursementCategories() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reimbursement_categories" WHERE "reimbursement_categories"."deleted_at" IS NULL ORDER BY name ASC`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(uuid.New(), "Meals").
+			AddRow(uuid.New(), "Travel"))
+
+	categories, err := s.repo.GetAllReimbursementCategories()
+	s.NoError(err)
+	s.Len(categories, 2)
+}
+
+func (s *ReimbursementCategoryRepositorySuite) TestUpdateReimbursementCategory() {
+	category := &domain.ReimbursementCategory{BaseModel: domain.BaseModel{ID: uuid.New()}, Name: "Meals"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reimbursement_categories" SET`)).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_reimbursement_categories_name"})
+	s.mock.ExpectRollback()
+
+	err := s.repo.UpdateReimbursementCategory(category)
+	s.ErrorIs(err, ErrDuplicateRecord)
+}
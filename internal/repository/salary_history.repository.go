@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// SalaryHistoryRepository defines the interface for salary history data operations.
+//
+//go:generate mockgen -source=salary_history.repository.go -destination=../../tests/mocks/repository/mock_salary_history_repository.go -package=mocks
+type SalaryHistoryRepository interface {
+	CreateSalaryHistory(history *domain.SalaryHistory) error
+	GetSalaryHistoriesByUserID(userID uuid.UUID) ([]domain.SalaryHistory, error)
+	GetSalaryHistoriesForPeriodByUserID(userID uuid.UUID, startDate, endDate time.Time) ([]domain.SalaryHistory, error)
+	GetSalaryHistoriesForPeriodGroupedByUser(startDate, endDate time.Time) (map[uuid.UUID][]domain.SalaryHistory, error)
+}
+
+// SalaryHistoryGormRepository implements repository.SalaryHistoryRepository using GORM.
+type SalaryHistoryGormRepository struct {
+	db *gorm.DB
+}
+
+// NewSalaryHistoryGormRepository creates a new SalaryHistoryGormRepository.
+func NewSalaryHistoryGormRepository(db *gorm.DB) SalaryHistoryRepository {
+	return &SalaryHistoryGormRepository{db: db}
+}
+
+// CreateSalaryHistory creates a new salary history record in the database. It returns
+// ErrDuplicateRecord when the employee already has a change on the same effective date.
+func (r *SalaryHistoryGormRepository) CreateSalaryHistory(history *domain.SalaryHistory) error {
+	return translateError(r.db.Create(history).Error)
+}
+
+// GetSalaryHistoriesByUserID retrieves every salary change of a user, oldest first.
+func (r *SalaryHistoryGormRepository) GetSalaryHistoriesByUserID(userID uuid.UUID) ([]domain.SalaryHistory, error) {
+	var histories []domain.SalaryHistory
+	err := r.db.Where("user_id = ?", userID).Order("effective_from ASC").Find(&histories).Error
+	return histories, err
+}
+
+// GetSalaryHistoriesForPeriodByUserID retrieves the salary changes of a user that are in force
+// during a date range, oldest first: the latest change effective on or before the start date and
+// every change effective within the range.
+func (r *SalaryHistoryGormRepository) GetSalaryHistoriesForPeriodByUserID(userID uuid.UUID, startDate, endDate time.Time) ([]domain.SalaryHistory, error) {
+	var histories []domain.SalaryHistory
+	err := r.inForce(startDate, endDate).
+		Where("user_id = ?", userID).
+		Order("effective_from ASC").
+		Find(&histories).Error
+	return histories, err
+}
+
+// GetSalaryHistoriesForPeriodGroupedByUser retrieves the salary changes of every user that are in
+// force during a date range, grouped by user ID and oldest first.
+func (r *SalaryHistoryGormRepository) GetSalaryHistoriesForPeriodGroupedByUser(startDate, endDate time.Time) (map[uuid.UUID][]domain.SalaryHistory, error) {
+	var histories []domain.SalaryHistory
+	err := r.inForce(startDate, endDate).
+		Order("user_id, effective_from ASC").
+		Find(&histories).Error
+	if err != nil {
+		return nil, err
+	}
+	return groupByUserID(histories, func(h domain.SalaryHistory) uuid.UUID { return h.UserID }), nil
+}
+
+// inForce scopes a query to the salary changes in force during a date range.
+func (r *SalaryHistoryGormRepository) inForce(startDate, endDate time.Time) *gorm.DB {
+	latestBeforeStart := r.db.Model(&domain.SalaryHistory{}).
+		Select("user_id, MAX(effective_from)").
+		Where("effective_from <= ?", startDate).
+		Group("user_id")
+	return r.db.Where("effective_from <= ? AND (effective_from > ? OR (user_id, effective_from) IN (?))", endDate, startDate, latestBeforeStart)
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// --- Test Suite Setup for SalaryHistoryRepository ---
+
+type SalaryHistoryRepositorySuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	repo SalaryHistoryRepository
+}
+
+// SetupSuite runs before the tests in the suite are run.
+func (s *SalaryHistoryRepositorySuite) SetupSuite() {
+	sqlDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	s.Require().NoError(err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = NewSalaryHistoryGormRepository(db)
+}
+
+// TearDownTest runs after each test in the suite.
+func (s *SalaryHistoryRepositorySuite) TearDownTest() {
+	s.Require().NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestSalaryHistoryRepository runs the test suite.
+func TestSalaryHistoryRepository(t *testing.T) {
+	suite.Run(t, new(SalaryHistoryRepositorySuite))
+}
+
+// --- Test Cases ---
+
+func (s *SalaryHistoryRepositorySuite) TestCreateSalaryHistory() {
+	historyID := uuid.New()
+	history := &domain.SalaryHistory{
+		BaseModel:     domain.BaseModel{ID: historyID},
+		UserID:        uuid.New(),
+		EffectiveFrom: time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC),
+		Salary:        decimal.NewFromInt(6000000),
+	}
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "salary_histories"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(historyID))
+				s.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Duplicate effective date",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "salary_histories"`)).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_salary_histories_user_effective"})
+				s.mock.ExpectRollback()
+			},
+			wantErr: ErrDuplicateRecord,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			err := s.repo.CreateSalaryHistory(history)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func (s *SalaryHistoryRepositorySuite) TestGetSalaryHistoriesByUserID() {
+	userID := uuid.New()
+	query := `SELECT * FROM "salary_histories" WHERE user_id = $1 AND "salary_histories"."deleted_at" IS NULL ORDER BY effective_from ASC`
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+		wantLen int
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uuid.New(), userID).AddRow(uuid.New(), userID)
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID).WillReturnRows(rows)
+			},
+			wantLen: 2,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID).WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			histories, err := s.repo.GetSalaryHistoriesByUserID(userID)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, histories, tc.wantLen)
+			}
+		})
+	}
+}
+
+func (s *SalaryHistoryRepositorySuite) TestGetSalaryHistoriesForPeriodByUserID() {
+	userID := uuid.New()
+	start := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+	query := `SELECT * FROM "salary_histories" WHERE (effective_from <= $1 AND (effective_from > $2 OR (user_id, effective_from) IN (SELECT user_id, MAX(effective_from) FROM "salary_histories" WHERE effective_from <= $3 AND "salary_histories"."deleted_at" IS NULL GROUP BY "user_id"))) AND user_id = $4 AND "salary_histories"."deleted_at" IS NULL ORDER BY effective_from ASC`
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(end, start, start, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uuid.New(), userID))
+
+	histories, err := s.repo.GetSalaryHistoriesForPeriodByUserID(userID, start, end)
+	s.NoError(err)
+	s.Len(histories, 1)
+}
+
+func (s *SalaryHistoryRepositorySuite) TestGetSalaryHistoriesForPeriodGroupedByUser() {
+	start := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+	query := `SELECT * FROM "salary_histories" WHERE (effective_from <= $1 AND (effective_from > $2 OR (user_id, effective_from) IN (SELECT user_id, MAX(effective_from) FROM "salary_histories" WHERE effective_from <= $3 AND "salary_histories"."deleted_at" IS NULL GROUP BY "user_id"))) AND "salary_histories"."deleted_at" IS NULL ORDER BY user_id, effective_from ASC`
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				first, second := uuid.New(), uuid.New()
+				rows := sqlmock.NewRows([]string{"id", "user_id"}).
+					AddRow(uuid.New(), first).
+					AddRow(uuid.New(), first).
+					AddRow(uuid.New(), second)
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(end, start, start).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(end, start, start).WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			grouped, err := s.repo.GetSalaryHistoriesForPeriodGroupedByUser(start, end)
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, grouped)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, grouped, 2)
+			}
+		})
+	}
+}
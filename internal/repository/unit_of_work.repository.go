@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// Repositories groups every repository bound to the same database handle. Within
+// UnitOfWork.Transaction all of them read and write through the same transaction.
+type Repositories struct {
+	Users                   UserRepository
+	EmployeeProfiles        EmployeeProfileRepository
+	PayrollPeriods          PayrollPeriodRepository
+	PayrollRuns             PayrollRunRepository
+	Attendances             AttendanceRepository
+	AttendanceSettings      AttendanceSettingRepository
+	Overtimes               OvertimeRepository
+	Reimbursements          ReimbursementRepository
+	ReimbursementCategories ReimbursementCategoryRepository
+	Payslips                PayslipRepository
+	SalaryHistories         SalaryHistoryRepository
+	Holidays                HolidayRepository
+	WorkSchedules           WorkScheduleRepository
+	Leaves                  LeaveRepository
+	AuditLogs               AuditLogRepository
+}
+
+// NewRepositories creates the GORM implementation of every repository on the given database handle.
+func NewRepositories(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Users:                   NewUserGormRepository(db),
+		EmployeeProfiles:        NewEmployeeProfileGormRepository(db),
+		PayrollPeriods:          NewPayrollPeriodGormRepository(db),
+		PayrollRuns:             NewPayrollRunGormRepository(db),
+		Attendances:             NewAttendanceGormRepository(db),
+		AttendanceSettings:      NewAttendanceSettingGormRepository(db),
+		Overtimes:               NewOvertimeGormRepository(db),
+		Reimbursements:          NewReimbursementGormRepository(db),
+		ReimbursementCategories: NewReimbursementCategoryGormRepository(db),
+		Payslips:                NewPayslipGormRepository(db),
+		SalaryHistories:         NewSalaryHistoryGormRepository(db),
+		Holidays:                NewHolidayGormRepository(db),
+		WorkSchedules:           NewWorkScheduleGormRepository(db),
+		Leaves:                  NewLeaveGormRepository(db),
+		AuditLogs:               NewAuditLogGormRepository(db),
+	}
+}
+
+// UnitOfWork runs a group of repository operations atomically.
+//
+//go:generate mockgen -source=unit_of_work.repository.go -destination=../../tests/mocks/repository/mock_unit_of_work_repository.go -package=mocks
+type UnitOfWork interface {
+	// Transaction runs fn within a database transaction. Every repository passed to fn is bound
+	// to that transaction: the transaction is committed when fn returns nil and rolled back when
+	// fn returns an error or panics.
+	Transaction(fn func(repos *Repositories) error) error
+}
+
+// GormUnitOfWork implements repository.UnitOfWork using GORM transactions.
+type GormUnitOfWork struct {
+	db *gorm.DB
+}
+
+// NewGormUnitOfWork creates a new GormUnitOfWork.
+func NewGormUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &GormUnitOfWork{db: db}
+}
+
+// Transaction runs fn within a database transaction with repositories bound to it.
+func (u *GormUnitOfWork) Transaction(fn func(repos *Repositories) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+}
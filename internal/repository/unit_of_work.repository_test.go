@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// --- Test Suite Setup for UnitOfWork ---
+
+type UnitOfWorkSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	uow  UnitOfWork
+}
+
+// SetupSuite runs before the tests in the suite are run.
+func (s *UnitOfWorkSuite) SetupSuite() {
+	sqlDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	s.Require().NoError(err)
+
+	s.db = db
+	s.mock = mock
+	s.uow = NewGormUnitOfWork(db)
+}
+
+// TearDownTest runs after each test in the suite.
+func (s *UnitOfWorkSuite) TearDownTest() {
+	s.Require().NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestUnitOfWork runs the test suite.
+func TestUnitOfWork(t *testing.T) {
+	suite.Run(t, new(UnitOfWorkSuite))
+}
+
+// --- Test Cases ---
+
+func (s *UnitOfWorkSuite) TestTransaction() {
+	periodID := uuid.New()
+	userID := uuid.New()
+
+	markProcessed := regexp.QuoteMeta(`UPDATE "payroll_periods" SET "is_processed"=$1,"processed_at"=$2,"updated_at"=$3 WHERE (id = $4 AND is_processed = $5) AND "payroll_periods"."deleted_at" IS NULL`)
+	insertAudit := regexp.QuoteMeta(`INSERT INTO "audit_logs"`)
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		fn      func(repos *Repositories) error
+		wantErr bool
+	}{
+		{
+			name: "Commits every repository write in one transaction",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectExec(markProcessed).
+					WithArgs(true, sqlmock.AnyArg(), sqlmock.AnyArg(), periodID, false).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.mock.ExpectQuery(insertAudit).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+				s.mock.ExpectCommit()
+			},
+			fn: func(repos *Repositories) error {
+				if err := repos.PayrollPeriods.MarkPayrollPeriodAsProcessed(periodID); err != nil {
+					return err
+				}
+				return CreateAuditLog(repos.AuditLogs, &userID, "UPDATE", "PayrollPeriod", &periodID, nil, domain.PayrollPeriod{}, "127.0.0.1", "req-1")
+			},
+		},
+		{
+			name: "Rolls back earlier writes when the audit log fails",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectExec(markProcessed).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.mock.ExpectQuery(insertAudit).
+					WillReturnError(errors.New("audit db error"))
+				s.mock.ExpectRollback()
+			},
+			fn: func(repos *Repositories) error {
+				if err := repos.PayrollPeriods.MarkPayrollPeriodAsProcessed(periodID); err != nil {
+					return err
+				}
+				return CreateAuditLog(repos.AuditLogs, &userID, "UPDATE", "PayrollPeriod", &periodID, nil, domain.PayrollPeriod{}, "127.0.0.1", "req-1")
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			err := s.uow.Transaction(tc.fn)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ type UserRepository interface {
 	CreateUser(user *domain.User) error
 	GetUserByUsername(username string) (*domain.User, error)
 	GetUserByID(id uuid.UUID) (*domain.User, error)
+	UpdateUser(user *domain.User) error
 }
 
 // UserGormRepository implements repository.UserRepository using GORM.
@@ -26,9 +27,10 @@ func NewUserGormRepository(db *gorm.DB) UserRepository {
 	return &UserGormRepository{db: db}
 }
 
-// CreateUser creates a new user in the database.
+// CreateUser creates a new user in the database. It returns ErrDuplicateRecord when the
+// username is already taken.
 func (r *UserGormRepository) CreateUser(user *domain.User) error {
-	return r.db.Create(user).Error
+	return translateError(r.db.Create(user).Error)
 }
 
 // GetUserByUsername retrieves a user by their username.
@@ -50,3 +52,9 @@ func (r *UserGormRepository) GetUserByID(id uuid.UUID) (*domain.User, error) {
 	}
 	return &user, err
 }
+
+// UpdateUser updates an existing user in the database. It returns ErrDuplicateRecord when the
+// new username is already taken.
+func (r *UserGormRepository) UpdateUser(user *domain.User) error {
+	return translateError(r.db.Save(user).Error)
+}
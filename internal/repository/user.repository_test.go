@@ -7,6 +7,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/postgres"
@@ -210,3 +211,59 @@ func (s *UserRepositorySuite) TestGetUserByID() {
 		})
 	}
 }
+
+func (s *UserRepositorySuite) TestUpdateUser() {
+	user := &domain.User{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		Username:  "renamed",
+		Role:      "employee",
+	}
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET`)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				s.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Duplicate username",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET`)).
+					WillReturnError(&pgconn.PgError{Code: "23505"})
+				s.mock.ExpectRollback()
+			},
+			wantErr: ErrDuplicateRecord,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET`)).
+					WillReturnError(errors.New("db error"))
+				s.mock.ExpectRollback()
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			err := s.repo.UpdateUser(user)
+			if tc.wantErr != nil {
+				assert.EqualError(t, err, tc.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
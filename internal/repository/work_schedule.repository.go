@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// WorkScheduleRepository defines the interface for work schedule data operations.
+//
+//go:generate mockgen -source=work_schedule.repository.go -destination=../../tests/mocks/repository/mock_work_schedule_repository.go -package=mocks
+type WorkScheduleRepository interface {
+	CreateWorkSchedule(schedule *domain.WorkSchedule) error
+	GetWorkScheduleByID(id uuid.UUID) (*domain.WorkSchedule, error)
+	GetWorkScheduleByUserID(userID uuid.UUID) (*domain.WorkSchedule, error)
+	GetAllWorkSchedules() ([]domain.WorkSchedule, error)
+	GetAssignedWorkSchedules() (map[uuid.UUID]*domain.WorkSchedule, error)
+	UpdateWorkSchedule(schedule *domain.WorkSchedule) error
+}
+
+// WorkScheduleGormRepository implements repository.WorkScheduleRepository using GORM.
+type WorkScheduleGormRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkScheduleGormRepository creates a new WorkScheduleGormRepository.
+func NewWorkScheduleGormRepository(db *gorm.DB) WorkScheduleRepository {
+	return &WorkScheduleGormRepository{db: db}
+}
+
+// CreateWorkSchedule creates a new work schedule in the database. It returns ErrDuplicateRecord
+// when another schedule has the same name.
+func (r *WorkScheduleGormRepository) CreateWorkSchedule(schedule *domain.WorkSchedule) error {
+	return translateError(r.db.Create(schedule).Error)
+}
+
+// GetWorkScheduleByID retrieves a work schedule by its ID.
+func (r *WorkScheduleGormRepository) GetWorkScheduleByID(id uuid.UUID) (*domain.WorkSchedule, error) {
+	var schedule domain.WorkSchedule
+	err := r.db.First(&schedule, "id = ?", id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &schedule, err
+}
+
+// GetWorkScheduleByUserID retrieves the work schedule assigned to a user. It returns nil when the
+// user has no assigned schedule and follows the default schedule.
+func (r *WorkScheduleGormRepository) GetWorkScheduleByUserID(userID uuid.UUID) (*domain.WorkSchedule, error) {
+	var schedule domain.WorkSchedule
+	err := r.db.
+		Where("id = (SELECT work_schedule_id FROM employee_profiles WHERE user_id = ? AND deleted_at IS NULL)", userID).
+		First(&schedule).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &schedule, err
+}
+
+// GetAllWorkSchedules retrieves every work schedule, ordered by name.
+func (r *WorkScheduleGormRepository) GetAllWorkSchedules() ([]domain.WorkSchedule, error) {
+	var schedules []domain.WorkSchedule
+	err := r.db.Order("name ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+// GetAssignedWorkSchedules retrieves the work schedule of every user with an assigned schedule,
+// keyed by user ID. Users without one follow the default schedule and are left out.
+func (r *WorkScheduleGormRepository) GetAssignedWorkSchedules() (map[uuid.UUID]*domain.WorkSchedule, error) {
+	var assignments []struct {
+		UserID         uuid.UUID
+		WorkScheduleID uuid.UUID
+	}
+	err := r.db.Model(&domain.EmployeeProfile{}).
+		Select("user_id, work_schedule_id").
+		Where("work_schedule_id IS NOT NULL").
+		Scan(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := make(map[uuid.UUID]*domain.WorkSchedule, len(assignments))
+	if len(assignments) == 0 {
+		return assigned, nil
+	}
+
+	schedules, err := r.GetAllWorkSchedules()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*domain.WorkSchedule, len(schedules))
+	for i := range schedules {
+		byID[schedules[i].ID] = &schedules[i]
+	}
+	for _, a := range assignments {
+		if schedule, ok := byID[a.WorkScheduleID]; ok {
+			assigned[a.UserID] = schedule
+		}
+	}
+	return assigned, nil
+}
+
+// UpdateWorkSchedule updates an existing work schedule. It returns ErrDuplicateRecord when another
+// schedule has the new name.
+func (r *WorkScheduleGormRepository) UpdateWorkSchedule(schedule *domain.WorkSchedule) error {
+	return translateError(r.db.Save(schedule).Error)
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"payroll-system/internal/domain"
+)
+
+// --- Test Suite Setup for WorkScheduleRepository ---
+
+type WorkScheduleRepositorySuite struct {
+	suite.Suite
+	db   *gorm.DB
+	mock sqlmock.Sqlmock
+	repo WorkScheduleRepository
+}
+
+// SetupSuite runs before the tests in the suite are run.
+func (s *WorkScheduleRepositorySuite) SetupSuite() {
+	sqlDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	s.Require().NoError(err)
+
+	s.db = db
+	s.mock = mock
+	s.repo = NewWorkScheduleGormRepository(db)
+}
+
+// TearDownTest runs after each test in the suite.
+func (s *WorkScheduleRepositorySuite) TearDownTest() {
+	s.Require().NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestWorkScheduleRepository runs the test suite.
+func TestWorkScheduleRepository(t *testing.T) {
+	suite.Run(t, new(WorkScheduleRepositorySuite))
+}
+
+// --- Test Cases ---
+
+func (s *WorkScheduleRepositorySuite) TestCreateWorkSchedule() {
+	scheduleID := uuid.New()
+	schedule := &domain.WorkSchedule{
+		BaseModel:   domain.BaseModel{ID: scheduleID},
+		Name:        "Retail",
+		WorkingDays: "mon,tue,wed,thu,fri,sat",
+		ShiftStart:  "10:00",
+		ShiftEnd:    "17:00",
+		DailyHours:  decimal.NewFromInt(7),
+	}
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "work_schedules"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(scheduleID))
+				s.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Duplicate name",
+			mock: func() {
+				s.mock.ExpectBegin()
+				s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "work_schedules"`)).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_work_schedules_name"})
+				s.mock.ExpectRollback()
+			},
+			wantErr: ErrDuplicateRecord,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			err := s.repo.CreateWorkSchedule(schedule)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func (s *WorkScheduleRepositorySuite) TestGetWorkScheduleByID() {
+	scheduleID := uuid.New()
+	query := `SELECT * FROM "work_schedules" WHERE id = $1 AND "work_schedules"."deleted_at" IS NULL ORDER BY "work_schedules"."id" LIMIT $2`
+
+	testCases := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+		wantNil bool
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(scheduleID, "Retail")
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(scheduleID, 1).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(scheduleID, 1).WillReturnError(gorm.ErrRecordNotFound)
+			},
+			wantNil: true,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(scheduleID, 1).WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			schedule, err := s.repo.GetWorkScheduleByID(scheduleID)
+			switch {
+			case tc.wantErr:
+				assert.Error(t, err)
+			case tc.wantNil:
+				assert.NoError(t, err)
+				assert.Nil(t, schedule)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, scheduleID, schedule.ID)
+			}
+		})
+	}
+}
+
+func (s *WorkScheduleRepositorySuite) TestGetWorkScheduleByUserID() {
+	userID := uuid.New()
+	query := `SELECT * FROM "work_schedules" WHERE (id = (SELECT work_schedule_id FROM employee_profiles WHERE user_id = $1 AND deleted_at IS NULL)) AND "work_schedules"."deleted_at" IS NULL ORDER BY "work_schedules"."id" LIMIT $2`
+
+	s.Run("Success", func() {
+		rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(uuid.New(), "Retail")
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID, 1).WillReturnRows(rows)
+
+		schedule, err := s.repo.GetWorkScheduleByUserID(userID)
+		s.NoError(err)
+		s.Equal("Retail", schedule.Name)
+	})
+
+	s.Run("Default schedule", func() {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID, 1).WillReturnError(gorm.ErrRecordNotFound)
+
+		schedule, err := s.repo.GetWorkScheduleByUserID(userID)
+		s.NoError(err)
+		s.Nil(schedule)
+	})
+}
+
+func (s *WorkScheduleRepositorySuite) TestGetAllWorkSchedules() {
+	query := `SELECT * FROM "work_schedules" WHERE "work_schedules"."deleted_at" IS NULL ORDER BY name ASC`
+
+	rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(uuid.New(), "Night Shift").AddRow(uuid.New(), "Retail")
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(rows)
+
+	schedules, err := s.repo.GetAllWorkSchedules()
+	s.NoError(err)
+	s.Len(schedules, 2)
+}
+
+func (s *WorkScheduleRepositorySuite) TestGetAssignedWorkSchedules() {
+	assignmentQuery := `SELECT user_id, work_schedule_id FROM "employee_profiles" WHERE work_schedule_id IS NOT NULL AND "employee_profiles"."deleted_at" IS NULL`
+	schedulesQuery := `SELECT * FROM "work_schedules" WHERE "work_schedules"."deleted_at" IS NULL ORDER BY name ASC`
+
+	s.Run("No assignments", func() {
+		s.mock.ExpectQuery(regexp.QuoteMeta(assignmentQuery)).WillReturnRows(sqlmock.NewRows([]string{"user_id", "work_schedule_id"}))
+
+		assigned, err := s.repo.GetAssignedWorkSchedules()
+		s.NoError(err)
+		s.Empty(assigned)
+	})
+
+	s.Run("Success", func() {
+		retailID, nightID := uuid.New(), uuid.New()
+		alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+
+		s.mock.ExpectQuery(regexp.QuoteMeta(assignmentQuery)).WillReturnRows(
+			sqlmock.NewRows([]string{"user_id", "work_schedule_id"}).
+				AddRow(alice, retailID).
+				AddRow(bob, retailID).
+				AddRow(carol, nightID),
+		)
+		s.mock.ExpectQuery(regexp.QuoteMeta(schedulesQuery)).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).AddRow(nightID, "Night Shift").AddRow(retailID, "Retail"),
+		)
+
+		assigned, err := s.repo.GetAssignedWorkSchedules()
+		s.NoError(err)
+		s.Len(assigned, 3)
+		s.Equal("Retail", assigned[alice].Name)
+		s.Same(assigned[alice], assigned[bob])
+		s.Equal("Night Shift", assigned[carol].Name)
+	})
+
+	s.Run("DB Error", func() {
+		s.mock.ExpectQuery(regexp.QuoteMeta(assignmentQuery)).WillReturnError(errors.New("db error"))
+
+		_, err := s.repo.GetAssignedWorkSchedules()
+		s.Error(err)
+	})
+}
+
+func (s *WorkScheduleRepositorySuite) TestUpdateWorkSchedule() {
+	schedule := &domain.WorkSchedule{
+		BaseModel:   domain.BaseModel{ID: uuid.New()},
+		Name:        "Retail",
+		WorkingDays: "tue,wed,thu,fri,sat",
+		ShiftStart:  "10:00",
+		ShiftEnd:    "18:00",
+		DailyHours:  decimal.NewFromInt(8),
+	}
+
+	s.Run("Success", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "work_schedules"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+
+		s.NoError(s.repo.UpdateWorkSchedule(schedule))
+	})
+
+	s.Run("Duplicate name", func() {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "work_schedules"`)).
+			WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_work_schedules_name"})
+		s.mock.ExpectRollback()
+
+		s.ErrorIs(s.repo.UpdateWorkSchedule(schedule), ErrDuplicateRecord)
+	})
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,82 +12,435 @@ import (
 	"payroll-system/internal/repository"
 )
 
+var (
+	// ErrAttendanceNotAllowed is returned when attendance is recorded on a rest day or a holiday.
+	ErrAttendanceNotAllowed = errors.New("attendance cannot be submitted")
+	// ErrAlreadyClockedIn is returned when an employee clocks in while clocked in, or clocks in
+	// again on a day with a recorded attendance.
+	ErrAlreadyClockedIn = errors.New("already clocked in")
+	// ErrNotClockedIn is returned when an employee clocks out without an open attendance.
+	ErrNotClockedIn = errors.New("not clocked in")
+)
+
 // AttendanceServiceInterface defines the methods of AttendanceService for mocking purposes.
 //
 //go:generate mockgen -source=attendance.service.go -destination=../../tests/mocks/service/mock_attendance_service.go -package=mocks
 type AttendanceServiceInterface interface {
 	// SubmitAttendance allows an employee to submit their attendance.
-	SubmitAttendance(userID uuid.UUID, checkInTime, checkOutTime time.Time, ipAddress string, requestID string) (*domain.Attendance, error)
+	SubmitAttendance(userID uuid.UUID, checkInTime time.Time, checkOutTime *time.Time, ipAddress string, requestID string) (*domain.Attendance, error)
+	// ClockIn opens the attendance of the day of now for an employee.
+	ClockIn(userID uuid.UUID, now time.Time, ipAddress string, requestID string) (*domain.Attendance, error)
+	// ClockOut closes the open attendance of an employee at now.
+	ClockOut(userID uuid.UUID, now time.Time, ipAddress string, requestID string) (*domain.Attendance, error)
+	// GetAttendanceHistory returns a page of an employee's attendance records and how many match the filter.
+	GetAttendanceHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Attendance, int64, error)
+	// CloseOpenAttendances closes the attendances left open at the end of their day, following the attendance policy.
+	CloseOpenAttendances(now time.Time, closedBy uuid.UUID, ipAddress string, requestID string) (int, error)
+	// GetAttendancePolicy returns the attendance policy in force.
+	GetAttendancePolicy() (AttendancePolicy, error)
+	// UpdateOpenAttendanceAction changes how attendances left open are closed and returns the policy in force.
+	UpdateOpenAttendanceAction(action string, updatedBy uuid.UUID, ipAddress string, requestID string) (AttendancePolicy, error)
 }
 
 // AttendanceService provides business logic for attendance management.
 type AttendanceService struct {
-	attendanceRepo repository.AttendanceRepository
-	auditRepo      repository.AuditLogRepository
+	attendanceRepo   repository.AttendanceRepository
+	holidayRepo      repository.HolidayRepository
+	workScheduleRepo repository.WorkScheduleRepository
+	settingRepo      repository.AttendanceSettingRepository
+	auditRepo        repository.AuditLogRepository
+	periodLock       *PeriodLock
+	policy           AttendancePolicy // Configured at startup; the open attendance action an admin saved takes precedence
 }
 
 // NewAttendanceService creates a new AttendanceService.
-func NewAttendanceService(attendanceRepo repository.AttendanceRepository, auditRepo repository.AuditLogRepository) *AttendanceService {
+func NewAttendanceService(
+	attendanceRepo repository.AttendanceRepository,
+	holidayRepo repository.HolidayRepository,
+	workScheduleRepo repository.WorkScheduleRepository,
+	settingRepo repository.AttendanceSettingRepository,
+	auditRepo repository.AuditLogRepository,
+	periodLock *PeriodLock,
+	policy AttendancePolicy,
+) *AttendanceService {
 	return &AttendanceService{
-		attendanceRepo: attendanceRepo,
-		auditRepo:      auditRepo,
+		attendanceRepo:   attendanceRepo,
+		holidayRepo:      holidayRepo,
+		workScheduleRepo: workScheduleRepo,
+		settingRepo:      settingRepo,
+		auditRepo:        auditRepo,
+		periodLock:       periodLock,
+		policy:           policy,
 	}
 }
 
 // SubmitAttendance allows an employee to submit their attendance.
 // It handles both check-in and check-out, and updates existing records for the same day.
-func (s *AttendanceService) SubmitAttendance(userID uuid.UUID, checkInTime, checkOutTime time.Time, ipAddress string, requestID string) (*domain.Attendance, error) {
-	// Rule: Users cannot submit on weekends.
-	if checkInTime.Weekday() == time.Saturday || checkInTime.Weekday() == time.Sunday {
-		return nil, errors.New("attendance cannot be submitted on weekends")
+// Without a check-out the attendance stays open until the employee clocks out.
+// The attendance belongs to the day of the check-in, also when an overnight shift checks out the
+// next day, and cannot be submitted for a day in a processed payroll period.
+func (s *AttendanceService) SubmitAttendance(userID uuid.UUID, checkInTime time.Time, checkOutTime *time.Time, ipAddress string, requestID string) (*domain.Attendance, error) {
+	schedule, err := s.workingDaySchedule(userID, checkInTime)
+	if err != nil {
+		return nil, err
 	}
 
-	now := time.Now()
+	var attendance *domain.Attendance
+	err = s.periodLock.Transaction(checkInTime, checkInTime, func(repos *repository.Repositories) error {
+		now := time.Now()
+
+		// Check if an attendance record already exists for this user and date.
+		existingAttendance, err := repos.Attendances.GetAttendanceByUserIDAndDate(userID, checkInTime)
+		if err != nil {
+			return err
+		}
+
+		if existingAttendance != nil {
+			// Update existing record
+			oldValue := *existingAttendance
+			existingAttendance.CheckInTime = checkInTime
+			existingAttendance.CheckOutTime = checkOutTime
+			existingAttendance.UpdatedAt = now
+			existingAttendance.UpdatedBy = userID
+			existingAttendance.IPAddress = ipAddress
+
+			if err := repos.Attendances.UpdateAttendance(existingAttendance); err != nil {
+				return err
+			}
+			existingAttendance.WorkSchedule = &schedule
+			attendance = existingAttendance
+
+			// Create audit log
+			return writeAttendanceAuditLog(repos, userID, "UPDATE", attendance, oldValue, ipAddress, requestID)
+		}
+
+		// Create new attendance record
+		attendance = &domain.Attendance{
+			UserID:       userID,
+			Date:         checkInTime,
+			CheckInTime:  checkInTime,
+			CheckOutTime: checkOutTime,
+			WorkSchedule: &schedule,
+			BaseModel: domain.BaseModel{
+				CreatedAt: now,
+				UpdatedAt: now,
+				CreatedBy: userID,
+				UpdatedBy: userID,
+				IPAddress: ipAddress,
+			},
+		}
+
+		if err := repos.Attendances.CreateAttendance(attendance); err != nil {
+			return err
+		}
 
-	// Check if an attendance record already exists for this user and date.
-	existingAttendance, err := s.attendanceRepo.GetAttendanceByUserIDAndDate(userID, checkInTime)
+		// Create audit log for creation
+		return writeAttendanceAuditLog(repos, userID, "CREATE", attendance, nil, ipAddress, requestID)
+	})
 	if err != nil {
 		return nil, err
 	}
+	return attendance, nil
+}
 
-	if existingAttendance != nil {
-		// Update existing record
-		oldValue := *existingAttendance
-		existingAttendance.CheckInTime = checkInTime
-		existingAttendance.CheckOutTime = checkOutTime
-		existingAttendance.UpdatedAt = now
-		existingAttendance.UpdatedBy = userID
-		existingAttendance.IPAddress = ipAddress
+// ClockIn opens the attendance of the day of now for an employee. An employee can clock in once a
+// day. An attendance of an earlier day that is still open was left open and is first closed
+// following the attendance policy, unless the employee is still within that day's overnight shift.
+// An attendance left open in a processed payroll period stays as it was paid.
+func (s *AttendanceService) ClockIn(userID uuid.UUID, now time.Time, ipAddress string, requestID string) (*domain.Attendance, error) {
+	schedule, err := s.workingDaySchedule(userID, now)
+	if err != nil {
+		return nil, err
+	}
 
-		if err := s.attendanceRepo.UpdateAttendance(existingAttendance); err != nil {
+	open, err := s.attendanceRepo.GetOpenAttendanceByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	var policy AttendancePolicy
+	if open != nil {
+		if sameDate(open.Date, now) || now.Before(schedule.ShiftEndsAt(dateIn(open.Date, now.Location()))) {
+			return nil, fmt.Errorf("%w: clock out of the attendance of %s first", ErrAlreadyClockedIn, open.Date.Format("2006-01-02"))
+		}
+		if policy, err = s.GetAttendancePolicy(); err != nil {
 			return nil, err
 		}
+	}
+
+	var attendance *domain.Attendance
+	err = s.periodLock.Transaction(now, now, func(repos *repository.Repositories) error {
+		if open != nil {
+			err := s.periodLock.Check(repos, open.Date)
+			if err == nil {
+				err = s.closeOpenAttendance(repos, open, schedule, policy, userID, ipAddress, requestID)
+			}
+			if err != nil && !errors.Is(err, ErrPeriodLocked) {
+				return err
+			}
+		}
+
+		existingAttendance, err := repos.Attendances.GetAttendanceByUserIDAndDate(userID, now)
+		if err != nil {
+			return err
+		}
+		if existingAttendance != nil {
+			return fmt.Errorf("%w: attendance of %s is already recorded", ErrAlreadyClockedIn, now.Format("2006-01-02"))
+		}
+
+		attendance = &domain.Attendance{
+			UserID:       userID,
+			Date:         now,
+			CheckInTime:  now,
+			WorkSchedule: &schedule,
+			BaseModel: domain.BaseModel{
+				CreatedAt: now,
+				UpdatedAt: now,
+				CreatedBy: userID,
+				UpdatedBy: userID,
+				IPAddress: ipAddress,
+			},
+		}
+
+		if err := repos.Attendances.CreateAttendance(attendance); err != nil {
+			return err
+		}
+		return writeAttendanceAuditLog(repos, userID, "CLOCK_IN", attendance, nil, ipAddress, requestID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attendance, nil
+}
+
+// ClockOut closes the open attendance of an employee at now. An overnight shift clocks out on the
+// day after its attendance. An attendance left open past the end of its day is closed following
+// the attendance policy instead, and the employee is not clocked in.
+func (s *AttendanceService) ClockOut(userID uuid.UUID, now time.Time, ipAddress string, requestID string) (*domain.Attendance, error) {
+	attendance, err := s.attendanceRepo.GetOpenAttendanceByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if attendance == nil {
+		return nil, fmt.Errorf("%w: clock in first", ErrNotClockedIn)
+	}
 
-		// Create audit log
-		_ = repository.CreateAuditLog(s.auditRepo, &userID, "UPDATE", "Attendance", &existingAttendance.ID, oldValue, existingAttendance, ipAddress, requestID)
-		return existingAttendance, nil
+	assigned, err := s.workScheduleRepo.GetWorkScheduleByUserID(userID)
+	if err != nil {
+		return nil, err
 	}
+	schedule := domain.WorkScheduleOrDefault(assigned)
 
-	// Create new attendance record
-	newAttendance := &domain.Attendance{
-		UserID:       userID,
-		Date:         checkInTime,
-		CheckInTime:  checkInTime,
-		CheckOutTime: checkOutTime,
-		BaseModel: domain.BaseModel{
-			CreatedAt: now,
-			UpdatedAt: now,
-			CreatedBy: userID,
-			UpdatedBy: userID,
-			IPAddress: ipAddress,
-		},
+	if !now.Before(schedule.AttendanceClosesAt(dateIn(attendance.Date, now.Location()))) {
+		policy, err := s.GetAttendancePolicy()
+		if err != nil {
+			return nil, err
+		}
+		err = s.periodLock.Transaction(attendance.Date, attendance.Date, func(repos *repository.Repositories) error {
+			return s.closeOpenAttendance(repos, attendance, schedule, policy, userID, ipAddress, requestID)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: the attendance of %s was left open and has been closed, clock in first", ErrNotClockedIn, attendance.Date.Format("2006-01-02"))
 	}
 
-	if err := s.attendanceRepo.CreateAttendance(newAttendance); err != nil {
+	err = s.periodLock.Transaction(attendance.Date, attendance.Date, func(repos *repository.Repositories) error {
+		oldValue := *attendance
+		attendance.CheckOutTime = &now
+		attendance.UpdatedAt = now
+		attendance.UpdatedBy = userID
+		attendance.IPAddress = ipAddress
+
+		if err := repos.Attendances.UpdateAttendance(attendance); err != nil {
+			return err
+		}
+		attendance.WorkSchedule = &schedule
+		return writeAttendanceAuditLog(repos, userID, "CLOCK_OUT", attendance, oldValue, ipAddress, requestID)
+	})
+	if err != nil {
 		return nil, err
 	}
+	return attendance, nil
+}
+
+// GetAttendanceHistory returns a page of an employee's attendance records, latest first, and how many
+// records match the filter. The hours worked are those under the employee's current work schedule.
+func (s *AttendanceService) GetAttendanceHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Attendance, int64, error) {
+	attendances, total, err := s.attendanceRepo.GetAttendanceHistory(userID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	assigned, err := s.workScheduleRepo.GetWorkScheduleByUserID(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	schedule := domain.WorkScheduleOrDefault(assigned)
+	for i := range attendances {
+		attendances[i].WorkSchedule = &schedule
+	}
+	return attendances, total, nil
+}
+
+// CloseOpenAttendances closes every attendance still open at the end of its day, following the
+// attendance policy, and returns how many were closed. It is run periodically by the server with
+// closedBy set to uuid.Nil, and can be triggered by an admin. Attendances in a processed payroll
+// period are left open and logged; an attendance that fails to close does not stop the others, and
+// the failures are returned together.
+func (s *AttendanceService) CloseOpenAttendances(now time.Time, closedBy uuid.UUID, ipAddress string, requestID string) (int, error) {
+	attendances, err := s.attendanceRepo.GetOpenAttendances()
+	if err != nil {
+		return 0, err
+	}
+	if len(attendances) == 0 {
+		return 0, nil
+	}
+
+	schedules, err := s.workScheduleRepo.GetAssignedWorkSchedules()
+	if err != nil {
+		return 0, err
+	}
+	policy, err := s.GetAttendancePolicy()
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	var errs []error
+	for i := range attendances {
+		schedule := domain.WorkScheduleOrDefault(schedules[attendances[i].UserID])
+		if now.Before(schedule.AttendanceClosesAt(dateIn(attendances[i].Date, now.Location()))) {
+			continue
+		}
+		attendance := &attendances[i]
+		err := s.periodLock.Transaction(attendance.Date, attendance.Date, func(repos *repository.Repositories) error {
+			return s.closeOpenAttendance(repos, attendance, schedule, policy, closedBy, ipAddress, requestID)
+		})
+		switch {
+		case errors.Is(err, ErrPeriodLocked):
+			log.Printf("attendance %s left open: %v", attendances[i].ID, err)
+		case err != nil:
+			errs = append(errs, fmt.Errorf("attendance %s: %w", attendances[i].ID, err))
+		default:
+			closed++
+		}
+	}
+	return closed, errors.Join(errs...)
+}
+
+// GetAttendancePolicy returns the attendance policy in force: the policy configured at startup, with
+// the open attendance action an admin saved, if any.
+func (s *AttendanceService) GetAttendancePolicy() (AttendancePolicy, error) {
+	policy := s.policy
+	setting, err := s.settingRepo.GetAttendanceSetting()
+	if err != nil {
+		return AttendancePolicy{}, err
+	}
+	if setting != nil {
+		policy.OpenAttendance = OpenAttendanceAction(setting.OpenAttendance)
+	}
+	return policy, nil
+}
+
+// UpdateOpenAttendanceAction saves how attendances left open are closed from now on, unpaid or
+// shift_end, and returns the policy in force. It returns ErrInvalidOpenAttendanceAction for any
+// other action. Attendances already closed keep their check-out.
+func (s *AttendanceService) UpdateOpenAttendanceAction(action string, updatedBy uuid.UUID, ipAddress string, requestID string) (AttendancePolicy, error) {
+	openAttendance, err := ParseOpenAttendanceAction(action)
+	if err != nil {
+		return AttendancePolicy{}, err
+	}
+
+	setting, err := s.settingRepo.GetAttendanceSetting()
+	if err != nil {
+		return AttendancePolicy{}, err
+	}
+	now := time.Now()
+	auditAction, oldValue := "UPDATE", any(nil)
+	if setting == nil {
+		auditAction = "CREATE"
+		setting = &domain.AttendanceSetting{
+			BaseModel: domain.BaseModel{
+				CreatedAt: now,
+				CreatedBy: updatedBy,
+			},
+		}
+	} else {
+		oldValue = *setting
+	}
+	setting.OpenAttendance = string(openAttendance)
+	setting.UpdatedAt = now
+	setting.UpdatedBy = updatedBy
+	setting.IPAddress = ipAddress
+
+	if err := s.settingRepo.SaveAttendanceSetting(setting); err != nil {
+		return AttendancePolicy{}, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, &updatedBy, auditAction, "AttendanceSetting", &setting.ID, oldValue, setting, ipAddress, requestID)
+
+	policy := s.policy
+	policy.OpenAttendance = openAttendance
+	return policy, nil
+}
+
+// closeOpenAttendance records the check-out of an attendance left open, as set by the attendance
+// policy, through repos. Callers check first, in the same transaction, that the attendance is not
+// dated in a processed payroll period.
+func (s *AttendanceService) closeOpenAttendance(repos *repository.Repositories, attendance *domain.Attendance, schedule domain.WorkSchedule, policy AttendancePolicy, closedBy uuid.UUID, ipAddress string, requestID string) error {
+	oldValue := *attendance
+	checkOut := policy.CheckOutFor(*attendance, schedule)
+	attendance.CheckOutTime = &checkOut
+	attendance.UpdatedAt = time.Now()
+	attendance.UpdatedBy = closedBy
+	attendance.IPAddress = ipAddress
+
+	if err := repos.Attendances.UpdateAttendance(attendance); err != nil {
+		return err
+	}
+	return writeAttendanceAuditLog(repos, closedBy, "CLOSE", attendance, oldValue, ipAddress, requestID)
+}
+
+// writeAttendanceAuditLog records a change of an attendance in the audit log through repos, so the
+// change is rolled back with its transaction if the entry cannot be written.
+func writeAttendanceAuditLog(repos *repository.Repositories, userID uuid.UUID, action string, attendance *domain.Attendance, oldValue any, ipAddress string, requestID string) error {
+	if err := repository.CreateAuditLog(repos.AuditLogs, &userID, action, "Attendance", &attendance.ID, oldValue, attendance, ipAddress, requestID); err != nil {
+		return fmt.Errorf("failed to write audit log for attendance: %w", err)
+	}
+	return nil
+}
+
+// workingDaySchedule returns the work schedule of an employee after checking that date is one of its
+// working days and not a holiday.
+func (s *AttendanceService) workingDaySchedule(userID uuid.UUID, date time.Time) (domain.WorkSchedule, error) {
+	assigned, err := s.workScheduleRepo.GetWorkScheduleByUserID(userID)
+	if err != nil {
+		return domain.WorkSchedule{}, err
+	}
+	schedule := domain.WorkScheduleOrDefault(assigned)
+
+	// Rule: Users cannot submit on the rest days of their work schedule.
+	if !schedule.WorksOn(date.Weekday()) {
+		return schedule, fmt.Errorf("%w on rest days: %s is not a working day of the %s schedule", ErrAttendanceNotAllowed, date.Weekday(), schedule.Name)
+	}
+
+	// Rule: Users cannot submit on holidays of the holiday calendar.
+	holiday, err := s.holidayRepo.GetHolidayByDate(date)
+	if err != nil {
+		return schedule, err
+	}
+	if holiday != nil {
+		return schedule, fmt.Errorf("%w on holidays: %s is %s", ErrAttendanceNotAllowed, date.Format("2006-01-02"), holiday.Name)
+	}
+	return schedule, nil
+}
+
+// sameDate reports whether a and b fall on the same calendar day.
+func sameDate(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
 
-	// Create audit log for creation
-	_ = repository.CreateAuditLog(s.auditRepo, &userID, "CREATE", "Attendance", &newAttendance.ID, nil, newAttendance, ipAddress, requestID)
-	return newAttendance, nil
+// dateIn returns the midnight of date in loc. Attendance dates are loaded from the database in UTC.
+func dateIn(date time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
 }
@@ -6,10 +6,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
 	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
 	"payroll-system/internal/service"
 	mockRepo "payroll-system/tests/mocks/repository"
 )
@@ -19,12 +21,23 @@ func TestSubmitAttendance(t *testing.T) {
 	ip := "127.0.0.1"
 	requestID := "req-123"
 	now := time.Date(2025, 8, 18, 9, 0, 0, 0, time.UTC) // Monday
+	previousCheckOut := now.Add(7 * time.Hour)
+	sixDayWeek := &domain.WorkSchedule{
+		Name:        "Retail",
+		WorkingDays: "mon,tue,wed,thu,fri,sat",
+		ShiftStart:  "10:00",
+		ShiftEnd:    "17:00",
+		DailyHours:  decimal.NewFromInt(7),
+	}
 
 	tests := []struct {
 		name            string
 		checkIn         time.Time
 		checkOut        time.Time
 		mockExisting    *domain.Attendance
+		mockHoliday     *domain.Holiday
+		mockSchedule    *domain.WorkSchedule
+		mockProcessed   *domain.PayrollPeriod
 		mockGetError    error
 		mockCreateError error
 		mockUpdateError error
@@ -36,7 +49,35 @@ func TestSubmitAttendance(t *testing.T) {
 			name:          "weekend submission error",
 			checkIn:       time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC), // Saturday
 			checkOut:      time.Date(2025, 8, 16, 17, 0, 0, 0, time.UTC),
-			expectedError: "attendance cannot be submitted on weekends",
+			expectedError: "attendance cannot be submitted on rest days: Saturday is not a working day of the Standard schedule",
+		},
+		{
+			name:          "rest day of assigned schedule error",
+			checkIn:       time.Date(2025, 8, 17, 10, 0, 0, 0, time.UTC), // Sunday
+			checkOut:      time.Date(2025, 8, 17, 17, 0, 0, 0, time.UTC),
+			mockSchedule:  sixDayWeek,
+			expectedError: "attendance cannot be submitted on rest days: Sunday is not a working day of the Retail schedule",
+		},
+		{
+			name:         "working saturday of assigned schedule success",
+			checkIn:      time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC), // Saturday
+			checkOut:     time.Date(2025, 8, 16, 17, 0, 0, 0, time.UTC),
+			mockSchedule: sixDayWeek,
+			expectCreate: true,
+		},
+		{
+			name:          "holiday submission error",
+			checkIn:       time.Date(2025, 8, 18, 9, 0, 0, 0, time.UTC), // Monday
+			checkOut:      time.Date(2025, 8, 18, 17, 0, 0, 0, time.UTC),
+			mockHoliday:   &domain.Holiday{Date: time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC), Name: "Cuti Bersama Hari Kemerdekaan", Type: domain.HolidayTypeCollectiveLeave},
+			expectedError: "attendance cannot be submitted on holidays: 2025-08-18 is Cuti Bersama Hari Kemerdekaan",
+		},
+		{
+			name:          "processed payroll period error",
+			checkIn:       now,
+			checkOut:      now.Add(8 * time.Hour),
+			mockProcessed: august2025,
+			expectedError: "payroll period is locked: 2025-08-18 falls in the payroll period 2025-08-01 to 2025-08-31, which has already been processed",
 		},
 		{
 			name:         "new attendance success",
@@ -54,7 +95,7 @@ func TestSubmitAttendance(t *testing.T) {
 				UserID:       userID,
 				Date:         now, // must match checkIn to trigger update
 				CheckInTime:  now.Add(-1 * time.Hour),
-				CheckOutTime: now.Add(7 * time.Hour),
+				CheckOutTime: &previousCheckOut,
 			},
 			expectUpdate: true,
 		},
@@ -96,8 +137,24 @@ func TestSubmitAttendance(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockAttendanceRepo := mockRepo.NewMockAttendanceRepository(ctrl)
+			mockHolidayRepo := mockRepo.NewMockHolidayRepository(ctrl)
+			mockWorkScheduleRepo := mockRepo.NewMockWorkScheduleRepository(ctrl)
 			mockAuditRepo := mockRepo.NewMockAuditLogRepository(ctrl)
-			svc := service.NewAttendanceService(mockAttendanceRepo, mockAuditRepo)
+			svc := service.NewAttendanceService(mockAttendanceRepo, mockHolidayRepo, mockWorkScheduleRepo, newAttendanceSettingRepo(ctrl, nil), mockAuditRepo, newPeriodLock(ctrl, tt.mockProcessed, &repository.Repositories{Attendances: mockAttendanceRepo, AuditLogs: mockAuditRepo}), service.DefaultAttendancePolicy())
+
+			// Mock GetWorkScheduleByUserID
+			mockWorkScheduleRepo.
+				EXPECT().
+				GetWorkScheduleByUserID(userID).
+				Return(tt.mockSchedule, nil).
+				Times(1)
+
+			// Mock GetHolidayByDate
+			mockHolidayRepo.
+				EXPECT().
+				GetHolidayByDate(tt.checkIn).
+				Return(tt.mockHoliday, nil).
+				AnyTimes()
 
 			// Mock GetAttendanceByUserIDAndDate
 			mockAttendanceRepo.
@@ -129,7 +186,7 @@ func TestSubmitAttendance(t *testing.T) {
 				Return(nil).
 				AnyTimes()
 
-			att, err := svc.SubmitAttendance(userID, tt.checkIn, tt.checkOut, ip, requestID)
+			att, err := svc.SubmitAttendance(userID, tt.checkIn, &tt.checkOut, ip, requestID)
 
 			if tt.expectedError != "" {
 				assert.Nil(t, att)
@@ -142,3 +199,443 @@ func TestSubmitAttendance(t *testing.T) {
 		})
 	}
 }
+
+func TestClockIn(t *testing.T) {
+	userID := uuid.New()
+	monday := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	now := monday.Add(9 * time.Hour)
+	nightShift := &domain.WorkSchedule{
+		Name:        "Night Shift",
+		WorkingDays: "mon,tue,wed,thu,fri",
+		ShiftStart:  "22:00",
+		ShiftEnd:    "06:00",
+		DailyHours:  decimal.NewFromInt(8),
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		mockSchedule  *domain.WorkSchedule
+		mockOpen      *domain.Attendance
+		mockExisting  *domain.Attendance
+		mockProcessed *domain.PayrollPeriod
+		expectClose   time.Time // check-out recorded for the open attendance, if it is closed
+		expectCreate  bool
+		expectedError error
+	}{
+		{
+			name:         "success",
+			now:          now,
+			expectCreate: true,
+		},
+		{
+			name:          "already clocked in today",
+			now:           now.Add(time.Hour),
+			mockOpen:      &domain.Attendance{UserID: userID, Date: monday, CheckInTime: now},
+			expectedError: service.ErrAlreadyClockedIn,
+		},
+		{
+			name:          "attendance of today already recorded",
+			now:           now.Add(9 * time.Hour),
+			mockExisting:  &domain.Attendance{UserID: userID, Date: monday, CheckInTime: now},
+			expectedError: service.ErrAlreadyClockedIn,
+		},
+		{
+			name:          "still within last night's shift",
+			now:           monday.AddDate(0, 0, 1).Add(2 * time.Hour), // Tuesday 02:00
+			mockSchedule:  nightShift,
+			mockOpen:      &domain.Attendance{UserID: userID, Date: monday, CheckInTime: monday.Add(22 * time.Hour)},
+			expectedError: service.ErrAlreadyClockedIn,
+		},
+		{
+			name:         "attendance of yesterday left open is closed first",
+			now:          now.AddDate(0, 0, 1),
+			mockOpen:     &domain.Attendance{UserID: userID, Date: monday, CheckInTime: now},
+			expectClose:  now,
+			expectCreate: true,
+		},
+		{
+			name:          "attendance left open in a processed period stays open",
+			now:           time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC), // Monday after the processed August
+			mockOpen:      &domain.Attendance{UserID: userID, Date: time.Date(2025, 8, 29, 0, 0, 0, 0, time.UTC), CheckInTime: now},
+			mockProcessed: august2025,
+			expectCreate:  true,
+		},
+		{
+			name:          "rest day",
+			now:           now.AddDate(0, 0, -1), // Sunday
+			expectedError: service.ErrAttendanceNotAllowed,
+		},
+		{
+			name:          "processed payroll period",
+			now:           now,
+			mockProcessed: august2025,
+			expectedError: service.ErrPeriodLocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAttendanceRepo := mockRepo.NewMockAttendanceRepository(ctrl)
+			mockHolidayRepo := mockRepo.NewMockHolidayRepository(ctrl)
+			mockWorkScheduleRepo := mockRepo.NewMockWorkScheduleRepository(ctrl)
+			mockAuditRepo := mockRepo.NewMockAuditLogRepository(ctrl)
+			svc := service.NewAttendanceService(mockAttendanceRepo, mockHolidayRepo, mockWorkScheduleRepo, newAttendanceSettingRepo(ctrl, nil), mockAuditRepo, newPeriodLock(ctrl, tt.mockProcessed, &repository.Repositories{Attendances: mockAttendanceRepo, AuditLogs: mockAuditRepo}), service.DefaultAttendancePolicy())
+
+			mockWorkScheduleRepo.EXPECT().GetWorkScheduleByUserID(userID).Return(tt.mockSchedule, nil)
+			mockHolidayRepo.EXPECT().GetHolidayByDate(tt.now).Return(nil, nil).AnyTimes()
+			mockAttendanceRepo.EXPECT().GetOpenAttendanceByUserID(userID).Return(tt.mockOpen, nil).AnyTimes()
+			mockAttendanceRepo.EXPECT().GetAttendanceByUserIDAndDate(userID, tt.now).Return(tt.mockExisting, nil).AnyTimes()
+			mockAuditRepo.EXPECT().Create(gomock.Any()).Return(nil).AnyTimes()
+			if !tt.expectClose.IsZero() {
+				mockAttendanceRepo.EXPECT().UpdateAttendance(tt.mockOpen).DoAndReturn(func(a *domain.Attendance) error {
+					assert.Equal(t, tt.expectClose, *a.CheckOutTime)
+					return nil
+				})
+			}
+			if tt.expectCreate {
+				mockAttendanceRepo.EXPECT().CreateAttendance(gomock.Any()).Return(nil)
+			}
+
+			att, err := svc.ClockIn(userID, tt.now, "127.0.0.1", "req-123")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, att)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, att.IsOpen())
+			assert.Equal(t, tt.now, att.CheckInTime)
+		})
+	}
+}
+
+func TestClockOut(t *testing.T) {
+	userID := uuid.New()
+	monday := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	nightShift := &domain.WorkSchedule{
+		Name:        "Night Shift",
+		WorkingDays: "mon,tue,wed,thu,fri",
+		ShiftStart:  "22:00",
+		ShiftEnd:    "06:00",
+		DailyHours:  decimal.NewFromInt(8),
+	}
+	// Check-in times are loaded from the database without their date
+	clock := func(hour int) time.Time { return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		mockSchedule  *domain.WorkSchedule
+		mockOpen      *domain.Attendance
+		mockProcessed *domain.PayrollPeriod
+		expectedHours float64
+		expectedError error
+	}{
+		{
+			name:          "success",
+			now:           monday.Add(17 * time.Hour),
+			mockOpen:      &domain.Attendance{UserID: userID, Date: monday, CheckInTime: clock(9)},
+			expectedHours: 8,
+		},
+		{
+			name:          "overnight shift clocks out the next day",
+			now:           monday.AddDate(0, 0, 1).Add(6 * time.Hour),
+			mockSchedule:  nightShift,
+			mockOpen:      &domain.Attendance{UserID: userID, Date: monday, CheckInTime: clock(22)},
+			expectedHours: 8,
+		},
+		{
+			name:          "not clocked in",
+			now:           monday.Add(17 * time.Hour),
+			expectedError: service.ErrNotClockedIn,
+		},
+		{
+			name:          "attendance left open past the end of its day",
+			now:           monday.AddDate(0, 0, 1).Add(17 * time.Hour),
+			mockOpen:      &domain.Attendance{UserID: userID, Date: monday, CheckInTime: clock(9)},
+			expectedError: service.ErrNotClockedIn,
+		},
+		{
+			name:          "attendance in a processed payroll period",
+			now:           monday.Add(17 * time.Hour),
+			mockOpen:      &domain.Attendance{UserID: userID, Date: monday, CheckInTime: clock(9)},
+			mockProcessed: august2025,
+			expectedError: service.ErrPeriodLocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAttendanceRepo := mockRepo.NewMockAttendanceRepository(ctrl)
+			mockWorkScheduleRepo := mockRepo.NewMockWorkScheduleRepository(ctrl)
+			mockAuditRepo := mockRepo.NewMockAuditLogRepository(ctrl)
+			svc := service.NewAttendanceService(mockAttendanceRepo, mockRepo.NewMockHolidayRepository(ctrl), mockWorkScheduleRepo, newAttendanceSettingRepo(ctrl, nil), mockAuditRepo, newPeriodLock(ctrl, tt.mockProcessed, &repository.Repositories{Attendances: mockAttendanceRepo, AuditLogs: mockAuditRepo}), service.DefaultAttendancePolicy())
+
+			mockAttendanceRepo.EXPECT().GetOpenAttendanceByUserID(userID).Return(tt.mockOpen, nil)
+			mockWorkScheduleRepo.EXPECT().GetWorkScheduleByUserID(userID).Return(tt.mockSchedule, nil).AnyTimes()
+			mockAuditRepo.EXPECT().Create(gomock.Any()).Return(nil).AnyTimes()
+			if tt.mockOpen != nil && tt.mockProcessed == nil {
+				// Either clocked out, or closed by the attendance policy
+				mockAttendanceRepo.EXPECT().UpdateAttendance(tt.mockOpen).Return(nil)
+			}
+
+			att, err := svc.ClockOut(userID, tt.now, "127.0.0.1", "req-123")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, att)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.now, *att.CheckOutTime)
+			assert.Equal(t, tt.expectedHours, att.WorkedHours(*att.WorkSchedule))
+		})
+	}
+}
+
+// newAttendanceSettingRepo returns an attendance setting repository holding setting, or no setting
+// when it is nil.
+func newAttendanceSettingRepo(ctrl *gomock.Controller, setting *domain.AttendanceSetting) *mockRepo.MockAttendanceSettingRepository {
+	repo := mockRepo.NewMockAttendanceSettingRepository(ctrl)
+	repo.EXPECT().GetAttendanceSetting().Return(setting, nil).AnyTimes()
+	return repo
+}
+
+func TestCloseOpenAttendances(t *testing.T) {
+	monday := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	tuesdayNoon := monday.AddDate(0, 0, 1).Add(12 * time.Hour)
+	dayWorker, nightWorker, lateStarter := uuid.New(), uuid.New(), uuid.New()
+	nightShift := &domain.WorkSchedule{
+		Name:        "Night Shift",
+		WorkingDays: "mon,tue,wed,thu,fri",
+		ShiftStart:  "22:00",
+		ShiftEnd:    "06:00",
+		DailyHours:  decimal.NewFromInt(8),
+	}
+	clock := func(hour int) time.Time { return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name           string
+		policy         service.AttendancePolicy
+		setting        *domain.AttendanceSetting
+		expectedClosed map[uuid.UUID]time.Time // check-out recorded per user
+	}{
+		{
+			name:   "unpaid closes at the check-in",
+			policy: service.DefaultAttendancePolicy(),
+			expectedClosed: map[uuid.UUID]time.Time{
+				dayWorker:   monday.Add(9 * time.Hour),
+				lateStarter: monday.Add(18 * time.Hour),
+			},
+		},
+		{
+			name:   "shift_end closes at the end of the shift",
+			policy: service.AttendancePolicy{OpenAttendance: service.OpenAttendanceShiftEnd},
+			expectedClosed: map[uuid.UUID]time.Time{
+				dayWorker:   monday.Add(17 * time.Hour),
+				lateStarter: monday.Add(18 * time.Hour), // clocked in after the shift ended
+			},
+		},
+		{
+			name:    "action saved by an admin overrides the configured one",
+			policy:  service.DefaultAttendancePolicy(),
+			setting: &domain.AttendanceSetting{OpenAttendance: string(service.OpenAttendanceShiftEnd)},
+			expectedClosed: map[uuid.UUID]time.Time{
+				dayWorker:   monday.Add(17 * time.Hour),
+				lateStarter: monday.Add(18 * time.Hour),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAttendanceRepo := mockRepo.NewMockAttendanceRepository(ctrl)
+			mockWorkScheduleRepo := mockRepo.NewMockWorkScheduleRepository(ctrl)
+			mockAuditRepo := mockRepo.NewMockAuditLogRepository(ctrl)
+			svc := service.NewAttendanceService(mockAttendanceRepo, mockRepo.NewMockHolidayRepository(ctrl), mockWorkScheduleRepo, newAttendanceSettingRepo(ctrl, tt.setting), mockAuditRepo, newPeriodLock(ctrl, nil, &repository.Repositories{Attendances: mockAttendanceRepo, AuditLogs: mockAuditRepo}), tt.policy)
+
+			// The night worker's Monday shift ends on Tuesday, so their attendance is not left open yet
+			mockAttendanceRepo.EXPECT().GetOpenAttendances().Return([]domain.Attendance{
+				{UserID: dayWorker, Date: monday, CheckInTime: clock(9)},
+				{UserID: nightWorker, Date: monday, CheckInTime: clock(22)},
+				{UserID: lateStarter, Date: monday, CheckInTime: clock(18)},
+			}, nil)
+			mockWorkScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(map[uuid.UUID]*domain.WorkSchedule{nightWorker: nightShift}, nil)
+			mockAttendanceRepo.EXPECT().UpdateAttendance(gomock.Any()).DoAndReturn(func(a *domain.Attendance) error {
+				expected, ok := tt.expectedClosed[a.UserID]
+				assert.True(t, ok, "unexpected attendance closed")
+				assert.Equal(t, expected, *a.CheckOutTime)
+				assert.Equal(t, uuid.Nil, a.UpdatedBy)
+				return nil
+			}).Times(len(tt.expectedClosed))
+			mockAuditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+				assert.Equal(t, "CLOSE", log.Action)
+				return nil
+			}).Times(len(tt.expectedClosed))
+
+			closed, err := svc.CloseOpenAttendances(tuesdayNoon, uuid.Nil, "", "")
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.expectedClosed), closed)
+		})
+	}
+}
+
+func TestCloseOpenAttendances_LockedAndFailing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	friday := time.Date(2025, 8, 29, 0, 0, 0, 0, time.UTC) // in the processed August
+	monday := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	tuesdayNoon := monday.AddDate(0, 0, 1).Add(12 * time.Hour)
+	locked, failing, closing := uuid.New(), uuid.New(), uuid.New()
+	checkIn := time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	mockAttendanceRepo := mockRepo.NewMockAttendanceRepository(ctrl)
+	mockWorkScheduleRepo := mockRepo.NewMockWorkScheduleRepository(ctrl)
+	mockAuditRepo := mockRepo.NewMockAuditLogRepository(ctrl)
+	svc := service.NewAttendanceService(mockAttendanceRepo, mockRepo.NewMockHolidayRepository(ctrl), mockWorkScheduleRepo, newAttendanceSettingRepo(ctrl, nil), mockAuditRepo, newPeriodLock(ctrl, august2025, &repository.Repositories{Attendances: mockAttendanceRepo, AuditLogs: mockAuditRepo}), service.DefaultAttendancePolicy())
+
+	mockAttendanceRepo.EXPECT().GetOpenAttendances().Return([]domain.Attendance{
+		{UserID: locked, Date: friday, CheckInTime: checkIn},
+		{UserID: failing, Date: monday, CheckInTime: checkIn},
+		{UserID: closing, Date: monday, CheckInTime: checkIn},
+	}, nil)
+	mockWorkScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil)
+	// The locked attendance is never written; the failing one does not stop the next one
+	mockAttendanceRepo.EXPECT().UpdateAttendance(gomock.Any()).DoAndReturn(func(a *domain.Attendance) error {
+		assert.NotEqual(t, locked, a.UserID)
+		if a.UserID == failing {
+			return errors.New("db error")
+		}
+		return nil
+	}).Times(2)
+	mockAuditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+	closed, err := svc.CloseOpenAttendances(tuesdayNoon, uuid.Nil, "", "")
+	assert.ErrorContains(t, err, "db error")
+	assert.Equal(t, 1, closed)
+}
+
+func TestUpdateOpenAttendanceAction(t *testing.T) {
+	adminID := uuid.New()
+	settingID := uuid.New()
+
+	tests := []struct {
+		name           string
+		action         string
+		existing       *domain.AttendanceSetting
+		saveErr        error
+		expectedAudit  string
+		expectedErr    error
+		expectedErrMsg string
+	}{
+		{
+			name:          "first change creates the setting",
+			action:        "shift_end",
+			expectedAudit: "CREATE",
+		},
+		{
+			name:          "later change updates the setting",
+			action:        "unpaid",
+			existing:      &domain.AttendanceSetting{BaseModel: domain.BaseModel{ID: settingID}, OpenAttendance: "shift_end"},
+			expectedAudit: "UPDATE",
+		},
+		{
+			name:        "invalid action",
+			action:      "paid",
+			expectedErr: service.ErrInvalidOpenAttendanceAction,
+		},
+		{
+			name:           "save error",
+			action:         "shift_end",
+			saveErr:        errors.New("db error"),
+			expectedErrMsg: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSettingRepo := mockRepo.NewMockAttendanceSettingRepository(ctrl)
+			mockAuditRepo := mockRepo.NewMockAuditLogRepository(ctrl)
+			svc := service.NewAttendanceService(mockRepo.NewMockAttendanceRepository(ctrl), mockRepo.NewMockHolidayRepository(ctrl), mockRepo.NewMockWorkScheduleRepository(ctrl), mockSettingRepo, mockAuditRepo, newPeriodLock(ctrl, nil, &repository.Repositories{}), service.DefaultAttendancePolicy())
+
+			if tt.expectedErr == nil {
+				mockSettingRepo.EXPECT().GetAttendanceSetting().Return(tt.existing, nil)
+				mockSettingRepo.EXPECT().SaveAttendanceSetting(gomock.Any()).DoAndReturn(func(setting *domain.AttendanceSetting) error {
+					assert.Equal(t, tt.action, setting.OpenAttendance)
+					assert.Equal(t, adminID, setting.UpdatedBy)
+					if tt.existing != nil {
+						assert.Equal(t, settingID, setting.ID)
+					}
+					return tt.saveErr
+				})
+			}
+			if tt.expectedAudit != "" {
+				mockAuditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, tt.expectedAudit, log.Action)
+					return nil
+				})
+			}
+
+			policy, err := svc.UpdateOpenAttendanceAction(tt.action, adminID, "127.0.0.1", "req-1")
+			switch {
+			case tt.expectedErr != nil:
+				assert.ErrorIs(t, err, tt.expectedErr)
+			case tt.expectedErrMsg != "":
+				assert.EqualError(t, err, tt.expectedErrMsg)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, service.OpenAttendanceAction(tt.action), policy.OpenAttendance)
+				assert.Equal(t, domain.AttendancePayFullDay, policy.PayMode)
+			}
+		})
+	}
+}
+
+func TestGetAttendanceHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	monday := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	clock := func(hour int) time.Time { return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC) }
+	checkOut := clock(6)
+	nightShift := &domain.WorkSchedule{
+		Name:        "Night Shift",
+		WorkingDays: "mon,tue,wed,thu,fri",
+		ShiftStart:  "22:00",
+		ShiftEnd:    "06:00",
+		DailyHours:  decimal.NewFromInt(8),
+	}
+	filter := domain.HistoryFilter{Page: 1, PageSize: 20}
+
+	mockAttendanceRepo := mockRepo.NewMockAttendanceRepository(ctrl)
+	mockWorkScheduleRepo := mockRepo.NewMockWorkScheduleRepository(ctrl)
+	svc := service.NewAttendanceService(mockAttendanceRepo, mockRepo.NewMockHolidayRepository(ctrl), mockWorkScheduleRepo, newAttendanceSettingRepo(ctrl, nil), mockRepo.NewMockAuditLogRepository(ctrl), newPeriodLock(ctrl, nil, &repository.Repositories{}), service.DefaultAttendancePolicy())
+
+	mockAttendanceRepo.EXPECT().GetAttendanceHistory(userID, filter).
+		Return([]domain.Attendance{{UserID: userID, Date: monday, CheckInTime: clock(22), CheckOutTime: &checkOut}}, int64(1), nil)
+	mockWorkScheduleRepo.EXPECT().GetWorkScheduleByUserID(userID).Return(nightShift, nil)
+
+	attendances, total, err := svc.GetAttendanceHistory(userID, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	if assert.Len(t, attendances, 1) {
+		assert.Equal(t, 8.0, attendances[0].WorkedHours(*attendances[0].WorkSchedule))
+		assert.Equal(t, monday.AddDate(0, 0, 1).Add(6*time.Hour), *attendances[0].CheckOutAt())
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"payroll-system/internal/domain"
+)
+
+// OpenAttendanceAction defines how an attendance left open at the end of its day is closed.
+type OpenAttendanceAction string
+
+const (
+	OpenAttendanceUnpaid   OpenAttendanceAction = "unpaid"    // closed at the check-in, the day is worth no hours
+	OpenAttendanceShiftEnd OpenAttendanceAction = "shift_end" // closed at the scheduled end of the employee's shift
+)
+
+// ErrInvalidOpenAttendanceAction is returned when an open attendance action is neither unpaid nor shift_end.
+var ErrInvalidOpenAttendanceAction = errors.New("invalid open attendance action")
+
+// AttendancePolicy describes how attendances are handled when employees do not clock out, and how
+// the hours worked on a day are paid.
+type AttendancePolicy struct {
+	OpenAttendance OpenAttendanceAction
+	PayMode        domain.AttendancePayMode
+}
+
+// DefaultAttendancePolicy returns the policy used when nothing is configured: an attendance left
+// open is closed without paid hours, as no check-out was recorded, and a day is only paid once the
+// daily hours of the employee's schedule are worked.
+func DefaultAttendancePolicy() AttendancePolicy {
+	return AttendancePolicy{
+		OpenAttendance: OpenAttendanceUnpaid,
+		PayMode:        domain.AttendancePayFullDay,
+	}
+}
+
+// ParseAttendancePolicy builds an AttendancePolicy from its textual configuration.
+// Empty values fall back to the defaults of DefaultAttendancePolicy.
+func ParseAttendancePolicy(openAttendance, payMode string) (AttendancePolicy, error) {
+	policy := DefaultAttendancePolicy()
+
+	if openAttendance != "" {
+		action, err := ParseOpenAttendanceAction(openAttendance)
+		if err != nil {
+			return policy, err
+		}
+		policy.OpenAttendance = action
+	}
+
+	if payMode != "" {
+		switch domain.AttendancePayMode(payMode) {
+		case domain.AttendancePayFullDay, domain.AttendancePayHourly, domain.AttendancePayHalfDay:
+			policy.PayMode = domain.AttendancePayMode(payMode)
+		default:
+			return policy, fmt.Errorf("invalid attendance pay mode %q", payMode)
+		}
+	}
+
+	return policy, nil
+}
+
+// ParseOpenAttendanceAction returns the open attendance action named action, or
+// ErrInvalidOpenAttendanceAction when there is none.
+func ParseOpenAttendanceAction(action string) (OpenAttendanceAction, error) {
+	switch OpenAttendanceAction(action) {
+	case OpenAttendanceUnpaid, OpenAttendanceShiftEnd:
+		return OpenAttendanceAction(action), nil
+	default:
+		return "", fmt.Errorf("%w %q, use %s or %s", ErrInvalidOpenAttendanceAction, action, OpenAttendanceUnpaid, OpenAttendanceShiftEnd)
+	}
+}
+
+// CheckOutFor returns the check-out recorded for an attendance left open under the employee's schedule.
+// With shift_end an employee who clocked in after the end of their shift is closed at the check-in.
+func (p AttendancePolicy) CheckOutFor(attendance domain.Attendance, schedule domain.WorkSchedule) time.Time {
+	checkIn := attendance.CheckInAt()
+	if p.OpenAttendance == OpenAttendanceShiftEnd {
+		if shiftEnd := schedule.ShiftEndsAt(attendance.Date); shiftEnd.After(checkIn) {
+			return shiftEnd
+		}
+	}
+	return checkIn
+}
@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+)
+
+func TestAttendancePolicy_CheckOutFor(t *testing.T) {
+	monday := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	clock := func(hour int) time.Time { return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC) }
+	nightShift := domain.WorkSchedule{WorkingDays: "mon,tue,wed,thu,fri", ShiftStart: "22:00", ShiftEnd: "06:00", DailyHours: decimal.NewFromInt(8)}
+	shiftEnd := service.AttendancePolicy{OpenAttendance: service.OpenAttendanceShiftEnd}
+
+	tests := []struct {
+		name     string
+		policy   service.AttendancePolicy
+		checkIn  time.Time
+		schedule domain.WorkSchedule
+		expected time.Time
+	}{
+		{"unpaid", service.DefaultAttendancePolicy(), clock(9), domain.DefaultWorkSchedule(), monday.Add(9 * time.Hour)},
+		{"shift end", shiftEnd, clock(9), domain.DefaultWorkSchedule(), monday.Add(17 * time.Hour)},
+		{"shift end of an overnight shift", shiftEnd, clock(22), nightShift, monday.Add(30 * time.Hour)},
+		{"clocked in after the shift ended", shiftEnd, clock(18), domain.DefaultWorkSchedule(), monday.Add(18 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attendance := domain.Attendance{Date: monday, CheckInTime: tt.checkIn}
+			assert.Equal(t, tt.expected, tt.policy.CheckOutFor(attendance, tt.schedule))
+		})
+	}
+}
+
+func TestAttendancePayMode_PaidHours(t *testing.T) {
+	tests := []struct {
+		mode     domain.AttendancePayMode
+		worked   float64
+		expected float64
+	}{
+		{domain.AttendancePayFullDay, 8, 8},
+		{domain.AttendancePayFullDay, 7.5, 0},
+		{domain.AttendancePayHourly, 8, 8},
+		{domain.AttendancePayHourly, 5.5, 5.5},
+		{domain.AttendancePayHalfDay, 8, 8},
+		{domain.AttendancePayHalfDay, 5, 4},
+		{domain.AttendancePayHalfDay, 4, 4},
+		{domain.AttendancePayHalfDay, 3.5, 0},
+		{"", 6, 0}, // Payslips stored before the pay modes paid full days
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.mode.PaidHours(tt.worked, 8), "%s with %v hours worked", tt.mode, tt.worked)
+	}
+}
+
+func TestParseAttendancePolicy(t *testing.T) {
+	policy, err := service.ParseAttendancePolicy("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, service.DefaultAttendancePolicy(), policy)
+
+	policy, err = service.ParseAttendancePolicy("shift_end", "half_day")
+	assert.NoError(t, err)
+	assert.Equal(t, service.OpenAttendanceShiftEnd, policy.OpenAttendance)
+	assert.Equal(t, domain.AttendancePayHalfDay, policy.PayMode)
+
+	_, err = service.ParseAttendancePolicy("ignore", "")
+	assert.Error(t, err)
+
+	_, err = service.ParseAttendancePolicy("", "per_minute")
+	assert.Error(t, err)
+}
@@ -13,11 +13,18 @@ import (
 	"payroll-system/internal/repository"
 )
 
+var (
+	// ErrEmployeeRegistration is returned when a user registers with the employee role. Employees are
+	// created by an admin together with the employee profile payroll is calculated from.
+	ErrEmployeeRegistration = errors.New("employees cannot register themselves, an admin creates them with their employee profile")
+)
+
 // AuthServiceInterface defines the methods of AuthService for mocking purposes.
 //
 //go:generate mockgen -source=auth.service.go -destination=../../tests/mocks/service/mock_auth_service.go -package=mocks
 type AuthServiceInterface interface {
-	// RegisterUser registers a new user.
+	// RegisterUser registers a new user. Employees cannot be registered, they are created with their
+	// employee profile by EmployeeService.CreateEmployee.
 	RegisterUser(username, password, role, ipAddress, requestID string) (*domain.User, error)
 	// LoginUser authenticates a user and returns a JWT token.
 	LoginUser(username, password, ipAddress, requestID string) (string, error)
@@ -39,8 +46,14 @@ func NewAuthService(userRepo repository.UserRepository, auditRepo repository.Aud
 	}
 }
 
-// RegisterUser registers a new user.
+// RegisterUser registers a new user. It returns ErrEmployeeRegistration for the employee role: a user
+// without an employee profile would never be paid, so employees are created by
+// EmployeeService.CreateEmployee instead.
 func (s *AuthService) RegisterUser(username, password, role string, ipAddress string, requestID string) (*domain.User, error) {
+	if role == "employee" {
+		return nil, ErrEmployeeRegistration
+	}
+
 	existingUser, err := s.userRepo.GetUserByUsername(username)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
@@ -89,6 +102,9 @@ func (s *AuthService) LoginUser(username, password string, ipAddress string, req
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
 		return "", errors.New("invalid credentials")
 	}
+	if !user.IsActive() {
+		return "", errors.New("user is deactivated")
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id":  user.ID,
@@ -3,6 +3,7 @@ package service_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/google/uuid"
@@ -18,17 +19,22 @@ import (
 func TestAuthService_RegisterUser(t *testing.T) {
 	username := "johndoe"
 	password := "password123"
-	role := "admin"
 	ip := "127.0.0.1"
 	requestID := "req-1"
 
 	tests := []struct {
 		name            string
+		role            string
 		mockExisting    *domain.User
 		mockGetError    error
 		mockCreateError error
 		expectedError   string
 	}{
+		{
+			name:          "employee role",
+			role:          "employee",
+			expectedError: service.ErrEmployeeRegistration.Error(),
+		},
 		{
 			name:          "username already exists",
 			mockExisting:  &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Username: username},
@@ -77,6 +83,10 @@ func TestAuthService_RegisterUser(t *testing.T) {
 					AnyTimes()
 			}
 
+			role := tt.role
+			if role == "" {
+				role = "admin"
+			}
 			user, err := svc.RegisterUser(username, password, role, ip, requestID)
 
 			if tt.expectedError != "" {
@@ -99,6 +109,7 @@ func TestAuthService_LoginUser(t *testing.T) {
 	requestID := "req-1"
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	userID := uuid.New()
+	deactivatedAt := time.Now()
 
 	tests := []struct {
 		name         string
@@ -126,6 +137,12 @@ func TestAuthService_LoginUser(t *testing.T) {
 			inputPass:   "wrongpass",
 			expectedErr: "invalid credentials",
 		},
+		{
+			name:        "deactivated user",
+			mockUser:    &domain.User{BaseModel: domain.BaseModel{ID: userID}, Username: username, Password: string(hashedPassword), DeactivatedAt: &deactivatedAt},
+			inputPass:   password,
+			expectedErr: "user is deactivated",
+		},
 		{
 			name:      "successful login",
 			mockUser:  &domain.User{BaseModel: domain.BaseModel{ID: userID}, Username: username, Password: string(hashedPassword), Role: "admin"},
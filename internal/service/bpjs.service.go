@@ -0,0 +1,184 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+)
+
+// Payslip item codes of the BPJS social security components. Employee contributions are
+// deductions, employer contributions are reported on the payslip without changing take-home pay.
+const (
+	PayslipItemCodeBPJSJHT               = "BPJS_JHT"                // Jaminan Hari Tua, employee share
+	PayslipItemCodeBPJSJP                = "BPJS_JP"                 // Jaminan Pensiun, employee share
+	PayslipItemCodeBPJSKesehatan         = "BPJS_KESEHATAN"          // BPJS Kesehatan, employee share
+	PayslipItemCodeBPJSJHTEmployer       = "BPJS_JHT_EMPLOYER"       // Jaminan Hari Tua, employer share
+	PayslipItemCodeBPJSJPEmployer        = "BPJS_JP_EMPLOYER"        // Jaminan Pensiun, employer share
+	PayslipItemCodeBPJSJKK               = "BPJS_JKK"                // Jaminan Kecelakaan Kerja, employer only
+	PayslipItemCodeBPJSJKM               = "BPJS_JKM"                // Jaminan Kematian, employer only
+	PayslipItemCodeBPJSKesehatanEmployer = "BPJS_KESEHATAN_EMPLOYER" // BPJS Kesehatan, employer share
+)
+
+var bpjsItemCodes = map[string]bool{
+	PayslipItemCodeBPJSJHT:               true,
+	PayslipItemCodeBPJSJP:                true,
+	PayslipItemCodeBPJSKesehatan:         true,
+	PayslipItemCodeBPJSJHTEmployer:       true,
+	PayslipItemCodeBPJSJPEmployer:        true,
+	PayslipItemCodeBPJSJKK:               true,
+	PayslipItemCodeBPJSJKM:               true,
+	PayslipItemCodeBPJSKesehatanEmployer: true,
+}
+
+// IsBPJSItemCode reports whether a payslip item code is one of the BPJS contribution codes.
+func IsBPJSItemCode(code string) bool {
+	return bpjsItemCodes[code]
+}
+
+// BPJSRates holds the contribution rates, as percentages of the monthly wage, and the wage caps
+// of the BPJS programs. A zero cap means the whole wage is contributed on.
+type BPJSRates struct {
+	JHTEmployee       decimal.Decimal
+	JHTEmployer       decimal.Decimal
+	JPEmployee        decimal.Decimal
+	JPEmployer        decimal.Decimal
+	JPWageCap         decimal.Decimal
+	JKK               decimal.Decimal // Depends on the risk class of the workplace, 0.24% to 1.74%
+	JKM               decimal.Decimal
+	KesehatanEmployee decimal.Decimal
+	KesehatanEmployer decimal.Decimal
+	KesehatanWageCap  decimal.Decimal
+}
+
+// DefaultBPJSRates returns the statutory rates for 2025, with JKK at the lowest risk class.
+func DefaultBPJSRates() BPJSRates {
+	return BPJSRates{
+		JHTEmployee:       decimal.NewFromInt(2),
+		JHTEmployer:       decimal.RequireFromString("3.7"),
+		JPEmployee:        decimal.NewFromInt(1),
+		JPEmployer:        decimal.NewFromInt(2),
+		JPWageCap:         decimal.NewFromInt(10547400),
+		JKK:               decimal.RequireFromString("0.24"),
+		JKM:               decimal.RequireFromString("0.3"),
+		KesehatanEmployee: decimal.NewFromInt(1),
+		KesehatanEmployer: decimal.NewFromInt(4),
+		KesehatanWageCap:  decimal.NewFromInt(12000000),
+	}
+}
+
+// ParseBPJSRates builds BPJSRates from their textual configuration, read with lookup (e.g.
+// os.Getenv) from the BPJS_* variables. Empty values fall back to the defaults of DefaultBPJSRates.
+func ParseBPJSRates(lookup func(key string) string) (BPJSRates, error) {
+	rates := DefaultBPJSRates()
+
+	fields := []struct {
+		key   string
+		value *decimal.Decimal
+	}{
+		{"BPJS_JHT_EMPLOYEE_RATE", &rates.JHTEmployee},
+		{"BPJS_JHT_EMPLOYER_RATE", &rates.JHTEmployer},
+		{"BPJS_JP_EMPLOYEE_RATE", &rates.JPEmployee},
+		{"BPJS_JP_EMPLOYER_RATE", &rates.JPEmployer},
+		{"BPJS_JP_WAGE_CAP", &rates.JPWageCap},
+		{"BPJS_JKK_RATE", &rates.JKK},
+		{"BPJS_JKM_RATE", &rates.JKM},
+		{"BPJS_KESEHATAN_EMPLOYEE_RATE", &rates.KesehatanEmployee},
+		{"BPJS_KESEHATAN_EMPLOYER_RATE", &rates.KesehatanEmployer},
+		{"BPJS_KESEHATAN_WAGE_CAP", &rates.KesehatanWageCap},
+	}
+	for _, field := range fields {
+		raw := lookup(field.key)
+		if raw == "" {
+			continue
+		}
+		value, err := decimal.NewFromString(raw)
+		if err != nil || value.IsNegative() {
+			return rates, fmt.Errorf("invalid %s %q", field.key, raw)
+		}
+		*field.value = value
+	}
+
+	return rates, nil
+}
+
+// BPJSCalculator calculates the BPJS Ketenagakerjaan (JHT, JP, JKK, JKM) and BPJS Kesehatan
+// contributions on the employee's monthly base salary, capped per program. An employee who joins or
+// leaves during the period contributes on the share of the capped wage that their employment window
+// covers, by working hours, the same share their basic salary is prorated by.
+//
+// The JKK, JKM and BPJS Kesehatan premiums paid by the employer are a taxable benefit of the
+// employee, so their lines are taxable and must be calculated before PPh21Calculator. The employee
+// JHT and JP contributions are deducted from the annual income taxed by the December true-up.
+type BPJSCalculator struct {
+	Rates BPJSRates
+}
+
+// Calculate returns the employee deduction lines followed by the employer contribution lines.
+// Lines with a zero rate are omitted.
+func (b BPJSCalculator) Calculate(calc *PayslipCalculation) []domain.PayslipItem {
+	wage := calc.Profile.Salary
+	if !wage.IsPositive() {
+		return nil
+	}
+	jpWage := capWage(wage, b.Rates.JPWageCap)
+	kesehatanWage := capWage(wage, b.Rates.KesehatanWageCap)
+
+	if calc.Period != nil {
+		periodHours := calc.Calendar.WorkingHours(calc.Period.StartDate, calc.Period.EndDate)
+		if periodHours.IsPositive() && calc.WorkingHours.LessThan(periodHours) {
+			prorate := func(w decimal.Decimal) decimal.Decimal {
+				return calc.Rounding.RoundLine(w.Mul(calc.WorkingHours).Div(periodHours))
+			}
+			wage, jpWage, kesehatanWage = prorate(wage), prorate(jpWage), prorate(kesehatanWage)
+			if !wage.IsPositive() {
+				return nil
+			}
+		}
+	}
+
+	lines := []struct {
+		componentType string
+		code          string
+		name          string
+		wage          decimal.Decimal
+		rate          decimal.Decimal
+		taxable       bool
+	}{
+		{domain.PayslipComponentDeduction, PayslipItemCodeBPJSJHT, "BPJS JHT", wage, b.Rates.JHTEmployee, false},
+		{domain.PayslipComponentDeduction, PayslipItemCodeBPJSJP, "BPJS JP", jpWage, b.Rates.JPEmployee, false},
+		{domain.PayslipComponentDeduction, PayslipItemCodeBPJSKesehatan, "BPJS Kesehatan", kesehatanWage, b.Rates.KesehatanEmployee, false},
+		{domain.PayslipComponentEmployerContribution, PayslipItemCodeBPJSJHTEmployer, "BPJS JHT (Employer)", wage, b.Rates.JHTEmployer, false},
+		{domain.PayslipComponentEmployerContribution, PayslipItemCodeBPJSJPEmployer, "BPJS JP (Employer)", jpWage, b.Rates.JPEmployer, false},
+		{domain.PayslipComponentEmployerContribution, PayslipItemCodeBPJSJKK, "BPJS JKK (Employer)", wage, b.Rates.JKK, true},
+		{domain.PayslipComponentEmployerContribution, PayslipItemCodeBPJSJKM, "BPJS JKM (Employer)", wage, b.Rates.JKM, true},
+		{domain.PayslipComponentEmployerContribution, PayslipItemCodeBPJSKesehatanEmployer, "BPJS Kesehatan (Employer)", kesehatanWage, b.Rates.KesehatanEmployer, true},
+	}
+
+	items := make([]domain.PayslipItem, 0, len(lines))
+	for _, line := range lines {
+		if line.rate.IsZero() {
+			continue
+		}
+		rate := line.rate.Div(hundred)
+		items = append(items, domain.PayslipItem{
+			ComponentType: line.componentType,
+			Code:          line.code,
+			Name:          line.name,
+			Quantity:      line.wage,
+			Rate:          rate,
+			Amount:        calc.Rounding.RoundLine(line.wage.Mul(rate)),
+			Taxable:       line.taxable,
+		})
+	}
+	return items
+}
+
+// capWage limits the wage a contribution is calculated on to limit; a zero limit leaves it unlimited.
+func capWage(wage, limit decimal.Decimal) decimal.Decimal {
+	if limit.IsPositive() && wage.GreaterThan(limit) {
+		return limit
+	}
+	return wage
+}
@@ -0,0 +1,257 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+)
+
+func TestBPJSCalculator_Calculate(t *testing.T) {
+	tests := []struct {
+		name    string
+		salary  int64
+		rates   func(r *service.BPJSRates)
+		want    map[string]string
+		taxable map[string]bool
+	}{
+		{
+			name:   "below every wage cap",
+			salary: 8000000,
+			want: map[string]string{
+				service.PayslipItemCodeBPJSJHT:               "160000",
+				service.PayslipItemCodeBPJSJP:                "80000",
+				service.PayslipItemCodeBPJSKesehatan:         "80000",
+				service.PayslipItemCodeBPJSJHTEmployer:       "296000",
+				service.PayslipItemCodeBPJSJPEmployer:        "160000",
+				service.PayslipItemCodeBPJSJKK:               "19200",
+				service.PayslipItemCodeBPJSJKM:               "24000",
+				service.PayslipItemCodeBPJSKesehatanEmployer: "320000",
+			},
+		},
+		{
+			name:   "JP and Kesehatan are capped, JHT is not",
+			salary: 20000000,
+			want: map[string]string{
+				service.PayslipItemCodeBPJSJHT:               "400000",
+				service.PayslipItemCodeBPJSJP:                "105474",
+				service.PayslipItemCodeBPJSKesehatan:         "120000",
+				service.PayslipItemCodeBPJSJHTEmployer:       "740000",
+				service.PayslipItemCodeBPJSJPEmployer:        "210948",
+				service.PayslipItemCodeBPJSJKK:               "48000",
+				service.PayslipItemCodeBPJSJKM:               "60000",
+				service.PayslipItemCodeBPJSKesehatanEmployer: "480000",
+			},
+		},
+		{
+			name:   "zero rates are omitted",
+			salary: 8000000,
+			rates: func(r *service.BPJSRates) {
+				r.JPEmployee = decimal.Zero
+				r.JPEmployer = decimal.Zero
+				r.JKK = decimal.RequireFromString("1.74")
+			},
+			want: map[string]string{
+				service.PayslipItemCodeBPJSJHT:               "160000",
+				service.PayslipItemCodeBPJSKesehatan:         "80000",
+				service.PayslipItemCodeBPJSJHTEmployer:       "296000",
+				service.PayslipItemCodeBPJSJKK:               "139200",
+				service.PayslipItemCodeBPJSJKM:               "24000",
+				service.PayslipItemCodeBPJSKesehatanEmployer: "320000",
+			},
+		},
+		{
+			name:   "no salary",
+			salary: 0,
+			want:   map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates := service.DefaultBPJSRates()
+			if tt.rates != nil {
+				tt.rates(&rates)
+			}
+			calc := &service.PayslipCalculation{
+				Profile:  domain.EmployeeProfile{Salary: decimal.NewFromInt(tt.salary)},
+				Rounding: service.DefaultRoundingPolicy(),
+			}
+
+			items := service.BPJSCalculator{Rates: rates}.Calculate(calc)
+
+			got := make(map[string]string, len(items))
+			for _, item := range items {
+				got[item.Code] = item.Amount.String()
+				assert.True(t, service.IsBPJSItemCode(item.Code))
+
+				switch item.Code {
+				case service.PayslipItemCodeBPJSJHT, service.PayslipItemCodeBPJSJP, service.PayslipItemCodeBPJSKesehatan:
+					assert.Equal(t, domain.PayslipComponentDeduction, item.ComponentType)
+					assert.False(t, item.Taxable)
+				case service.PayslipItemCodeBPJSJKK, service.PayslipItemCodeBPJSJKM, service.PayslipItemCodeBPJSKesehatanEmployer:
+					// Employer-paid insurance premiums are a taxable benefit
+					assert.Equal(t, domain.PayslipComponentEmployerContribution, item.ComponentType)
+					assert.True(t, item.Taxable)
+				default:
+					assert.Equal(t, domain.PayslipComponentEmployerContribution, item.ComponentType)
+					assert.False(t, item.Taxable)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBPJSCalculator_PartialPeriod(t *testing.T) {
+	period := &domain.PayrollPeriod{
+		StartDate: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC),
+	}
+	calendar := domain.NewWorkCalendar(nil)
+
+	tests := []struct {
+		name         string
+		salary       int64
+		workingHours int64
+		want         map[string]string
+	}{
+		{
+			// August 2025 has 21 working days, 168 hours
+			name:         "employed the whole period",
+			salary:       4200000,
+			workingHours: 168,
+			want: map[string]string{
+				service.PayslipItemCodeBPJSJHT:       "84000",
+				service.PayslipItemCodeBPJSJP:        "42000",
+				service.PayslipItemCodeBPJSKesehatan: "42000",
+			},
+		},
+		{
+			// Joined on 20 August: 8 working days, 64 of 168 hours, a wage of 1,600,000
+			name:         "joined mid-period",
+			salary:       4200000,
+			workingHours: 64,
+			want: map[string]string{
+				service.PayslipItemCodeBPJSJHT:       "32000",
+				service.PayslipItemCodeBPJSJP:        "16000",
+				service.PayslipItemCodeBPJSKesehatan: "16000",
+			},
+		},
+		{
+			// The caps apply to the monthly wage before it is prorated: JP on 10,547,400 * 84 / 168
+			// and Kesehatan on 12,000,000 * 84 / 168
+			name:         "left mid-period above the wage caps",
+			salary:       21000000,
+			workingHours: 84,
+			want: map[string]string{
+				service.PayslipItemCodeBPJSJHT:       "210000",
+				service.PayslipItemCodeBPJSJP:        "52737",
+				service.PayslipItemCodeBPJSKesehatan: "60000",
+			},
+		},
+		{
+			name:         "no working hours in the period",
+			salary:       4200000,
+			workingHours: 0,
+			want:         map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calc := &service.PayslipCalculation{
+				Profile:      domain.EmployeeProfile{Salary: decimal.NewFromInt(tt.salary)},
+				Period:       period,
+				Calendar:     calendar,
+				WorkingHours: decimal.NewFromInt(tt.workingHours),
+				Rounding:     service.DefaultRoundingPolicy(),
+			}
+
+			items := service.BPJSCalculator{Rates: service.DefaultBPJSRates()}.Calculate(calc)
+
+			got := make(map[string]string)
+			for _, item := range items {
+				if item.ComponentType == domain.PayslipComponentDeduction {
+					got[item.Code] = item.Amount.String()
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBPJSContributions_TakeHomePayAndTax(t *testing.T) {
+	calc := &service.PayslipCalculation{
+		Profile:     domain.EmployeeProfile{Salary: decimal.NewFromInt(10000000), PTKPStatus: domain.PTKPStatusTK0},
+		WorkedHours: decimal.NewFromInt(160),
+		HourlyRate:  decimal.NewFromInt(62500),
+		Rounding:    service.DefaultRoundingPolicy(),
+	}
+
+	service.DefaultPayslipComponentRegistry(service.DefaultBPJSRates()).Calculate(calc)
+
+	// Employee shares: JHT 200,000 + JP 100,000 + Kesehatan 100,000
+	assert.Equal(t, "400000", calc.Total(domain.PayslipComponentDeduction).Sub(calc.Amount(service.PayslipItemCodePPh21)).String())
+	// Employer shares: JHT 370,000 + JP 200,000 + JKK 24,000 + JKM 30,000 + Kesehatan 400,000
+	assert.Equal(t, "1024000", calc.Total(domain.PayslipComponentEmployerContribution).String())
+
+	// The tax base includes the JKK, JKM and Kesehatan employer premiums: 10,454,000 is taxed at TER A 2.5%
+	tax := calc.Items[len(calc.Items)-1]
+	require.Equal(t, service.PayslipItemCodePPh21, tax.Code)
+	assert.Equal(t, "10454000", tax.Quantity.String())
+	assert.Equal(t, "261350", tax.Amount.String())
+}
+
+func TestParseBPJSRates(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+		check   func(t *testing.T, rates service.BPJSRates)
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{},
+			check: func(t *testing.T, rates service.BPJSRates) {
+				assert.Equal(t, service.DefaultBPJSRates(), rates)
+			},
+		},
+		{
+			name: "overrides",
+			env:  map[string]string{"BPJS_JKK_RATE": "0.89", "BPJS_JP_WAGE_CAP": "11000000"},
+			check: func(t *testing.T, rates service.BPJSRates) {
+				assert.Equal(t, "0.89", rates.JKK.String())
+				assert.Equal(t, "11000000", rates.JPWageCap.String())
+				assert.Equal(t, "2", rates.JHTEmployee.String())
+			},
+		},
+		{
+			name:    "invalid rate",
+			env:     map[string]string{"BPJS_JHT_EMPLOYEE_RATE": "two"},
+			wantErr: `invalid BPJS_JHT_EMPLOYEE_RATE "two"`,
+		},
+		{
+			name:    "negative cap",
+			env:     map[string]string{"BPJS_KESEHATAN_WAGE_CAP": "-1"},
+			wantErr: `invalid BPJS_KESEHATAN_WAGE_CAP "-1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates, err := service.ParseBPJSRates(func(key string) string { return tt.env[key] })
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, rates)
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"golang.org/x/crypto/bcrypt"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+)
+
+var (
+	// ErrEmployeeNotFound is returned when a user has no employee profile.
+	ErrEmployeeNotFound = errors.New("employee profile not found")
+	// ErrUsernameTaken is returned when another user already has the username.
+	ErrUsernameTaken = errors.New("username is already taken")
+	// ErrEmployeeDeactivated is returned when deactivating an employee that is already deactivated.
+	ErrEmployeeDeactivated = errors.New("employee is already deactivated")
+	// ErrEmployeeTerminated is returned when terminating an employee that already has a termination date.
+	ErrEmployeeTerminated = errors.New("employee is already terminated")
+	// ErrInvalidEmploymentDates is returned when a termination date would fall before the hire date.
+	ErrInvalidEmploymentDates = errors.New("termination date cannot be before the hire date")
+	// ErrInvalidManager is returned when assigning an employee a manager who is not an employee, or who reports to them.
+	ErrInvalidManager = errors.New("invalid manager")
+	// ErrNotEmployedInPeriod is returned when calculating a payslip for a period the employee is not employed in.
+	ErrNotEmployedInPeriod = errors.New("employee is not employed during the payroll period")
+)
+
+// EmployeeServiceInterface defines the methods of EmployeeService for mocking purposes.
+//
+//go:generate mockgen -source=employee.service.go -destination=../../tests/mocks/service/mock_employee_service.go -package=mocks
+type EmployeeServiceInterface interface {
+	// CreateEmployee creates an employee user together with their employee profile.
+	CreateEmployee(username, password string, salary decimal.Decimal, ptkpStatus string, hireDate time.Time, createdBy uuid.UUID, ipAddress, requestID string) (*domain.EmployeeProfile, error)
+	// UpdateEmployee updates the username, PTKP status and, when hireDate is not nil, the hire date of an employee.
+	UpdateEmployee(userID uuid.UUID, username, ptkpStatus string, hireDate *time.Time, updatedBy uuid.UUID, ipAddress, requestID string) (*domain.EmployeeProfile, error)
+	// GetEmployee returns the employee profile of a user, with the user.
+	GetEmployee(userID uuid.UUID) (*domain.EmployeeProfile, error)
+	// SearchEmployees returns the employees whose username contains search.
+	SearchEmployees(search string) ([]domain.EmployeeProfile, error)
+	// DeactivateEmployee deactivates an employee, who can no longer log in and is left out of later payroll runs.
+	DeactivateEmployee(userID uuid.UUID, deactivatedBy uuid.UUID, ipAddress, requestID string) (*domain.EmployeeProfile, error)
+	// TerminateEmployee records the last day of employment of an employee.
+	TerminateEmployee(userID uuid.UUID, terminationDate time.Time, terminatedBy uuid.UUID, ipAddress, requestID string) (*domain.EmployeeProfile, error)
+	// AssignManager sets the employee an employee reports to, or removes it when managerID is nil.
+	AssignManager(userID uuid.UUID, managerID *uuid.UUID, assignedBy uuid.UUID, ipAddress, requestID string) (*domain.EmployeeProfile, error)
+}
+
+// EmployeeService provides business logic for managing employees.
+type EmployeeService struct {
+	userRepo            repository.UserRepository
+	employeeProfileRepo repository.EmployeeProfileRepository
+	uow                 repository.UnitOfWork // For transaction management
+}
+
+// NewEmployeeService creates a new EmployeeService.
+func NewEmployeeService(
+	userRepo repository.UserRepository,
+	employeeProfileRepo repository.EmployeeProfileRepository,
+	uow repository.UnitOfWork,
+) *EmployeeService {
+	return &EmployeeService{
+		userRepo:            userRepo,
+		employeeProfileRepo: employeeProfileRepo,
+		uow:                 uow,
+	}
+}
+
+// CreateEmployee creates a user with the employee role and their employee profile. The user, the
+// profile and their audit log entries are written in one transaction, so an employee never exists
+// without the profile payroll is calculated from. An empty ptkpStatus defaults to TK/0. The employee
+// is paid from hireDate on.
+func (s *EmployeeService) CreateEmployee(
+	username, password string,
+	salary decimal.Decimal,
+	ptkpStatus string,
+	hireDate time.Time,
+	createdBy uuid.UUID,
+	ipAddress, requestID string,
+) (*domain.EmployeeProfile, error) {
+	username = strings.TrimSpace(username)
+	if username == "" || password == "" {
+		return nil, errors.New("username and password are required")
+	}
+	if !salary.IsPositive() {
+		return nil, errors.New("salary must be greater than 0")
+	}
+	if ptkpStatus == "" {
+		ptkpStatus = domain.PTKPStatusTK0
+	}
+	if !domain.IsValidPTKPStatus(ptkpStatus) {
+		return nil, fmt.Errorf("invalid PTKP status %q", ptkpStatus)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	base := domain.BaseModel{
+		CreatedAt: now,
+		UpdatedAt: now,
+		CreatedBy: createdBy,
+		UpdatedBy: createdBy,
+		IPAddress: ipAddress,
+	}
+	user := &domain.User{
+		BaseModel: base,
+		Username:  username,
+		Password:  string(hashedPassword),
+		Role:      "employee",
+	}
+	profile := &domain.EmployeeProfile{
+		BaseModel:  base,
+		Salary:     salary,
+		PTKPStatus: ptkpStatus,
+		HireDate:   &hireDate,
+	}
+
+	err = s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := repos.Users.CreateUser(user); err != nil {
+			if errors.Is(err, repository.ErrDuplicateRecord) {
+				return ErrUsernameTaken
+			}
+			return err
+		}
+
+		profile.UserID = user.ID
+		if err := repos.EmployeeProfiles.CreateEmployeeProfile(profile); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &createdBy, "CREATE", "User", &user.ID, nil, user, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for user: %w", err)
+		}
+		if err := repository.CreateAuditLog(repos.AuditLogs, &createdBy, "CREATE", "EmployeeProfile", &profile.ID, nil, profile, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for employee profile: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	profile.User = *user
+	return profile, nil
+}
+
+// UpdateEmployee updates the username and PTKP status of an employee, and their hire date when
+// hireDate is not nil. Salary changes are recorded through SalaryHistoryService so that payroll
+// keeps the salary in force on every date.
+func (s *EmployeeService) UpdateEmployee(
+	userID uuid.UUID,
+	username, ptkpStatus string,
+	hireDate *time.Time,
+	updatedBy uuid.UUID,
+	ipAddress, requestID string,
+) (*domain.EmployeeProfile, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	if !domain.IsValidPTKPStatus(ptkpStatus) {
+		return nil, fmt.Errorf("invalid PTKP status %q", ptkpStatus)
+	}
+
+	var profile *domain.EmployeeProfile
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		var err error
+		profile, err = loadEmployee(repos.Users, repos.EmployeeProfiles, userID)
+		if err != nil {
+			return err
+		}
+		oldProfile := *profile
+		if hireDate != nil {
+			if profile.TerminationDate != nil && profile.TerminationDate.Before(*hireDate) {
+				return ErrInvalidEmploymentDates
+			}
+			profile.HireDate = hireDate
+		}
+
+		now := time.Now()
+		profile.User.Username = username
+		profile.User.UpdatedAt = now
+		profile.User.UpdatedBy = updatedBy
+		profile.User.IPAddress = ipAddress
+		profile.PTKPStatus = ptkpStatus
+		profile.UpdatedAt = now
+		profile.UpdatedBy = updatedBy
+		profile.IPAddress = ipAddress
+
+		if err := repos.Users.UpdateUser(&profile.User); err != nil {
+			if errors.Is(err, repository.ErrDuplicateRecord) {
+				return ErrUsernameTaken
+			}
+			return err
+		}
+		if err := repos.EmployeeProfiles.UpdateEmployeeProfile(profile); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &updatedBy, "UPDATE", "EmployeeProfile", &profile.ID, oldProfile, profile, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for employee profile: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// GetEmployee returns the employee profile of a user, with the user.
+func (s *EmployeeService) GetEmployee(userID uuid.UUID) (*domain.EmployeeProfile, error) {
+	return loadEmployee(s.userRepo, s.employeeProfileRepo, userID)
+}
+
+// SearchEmployees returns the employees whose username contains search, ignoring case, ordered
+// by username. An empty search returns every employee, deactivated ones included.
+func (s *EmployeeService) SearchEmployees(search string) ([]domain.EmployeeProfile, error) {
+	return s.employeeProfileRepo.SearchEmployeeProfiles(strings.TrimSpace(search))
+}
+
+// DeactivateEmployee deactivates an employee. A deactivated employee can no longer log in and is
+// left out of later payroll runs; payslips that are already processed are kept.
+func (s *EmployeeService) DeactivateEmployee(
+	userID uuid.UUID,
+	deactivatedBy uuid.UUID,
+	ipAddress, requestID string,
+) (*domain.EmployeeProfile, error) {
+	var profile *domain.EmployeeProfile
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		var err error
+		profile, err = loadEmployee(repos.Users, repos.EmployeeProfiles, userID)
+		if err != nil {
+			return err
+		}
+		if !profile.User.IsActive() {
+			return ErrEmployeeDeactivated
+		}
+		oldUser := profile.User
+
+		now := time.Now()
+		profile.User.DeactivatedAt = &now
+		profile.User.UpdatedAt = now
+		profile.User.UpdatedBy = deactivatedBy
+		profile.User.IPAddress = ipAddress
+
+		if err := repos.Users.UpdateUser(&profile.User); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &deactivatedBy, "DEACTIVATE", "User", &userID, oldUser, profile.User, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// TerminateEmployee records the last day of employment of an employee. Payroll runs only pay the
+// employee up to and including terminationDate and leave them out of later periods. Periods that
+// are already processed are not recalculated; reverse and rerun them to apply a backdated
+// termination.
+func (s *EmployeeService) TerminateEmployee(
+	userID uuid.UUID,
+	terminationDate time.Time,
+	terminatedBy uuid.UUID,
+	ipAddress, requestID string,
+) (*domain.EmployeeProfile, error) {
+	var profile *domain.EmployeeProfile
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		var err error
+		profile, err = loadEmployee(repos.Users, repos.EmployeeProfiles, userID)
+		if err != nil {
+			return err
+		}
+		if profile.TerminationDate != nil {
+			return ErrEmployeeTerminated
+		}
+		if profile.HireDate != nil && terminationDate.Before(*profile.HireDate) {
+			return ErrInvalidEmploymentDates
+		}
+		oldProfile := *profile
+
+		profile.TerminationDate = &terminationDate
+		profile.UpdatedAt = time.Now()
+		profile.UpdatedBy = terminatedBy
+		profile.IPAddress = ipAddress
+
+		if err := repos.EmployeeProfiles.UpdateEmployeeProfile(profile); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &terminatedBy, "TERMINATE", "EmployeeProfile", &profile.ID, oldProfile, profile, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for employee profile: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// AssignManager sets the manager an employee reports to, who reviews their leave requests. With a
+// nil managerID the employee reports to nobody and only admins review their leave. The manager must
+// be another employee and may not report to the employee, directly or through other managers.
+func (s *EmployeeService) AssignManager(
+	userID uuid.UUID,
+	managerID *uuid.UUID,
+	assignedBy uuid.UUID,
+	ipAddress, requestID string,
+) (*domain.EmployeeProfile, error) {
+	var profile *domain.EmployeeProfile
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		var err error
+		profile, err = loadEmployee(repos.Users, repos.EmployeeProfiles, userID)
+		if err != nil {
+			return err
+		}
+		if managerID != nil {
+			if err := checkReportingLine(repos.EmployeeProfiles, userID, *managerID); err != nil {
+				return err
+			}
+		}
+		oldProfile := *profile
+
+		profile.ManagerID = managerID
+		profile.UpdatedAt = time.Now()
+		profile.UpdatedBy = assignedBy
+		profile.IPAddress = ipAddress
+
+		if err := repos.EmployeeProfiles.UpdateEmployeeProfile(profile); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &assignedBy, "ASSIGN", "EmployeeProfile", &profile.ID, oldProfile, profile, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for employee profile: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// checkReportingLine returns ErrInvalidManager unless managerID is another employee whose chain of
+// managers does not lead back to userID.
+func checkReportingLine(employeeProfileRepo repository.EmployeeProfileRepository, userID, managerID uuid.UUID) error {
+	if managerID == userID {
+		return fmt.Errorf("%w: an employee cannot report to themselves", ErrInvalidManager)
+	}
+	manager, err := employeeProfileRepo.GetEmployeeProfileByUserID(managerID)
+	if err != nil {
+		return err
+	}
+	if manager == nil {
+		return fmt.Errorf("%w: manager is not an employee", ErrInvalidManager)
+	}
+
+	seen := map[uuid.UUID]bool{managerID: true}
+	for manager != nil && manager.ManagerID != nil && !seen[*manager.ManagerID] {
+		if *manager.ManagerID == userID {
+			return fmt.Errorf("%w: the manager reports to the employee", ErrInvalidManager)
+		}
+		seen[*manager.ManagerID] = true
+		if manager, err = employeeProfileRepo.GetEmployeeProfileByUserID(*manager.ManagerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// canReview reports whether reviewer may approve or reject a request of the employee with the given
+// profile: an admin or the manager the employee reports to can, but nobody reviews their own request.
+func canReview(reviewer *domain.User, userID uuid.UUID, profile *domain.EmployeeProfile) bool {
+	if userID == reviewer.ID {
+		return false
+	}
+	isManager := profile != nil && profile.ManagerID != nil && *profile.ManagerID == reviewer.ID
+	return reviewer.Role == "admin" || isManager
+}
+
+// loadEmployee returns the employee profile of a user with the user set, or ErrEmployeeNotFound
+// when the user or their profile does not exist.
+func loadEmployee(userRepo repository.UserRepository, employeeProfileRepo repository.EmployeeProfileRepository, userID uuid.UUID) (*domain.EmployeeProfile, error) {
+	profile, err := employeeProfileRepo.GetEmployeeProfileByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	user, err := userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	profile.User = *user
+	return profile, nil
+}
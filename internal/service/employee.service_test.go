@@ -0,0 +1,564 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+	"payroll-system/internal/service"
+	mockrepo "payroll-system/tests/mocks/repository"
+)
+
+func TestEmployeeService_CreateEmployee(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	hireDate := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		username   string
+		salary     decimal.Decimal
+		ptkpStatus string
+		setupMocks func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories)
+		expectErr  error
+		errMessage string
+	}{
+		{
+			name:       "success",
+			username:   " alice ",
+			salary:     decimal.NewFromInt(6000000),
+			ptkpStatus: "",
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.userRepo.EXPECT().CreateUser(gomock.Any()).DoAndReturn(func(u *domain.User) error {
+					assert.Equal(t, "alice", u.Username)
+					assert.Equal(t, "employee", u.Role)
+					assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("secret")))
+					u.ID = userID
+					return nil
+				})
+				tx.employeeProfileRepo.EXPECT().CreateEmployeeProfile(gomock.Any()).DoAndReturn(func(p *domain.EmployeeProfile) error {
+					// The profile is created for the new user in the same transaction
+					assert.Equal(t, userID, p.UserID)
+					assert.Equal(t, domain.PTKPStatusTK0, p.PTKPStatus)
+					assert.Equal(t, hireDate, *p.HireDate)
+					assert.Equal(t, adminID, p.CreatedBy)
+					return nil
+				})
+				tx.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+			},
+		},
+		{
+			name:       "invalid PTKP status",
+			username:   "alice",
+			salary:     decimal.NewFromInt(6000000),
+			ptkpStatus: "X/9",
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {},
+			errMessage: `invalid PTKP status "X/9"`,
+		},
+		{
+			name:       "salary not positive",
+			username:   "alice",
+			salary:     decimal.Zero,
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {},
+			errMessage: "salary must be greater than 0",
+		},
+		{
+			name:     "username taken",
+			username: "alice",
+			salary:   decimal.NewFromInt(6000000),
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.userRepo.EXPECT().CreateUser(gomock.Any()).Return(repository.ErrDuplicateRecord)
+			},
+			expectErr: service.ErrUsernameTaken,
+		},
+		{
+			name:     "profile failure rolls back the user",
+			username: "alice",
+			salary:   decimal.NewFromInt(6000000),
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.userRepo.EXPECT().CreateUser(gomock.Any()).Return(nil)
+				tx.employeeProfileRepo.EXPECT().CreateEmployeeProfile(gomock.Any()).Return(errors.New("db error"))
+			},
+			errMessage: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			tt.setupMocks(tx, uow, txRepos)
+
+			svc := service.NewEmployeeService(mockrepo.NewMockUserRepository(ctrl), mockrepo.NewMockEmployeeProfileRepository(ctrl), uow)
+			profile, err := svc.CreateEmployee(tt.username, "secret", tt.salary, tt.ptkpStatus, hireDate, adminID, "127.0.0.1", "req-123")
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, profile)
+			case tt.errMessage != "":
+				assert.EqualError(t, err, tt.errMessage)
+				assert.Nil(t, profile)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, userID, profile.UserID)
+				assert.Equal(t, "alice", profile.User.Username)
+				assert.True(t, tt.salary.Equal(profile.Salary))
+			}
+		})
+	}
+}
+
+func TestEmployeeService_UpdateEmployee(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		setupMocks func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories)
+		expectErr  error
+	}{
+		{
+			name: "success",
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID, PTKPStatus: domain.PTKPStatusTK0}, nil)
+				tx.userRepo.EXPECT().GetUserByID(userID).Return(&domain.User{BaseModel: domain.BaseModel{ID: userID}, Username: "alice"}, nil)
+				tx.userRepo.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(u *domain.User) error {
+					assert.Equal(t, "alice.w", u.Username)
+					return nil
+				})
+				tx.employeeProfileRepo.EXPECT().UpdateEmployeeProfile(gomock.Any()).DoAndReturn(func(p *domain.EmployeeProfile) error {
+					assert.Equal(t, domain.PTKPStatusK1, p.PTKPStatus)
+					assert.Equal(t, adminID, p.UpdatedBy)
+					return nil
+				})
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, "UPDATE", log.Action)
+					assert.Equal(t, "EmployeeProfile", log.EntityName)
+					return nil
+				})
+			},
+		},
+		{
+			name: "employee not found",
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(nil, nil)
+			},
+			expectErr: service.ErrEmployeeNotFound,
+		},
+		{
+			name: "username taken",
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID}, nil)
+				tx.userRepo.EXPECT().GetUserByID(userID).Return(&domain.User{BaseModel: domain.BaseModel{ID: userID}}, nil)
+				tx.userRepo.EXPECT().UpdateUser(gomock.Any()).Return(repository.ErrDuplicateRecord)
+			},
+			expectErr: service.ErrUsernameTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			tt.setupMocks(tx, uow, txRepos)
+
+			svc := service.NewEmployeeService(mockrepo.NewMockUserRepository(ctrl), mockrepo.NewMockEmployeeProfileRepository(ctrl), uow)
+			profile, err := svc.UpdateEmployee(userID, "alice.w", domain.PTKPStatusK1, nil, adminID, "127.0.0.1", "req-123")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, profile)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "alice.w", profile.User.Username)
+		})
+	}
+}
+
+func TestEmployeeService_DeactivateEmployee(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	deactivatedAt := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		user       *domain.User
+		setupMocks func(tx *txMocks)
+		expectErr  error
+	}{
+		{
+			name: "success",
+			user: &domain.User{BaseModel: domain.BaseModel{ID: userID}},
+			setupMocks: func(tx *txMocks) {
+				tx.userRepo.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(u *domain.User) error {
+					assert.NotNil(t, u.DeactivatedAt)
+					assert.Equal(t, adminID, u.UpdatedBy)
+					return nil
+				})
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, "DEACTIVATE", log.Action)
+					return nil
+				})
+			},
+		},
+		{
+			name:       "already deactivated",
+			user:       &domain.User{BaseModel: domain.BaseModel{ID: userID}, DeactivatedAt: &deactivatedAt},
+			setupMocks: func(tx *txMocks) {},
+			expectErr:  service.ErrEmployeeDeactivated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			expectTransaction(uow, txRepos)
+			tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID}, nil)
+			tx.userRepo.EXPECT().GetUserByID(userID).Return(tt.user, nil)
+			tt.setupMocks(tx)
+
+			svc := service.NewEmployeeService(mockrepo.NewMockUserRepository(ctrl), mockrepo.NewMockEmployeeProfileRepository(ctrl), uow)
+			profile, err := svc.DeactivateEmployee(userID, adminID, "127.0.0.1", "req-123")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, profile)
+				return
+			}
+			require.NoError(t, err)
+			assert.False(t, profile.User.IsActive())
+		})
+	}
+}
+
+func TestEmployeeService_GetEmployee(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mockrepo.NewMockUserRepository(ctrl)
+	profileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+	svc := service.NewEmployeeService(userRepo, profileRepo, mockrepo.NewMockUnitOfWork(ctrl))
+
+	userID := uuid.New()
+	profileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID}, nil)
+	userRepo.EXPECT().GetUserByID(userID).Return(&domain.User{BaseModel: domain.BaseModel{ID: userID}, Username: "alice"}, nil)
+
+	profile, err := svc.GetEmployee(userID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", profile.User.Username)
+
+	// A user without an employee profile, such as an admin, is not an employee
+	adminID := uuid.New()
+	profileRepo.EXPECT().GetEmployeeProfileByUserID(adminID).Return(nil, nil)
+	_, err = svc.GetEmployee(adminID)
+	assert.ErrorIs(t, err, service.ErrEmployeeNotFound)
+}
+
+func TestEmployeeService_SearchEmployees(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	profileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+	svc := service.NewEmployeeService(mockrepo.NewMockUserRepository(ctrl), profileRepo, mockrepo.NewMockUnitOfWork(ctrl))
+
+	profileRepo.EXPECT().SearchEmployeeProfiles("ali").Return([]domain.EmployeeProfile{{}, {}}, nil)
+
+	profiles, err := svc.SearchEmployees("  ali ")
+	require.NoError(t, err)
+	assert.Len(t, profiles, 2)
+}
+
+func TestEmployeeService_TerminateEmployee(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	hireDate := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+	terminationDate := time.Date(2025, 8, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		profile    *domain.EmployeeProfile
+		date       time.Time
+		setupMocks func(tx *txMocks)
+		expectErr  error
+	}{
+		{
+			name:    "success",
+			profile: &domain.EmployeeProfile{UserID: userID, HireDate: &hireDate},
+			date:    terminationDate,
+			setupMocks: func(tx *txMocks) {
+				tx.employeeProfileRepo.EXPECT().UpdateEmployeeProfile(gomock.Any()).DoAndReturn(func(p *domain.EmployeeProfile) error {
+					assert.Equal(t, terminationDate, *p.TerminationDate)
+					assert.Equal(t, adminID, p.UpdatedBy)
+					return nil
+				})
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, "TERMINATE", log.Action)
+					assert.Equal(t, "EmployeeProfile", log.EntityName)
+					return nil
+				})
+			},
+		},
+		{
+			name:       "before the hire date",
+			profile:    &domain.EmployeeProfile{UserID: userID, HireDate: &hireDate},
+			date:       hireDate.AddDate(0, 0, -1),
+			setupMocks: func(tx *txMocks) {},
+			expectErr:  service.ErrInvalidEmploymentDates,
+		},
+		{
+			name:       "already terminated",
+			profile:    &domain.EmployeeProfile{UserID: userID, HireDate: &hireDate, TerminationDate: &terminationDate},
+			date:       terminationDate,
+			setupMocks: func(tx *txMocks) {},
+			expectErr:  service.ErrEmployeeTerminated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			expectTransaction(uow, txRepos)
+			tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(tt.profile, nil)
+			tx.userRepo.EXPECT().GetUserByID(userID).Return(&domain.User{BaseModel: domain.BaseModel{ID: userID}}, nil)
+			tt.setupMocks(tx)
+
+			svc := service.NewEmployeeService(mockrepo.NewMockUserRepository(ctrl), mockrepo.NewMockEmployeeProfileRepository(ctrl), uow)
+			profile, err := svc.TerminateEmployee(userID, tt.date, adminID, "127.0.0.1", "req-123")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, profile)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, terminationDate, *profile.TerminationDate)
+		})
+	}
+}
+
+func TestEmployeeService_AssignManager(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	managerID := uuid.New()
+	directorID := uuid.New()
+
+	tests := []struct {
+		name       string
+		managerID  *uuid.UUID
+		setupMocks func(tx *txMocks)
+		expectErr  error
+		errMessage string
+	}{
+		{
+			name:      "success",
+			managerID: &managerID,
+			setupMocks: func(tx *txMocks) {
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(managerID).Return(&domain.EmployeeProfile{UserID: managerID, ManagerID: &directorID}, nil)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(directorID).Return(&domain.EmployeeProfile{UserID: directorID}, nil)
+				tx.employeeProfileRepo.EXPECT().UpdateEmployeeProfile(gomock.Any()).DoAndReturn(func(p *domain.EmployeeProfile) error {
+					assert.Equal(t, managerID, *p.ManagerID)
+					assert.Equal(t, adminID, p.UpdatedBy)
+					return nil
+				})
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, "ASSIGN", log.Action)
+					assert.Equal(t, "EmployeeProfile", log.EntityName)
+					return nil
+				})
+			},
+		},
+		{
+			name:      "unassign",
+			managerID: nil,
+			setupMocks: func(tx *txMocks) {
+				tx.employeeProfileRepo.EXPECT().UpdateEmployeeProfile(gomock.Any()).Return(nil)
+				tx.auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:       "reports to themselves",
+			managerID:  &userID,
+			setupMocks: func(tx *txMocks) {},
+			expectErr:  service.ErrInvalidManager,
+			errMessage: "invalid manager: an employee cannot report to themselves",
+		},
+		{
+			name:      "manager is not an employee",
+			managerID: &managerID,
+			setupMocks: func(tx *txMocks) {
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(managerID).Return(nil, nil)
+			},
+			expectErr:  service.ErrInvalidManager,
+			errMessage: "invalid manager: manager is not an employee",
+		},
+		{
+			name:      "manager reports to the employee",
+			managerID: &managerID,
+			setupMocks: func(tx *txMocks) {
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(managerID).Return(&domain.EmployeeProfile{UserID: managerID, ManagerID: &directorID}, nil)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(directorID).Return(&domain.EmployeeProfile{UserID: directorID, ManagerID: &userID}, nil)
+			},
+			expectErr:  service.ErrInvalidManager,
+			errMessage: "invalid manager: the manager reports to the employee",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			expectTransaction(uow, txRepos)
+			tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID}, nil)
+			tx.userRepo.EXPECT().GetUserByID(userID).Return(&domain.User{BaseModel: domain.BaseModel{ID: userID}}, nil)
+			tt.setupMocks(tx)
+
+			svc := service.NewEmployeeService(mockrepo.NewMockUserRepository(ctrl), mockrepo.NewMockEmployeeProfileRepository(ctrl), uow)
+			profile, err := svc.AssignManager(userID, tt.managerID, adminID, "127.0.0.1", "req-123")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.EqualError(t, err, tt.errMessage)
+				assert.Nil(t, profile)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.managerID, profile.ManagerID)
+		})
+	}
+}
+
+func TestPreviewPayroll_ProratesJoinersAndLeavers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Monday 4 to Friday 15 August: 10 working days, 80 hours
+	period := &domain.PayrollPeriod{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		StartDate: time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC),
+	}
+	joinerID := uuid.New()
+	leaverID := uuid.New()
+	hireDate := time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC)
+	terminationDate := time.Date(2025, 8, 8, 0, 0, 0, 0, time.UTC)
+
+	// Both have attendance on every working day of the period, including the days outside
+	// their employment
+	attendancesOf := func(userID uuid.UUID) []domain.Attendance {
+		var attendances []domain.Attendance
+		for d := period.StartDate; !d.After(period.EndDate); d = d.AddDate(0, 0, 1) {
+			if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+				continue
+			}
+			checkIn := d.Add(9 * time.Hour)
+			checkOut := checkIn.Add(8 * time.Hour)
+			attendances = append(attendances, domain.Attendance{UserID: userID, Date: d, CheckInTime: checkIn, CheckOutTime: &checkOut})
+		}
+		return attendances
+	}
+
+	payrollPeriodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
+	employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+	salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+	attendanceRepo := mockrepo.NewMockAttendanceRepository(ctrl)
+	overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
+	reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+	holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+	workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+	leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
+
+	payrollPeriodRepo.EXPECT().GetPayrollPeriodByID(period.ID).Return(period, nil)
+	employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(period.StartDate, period.EndDate).Return([]domain.EmployeeProfile{
+		{UserID: joinerID, Salary: decimal.NewFromInt(8000000), HireDate: &hireDate},
+		{UserID: leaverID, Salary: decimal.NewFromInt(8000000), TerminationDate: &terminationDate},
+	}, nil)
+	salaryHistoryRepo.EXPECT().GetSalaryHistoriesForPeriodGroupedByUser(period.StartDate, period.EndDate).Return(nil, nil)
+	attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Attendance{
+		joinerID: attendancesOf(joinerID),
+		leaverID: attendancesOf(leaverID),
+	}, nil)
+	overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Overtime{
+		joinerID: {{UserID: joinerID, Date: time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC), Hours: 2}},
+	}, nil)
+	reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(period.EndDate).Return(nil, nil)
+	holidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return(nil, nil)
+	workScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil)
+	leaveRepo.EXPECT().GetApprovedLeaveRequestsByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	registry := service.NewPayslipComponentRegistry(service.BasicSalaryCalculator{}, service.OvertimeCalculator{})
+	svc := service.NewPayrollService(
+		mockrepo.NewMockPayslipRepository(ctrl), payrollPeriodRepo, employeeProfileRepo, salaryHistoryRepo, attendanceRepo, overtimeRepo, reimbursementRepo, holidayRepo, workScheduleRepo, leaveRepo,
+		mockrepo.NewMockAuditLogRepository(ctrl), mockrepo.NewMockUnitOfWork(ctrl), service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), registry,
+	)
+
+	preview, err := svc.PreviewPayroll(period.ID)
+	require.NoError(t, err)
+	require.Len(t, preview.Payslips, 2)
+
+	// Each is paid for the 40 hours of their active week at 8,000,000 / 80h; the joiner's
+	// overtime before the hire date is not paid
+	for _, payslip := range preview.Payslips {
+		assert.Equal(t, "8000000", payslip.BaseSalary.String())
+		assert.Equal(t, "4000000", payslip.ProratedSalary.String())
+		assert.Equal(t, "0", payslip.OvertimePay.String())
+		assert.Equal(t, "4000000", payslip.TotalTakeHomePay.String())
+	}
+}
+
+func TestCalculatePayslip_NotEmployedInPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	period := &domain.PayrollPeriod{
+		StartDate: time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC),
+	}
+	userID := uuid.New()
+	terminationDate := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+
+	employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+	employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID, TerminationDate: &terminationDate}, nil)
+
+	svc := service.NewPayrollService(
+		mockrepo.NewMockPayslipRepository(ctrl), mockrepo.NewMockPayrollPeriodRepository(ctrl), employeeProfileRepo, mockrepo.NewMockSalaryHistoryRepository(ctrl),
+		mockrepo.NewMockAttendanceRepository(ctrl), mockrepo.NewMockOvertimeRepository(ctrl), mockrepo.NewMockReimbursementRepository(ctrl), mockrepo.NewMockHolidayRepository(ctrl),
+		mockrepo.NewMockWorkScheduleRepository(ctrl), mockrepo.NewMockLeaveRepository(ctrl), mockrepo.NewMockAuditLogRepository(ctrl), mockrepo.NewMockUnitOfWork(ctrl), service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), service.NewPayslipComponentRegistry(),
+	)
+
+	_, _, _, _, err := svc.CalculatePayslip(userID, period, uuid.New(), "127.0.0.1")
+	assert.ErrorIs(t, err, service.ErrNotEmployedInPeriod)
+}
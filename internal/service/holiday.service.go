@@ -0,0 +1,397 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+)
+
+var (
+	// ErrHolidayNotFound is returned when a holiday does not exist.
+	ErrHolidayNotFound = errors.New("holiday not found")
+	// ErrDuplicateHoliday is returned when the date of a holiday is already on the calendar.
+	ErrDuplicateHoliday = errors.New("a holiday is already on the calendar on this date")
+	// ErrInvalidHolidayType is returned for a holiday type other than national or collective_leave.
+	ErrInvalidHolidayType = errors.New("holiday type must be national or collective_leave")
+	// ErrInvalidICalendar is returned when an imported file is not a valid iCalendar file.
+	ErrInvalidICalendar = errors.New("invalid iCalendar file")
+)
+
+// HolidayServiceInterface defines the methods of HolidayService for mocking purposes.
+//
+//go:generate mockgen -source=holiday.service.go -destination=../../tests/mocks/service/mock_holiday_service.go -package=mocks
+type HolidayServiceInterface interface {
+	// CreateHoliday adds a holiday to the calendar.
+	CreateHoliday(date time.Time, name, holidayType string, createdBy uuid.UUID, ipAddress, requestID string) (*domain.Holiday, error)
+	// UpdateHoliday changes the date, name and type of a holiday.
+	UpdateHoliday(id uuid.UUID, date time.Time, name, holidayType string, updatedBy uuid.UUID, ipAddress, requestID string) (*domain.Holiday, error)
+	// DeleteHoliday removes a holiday from the calendar.
+	DeleteHoliday(id uuid.UUID, deletedBy uuid.UUID, ipAddress, requestID string) error
+	// GetHolidays returns the holidays of a year, ordered by date.
+	GetHolidays(year int) ([]domain.Holiday, error)
+	// ImportHolidays adds the all-day events of an iCalendar file to the calendar.
+	ImportHolidays(r io.Reader, holidayType string, importedBy uuid.UUID, ipAddress, requestID string) (*HolidayImport, error)
+}
+
+// HolidayImport is the outcome of an iCalendar import.
+type HolidayImport struct {
+	Created []domain.Holiday // Holidays added to the calendar
+	Skipped []domain.Holiday // Events on a date that was already on the calendar; not saved
+}
+
+// HolidayService provides business logic for the holiday calendar.
+type HolidayService struct {
+	holidayRepo repository.HolidayRepository
+	uow         repository.UnitOfWork // For transaction management
+}
+
+// NewHolidayService creates a new HolidayService.
+func NewHolidayService(holidayRepo repository.HolidayRepository, uow repository.UnitOfWork) *HolidayService {
+	return &HolidayService{
+		holidayRepo: holidayRepo,
+		uow:         uow,
+	}
+}
+
+// CreateHoliday adds a holiday to the calendar. An empty holidayType is a national holiday.
+// The holiday and its audit log entry are written in one transaction. Payroll periods that are
+// already processed are not recalculated; reverse and rerun them to apply the holiday.
+func (s *HolidayService) CreateHoliday(date time.Time, name, holidayType string, createdBy uuid.UUID, ipAddress, requestID string) (*domain.Holiday, error) {
+	holidayType, err := holidayTypeOrDefault(holidayType)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("holiday name is required")
+	}
+
+	now := time.Now()
+	holiday := &domain.Holiday{
+		Date: date,
+		Name: name,
+		Type: holidayType,
+		BaseModel: domain.BaseModel{
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: createdBy,
+			UpdatedBy: createdBy,
+			IPAddress: ipAddress,
+		},
+	}
+
+	err = s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := repos.Holidays.CreateHoliday(holiday); err != nil {
+			if errors.Is(err, repository.ErrDuplicateRecord) {
+				return ErrDuplicateHoliday
+			}
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &createdBy, "CREATE", "Holiday", &holiday.ID, nil, holiday, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for holiday: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return holiday, nil
+}
+
+// UpdateHoliday changes the date, name and type of a holiday. An empty holidayType is a
+// national holiday.
+func (s *HolidayService) UpdateHoliday(id uuid.UUID, date time.Time, name, holidayType string, updatedBy uuid.UUID, ipAddress, requestID string) (*domain.Holiday, error) {
+	holidayType, err := holidayTypeOrDefault(holidayType)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("holiday name is required")
+	}
+
+	holiday, err := s.holidayRepo.GetHolidayByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if holiday == nil {
+		return nil, ErrHolidayNotFound
+	}
+
+	oldValue := *holiday
+	holiday.Date = date
+	holiday.Name = name
+	holiday.Type = holidayType
+	holiday.UpdatedAt = time.Now()
+	holiday.UpdatedBy = updatedBy
+	holiday.IPAddress = ipAddress
+
+	err = s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := repos.Holidays.UpdateHoliday(holiday); err != nil {
+			if errors.Is(err, repository.ErrDuplicateRecord) {
+				return ErrDuplicateHoliday
+			}
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &updatedBy, "UPDATE", "Holiday", &holiday.ID, oldValue, holiday, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for holiday: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return holiday, nil
+}
+
+// DeleteHoliday removes a holiday from the calendar; its date becomes a regular day again.
+func (s *HolidayService) DeleteHoliday(id uuid.UUID, deletedBy uuid.UUID, ipAddress, requestID string) error {
+	holiday, err := s.holidayRepo.GetHolidayByID(id)
+	if err != nil {
+		return err
+	}
+	if holiday == nil {
+		return ErrHolidayNotFound
+	}
+
+	return s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := repos.Holidays.DeleteHoliday(holiday); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &deletedBy, "DELETE", "Holiday", &holiday.ID, holiday, nil, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for holiday: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetHolidays returns the holidays of a year, ordered by date.
+func (s *HolidayService) GetHolidays(year int) ([]domain.Holiday, error) {
+	return s.holidayRepo.GetHolidaysBetween(
+		time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC),
+	)
+}
+
+// ImportHolidays adds the all-day events of an iCalendar (.ics) file to the calendar, one holiday
+// per day of every event. Events on a date that is already on the calendar are skipped, so the
+// same file can be imported again after it was extended. When holidayType is empty, events whose
+// summary mentions "cuti bersama" are collective leave and every other event is a national holiday.
+// All holidays and their audit log entries are written in one transaction.
+func (s *HolidayService) ImportHolidays(r io.Reader, holidayType string, importedBy uuid.UUID, ipAddress, requestID string) (*HolidayImport, error) {
+	if holidayType != "" && !domain.IsValidHolidayType(holidayType) {
+		return nil, ErrInvalidHolidayType
+	}
+
+	events, err := parseICalendarEvents(r)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var holidays []domain.Holiday
+	for _, event := range events {
+		eventType := holidayType
+		if eventType == "" {
+			eventType = domain.HolidayTypeNational
+			if strings.Contains(strings.ToLower(event.Summary), "cuti bersama") {
+				eventType = domain.HolidayTypeCollectiveLeave
+			}
+		}
+		for d := event.Start; d.Before(event.End); d = d.AddDate(0, 0, 1) {
+			holidays = append(holidays, domain.Holiday{
+				Date: d,
+				Name: event.Summary,
+				Type: eventType,
+				BaseModel: domain.BaseModel{
+					ID:        uuid.New(),
+					CreatedAt: now,
+					UpdatedAt: now,
+					CreatedBy: importedBy,
+					UpdatedBy: importedBy,
+					IPAddress: ipAddress,
+				},
+			})
+		}
+	}
+
+	first, last := holidays[0].Date, holidays[0].Date
+	for _, h := range holidays {
+		if h.Date.Before(first) {
+			first = h.Date
+		}
+		if h.Date.After(last) {
+			last = h.Date
+		}
+	}
+
+	result := &HolidayImport{}
+	err = s.uow.Transaction(func(repos *repository.Repositories) error {
+		existing, err := repos.Holidays.GetHolidaysBetween(first, last)
+		if err != nil {
+			return err
+		}
+		calendar := domain.NewWorkCalendar(existing)
+
+		// A date is imported once, even when several events of the file fall on it.
+		seen := make(map[string]bool, len(holidays))
+		for _, h := range holidays {
+			key := h.Date.Format("2006-01-02")
+			if _, onCalendar := calendar.Holiday(h.Date); onCalendar || seen[key] {
+				result.Skipped = append(result.Skipped, h)
+				continue
+			}
+			seen[key] = true
+			result.Created = append(result.Created, h)
+		}
+
+		if err := repos.Holidays.CreateHolidays(result.Created); err != nil {
+			if errors.Is(err, repository.ErrDuplicateRecord) {
+				return ErrDuplicateHoliday
+			}
+			return fmt.Errorf("failed to save holidays: %w", err)
+		}
+
+		audits := make([]*domain.AuditLog, 0, len(result.Created))
+		for i := range result.Created {
+			audit, err := repository.NewAuditLog(&importedBy, "CREATE", "Holiday", &result.Created[i].ID, nil, result.Created[i], ipAddress, requestID)
+			if err != nil {
+				return fmt.Errorf("failed to build audit log for holiday %s: %w", result.Created[i].ID, err)
+			}
+			audits = append(audits, audit)
+		}
+		if err := repos.AuditLogs.CreateBatch(audits); err != nil {
+			return fmt.Errorf("failed to write audit logs for holidays: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// holidayTypeOrDefault validates a holiday type; an empty type is a national holiday.
+func holidayTypeOrDefault(holidayType string) (string, error) {
+	if holidayType == "" {
+		return domain.HolidayTypeNational, nil
+	}
+	if !domain.IsValidHolidayType(holidayType) {
+		return "", ErrInvalidHolidayType
+	}
+	return holidayType, nil
+}
+
+// loadWorkCalendar loads the work calendar of a date range.
+func loadWorkCalendar(holidayRepo repository.HolidayRepository, startDate, endDate time.Time) (domain.WorkCalendar, error) {
+	holidays, err := holidayRepo.GetHolidaysBetween(startDate, endDate)
+	if err != nil {
+		return domain.WorkCalendar{}, err
+	}
+	return domain.NewWorkCalendar(holidays), nil
+}
+
+// icalEvent is an all-day event of an iCalendar file, from Start up to but not including End.
+type icalEvent struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// icalTextUnescaper undoes the escaping of iCalendar TEXT values (RFC 5545, section 3.3.11).
+var icalTextUnescaper = strings.NewReplacer(`\\`, `\`, `\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ")
+
+// parseICalendarEvents reads the VEVENT components of an iCalendar file. Only the date of DTSTART
+// and DTEND is used, so timed events count as all-day events; without DTEND an event lasts one day.
+func parseICalendarEvents(r io.Reader) ([]icalEvent, error) {
+	// Long lines are folded onto continuation lines that start with a space or a tab.
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidICalendar, err)
+	}
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "BEGIN:VCALENDAR" {
+		return nil, fmt.Errorf("%w: missing BEGIN:VCALENDAR", ErrInvalidICalendar)
+	}
+
+	var events []icalEvent
+	var event *icalEvent
+	for _, line := range lines {
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		// Drop parameters such as ";VALUE=DATE" from the property name.
+		name, _, _ = strings.Cut(name, ";")
+		name = strings.ToUpper(name)
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = &icalEvent{}
+		case event == nil:
+			continue
+		case name == "SUMMARY":
+			event.Summary = strings.TrimSpace(icalTextUnescaper.Replace(value))
+		case name == "DTSTART" || name == "DTEND":
+			date, err := parseICalendarDate(value)
+			if err != nil {
+				return nil, err
+			}
+			if name == "DTSTART" {
+				event.Start = date
+			} else {
+				event.End = date
+			}
+		case name == "END" && value == "VEVENT":
+			if event.Start.IsZero() {
+				return nil, fmt.Errorf("%w: event %q has no DTSTART", ErrInvalidICalendar, event.Summary)
+			}
+			if event.Summary == "" {
+				return nil, fmt.Errorf("%w: event on %s has no SUMMARY", ErrInvalidICalendar, event.Start.Format("2006-01-02"))
+			}
+			if !event.End.After(event.Start) {
+				event.End = event.Start.AddDate(0, 0, 1)
+			}
+			events = append(events, *event)
+			event = nil
+		}
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: no events found", ErrInvalidICalendar)
+	}
+	return events, nil
+}
+
+// parseICalendarDate parses the date of an iCalendar DATE (20250817) or DATE-TIME (20250817T000000Z) value.
+func parseICalendarDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("%w: invalid date %q", ErrInvalidICalendar, value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date %q", ErrInvalidICalendar, value)
+	}
+	return date, nil
+}
@@ -0,0 +1,407 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+	"payroll-system/internal/service"
+	mockrepo "payroll-system/tests/mocks/repository"
+)
+
+func TestHolidayService_CreateHoliday(t *testing.T) {
+	adminID := uuid.New()
+	date := time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		holidayType string
+		setupMocks  func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories)
+		expectErr   error
+		errMessage  string
+		expectType  string
+	}{
+		{
+			name: "success defaults to a national holiday",
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.holidayRepo.EXPECT().CreateHoliday(gomock.Any()).DoAndReturn(func(h *domain.Holiday) error {
+					assert.Equal(t, date, h.Date)
+					assert.Equal(t, adminID, h.CreatedBy)
+					return nil
+				})
+				// The holiday is audited in the same transaction
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, "CREATE", log.Action)
+					assert.Equal(t, "Holiday", log.EntityName)
+					return nil
+				})
+			},
+			expectType: domain.HolidayTypeNational,
+		},
+		{
+			name:        "invalid type",
+			holidayType: "regional",
+			setupMocks:  func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {},
+			expectErr:   service.ErrInvalidHolidayType,
+		},
+		{
+			name:        "date already on the calendar",
+			holidayType: domain.HolidayTypeCollectiveLeave,
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.holidayRepo.EXPECT().CreateHoliday(gomock.Any()).Return(repository.ErrDuplicateRecord)
+			},
+			expectErr: service.ErrDuplicateHoliday,
+		},
+		{
+			name: "audit failure rolls back the holiday",
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.holidayRepo.EXPECT().CreateHoliday(gomock.Any()).Return(nil)
+				tx.auditRepo.EXPECT().Create(gomock.Any()).Return(errors.New("audit db down"))
+			},
+			errMessage: "failed to write audit log for holiday: audit db down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			tt.setupMocks(tx, uow, txRepos)
+
+			svc := service.NewHolidayService(mockrepo.NewMockHolidayRepository(ctrl), uow)
+			holiday, err := svc.CreateHoliday(date, " Hari Kemerdekaan ", tt.holidayType, adminID, "127.0.0.1", "req-123")
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, holiday)
+			case tt.errMessage != "":
+				assert.EqualError(t, err, tt.errMessage)
+				assert.Nil(t, holiday)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, "Hari Kemerdekaan", holiday.Name)
+				assert.Equal(t, tt.expectType, holiday.Type)
+			}
+		})
+	}
+}
+
+func TestHolidayService_UpdateHoliday(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	holidayID := uuid.New()
+	adminID := uuid.New()
+	newDate := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+
+	holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+	uow := mockrepo.NewMockUnitOfWork(ctrl)
+	txRepos, tx := newTxRepositories(ctrl)
+	svc := service.NewHolidayService(holidayRepo, uow)
+
+	t.Run("not found", func(t *testing.T) {
+		holidayRepo.EXPECT().GetHolidayByID(holidayID).Return(nil, nil)
+
+		holiday, err := svc.UpdateHoliday(holidayID, newDate, "Cuti Bersama", domain.HolidayTypeCollectiveLeave, adminID, "127.0.0.1", "req-123")
+		assert.ErrorIs(t, err, service.ErrHolidayNotFound)
+		assert.Nil(t, holiday)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		holidayRepo.EXPECT().GetHolidayByID(holidayID).Return(&domain.Holiday{
+			BaseModel: domain.BaseModel{ID: holidayID},
+			Date:      time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC),
+			Name:      "Hari Kemerdekaan",
+			Type:      domain.HolidayTypeNational,
+		}, nil)
+		expectTransaction(uow, txRepos)
+		tx.holidayRepo.EXPECT().UpdateHoliday(gomock.Any()).Return(nil)
+		tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+			assert.Equal(t, "UPDATE", log.Action)
+			assert.Contains(t, string(log.OldValue), "Hari Kemerdekaan")
+			assert.Contains(t, string(log.NewValue), "Cuti Bersama")
+			return nil
+		})
+
+		holiday, err := svc.UpdateHoliday(holidayID, newDate, "Cuti Bersama", domain.HolidayTypeCollectiveLeave, adminID, "127.0.0.1", "req-123")
+		require.NoError(t, err)
+		assert.Equal(t, newDate, holiday.Date)
+		assert.Equal(t, domain.HolidayTypeCollectiveLeave, holiday.Type)
+		assert.Equal(t, adminID, holiday.UpdatedBy)
+	})
+}
+
+func TestHolidayService_DeleteHoliday(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	holidayID := uuid.New()
+	holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+	uow := mockrepo.NewMockUnitOfWork(ctrl)
+	txRepos, tx := newTxRepositories(ctrl)
+	svc := service.NewHolidayService(holidayRepo, uow)
+
+	t.Run("not found", func(t *testing.T) {
+		holidayRepo.EXPECT().GetHolidayByID(holidayID).Return(nil, nil)
+		assert.ErrorIs(t, svc.DeleteHoliday(holidayID, uuid.New(), "127.0.0.1", "req-123"), service.ErrHolidayNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		holiday := &domain.Holiday{BaseModel: domain.BaseModel{ID: holidayID}, Name: "Hari Kemerdekaan"}
+		holidayRepo.EXPECT().GetHolidayByID(holidayID).Return(holiday, nil)
+		expectTransaction(uow, txRepos)
+		tx.holidayRepo.EXPECT().DeleteHoliday(holiday).Return(nil)
+		tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+			assert.Equal(t, "DELETE", log.Action)
+			assert.Equal(t, "null", string(log.NewValue))
+			return nil
+		})
+
+		assert.NoError(t, svc.DeleteHoliday(holidayID, uuid.New(), "127.0.0.1", "req-123"))
+	})
+}
+
+func TestHolidayService_GetHolidays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+	holidayRepo.EXPECT().GetHolidaysBetween(
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+	).Return([]domain.Holiday{{Name: "Tahun Baru"}}, nil)
+
+	svc := service.NewHolidayService(holidayRepo, mockrepo.NewMockUnitOfWork(ctrl))
+	holidays, err := svc.GetHolidays(2025)
+	require.NoError(t, err)
+	assert.Len(t, holidays, 1)
+}
+
+// holidaysICS is an export of a public holiday calendar: a folded SUMMARY, a multi-day
+// collective leave event and a date-time event.
+const holidaysICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Example//Holidays//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20250101\r\n" +
+	"DTEND;VALUE=DATE:20250102\r\n" +
+	"SUMMARY:Tahun Baru\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20250402\r\n" +
+	"DTEND;VALUE=DATE:20250405\r\n" +
+	"SUMMARY:Cuti Bersama Hari Raya\r\n" +
+	"  Idul Fitri\\, 1446 H\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20250817T000000Z\r\n" +
+	"SUMMARY:Hari Kemerdekaan\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestHolidayService_ImportHolidays(t *testing.T) {
+	adminID := uuid.New()
+
+	t.Run("imports every day of every event and skips dates already on the calendar", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		uow := mockrepo.NewMockUnitOfWork(ctrl)
+		txRepos, tx := newTxRepositories(ctrl)
+		expectTransaction(uow, txRepos)
+
+		tx.holidayRepo.EXPECT().GetHolidaysBetween(
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC),
+		).Return([]domain.Holiday{{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Name: "Tahun Baru Masehi"}}, nil)
+		tx.holidayRepo.EXPECT().CreateHolidays(gomock.Any()).DoAndReturn(func(holidays []domain.Holiday) error {
+			assert.Len(t, holidays, 4)
+			return nil
+		})
+		tx.auditRepo.EXPECT().CreateBatch(gomock.Any()).DoAndReturn(func(audits []*domain.AuditLog) error {
+			assert.Len(t, audits, 4)
+			return nil
+		})
+
+		svc := service.NewHolidayService(mockrepo.NewMockHolidayRepository(ctrl), uow)
+		result, err := svc.ImportHolidays(strings.NewReader(holidaysICS), "", adminID, "127.0.0.1", "req-123")
+		require.NoError(t, err)
+
+		require.Len(t, result.Skipped, 1)
+		assert.Equal(t, "Tahun Baru", result.Skipped[0].Name)
+
+		// DTEND is exclusive: the collective leave lasts from 2 through 4 April
+		require.Len(t, result.Created, 4)
+		for i, day := range []int{2, 3, 4} {
+			assert.Equal(t, time.Date(2025, 4, day, 0, 0, 0, 0, time.UTC), result.Created[i].Date)
+			assert.Equal(t, "Cuti Bersama Hari Raya Idul Fitri, 1446 H", result.Created[i].Name)
+			assert.Equal(t, domain.HolidayTypeCollectiveLeave, result.Created[i].Type)
+		}
+		assert.Equal(t, time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC), result.Created[3].Date)
+		assert.Equal(t, domain.HolidayTypeNational, result.Created[3].Type)
+		assert.Equal(t, adminID, result.Created[3].CreatedBy)
+	})
+
+	t.Run("type given for the whole file", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		uow := mockrepo.NewMockUnitOfWork(ctrl)
+		txRepos, tx := newTxRepositories(ctrl)
+		expectTransaction(uow, txRepos)
+		tx.holidayRepo.EXPECT().GetHolidaysBetween(gomock.Any(), gomock.Any()).Return(nil, nil)
+		tx.holidayRepo.EXPECT().CreateHolidays(gomock.Any()).Return(nil)
+		tx.auditRepo.EXPECT().CreateBatch(gomock.Any()).Return(nil)
+
+		svc := service.NewHolidayService(mockrepo.NewMockHolidayRepository(ctrl), uow)
+		result, err := svc.ImportHolidays(strings.NewReader(holidaysICS), domain.HolidayTypeCollectiveLeave, adminID, "127.0.0.1", "req-123")
+		require.NoError(t, err)
+		require.Len(t, result.Created, 5)
+		for _, h := range result.Created {
+			assert.Equal(t, domain.HolidayTypeCollectiveLeave, h.Type)
+		}
+	})
+
+	invalid := []struct {
+		name        string
+		ics         string
+		holidayType string
+		expectErr   error
+		errMessage  string
+	}{
+		{
+			name:      "not an iCalendar file",
+			ics:       "date,name\n2025-01-01,Tahun Baru\n",
+			expectErr: service.ErrInvalidICalendar,
+		},
+		{
+			name:       "no events",
+			ics:        "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nEND:VCALENDAR\r\n",
+			errMessage: "invalid iCalendar file: no events found",
+		},
+		{
+			name:       "invalid date",
+			ics:        "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:2025-01-01\r\nSUMMARY:Tahun Baru\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			errMessage: `invalid iCalendar file: invalid date "2025-01-01"`,
+		},
+		{
+			name:       "event without a date",
+			ics:        "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:Tahun Baru\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			errMessage: `invalid iCalendar file: event "Tahun Baru" has no DTSTART`,
+		},
+		{
+			name:        "invalid type",
+			ics:         holidaysICS,
+			holidayType: "regional",
+			expectErr:   service.ErrInvalidHolidayType,
+		},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Nothing is written: gomock fails on any call to the unit of work.
+			svc := service.NewHolidayService(mockrepo.NewMockHolidayRepository(ctrl), mockrepo.NewMockUnitOfWork(ctrl))
+			result, err := svc.ImportHolidays(strings.NewReader(tt.ics), tt.holidayType, adminID, "127.0.0.1", "req-123")
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.EqualError(t, err, tt.errMessage)
+			}
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestPreviewPayroll_ExcludesHolidays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Thursday 29 May 2025 is Ascension Day and Friday 30 May is collective leave,
+	// so the week has 3 working days
+	period := &domain.PayrollPeriod{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		StartDate: time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC),
+	}
+	userID := uuid.New()
+	var attendances []domain.Attendance
+	for day := 26; day <= 28; day++ {
+		checkIn := time.Date(2025, 5, day, 9, 0, 0, 0, time.UTC)
+		checkOut := checkIn.Add(8 * time.Hour)
+		attendances = append(attendances, domain.Attendance{UserID: userID, Date: checkIn, CheckInTime: checkIn, CheckOutTime: &checkOut})
+	}
+
+	payrollPeriodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
+	employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+	salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+	attendanceRepo := mockrepo.NewMockAttendanceRepository(ctrl)
+	overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
+	reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+	holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+	workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+	leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
+
+	payrollPeriodRepo.EXPECT().GetPayrollPeriodByID(period.ID).Return(period, nil)
+	employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(period.StartDate, period.EndDate).Return([]domain.EmployeeProfile{{UserID: userID, Salary: decimal.NewFromInt(2400000)}}, nil)
+	salaryHistoryRepo.EXPECT().GetSalaryHistoriesForPeriodGroupedByUser(period.StartDate, period.EndDate).Return(nil, nil)
+	attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Attendance{userID: attendances}, nil)
+	overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Overtime{
+		userID: {
+			{UserID: userID, Date: time.Date(2025, 5, 27, 0, 0, 0, 0, time.UTC), Hours: 1},
+			{UserID: userID, Date: time.Date(2025, 5, 29, 0, 0, 0, 0, time.UTC), Hours: 2},
+		},
+	}, nil)
+	reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(period.EndDate).Return(nil, nil)
+	holidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return([]domain.Holiday{
+		{Date: time.Date(2025, 5, 29, 0, 0, 0, 0, time.UTC), Name: "Kenaikan Yesus Kristus", Type: domain.HolidayTypeNational},
+		{Date: time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC), Name: "Cuti Bersama Kenaikan Yesus Kristus", Type: domain.HolidayTypeCollectiveLeave},
+	}, nil)
+	workScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil)
+	leaveRepo.EXPECT().GetApprovedLeaveRequestsByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	registry := service.NewPayslipComponentRegistry(service.BasicSalaryCalculator{}, service.OvertimeCalculator{})
+	svc := service.NewPayrollService(
+		mockrepo.NewMockPayslipRepository(ctrl), payrollPeriodRepo, employeeProfileRepo, salaryHistoryRepo, attendanceRepo, overtimeRepo, reimbursementRepo, holidayRepo, workScheduleRepo, leaveRepo,
+		mockrepo.NewMockAuditLogRepository(ctrl), mockrepo.NewMockUnitOfWork(ctrl), service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), registry,
+	)
+
+	preview, err := svc.PreviewPayroll(period.ID)
+	require.NoError(t, err)
+	require.Len(t, preview.Payslips, 1)
+	payslip := preview.Payslips[0]
+
+	// Attending every working day earns the full salary: 2,400,000 / 24h = 100,000 per hour
+	require.Len(t, payslip.Items, 3)
+	assert.Equal(t, "24", payslip.Items[0].Quantity.String())
+	assert.Equal(t, "100000", payslip.Items[0].Rate.String())
+	assert.Equal(t, "2400000", payslip.ProratedSalary.String())
+
+	// Overtime on a working day is paid at 2x, overtime on a holiday at 3x
+	assert.Equal(t, "Overtime", payslip.Items[1].Name)
+	assert.Equal(t, "200000", payslip.Items[1].Amount.String())
+	assert.Equal(t, "Overtime (rest day)", payslip.Items[2].Name)
+	assert.Equal(t, "300000", payslip.Items[2].Rate.String())
+	assert.Equal(t, "600000", payslip.Items[2].Amount.String())
+	assert.Equal(t, "800000", payslip.OvertimePay.String())
+
+	// The calendar is attached for the payslip response
+	assert.False(t, payslip.WorkCalendar.IsWorkingDay(time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,442 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+)
+
+var (
+	// ErrLeaveRequestNotFound is returned when a leave request does not exist.
+	ErrLeaveRequestNotFound = errors.New("leave request not found")
+	// ErrInvalidLeaveRequest is returned when the type or dates of a leave request are invalid.
+	ErrInvalidLeaveRequest = errors.New("invalid leave request")
+	// ErrLeaveOverlap is returned when a leave request has days in common with a pending or approved one.
+	ErrLeaveOverlap = errors.New("leave request overlaps another pending or approved leave request")
+	// ErrInsufficientLeaveBalance is returned when a leave request takes more days than the balance has left.
+	ErrInsufficientLeaveBalance = errors.New("insufficient leave balance")
+	// ErrLeaveRequestReviewed is returned when reviewing a leave request that is no longer pending.
+	ErrLeaveRequestReviewed = errors.New("leave request has already been reviewed")
+	// ErrLeaveReviewForbidden is returned when a user other than the employee's manager or an admin reviews a leave request.
+	ErrLeaveReviewForbidden = errors.New("only the employee's manager or an admin can review this leave request")
+	// ErrInvalidLeaveEntitlement is returned when setting an entitlement for a leave type without a balance, or a negative one.
+	ErrInvalidLeaveEntitlement = errors.New("invalid leave entitlement")
+)
+
+// LeaveServiceInterface defines the methods of LeaveService for mocking purposes.
+//
+//go:generate mockgen -source=leave.service.go -destination=../../tests/mocks/service/mock_leave_service.go -package=mocks
+type LeaveServiceInterface interface {
+	// SubmitLeaveRequest allows an employee to request leave from one date through another.
+	SubmitLeaveRequest(userID uuid.UUID, leaveType string, startDate, endDate time.Time, reason string, ipAddress, requestID string) (*domain.LeaveRequest, error)
+	// GetLeaveRequests returns the leave requests of an employee, the latest first.
+	GetLeaveRequests(userID uuid.UUID) ([]domain.LeaveRequest, error)
+	// GetPendingLeaveRequests returns the pending leave requests a user can review.
+	GetPendingLeaveRequests(reviewer *domain.User) ([]domain.LeaveRequest, error)
+	// ReviewLeaveRequest approves or rejects a pending leave request.
+	ReviewLeaveRequest(id uuid.UUID, approve bool, note string, reviewer *domain.User, ipAddress, requestID string) (*domain.LeaveRequest, error)
+	// GetLeaveBalances returns the leave balances of an employee for a year.
+	GetLeaveBalances(userID uuid.UUID, year int, now time.Time) ([]LeaveBalance, error)
+	// SetLeaveEntitlement sets the yearly entitlement of an employee for a leave type.
+	SetLeaveEntitlement(userID uuid.UUID, year int, leaveType string, days decimal.Decimal, setBy uuid.UUID, ipAddress, requestID string) (*domain.LeaveEntitlement, error)
+}
+
+// LeaveBalance is the state of an employee's yearly balance of a leave type, in working days.
+type LeaveBalance struct {
+	LeaveType   string
+	Year        int
+	Entitlement decimal.Decimal // Yearly days, from the leave policy unless set for the employee
+	Accrued     decimal.Decimal // Days of the entitlement accrued on the balance date
+	Used        decimal.Decimal // Days of approved leave
+	Pending     decimal.Decimal // Days of leave waiting for review
+	Available   decimal.Decimal // Accrued days less the used and pending ones
+}
+
+// LeaveService provides business logic for leave requests and leave balances.
+type LeaveService struct {
+	leaveRepo           repository.LeaveRepository
+	employeeProfileRepo repository.EmployeeProfileRepository
+	holidayRepo         repository.HolidayRepository
+	workScheduleRepo    repository.WorkScheduleRepository
+	periodLock          *PeriodLock
+	uow                 repository.UnitOfWork // For transaction management
+	policy              LeavePolicy
+}
+
+// NewLeaveService creates a new LeaveService.
+func NewLeaveService(
+	leaveRepo repository.LeaveRepository,
+	employeeProfileRepo repository.EmployeeProfileRepository,
+	holidayRepo repository.HolidayRepository,
+	workScheduleRepo repository.WorkScheduleRepository,
+	periodLock *PeriodLock,
+	uow repository.UnitOfWork,
+	policy LeavePolicy,
+) *LeaveService {
+	return &LeaveService{
+		leaveRepo:           leaveRepo,
+		employeeProfileRepo: employeeProfileRepo,
+		holidayRepo:         holidayRepo,
+		workScheduleRepo:    workScheduleRepo,
+		periodLock:          periodLock,
+		uow:                 uow,
+		policy:              policy,
+	}
+}
+
+// SubmitLeaveRequest allows an employee to request leave from startDate through endDate, within one
+// year and within their employment. The request takes the working days of the employee's schedule
+// in the range that are not holidays. Leave of a type with a balance cannot take more days than are
+// accrued on startDate less the days already approved or pending. Leave cannot be requested for a
+// day in a processed payroll period, as its payroll would no longer pay or deduct it.
+func (s *LeaveService) SubmitLeaveRequest(
+	userID uuid.UUID,
+	leaveType string,
+	startDate, endDate time.Time,
+	reason string,
+	ipAddress, requestID string,
+) (*domain.LeaveRequest, error) {
+	if !domain.IsValidLeaveType(leaveType) {
+		return nil, fmt.Errorf("%w: leave type must be annual, sick, maternity or unpaid", ErrInvalidLeaveRequest)
+	}
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("%w: end date cannot be before the start date", ErrInvalidLeaveRequest)
+	}
+	if endDate.Year() != startDate.Year() {
+		return nil, fmt.Errorf("%w: leave cannot span two years, submit one request per year", ErrInvalidLeaveRequest)
+	}
+
+	profile, err := s.employeeProfileRepo.GetEmployeeProfileByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrEmployeeNotFound
+	}
+	if from, to, employed := profile.EmploymentWindow(startDate, endDate); !employed || !from.Equal(startDate) || !to.Equal(endDate) {
+		return nil, fmt.Errorf("%w: leave must be within the employment", ErrInvalidLeaveRequest)
+	}
+	calendar, err := loadWorkCalendar(s.holidayRepo, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := s.workScheduleRepo.GetWorkScheduleByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	days := calendar.WithSchedule(schedule).WorkingDays(startDate, endDate)
+	if days == 0 {
+		return nil, fmt.Errorf("%w: there are no working days from %s through %s", ErrInvalidLeaveRequest,
+			startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	}
+
+	overlapping, err := s.leaveRepo.GetOverlappingLeaveRequests(userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if len(overlapping) > 0 {
+		return nil, ErrLeaveOverlap
+	}
+
+	if s.policy.HasBalance(leaveType) {
+		balance, err := s.leaveBalance(s.leaveRepo, profile, leaveType, startDate)
+		if err != nil {
+			return nil, err
+		}
+		if decimal.NewFromInt(int64(days)).GreaterThan(balance.Available) {
+			return nil, fmt.Errorf("%w: %d days requested, %s %s leave days available", ErrInsufficientLeaveBalance,
+				days, balance.Available, leaveType)
+		}
+	}
+
+	now := time.Now()
+	leave := &domain.LeaveRequest{
+		UserID:    userID,
+		LeaveType: leaveType,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Days:      days,
+		Reason:    strings.TrimSpace(reason),
+		Status:    domain.LeaveStatusPending,
+		BaseModel: domain.BaseModel{
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: userID,
+			UpdatedBy: userID,
+			IPAddress: ipAddress,
+		},
+	}
+
+	err = s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := s.periodLock.CheckRange(repos, startDate, endDate); err != nil {
+			return err
+		}
+		if err := repos.Leaves.CreateLeaveRequest(leave); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &userID, "CREATE", "LeaveRequest", &leave.ID, nil, leave, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for leave request: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return leave, nil
+}
+
+// GetLeaveRequests returns the leave requests of an employee, the latest first.
+func (s *LeaveService) GetLeaveRequests(userID uuid.UUID) ([]domain.LeaveRequest, error) {
+	return s.leaveRepo.GetLeaveRequestsByUserID(userID)
+}
+
+// GetPendingLeaveRequests returns the pending leave requests reviewer can review: every pending
+// request for an admin, the requests of the employees who report to them for anyone else.
+func (s *LeaveService) GetPendingLeaveRequests(reviewer *domain.User) ([]domain.LeaveRequest, error) {
+	if reviewer.Role == "admin" {
+		return s.leaveRepo.GetPendingLeaveRequests()
+	}
+	return s.leaveRepo.GetPendingLeaveRequestsByManagerID(reviewer.ID)
+}
+
+// ReviewLeaveRequest approves or rejects a pending leave request. Only an admin or the manager the
+// employee reports to can review it, and nobody can review their own request. An approval is
+// checked against the balance again, as other requests may have been approved since it was
+// submitted. The review and its audit log entry are written in one transaction. Approved paid
+// leave is paid as worked days by the payroll runs of its periods; approved unpaid leave is deducted.
+// Leave with a day in a processed payroll period cannot be approved, but it can still be rejected:
+// that changes no pay and releases the days it holds in the balance.
+func (s *LeaveService) ReviewLeaveRequest(
+	id uuid.UUID,
+	approve bool,
+	note string,
+	reviewer *domain.User,
+	ipAddress, requestID string,
+) (*domain.LeaveRequest, error) {
+	var leave *domain.LeaveRequest
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		var err error
+		leave, err = repos.Leaves.GetLeaveRequestByID(id)
+		if err != nil {
+			return err
+		}
+		if leave == nil {
+			return ErrLeaveRequestNotFound
+		}
+
+		profile, err := repos.EmployeeProfiles.GetEmployeeProfileByUserID(leave.UserID)
+		if err != nil {
+			return err
+		}
+		if !canReview(reviewer, leave.UserID, profile) {
+			return ErrLeaveReviewForbidden
+		}
+		if leave.Status != domain.LeaveStatusPending {
+			return ErrLeaveRequestReviewed
+		}
+		if approve {
+			if err := s.periodLock.CheckRange(repos, leave.StartDate, leave.EndDate); err != nil {
+				return err
+			}
+		}
+
+		if approve && profile != nil && s.policy.HasBalance(leave.LeaveType) {
+			balance, err := s.leaveBalance(repos.Leaves, profile, leave.LeaveType, leave.StartDate)
+			if err != nil {
+				return err
+			}
+			if balance.Used.Add(decimal.NewFromInt(int64(leave.Days))).GreaterThan(balance.Accrued) {
+				return fmt.Errorf("%w: %d days requested, %s used of %s %s leave days accrued", ErrInsufficientLeaveBalance,
+					leave.Days, balance.Used, balance.Accrued, leave.LeaveType)
+			}
+		}
+
+		oldLeave := *leave
+		now := time.Now()
+		action := "REJECT"
+		leave.Status = domain.LeaveStatusRejected
+		if approve {
+			action = "APPROVE"
+			leave.Status = domain.LeaveStatusApproved
+		}
+		leave.ReviewedBy = &reviewer.ID
+		leave.ReviewedAt = &now
+		leave.ReviewNote = strings.TrimSpace(note)
+		leave.UpdatedAt = now
+		leave.UpdatedBy = reviewer.ID
+		leave.IPAddress = ipAddress
+
+		if err := repos.Leaves.UpdateLeaveRequest(leave); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &reviewer.ID, action, "LeaveRequest", &leave.ID, oldLeave, leave, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for leave request: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return leave, nil
+}
+
+// GetLeaveBalances returns the balances of every leave type with an entitlement for a year, accrued
+// up to now. For a past year the whole year is accrued, for a future year only its first month.
+func (s *LeaveService) GetLeaveBalances(userID uuid.UUID, year int, now time.Time) ([]LeaveBalance, error) {
+	profile, err := s.employeeProfileRepo.GetEmployeeProfileByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	asOf := now
+	if first := time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location()); asOf.Before(first) {
+		asOf = first
+	}
+	if last := time.Date(year, time.December, 31, 0, 0, 0, 0, now.Location()); asOf.After(last) {
+		asOf = last
+	}
+	return s.leaveBalances(s.leaveRepo, profile, asOf)
+}
+
+// SetLeaveEntitlement sets the yearly entitlement of an employee for a leave type with a balance,
+// in place of the one of the leave policy, e.g. to carry over annual leave. The entitlement and its
+// audit log entry are written in one transaction.
+func (s *LeaveService) SetLeaveEntitlement(
+	userID uuid.UUID,
+	year int,
+	leaveType string,
+	days decimal.Decimal,
+	setBy uuid.UUID,
+	ipAddress, requestID string,
+) (*domain.LeaveEntitlement, error) {
+	if !s.policy.HasBalance(leaveType) {
+		return nil, fmt.Errorf("%w: %q leave has no balance", ErrInvalidLeaveEntitlement, leaveType)
+	}
+	if days.IsNegative() {
+		return nil, fmt.Errorf("%w: days cannot be negative", ErrInvalidLeaveEntitlement)
+	}
+
+	var entitlement *domain.LeaveEntitlement
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		if _, err := loadEmployee(repos.Users, repos.EmployeeProfiles, userID); err != nil {
+			return err
+		}
+
+		existing, err := repos.Leaves.GetLeaveEntitlement(userID, year, leaveType)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		action := "CREATE"
+		var oldValue any
+		if existing != nil {
+			action = "UPDATE"
+			oldValue = *existing
+			entitlement = existing
+		} else {
+			entitlement = &domain.LeaveEntitlement{
+				UserID:    userID,
+				Year:      year,
+				LeaveType: leaveType,
+				BaseModel: domain.BaseModel{CreatedAt: now, CreatedBy: setBy},
+			}
+		}
+		entitlement.Days = days
+		entitlement.UpdatedAt = now
+		entitlement.UpdatedBy = setBy
+		entitlement.IPAddress = ipAddress
+
+		if err := repos.Leaves.SaveLeaveEntitlement(entitlement); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &setBy, action, "LeaveEntitlement", &entitlement.ID, oldValue, entitlement, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for leave entitlement: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entitlement, nil
+}
+
+// leaveBalance returns the balance of one leave type in the year of date, accrued up to date.
+func (s *LeaveService) leaveBalance(leaveRepo repository.LeaveRepository, profile *domain.EmployeeProfile, leaveType string, date time.Time) (LeaveBalance, error) {
+	balances, err := s.leaveBalances(leaveRepo, profile, date)
+	if err != nil {
+		return LeaveBalance{}, err
+	}
+	for _, balance := range balances {
+		if balance.LeaveType == leaveType {
+			return balance, nil
+		}
+	}
+	return LeaveBalance{}, fmt.Errorf("%w: %q leave has no balance", ErrInvalidLeaveRequest, leaveType)
+}
+
+// leaveBalances returns the balances of every leave type with an entitlement in the year of asOf,
+// accrued up to asOf, in the order annual, sick and maternity leave.
+func (s *LeaveService) leaveBalances(leaveRepo repository.LeaveRepository, profile *domain.EmployeeProfile, asOf time.Time) ([]LeaveBalance, error) {
+	year := asOf.Year()
+	entitlements, err := leaveRepo.GetLeaveEntitlementsByUserIDAndYear(profile.UserID, year)
+	if err != nil {
+		return nil, err
+	}
+	leaves, err := leaveRepo.GetLeaveRequestsByUserIDAndPeriod(profile.UserID,
+		time.Date(year, time.January, 1, 0, 0, 0, 0, asOf.Location()),
+		time.Date(year, time.December, 31, 0, 0, 0, 0, asOf.Location()))
+	if err != nil {
+		return nil, err
+	}
+
+	var balances []LeaveBalance
+	for _, leaveType := range []string{domain.LeaveTypeAnnual, domain.LeaveTypeSick, domain.LeaveTypeMaternity} {
+		if !s.policy.HasBalance(leaveType) {
+			continue
+		}
+		balance := LeaveBalance{
+			LeaveType:   leaveType,
+			Year:        year,
+			Entitlement: s.policy.Entitlements[leaveType],
+			Used:        decimal.Zero,
+			Pending:     decimal.Zero,
+		}
+		for _, entitlement := range entitlements {
+			if entitlement.LeaveType == leaveType {
+				balance.Entitlement = entitlement.Days
+			}
+		}
+		for _, leave := range leaves {
+			if leave.LeaveType != leaveType {
+				continue
+			}
+			switch leave.Status {
+			case domain.LeaveStatusApproved:
+				balance.Used = balance.Used.Add(decimal.NewFromInt(int64(leave.Days)))
+			case domain.LeaveStatusPending:
+				balance.Pending = balance.Pending.Add(decimal.NewFromInt(int64(leave.Days)))
+			}
+		}
+		balance.Accrued = s.policy.Accrued(leaveType, balance.Entitlement, profile.HireDate, asOf)
+		balance.Available = balance.Accrued.Sub(balance.Used).Sub(balance.Pending)
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
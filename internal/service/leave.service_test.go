@@ -0,0 +1,684 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+	"payroll-system/internal/service"
+	mockrepo "payroll-system/tests/mocks/repository"
+)
+
+// leaveMocks holds the mocks the leave service reads through outside of a transaction.
+type leaveMocks struct {
+	leaveRepo           *mockrepo.MockLeaveRepository
+	employeeProfileRepo *mockrepo.MockEmployeeProfileRepository
+	holidayRepo         *mockrepo.MockHolidayRepository
+	workScheduleRepo    *mockrepo.MockWorkScheduleRepository
+}
+
+// newLeaveService returns a LeaveService on mocks, checking processed payroll periods in the transactions of uow.
+func newLeaveService(ctrl *gomock.Controller, uow repository.UnitOfWork) (*service.LeaveService, *leaveMocks) {
+	m := &leaveMocks{
+		leaveRepo:           mockrepo.NewMockLeaveRepository(ctrl),
+		employeeProfileRepo: mockrepo.NewMockEmployeeProfileRepository(ctrl),
+		holidayRepo:         mockrepo.NewMockHolidayRepository(ctrl),
+		workScheduleRepo:    mockrepo.NewMockWorkScheduleRepository(ctrl),
+	}
+	svc := service.NewLeaveService(m.leaveRepo, m.employeeProfileRepo, m.holidayRepo, m.workScheduleRepo, service.NewPeriodLock(uow), uow, service.DefaultLeavePolicy())
+	return svc, m
+}
+
+func TestLeaveService_SubmitLeaveRequest(t *testing.T) {
+	userID := uuid.New()
+	hireDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	profile := &domain.EmployeeProfile{UserID: userID, HireDate: &hireDate}
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) } // March 10th is a Monday
+
+	// expectWorkingDays expects the calendar of the requested range to be loaded, without holidays
+	// and on the default schedule.
+	expectWorkingDays := func(m *leaveMocks, start, end time.Time) {
+		m.holidayRepo.EXPECT().GetHolidaysBetween(start, end).Return(nil, nil)
+		m.workScheduleRepo.EXPECT().GetWorkScheduleByUserID(userID).Return(nil, nil)
+	}
+	// expectBalance expects the entitlements and leave requests of 2025 to be loaded.
+	expectBalance := func(m *leaveMocks, entitlements []domain.LeaveEntitlement, leaves []domain.LeaveRequest) {
+		m.leaveRepo.EXPECT().GetLeaveEntitlementsByUserIDAndYear(userID, 2025).Return(entitlements, nil)
+		m.leaveRepo.EXPECT().GetLeaveRequestsByUserIDAndPeriod(userID,
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)).Return(leaves, nil)
+	}
+	expectCreate := func(t *testing.T, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories, days int) {
+		expectTransaction(uow, txRepos)
+		tx.leaveRepo.EXPECT().CreateLeaveRequest(gomock.Any()).DoAndReturn(func(l *domain.LeaveRequest) error {
+			assert.Equal(t, days, l.Days)
+			assert.Equal(t, domain.LeaveStatusPending, l.Status)
+			return nil
+		})
+		tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+			assert.Equal(t, "CREATE", log.Action)
+			assert.Equal(t, "LeaveRequest", log.EntityName)
+			return nil
+		})
+	}
+
+	tests := []struct {
+		name       string
+		leaveType  string
+		start, end time.Time
+		processed  *domain.PayrollPeriod
+		setupMocks func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories)
+		expectErr  error
+		errMessage string
+		expectDays int
+	}{
+		{
+			name:      "annual leave within the accrued balance",
+			leaveType: domain.LeaveTypeAnnual,
+			start:     day(10), end: day(11),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				m.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(profile, nil)
+				expectWorkingDays(m, day(10), day(11))
+				m.leaveRepo.EXPECT().GetOverlappingLeaveRequests(userID, day(10), day(11)).Return(nil, nil)
+				expectBalance(m, nil, nil)
+				expectCreate(t, tx, uow, txRepos, 2)
+			},
+			expectDays: 2,
+		},
+		{
+			name:      "weekend days are not taken",
+			leaveType: domain.LeaveTypeSick,
+			start:     day(14), end: day(17),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				m.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(profile, nil)
+				expectWorkingDays(m, day(14), day(17))
+				m.leaveRepo.EXPECT().GetOverlappingLeaveRequests(userID, day(14), day(17)).Return(nil, nil)
+				expectBalance(m, nil, nil)
+				expectCreate(t, tx, uow, txRepos, 2)
+			},
+			expectDays: 2,
+		},
+		{
+			name:      "unpaid leave has no balance",
+			leaveType: domain.LeaveTypeUnpaid,
+			start:     day(10), end: day(21),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				m.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(profile, nil)
+				expectWorkingDays(m, day(10), day(21))
+				m.leaveRepo.EXPECT().GetOverlappingLeaveRequests(userID, day(10), day(21)).Return(nil, nil)
+				expectCreate(t, tx, uow, txRepos, 10)
+			},
+			expectDays: 10,
+		},
+		{
+			name:      "carried over entitlement",
+			leaveType: domain.LeaveTypeAnnual,
+			start:     day(10), end: day(14),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				m.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(profile, nil)
+				expectWorkingDays(m, day(10), day(14))
+				m.leaveRepo.EXPECT().GetOverlappingLeaveRequests(userID, day(10), day(14)).Return(nil, nil)
+				// 24 days a year accrue 6 days by March
+				expectBalance(m, []domain.LeaveEntitlement{{LeaveType: domain.LeaveTypeAnnual, Days: decimal.NewFromInt(24)}}, nil)
+				expectCreate(t, tx, uow, txRepos, 5)
+			},
+			expectDays: 5,
+		},
+		{
+			name:      "more days than accrued less used and pending",
+			leaveType: domain.LeaveTypeAnnual,
+			start:     day(10), end: day(11),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				m.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(profile, nil)
+				expectWorkingDays(m, day(10), day(11))
+				m.leaveRepo.EXPECT().GetOverlappingLeaveRequests(userID, day(10), day(11)).Return(nil, nil)
+				expectBalance(m, nil, []domain.LeaveRequest{
+					{LeaveType: domain.LeaveTypeAnnual, Status: domain.LeaveStatusApproved, Days: 1},
+					{LeaveType: domain.LeaveTypeAnnual, Status: domain.LeaveStatusPending, Days: 1},
+					{LeaveType: domain.LeaveTypeAnnual, Status: domain.LeaveStatusRejected, Days: 3},
+				})
+			},
+			expectErr:  service.ErrInsufficientLeaveBalance,
+			errMessage: "insufficient leave balance: 2 days requested, 1 annual leave days available",
+		},
+		{
+			name:      "overlaps another request",
+			leaveType: domain.LeaveTypeSick,
+			start:     day(10), end: day(11),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				m.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(profile, nil)
+				expectWorkingDays(m, day(10), day(11))
+				m.leaveRepo.EXPECT().GetOverlappingLeaveRequests(userID, day(10), day(11)).Return([]domain.LeaveRequest{{Status: domain.LeaveStatusPending}}, nil)
+			},
+			expectErr: service.ErrLeaveOverlap,
+		},
+		{
+			name:      "no working days",
+			leaveType: domain.LeaveTypeAnnual,
+			start:     day(15), end: day(16),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				m.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(profile, nil)
+				expectWorkingDays(m, day(15), day(16))
+			},
+			expectErr:  service.ErrInvalidLeaveRequest,
+			errMessage: "invalid leave request: there are no working days from 2025-03-15 through 2025-03-16",
+		},
+		{
+			name:      "before the hire date",
+			leaveType: domain.LeaveTypeAnnual,
+			start:     time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC), end: time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				m.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(profile, nil)
+			},
+			expectErr:  service.ErrInvalidLeaveRequest,
+			errMessage: "invalid leave request: leave must be within the employment",
+		},
+		{
+			name:      "ends in a processed payroll period",
+			leaveType: domain.LeaveTypeAnnual,
+			start:     time.Date(2025, 8, 29, 0, 0, 0, 0, time.UTC), end: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+			processed: august2025,
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				start, end := time.Date(2025, 8, 29, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+				m.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(profile, nil)
+				expectWorkingDays(m, start, end)
+				m.leaveRepo.EXPECT().GetOverlappingLeaveRequests(userID, start, end).Return(nil, nil)
+				expectBalance(m, nil, nil)
+				expectTransaction(uow, txRepos)
+			},
+			expectErr:  service.ErrPeriodLocked,
+			errMessage: "payroll period is locked: 2025-08-29 falls in the payroll period 2025-08-01 to 2025-08-31, which has already been processed",
+		},
+		{
+			name:      "employee not found",
+			leaveType: domain.LeaveTypeAnnual,
+			start:     day(10), end: day(11),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				m.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(nil, nil)
+			},
+			expectErr: service.ErrEmployeeNotFound,
+		},
+		{
+			name:      "unknown leave type",
+			leaveType: "sabbatical",
+			start:     day(10), end: day(11),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+			},
+			expectErr:  service.ErrInvalidLeaveRequest,
+			errMessage: "invalid leave request: leave type must be annual, sick, maternity or unpaid",
+		},
+		{
+			name:      "end before start",
+			leaveType: domain.LeaveTypeAnnual,
+			start:     day(11), end: day(10),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+			},
+			expectErr:  service.ErrInvalidLeaveRequest,
+			errMessage: "invalid leave request: end date cannot be before the start date",
+		},
+		{
+			name:      "spans two years",
+			leaveType: domain.LeaveTypeAnnual,
+			start:     time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC), end: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+			setupMocks: func(t *testing.T, m *leaveMocks, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+			},
+			expectErr:  service.ErrInvalidLeaveRequest,
+			errMessage: "invalid leave request: leave cannot span two years, submit one request per year",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			expectPeriodLock(tx.payrollPeriodRepo, tt.processed)
+			svc, m := newLeaveService(ctrl, uow)
+			tt.setupMocks(t, m, tx, uow, txRepos)
+
+			leave, err := svc.SubmitLeaveRequest(userID, tt.leaveType, tt.start, tt.end, " family trip ", "127.0.0.1", "req-123")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				if tt.errMessage != "" {
+					assert.EqualError(t, err, tt.errMessage)
+				}
+				assert.Nil(t, leave)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectDays, leave.Days)
+			assert.Equal(t, "family trip", leave.Reason)
+		})
+	}
+}
+
+func TestLeaveService_GetPendingLeaveRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, m := newLeaveService(ctrl, mockrepo.NewMockUnitOfWork(ctrl))
+	admin := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "admin"}
+	manager := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "employee"}
+
+	m.leaveRepo.EXPECT().GetPendingLeaveRequests().Return([]domain.LeaveRequest{{}, {}}, nil)
+	m.leaveRepo.EXPECT().GetPendingLeaveRequestsByManagerID(manager.ID).Return([]domain.LeaveRequest{{}}, nil)
+
+	leaves, err := svc.GetPendingLeaveRequests(admin)
+	require.NoError(t, err)
+	assert.Len(t, leaves, 2)
+
+	leaves, err = svc.GetPendingLeaveRequests(manager)
+	require.NoError(t, err)
+	assert.Len(t, leaves, 1)
+}
+
+func TestLeaveService_ReviewLeaveRequest(t *testing.T) {
+	employeeID := uuid.New()
+	admin := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "admin"}
+	manager := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "employee"}
+	colleague := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "employee"}
+	profile := &domain.EmployeeProfile{UserID: employeeID, ManagerID: &manager.ID}
+
+	pending := func() *domain.LeaveRequest {
+		return &domain.LeaveRequest{
+			BaseModel: domain.BaseModel{ID: uuid.New()},
+			UserID:    employeeID,
+			LeaveType: domain.LeaveTypeAnnual,
+			StartDate: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC),
+			Days:      2,
+			Status:    domain.LeaveStatusPending,
+		}
+	}
+	expectUpdate := func(t *testing.T, tx *txMocks, status, action string) {
+		tx.leaveRepo.EXPECT().UpdateLeaveRequest(gomock.Any()).DoAndReturn(func(l *domain.LeaveRequest) error {
+			assert.Equal(t, status, l.Status)
+			assert.NotNil(t, l.ReviewedAt)
+			return nil
+		})
+		tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+			assert.Equal(t, action, log.Action)
+			assert.Equal(t, "LeaveRequest", log.EntityName)
+			return nil
+		})
+	}
+
+	tests := []struct {
+		name       string
+		leave      *domain.LeaveRequest
+		approve    bool
+		reviewer   *domain.User
+		processed  *domain.PayrollPeriod
+		setupMocks func(t *testing.T, tx *txMocks, leave *domain.LeaveRequest)
+		expectErr  error
+	}{
+		{
+			name:     "manager approves",
+			leave:    pending(),
+			approve:  true,
+			reviewer: manager,
+			setupMocks: func(t *testing.T, tx *txMocks, leave *domain.LeaveRequest) {
+				tx.leaveRepo.EXPECT().GetLeaveRequestByID(leave.ID).Return(leave, nil)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(employeeID).Return(profile, nil)
+				tx.leaveRepo.EXPECT().GetLeaveEntitlementsByUserIDAndYear(employeeID, 2025).Return(nil, nil)
+				tx.leaveRepo.EXPECT().GetLeaveRequestsByUserIDAndPeriod(employeeID, gomock.Any(), gomock.Any()).Return([]domain.LeaveRequest{*leave}, nil)
+				expectUpdate(t, tx, domain.LeaveStatusApproved, "APPROVE")
+			},
+		},
+		{
+			name:     "admin rejects",
+			leave:    pending(),
+			reviewer: admin,
+			setupMocks: func(t *testing.T, tx *txMocks, leave *domain.LeaveRequest) {
+				tx.leaveRepo.EXPECT().GetLeaveRequestByID(leave.ID).Return(leave, nil)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(employeeID).Return(profile, nil)
+				expectUpdate(t, tx, domain.LeaveStatusRejected, "REJECT")
+			},
+		},
+		{
+			name:     "approval over the accrued balance",
+			leave:    pending(),
+			approve:  true,
+			reviewer: manager,
+			setupMocks: func(t *testing.T, tx *txMocks, leave *domain.LeaveRequest) {
+				tx.leaveRepo.EXPECT().GetLeaveRequestByID(leave.ID).Return(leave, nil)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(employeeID).Return(profile, nil)
+				tx.leaveRepo.EXPECT().GetLeaveEntitlementsByUserIDAndYear(employeeID, 2025).Return(nil, nil)
+				// Two days were approved since the request was submitted, of the three accrued by March
+				tx.leaveRepo.EXPECT().GetLeaveRequestsByUserIDAndPeriod(employeeID, gomock.Any(), gomock.Any()).Return([]domain.LeaveRequest{
+					*leave, {LeaveType: domain.LeaveTypeAnnual, Status: domain.LeaveStatusApproved, Days: 2},
+				}, nil)
+			},
+			expectErr: service.ErrInsufficientLeaveBalance,
+		},
+		{
+			name:     "colleague cannot review",
+			leave:    pending(),
+			approve:  true,
+			reviewer: colleague,
+			setupMocks: func(t *testing.T, tx *txMocks, leave *domain.LeaveRequest) {
+				tx.leaveRepo.EXPECT().GetLeaveRequestByID(leave.ID).Return(leave, nil)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(employeeID).Return(profile, nil)
+			},
+			expectErr: service.ErrLeaveReviewForbidden,
+		},
+		{
+			name: "admin cannot review their own request",
+			leave: func() *domain.LeaveRequest {
+				l := pending()
+				l.UserID = admin.ID
+				return l
+			}(),
+			approve:  true,
+			reviewer: admin,
+			setupMocks: func(t *testing.T, tx *txMocks, leave *domain.LeaveRequest) {
+				tx.leaveRepo.EXPECT().GetLeaveRequestByID(leave.ID).Return(leave, nil)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(admin.ID).Return(&domain.EmployeeProfile{UserID: admin.ID}, nil)
+			},
+			expectErr: service.ErrLeaveReviewForbidden,
+		},
+		{
+			name: "already reviewed",
+			leave: func() *domain.LeaveRequest {
+				l := pending()
+				l.Status = domain.LeaveStatusRejected
+				return l
+			}(),
+			approve:  true,
+			reviewer: manager,
+			setupMocks: func(t *testing.T, tx *txMocks, leave *domain.LeaveRequest) {
+				tx.leaveRepo.EXPECT().GetLeaveRequestByID(leave.ID).Return(leave, nil)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(employeeID).Return(profile, nil)
+			},
+			expectErr: service.ErrLeaveRequestReviewed,
+		},
+		{
+			name: "processed payroll period",
+			leave: func() *domain.LeaveRequest {
+				l := pending()
+				l.StartDate = time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+				l.EndDate = time.Date(2025, 8, 19, 0, 0, 0, 0, time.UTC)
+				return l
+			}(),
+			approve:   true,
+			reviewer:  manager,
+			processed: august2025,
+			setupMocks: func(t *testing.T, tx *txMocks, leave *domain.LeaveRequest) {
+				tx.leaveRepo.EXPECT().GetLeaveRequestByID(leave.ID).Return(leave, nil)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(employeeID).Return(profile, nil)
+			},
+			expectErr: service.ErrPeriodLocked,
+		},
+		{
+			// Rejecting changes no pay and releases the pending days
+			name: "rejection in a processed payroll period",
+			leave: func() *domain.LeaveRequest {
+				l := pending()
+				l.StartDate = time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+				l.EndDate = time.Date(2025, 8, 19, 0, 0, 0, 0, time.UTC)
+				return l
+			}(),
+			reviewer:  admin,
+			processed: august2025,
+			setupMocks: func(t *testing.T, tx *txMocks, leave *domain.LeaveRequest) {
+				tx.leaveRepo.EXPECT().GetLeaveRequestByID(leave.ID).Return(leave, nil)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(employeeID).Return(profile, nil)
+				expectUpdate(t, tx, domain.LeaveStatusRejected, "REJECT")
+			},
+		},
+		{
+			name:     "not found",
+			leave:    pending(),
+			reviewer: admin,
+			setupMocks: func(t *testing.T, tx *txMocks, leave *domain.LeaveRequest) {
+				tx.leaveRepo.EXPECT().GetLeaveRequestByID(leave.ID).Return(nil, nil)
+			},
+			expectErr: service.ErrLeaveRequestNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			expectTransaction(uow, txRepos)
+			tt.setupMocks(t, tx, tt.leave)
+
+			expectPeriodLock(tx.payrollPeriodRepo, tt.processed)
+			svc, _ := newLeaveService(ctrl, uow)
+			leave, err := svc.ReviewLeaveRequest(tt.leave.ID, tt.approve, " ok ", tt.reviewer, "127.0.0.1", "req-123")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, leave)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.reviewer.ID, *leave.ReviewedBy)
+			assert.Equal(t, "ok", leave.ReviewNote)
+		})
+	}
+}
+
+func TestLeaveService_GetLeaveBalances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	hireDate := time.Date(2025, 4, 14, 0, 0, 0, 0, time.UTC)
+	svc, m := newLeaveService(ctrl, mockrepo.NewMockUnitOfWork(ctrl))
+
+	m.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID, HireDate: &hireDate}, nil)
+	m.leaveRepo.EXPECT().GetLeaveEntitlementsByUserIDAndYear(userID, 2025).Return([]domain.LeaveEntitlement{
+		{LeaveType: domain.LeaveTypeSick, Days: decimal.NewFromInt(6)},
+	}, nil)
+	m.leaveRepo.EXPECT().GetLeaveRequestsByUserIDAndPeriod(userID,
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)).Return([]domain.LeaveRequest{
+		{LeaveType: domain.LeaveTypeAnnual, Status: domain.LeaveStatusApproved, Days: 3},
+		{LeaveType: domain.LeaveTypeAnnual, Status: domain.LeaveStatusRejected, Days: 2},
+		{LeaveType: domain.LeaveTypeSick, Status: domain.LeaveStatusPending, Days: 1},
+		{LeaveType: domain.LeaveTypeUnpaid, Status: domain.LeaveStatusApproved, Days: 5},
+	}, nil)
+
+	// A past year is accrued in full: the nine months from April to December of annual leave
+	balances, err := svc.GetLeaveBalances(userID, 2025, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, balances, 3)
+
+	annual := balances[0]
+	assert.Equal(t, domain.LeaveTypeAnnual, annual.LeaveType)
+	assert.Equal(t, "12", annual.Entitlement.String())
+	assert.Equal(t, "9", annual.Accrued.String())
+	assert.Equal(t, "3", annual.Used.String())
+	assert.Equal(t, "6", annual.Available.String())
+
+	sick := balances[1]
+	assert.Equal(t, "6", sick.Entitlement.String())
+	assert.Equal(t, "1", sick.Pending.String())
+	assert.Equal(t, "5", sick.Available.String())
+
+	assert.Equal(t, domain.LeaveTypeMaternity, balances[2].LeaveType)
+	assert.Equal(t, "90", balances[2].Available.String())
+}
+
+func TestLeaveService_SetLeaveEntitlement(t *testing.T) {
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name       string
+		leaveType  string
+		days       decimal.Decimal
+		setupMocks func(t *testing.T, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories)
+		expectErr  error
+	}{
+		{
+			name:      "new entitlement",
+			leaveType: domain.LeaveTypeAnnual,
+			days:      decimal.NewFromInt(15),
+			setupMocks: func(t *testing.T, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID}, nil)
+				tx.userRepo.EXPECT().GetUserByID(userID).Return(&domain.User{BaseModel: domain.BaseModel{ID: userID}}, nil)
+				tx.leaveRepo.EXPECT().GetLeaveEntitlement(userID, 2025, domain.LeaveTypeAnnual).Return(nil, nil)
+				tx.leaveRepo.EXPECT().SaveLeaveEntitlement(gomock.Any()).DoAndReturn(func(e *domain.LeaveEntitlement) error {
+					assert.Equal(t, "15", e.Days.String())
+					assert.Equal(t, adminID, e.CreatedBy)
+					return nil
+				})
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, "CREATE", log.Action)
+					assert.Equal(t, "LeaveEntitlement", log.EntityName)
+					return nil
+				})
+			},
+		},
+		{
+			name:      "existing entitlement",
+			leaveType: domain.LeaveTypeSick,
+			days:      decimal.NewFromInt(6),
+			setupMocks: func(t *testing.T, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				existing := &domain.LeaveEntitlement{BaseModel: domain.BaseModel{ID: uuid.New()}, UserID: userID, Year: 2025, LeaveType: domain.LeaveTypeSick, Days: decimal.NewFromInt(12)}
+				expectTransaction(uow, txRepos)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID}, nil)
+				tx.userRepo.EXPECT().GetUserByID(userID).Return(&domain.User{BaseModel: domain.BaseModel{ID: userID}}, nil)
+				tx.leaveRepo.EXPECT().GetLeaveEntitlement(userID, 2025, domain.LeaveTypeSick).Return(existing, nil)
+				tx.leaveRepo.EXPECT().SaveLeaveEntitlement(existing).Return(nil)
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, "UPDATE", log.Action)
+					return nil
+				})
+			},
+		},
+		{
+			name:      "employee not found",
+			leaveType: domain.LeaveTypeAnnual,
+			days:      decimal.NewFromInt(15),
+			setupMocks: func(t *testing.T, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(nil, nil)
+			},
+			expectErr: service.ErrEmployeeNotFound,
+		},
+		{
+			name:       "unpaid leave has no balance",
+			leaveType:  domain.LeaveTypeUnpaid,
+			days:       decimal.NewFromInt(5),
+			setupMocks: func(t *testing.T, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {},
+			expectErr:  service.ErrInvalidLeaveEntitlement,
+		},
+		{
+			name:       "negative days",
+			leaveType:  domain.LeaveTypeAnnual,
+			days:       decimal.NewFromInt(-1),
+			setupMocks: func(t *testing.T, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {},
+			expectErr:  service.ErrInvalidLeaveEntitlement,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			tt.setupMocks(t, tx, uow, txRepos)
+
+			svc, _ := newLeaveService(ctrl, uow)
+			entitlement, err := svc.SetLeaveEntitlement(userID, 2025, tt.leaveType, tt.days, adminID, "127.0.0.1", "req-123")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, entitlement)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.days.Equal(entitlement.Days))
+		})
+	}
+}
+
+func TestPreviewPayroll_PaysLeave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Monday 4 to Friday 15 August: 10 working days, 80 hours at 100,000 per hour
+	period := &domain.PayrollPeriod{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		StartDate: time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC),
+	}
+	userID := uuid.New()
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC) }
+
+	// Attendance from Monday 4 to Friday 8
+	var attendances []domain.Attendance
+	for d := 4; d <= 8; d++ {
+		checkIn := day(d).Add(9 * time.Hour)
+		checkOut := checkIn.Add(8 * time.Hour)
+		attendances = append(attendances, domain.Attendance{UserID: userID, Date: day(d), CheckInTime: checkIn, CheckOutTime: &checkOut})
+	}
+	leaves := []domain.LeaveRequest{
+		// Friday 8 is attended and paid once; the weekend is not a working day
+		{UserID: userID, LeaveType: domain.LeaveTypeAnnual, StartDate: day(8), EndDate: day(12), Status: domain.LeaveStatusApproved},
+		{UserID: userID, LeaveType: domain.LeaveTypeUnpaid, StartDate: day(13), EndDate: day(14), Status: domain.LeaveStatusApproved},
+	}
+
+	payrollPeriodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
+	employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+	salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+	attendanceRepo := mockrepo.NewMockAttendanceRepository(ctrl)
+	overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
+	reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+	holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+	workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+	leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
+
+	payrollPeriodRepo.EXPECT().GetPayrollPeriodByID(period.ID).Return(period, nil)
+	employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(period.StartDate, period.EndDate).Return([]domain.EmployeeProfile{
+		{UserID: userID, Salary: decimal.NewFromInt(8000000)},
+	}, nil)
+	salaryHistoryRepo.EXPECT().GetSalaryHistoriesForPeriodGroupedByUser(period.StartDate, period.EndDate).Return(nil, nil)
+	attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Attendance{userID: attendances}, nil)
+	overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(nil, nil)
+	reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(period.EndDate).Return(nil, nil)
+	holidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return(nil, nil)
+	workScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil)
+	leaveRepo.EXPECT().GetApprovedLeaveRequestsByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.LeaveRequest{userID: leaves}, nil)
+
+	registry := service.NewPayslipComponentRegistry(service.BasicSalaryCalculator{}, service.UnpaidLeaveCalculator{})
+	svc := service.NewPayrollService(
+		mockrepo.NewMockPayslipRepository(ctrl), payrollPeriodRepo, employeeProfileRepo, salaryHistoryRepo, attendanceRepo, overtimeRepo, reimbursementRepo, holidayRepo, workScheduleRepo, leaveRepo,
+		mockrepo.NewMockAuditLogRepository(ctrl), mockrepo.NewMockUnitOfWork(ctrl), service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), registry,
+	)
+
+	preview, err := svc.PreviewPayroll(period.ID)
+	require.NoError(t, err)
+	require.Len(t, preview.Payslips, 1)
+	payslip := preview.Payslips[0]
+
+	// 40 hours attended, 16 of annual leave and 16 of unpaid leave are paid; Friday 15 is not
+	require.Len(t, payslip.Items, 2)
+	assert.Equal(t, "72", payslip.Items[0].Quantity.String())
+	assert.Equal(t, "7200000", payslip.ProratedSalary.String())
+
+	// The unpaid leave is deducted again
+	assert.Equal(t, service.PayslipItemCodeUnpaidLeave, payslip.Items[1].Code)
+	assert.Equal(t, "16", payslip.Items[1].Quantity.String())
+	assert.Equal(t, "1600000", payslip.Items[1].Amount.String())
+	assert.Equal(t, "1600000", payslip.TotalDeductions.String())
+	assert.Equal(t, "5600000", payslip.TotalTakeHomePay.String())
+}
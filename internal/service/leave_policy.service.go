@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+)
+
+// LeaveAccrual defines how the yearly annual leave entitlement becomes available.
+type LeaveAccrual string
+
+const (
+	LeaveAccrualMonthly LeaveAccrual = "monthly" // a twelfth of the entitlement at the start of every month employed
+	LeaveAccrualYearly  LeaveAccrual = "yearly"  // the whole entitlement on the first day of the year
+)
+
+// LeavePolicy holds the yearly leave entitlements, in working days, and how annual leave accrues.
+// Sick and maternity leave are available in full from the start of the year; unpaid leave has no
+// entitlement.
+type LeavePolicy struct {
+	Entitlements  map[string]decimal.Decimal // Yearly days per leave type
+	AnnualAccrual LeaveAccrual
+}
+
+// DefaultLeavePolicy returns the policy used when nothing is configured: 12 days of annual leave
+// accruing monthly, 12 days of sick leave and 90 days of maternity leave a year.
+func DefaultLeavePolicy() LeavePolicy {
+	return LeavePolicy{
+		Entitlements: map[string]decimal.Decimal{
+			domain.LeaveTypeAnnual:    decimal.NewFromInt(12),
+			domain.LeaveTypeSick:      decimal.NewFromInt(12),
+			domain.LeaveTypeMaternity: decimal.NewFromInt(90),
+		},
+		AnnualAccrual: LeaveAccrualMonthly,
+	}
+}
+
+// ParseLeavePolicy builds a LeavePolicy from the variables returned by lookup, typically
+// os.Getenv. Unset variables keep the defaults of DefaultLeavePolicy.
+func ParseLeavePolicy(lookup func(key string) string) (LeavePolicy, error) {
+	policy := DefaultLeavePolicy()
+
+	fields := []struct {
+		key       string
+		leaveType string
+	}{
+		{"LEAVE_ANNUAL_DAYS", domain.LeaveTypeAnnual},
+		{"LEAVE_SICK_DAYS", domain.LeaveTypeSick},
+		{"LEAVE_MATERNITY_DAYS", domain.LeaveTypeMaternity},
+	}
+	for _, field := range fields {
+		raw := lookup(field.key)
+		if raw == "" {
+			continue
+		}
+		days, err := decimal.NewFromString(raw)
+		if err != nil || days.IsNegative() {
+			return policy, fmt.Errorf("invalid %s %q", field.key, raw)
+		}
+		policy.Entitlements[field.leaveType] = days
+	}
+
+	if raw := lookup("LEAVE_ANNUAL_ACCRUAL"); raw != "" {
+		switch LeaveAccrual(raw) {
+		case LeaveAccrualMonthly, LeaveAccrualYearly:
+			policy.AnnualAccrual = LeaveAccrual(raw)
+		default:
+			return policy, fmt.Errorf("invalid LEAVE_ANNUAL_ACCRUAL %q", raw)
+		}
+	}
+
+	return policy, nil
+}
+
+// HasBalance reports whether leave of leaveType is taken from a yearly balance.
+func (p LeavePolicy) HasBalance(leaveType string) bool {
+	_, ok := p.Entitlements[leaveType]
+	return ok
+}
+
+// Accrued returns the part of a yearly entitlement of leaveType that is available on date. With
+// monthly accrual an employee accrues a twelfth of their annual leave entitlement at the start of
+// every month of the year they are employed in, from their hire date on.
+func (p LeavePolicy) Accrued(leaveType string, entitlement decimal.Decimal, hireDate *time.Time, date time.Time) decimal.Decimal {
+	if leaveType != domain.LeaveTypeAnnual || p.AnnualAccrual != LeaveAccrualMonthly {
+		return entitlement
+	}
+
+	firstMonth := time.January
+	if hireDate != nil {
+		switch {
+		case hireDate.Year() > date.Year():
+			return decimal.Zero
+		case hireDate.Year() == date.Year():
+			firstMonth = hireDate.Month()
+		}
+	}
+	months := int64(date.Month() - firstMonth + 1)
+	if months <= 0 {
+		return decimal.Zero
+	}
+	return entitlement.Mul(decimal.NewFromInt(months)).Div(decimal.NewFromInt(12)).RoundFloor(2)
+}
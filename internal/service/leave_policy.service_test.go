@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+)
+
+func TestLeavePolicy_Accrued(t *testing.T) {
+	date := func(year int, month time.Month) time.Time { return time.Date(year, month, 15, 0, 0, 0, 0, time.UTC) }
+	hired := func(year int, month time.Month) *time.Time {
+		d := time.Date(year, month, 20, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	monthly := service.DefaultLeavePolicy()
+	yearly := service.DefaultLeavePolicy()
+	yearly.AnnualAccrual = service.LeaveAccrualYearly
+	twelve := decimal.NewFromInt(12)
+
+	tests := []struct {
+		name      string
+		policy    service.LeavePolicy
+		leaveType string
+		hireDate  *time.Time
+		date      time.Time
+		expected  string
+	}{
+		{"monthly accrual in january", monthly, domain.LeaveTypeAnnual, nil, date(2025, time.January), "1"},
+		{"monthly accrual in june", monthly, domain.LeaveTypeAnnual, nil, date(2025, time.June), "6"},
+		{"hired in an earlier year", monthly, domain.LeaveTypeAnnual, hired(2020, time.May), date(2025, time.March), "3"},
+		{"hired this year", monthly, domain.LeaveTypeAnnual, hired(2025, time.April), date(2025, time.June), "3"},
+		{"hired later this year", monthly, domain.LeaveTypeAnnual, hired(2025, time.August), date(2025, time.June), "0"},
+		{"hired next year", monthly, domain.LeaveTypeAnnual, hired(2026, time.January), date(2025, time.December), "0"},
+		{"yearly accrual", yearly, domain.LeaveTypeAnnual, nil, date(2025, time.January), "12"},
+		{"sick leave is available in full", monthly, domain.LeaveTypeSick, nil, date(2025, time.January), "12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accrued := tt.policy.Accrued(tt.leaveType, twelve, tt.hireDate, tt.date)
+			assert.Equal(t, tt.expected, accrued.String())
+		})
+	}
+
+	// Accrual is rounded down to two decimals
+	assert.Equal(t, "1.25", monthly.Accrued(domain.LeaveTypeAnnual, decimal.NewFromInt(15), nil, date(2025, time.January)).String())
+}
+
+func TestParseLeavePolicy(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	policy, err := service.ParseLeavePolicy(env(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, service.DefaultLeavePolicy(), policy)
+	assert.True(t, policy.HasBalance(domain.LeaveTypeAnnual))
+	assert.False(t, policy.HasBalance(domain.LeaveTypeUnpaid))
+
+	policy, err = service.ParseLeavePolicy(env(map[string]string{
+		"LEAVE_ANNUAL_DAYS":    "14.5",
+		"LEAVE_SICK_DAYS":      "6",
+		"LEAVE_ANNUAL_ACCRUAL": "yearly",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "14.5", policy.Entitlements[domain.LeaveTypeAnnual].String())
+	assert.Equal(t, "6", policy.Entitlements[domain.LeaveTypeSick].String())
+	assert.Equal(t, "90", policy.Entitlements[domain.LeaveTypeMaternity].String())
+	assert.Equal(t, service.LeaveAccrualYearly, policy.AnnualAccrual)
+
+	_, err = service.ParseLeavePolicy(env(map[string]string{"LEAVE_SICK_DAYS": "-1"}))
+	assert.Error(t, err)
+
+	_, err = service.ParseLeavePolicy(env(map[string]string{"LEAVE_MATERNITY_DAYS": "ninety"}))
+	assert.Error(t, err)
+
+	_, err = service.ParseLeavePolicy(env(map[string]string{"LEAVE_ANNUAL_ACCRUAL": "weekly"}))
+	assert.Error(t, err)
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,76 +14,183 @@ import (
 
 const MaxOvertimeHoursPerDay = 3.0
 
+var (
+	// ErrOvertimeNotFound is returned when an overtime record does not exist.
+	ErrOvertimeNotFound = errors.New("overtime not found")
+	// ErrOvertimeReviewed is returned when reviewing overtime that is no longer pending.
+	ErrOvertimeReviewed = errors.New("overtime has already been reviewed")
+	// ErrOvertimeReviewForbidden is returned when a user other than the employee's manager or an admin reviews overtime.
+	ErrOvertimeReviewForbidden = errors.New("only the employee's manager or an admin can review this overtime")
+)
+
 // OvertimeServiceInterface defines the methods of OvertimeService for mocking purposes.
 //
 //go:generate mockgen -source=overtime.service.go -destination=../../tests/mocks/service/mock_overtime_service.go -package=mocks
 type OvertimeServiceInterface interface {
 	// SubmitOvertime allows an employee to submit their overtime hours.
 	SubmitOvertime(userID uuid.UUID, date time.Time, hours float64, ipAddress, requestID string) (*domain.Overtime, error)
+	// GetPendingOvertimes returns the pending overtime a user can review.
+	GetPendingOvertimes(reviewer *domain.User) ([]domain.Overtime, error)
+	// GetOvertimeHistory returns a page of an employee's overtime and how many records match the filter.
+	GetOvertimeHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Overtime, int64, error)
+	// ReviewOvertime approves or rejects pending overtime.
+	ReviewOvertime(id uuid.UUID, approve bool, note string, reviewer *domain.User, ipAddress, requestID string) (*domain.Overtime, error)
 }
 
 // OvertimeService provides business logic for overtime management.
 type OvertimeService struct {
 	overtimeRepo repository.OvertimeRepository
-	auditRepo    repository.AuditLogRepository
+	periodLock   *PeriodLock
+	uow          repository.UnitOfWork // For transaction management
 }
 
 // NewOvertimeService creates a new OvertimeService.
-func NewOvertimeService(overtimeRepo repository.OvertimeRepository, auditRepo repository.AuditLogRepository) *OvertimeService {
+func NewOvertimeService(overtimeRepo repository.OvertimeRepository, periodLock *PeriodLock, uow repository.UnitOfWork) *OvertimeService {
 	return &OvertimeService{
 		overtimeRepo: overtimeRepo,
-		auditRepo:    auditRepo,
+		periodLock:   periodLock,
+		uow:          uow,
 	}
 }
 
-// SubmitOvertime allows an employee to submit their overtime hours.
+// SubmitOvertime allows an employee to submit their overtime hours. The overtime is pending until
+// the employee's manager or an admin approves it; only approved overtime is paid. Overtime cannot be
+// submitted for a day in a processed payroll period. The overtime and its audit log entry are written
+// in one transaction.
 func (s *OvertimeService) SubmitOvertime(userID uuid.UUID, date time.Time, hours float64, ipAddress string, requestID string) (*domain.Overtime, error) {
-	// Rule: Overtime cannot be more than MaxOvertimeHoursPerDay per day.
-	existingOvertimes, err := s.overtimeRepo.GetOvertimeByUserIDAndDate(userID, date)
+	var newOvertime *domain.Overtime
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := s.periodLock.Check(repos, date); err != nil {
+			return err
+		}
+
+		// Rule: Overtime cannot be more than MaxOvertimeHoursPerDay per day.
+		existingOvertimes, err := repos.Overtimes.GetOvertimeByUserIDAndDate(userID, date)
+		if err != nil {
+			return err
+		}
+
+		totalHoursToday := 0.0
+		for _, ot := range existingOvertimes {
+			if ot.Status != domain.OvertimeStatusRejected {
+				totalHoursToday += ot.Hours
+			}
+		}
+
+		if totalHoursToday+hours > MaxOvertimeHoursPerDay {
+			return fmt.Errorf("total overtime hours for %s cannot exceed %.1f hours", date.Format("2006-01-02"), MaxOvertimeHoursPerDay)
+		}
+
+		newOvertime = &domain.Overtime{
+			UserID: userID,
+			Date:   date,
+			Hours:  hours,
+			Status: domain.OvertimeStatusPending,
+			BaseModel: domain.BaseModel{
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+				CreatedBy: userID,
+				UpdatedBy: userID,
+				IPAddress: ipAddress,
+			},
+		}
+
+		o, err := repos.Overtimes.CreateOvertime(newOvertime)
+		if err != nil {
+			return err
+		}
+
+		// Audit log for overtime submission
+		if err := repository.CreateAuditLog(repos.AuditLogs, &userID, "CREATE", "Overtime", &o.ID, nil, newOvertime, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for overtime: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	totalHoursToday := 0.0
-	for _, ot := range existingOvertimes {
-		totalHoursToday += ot.Hours
-	}
+	return newOvertime, nil
+}
 
-	if totalHoursToday+hours > MaxOvertimeHoursPerDay {
-		return nil, fmt.Errorf("total overtime hours for %s cannot exceed %.1f hours", date.Format("2006-01-02"), MaxOvertimeHoursPerDay)
+// GetPendingOvertimes returns the pending overtime reviewer can review: every pending overtime for
+// an admin, the overtime of the employees who report to them for anyone else.
+func (s *OvertimeService) GetPendingOvertimes(reviewer *domain.User) ([]domain.Overtime, error) {
+	if reviewer.Role == "admin" {
+		return s.overtimeRepo.GetPendingOvertimes()
 	}
+	return s.overtimeRepo.GetPendingOvertimesByManagerID(reviewer.ID)
+}
 
-	newOvertime := &domain.Overtime{
-		UserID: userID,
-		Date:   date,
-		Hours:  hours,
-		BaseModel: domain.BaseModel{
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-			CreatedBy: userID,
-			UpdatedBy: userID,
-			IPAddress: ipAddress,
-		},
-	}
+// GetOvertimeHistory returns a page of an employee's overtime records, latest first, and how many
+// records match the filter.
+func (s *OvertimeService) GetOvertimeHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Overtime, int64, error) {
+	return s.overtimeRepo.GetOvertimeHistory(userID, filter)
+}
+
+// ReviewOvertime approves or rejects pending overtime. Only an admin or the manager the employee
+// reports to can review it, and nobody can review their own overtime. Overtime in a processed
+// payroll period can no longer be reviewed, as its payroll has been run. The review and its audit
+// log entry are written in one transaction.
+func (s *OvertimeService) ReviewOvertime(
+	id uuid.UUID,
+	approve bool,
+	note string,
+	reviewer *domain.User,
+	ipAddress, requestID string,
+) (*domain.Overtime, error) {
+	var overtime *domain.Overtime
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		var err error
+		overtime, err = repos.Overtimes.GetOvertimeByID(id)
+		if err != nil {
+			return err
+		}
+		if overtime == nil {
+			return ErrOvertimeNotFound
+		}
 
-	o, err := s.overtimeRepo.CreateOvertime(newOvertime)
+		profile, err := repos.EmployeeProfiles.GetEmployeeProfileByUserID(overtime.UserID)
+		if err != nil {
+			return err
+		}
+		if !canReview(reviewer, overtime.UserID, profile) {
+			return ErrOvertimeReviewForbidden
+		}
+		if overtime.Status != domain.OvertimeStatusPending {
+			return ErrOvertimeReviewed
+		}
+		if err := s.periodLock.Check(repos, overtime.Date); err != nil {
+			return err
+		}
 
+		oldOvertime := *overtime
+		now := time.Now()
+		action := "REJECT"
+		overtime.Status = domain.OvertimeStatusRejected
+		if approve {
+			action = "APPROVE"
+			overtime.Status = domain.OvertimeStatusApproved
+		}
+		overtime.ReviewedBy = &reviewer.ID
+		overtime.ReviewedAt = &now
+		overtime.ReviewNote = strings.TrimSpace(note)
+		overtime.UpdatedAt = now
+		overtime.UpdatedBy = reviewer.ID
+		overtime.IPAddress = ipAddress
+
+		if err := repos.Overtimes.UpdateOvertime(overtime); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &reviewer.ID, action, "Overtime", &overtime.ID, oldOvertime, overtime, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for overtime: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Audit log for overtime submission
-	_ = repository.CreateAuditLog(
-		s.auditRepo,
-		&userID,
-		"CREATE",
-		"Overtime",
-		&o.ID,
-		nil,
-		newOvertime,
-		ipAddress,
-		requestID,
-	)
-
-	return newOvertime, nil
+	return overtime, nil
 }
@@ -8,9 +8,11 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
 	"payroll-system/internal/service"
 	mockRepo "payroll-system/tests/mocks/repository"
 )
@@ -25,6 +27,7 @@ func TestOvertimeService_SubmitOvertime(t *testing.T) {
 		name             string
 		hours            float64
 		mockExisting     []domain.Overtime
+		mockProcessed    *domain.PayrollPeriod
 		mockGetError     error
 		mockCreateError  error
 		expectedError    string
@@ -44,6 +47,18 @@ func TestOvertimeService_SubmitOvertime(t *testing.T) {
 				"total overtime hours for %s cannot exceed %.1f hours",
 				date.Format("2006-01-02"), service.MaxOvertimeHoursPerDay),
 		},
+		{
+			name:             "rejected overtime does not count toward the daily max",
+			hours:            2.0,
+			mockExisting:     []domain.Overtime{{Hours: 3.0, Status: domain.OvertimeStatusRejected}},
+			expectCreateCall: true,
+		},
+		{
+			name:          "processed payroll period",
+			hours:         2.0,
+			mockProcessed: august2025,
+			expectedError: "payroll period is locked: 2025-08-18 falls in the payroll period 2025-08-01 to 2025-08-31, which has already been processed",
+		},
 		{
 			name:          "get overtime repo error",
 			hours:         2.0,
@@ -68,7 +83,8 @@ func TestOvertimeService_SubmitOvertime(t *testing.T) {
 
 			mockOvertimeRepo := mockRepo.NewMockOvertimeRepository(ctrl)
 			mockAuditRepo := mockRepo.NewMockAuditLogRepository(ctrl)
-			svc := service.NewOvertimeService(mockOvertimeRepo, mockAuditRepo)
+			uow := newUnitOfWork(ctrl, tt.mockProcessed, &repository.Repositories{Overtimes: mockOvertimeRepo, AuditLogs: mockAuditRepo})
+			svc := service.NewOvertimeService(mockOvertimeRepo, service.NewPeriodLock(uow), uow)
 
 			// Mock GetOvertimeByUserIDAndDate
 			mockOvertimeRepo.
@@ -102,7 +118,159 @@ func TestOvertimeService_SubmitOvertime(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, userID, ot.UserID)
 				assert.Equal(t, tt.hours, ot.Hours)
+				assert.Equal(t, domain.OvertimeStatusPending, ot.Status)
+			}
+		})
+	}
+}
+
+func TestOvertimeService_GetPendingOvertimes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOvertimeRepo := mockRepo.NewMockOvertimeRepository(ctrl)
+	uow := mockRepo.NewMockUnitOfWork(ctrl)
+	svc := service.NewOvertimeService(mockOvertimeRepo, service.NewPeriodLock(uow), uow)
+	admin := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "admin"}
+	manager := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "employee"}
+
+	mockOvertimeRepo.EXPECT().GetPendingOvertimes().Return([]domain.Overtime{{}, {}}, nil)
+	mockOvertimeRepo.EXPECT().GetPendingOvertimesByManagerID(manager.ID).Return([]domain.Overtime{{}}, nil)
+
+	overtimes, err := svc.GetPendingOvertimes(admin)
+	require.NoError(t, err)
+	assert.Len(t, overtimes, 2)
+
+	overtimes, err = svc.GetPendingOvertimes(manager)
+	require.NoError(t, err)
+	assert.Len(t, overtimes, 1)
+}
+
+func TestOvertimeService_ReviewOvertime(t *testing.T) {
+	employeeID := uuid.New()
+	admin := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "admin"}
+	manager := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "employee"}
+	colleague := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "employee"}
+	profile := &domain.EmployeeProfile{UserID: employeeID, ManagerID: &manager.ID}
+
+	pending := func(userID uuid.UUID, status string) *domain.Overtime {
+		return &domain.Overtime{
+			BaseModel: domain.BaseModel{ID: uuid.New()},
+			UserID:    userID,
+			Date:      time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC),
+			Hours:     2,
+			Status:    status,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		overtime     *domain.Overtime
+		found        bool
+		approve      bool
+		reviewer     *domain.User
+		processed    *domain.PayrollPeriod
+		expectStatus string
+		expectAction string
+		expectErr    error
+	}{
+		{
+			name:         "manager approves",
+			overtime:     pending(employeeID, domain.OvertimeStatusPending),
+			found:        true,
+			approve:      true,
+			reviewer:     manager,
+			expectStatus: domain.OvertimeStatusApproved,
+			expectAction: "APPROVE",
+		},
+		{
+			name:         "admin rejects",
+			overtime:     pending(employeeID, domain.OvertimeStatusPending),
+			found:        true,
+			reviewer:     admin,
+			expectStatus: domain.OvertimeStatusRejected,
+			expectAction: "REJECT",
+		},
+		{
+			name:      "colleague cannot review",
+			overtime:  pending(employeeID, domain.OvertimeStatusPending),
+			found:     true,
+			approve:   true,
+			reviewer:  colleague,
+			expectErr: service.ErrOvertimeReviewForbidden,
+		},
+		{
+			name:      "admin cannot review their own overtime",
+			overtime:  pending(admin.ID, domain.OvertimeStatusPending),
+			found:     true,
+			approve:   true,
+			reviewer:  admin,
+			expectErr: service.ErrOvertimeReviewForbidden,
+		},
+		{
+			name:      "already reviewed",
+			overtime:  pending(employeeID, domain.OvertimeStatusApproved),
+			found:     true,
+			reviewer:  manager,
+			expectErr: service.ErrOvertimeReviewed,
+		},
+		{
+			name:      "processed payroll period",
+			overtime:  pending(employeeID, domain.OvertimeStatusPending),
+			found:     true,
+			approve:   true,
+			reviewer:  manager,
+			processed: august2025,
+			expectErr: service.ErrPeriodLocked,
+		},
+		{
+			name:      "not found",
+			overtime:  pending(employeeID, domain.OvertimeStatusPending),
+			reviewer:  admin,
+			expectErr: service.ErrOvertimeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockRepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			expectTransaction(uow, txRepos)
+
+			if !tt.found {
+				tx.overtimeRepo.EXPECT().GetOvertimeByID(tt.overtime.ID).Return(nil, nil)
+			} else {
+				tx.overtimeRepo.EXPECT().GetOvertimeByID(tt.overtime.ID).Return(tt.overtime, nil)
+				tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(tt.overtime.UserID).Return(profile, nil)
+			}
+			if tt.expectErr == nil {
+				tx.overtimeRepo.EXPECT().UpdateOvertime(gomock.Any()).DoAndReturn(func(o *domain.Overtime) error {
+					assert.Equal(t, tt.expectStatus, o.Status)
+					assert.NotNil(t, o.ReviewedAt)
+					return nil
+				})
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, tt.expectAction, log.Action)
+					assert.Equal(t, "Overtime", log.EntityName)
+					return nil
+				})
+			}
+
+			expectPeriodLock(tx.payrollPeriodRepo, tt.processed)
+			svc := service.NewOvertimeService(mockRepo.NewMockOvertimeRepository(ctrl), service.NewPeriodLock(uow), uow)
+			overtime, err := svc.ReviewOvertime(tt.overtime.ID, tt.approve, " approved for the release ", tt.reviewer, "127.0.0.1", "req-123")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, overtime)
+				return
 			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.reviewer.ID, *overtime.ReviewedBy)
+			assert.Equal(t, "approved for the release", overtime.ReviewNote)
 		})
 	}
 }
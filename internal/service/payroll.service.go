@@ -3,20 +3,19 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
+	"github.com/shopspring/decimal"
 
 	"payroll-system/internal/domain"
 	"payroll-system/internal/repository"
 )
 
 const (
-	RegularWorkingHoursPerDay = 8
-	WorkingDaysPerWeek        = 5
-	WorkingDaysPerMonth       = 20 // Approximation for monthly-based pay
 	OvertimeMultiplier        = 2.0
+	RestDayOvertimeMultiplier = 3.0 // Overtime on rest days of the work schedule and holidays
 )
 
 // PayrollServiceInterface defines methods of PayrollService for mocking purposes.
@@ -25,20 +24,60 @@ const (
 type PayrollServiceInterface interface {
 	// RunPayroll processes payroll for a given payroll period.
 	RunPayroll(periodID uuid.UUID, processedBy uuid.UUID, ipAddress, requestID string) error
+	// ProcessPayroll processes payroll for a given payroll period and reports its progress.
+	ProcessPayroll(periodID uuid.UUID, processedBy uuid.UUID, ipAddress, requestID string, onProgress PayrollProgressFunc) error
+	// ReversePayroll voids the payslips of a processed payroll period and reopens it.
+	ReversePayroll(periodID uuid.UUID, reason string, reversedBy uuid.UUID, ipAddress, requestID string) error
+	// CheckPendingItems returns ErrPayrollPendingItems when records dated in a payroll period are still pending review.
+	CheckPendingItems(period *domain.PayrollPeriod) error
+	// PreviewPayroll calculates payslips for a payroll period without persisting anything.
+	PreviewPayroll(periodID uuid.UUID) (*PayrollPreview, error)
 	// CalculatePayslip calculates payslip and related records for a user.
 	CalculatePayslip(userID uuid.UUID, period *domain.PayrollPeriod, processedBy uuid.UUID, ipAddress string) (*domain.Payslip, []domain.Attendance, []domain.Overtime, []domain.Reimbursement, error)
 }
 
+// PayrollProgressFunc receives the number of employees processed so far and the total number
+// of employees in the payroll run.
+type PayrollProgressFunc func(processed, total int)
+
+// PayrollPreview holds the outcome of a payroll dry-run for a period.
+type PayrollPreview struct {
+	Period                     *domain.PayrollPeriod
+	Payslips                   []domain.Payslip
+	TotalBaseSalary            decimal.Decimal
+	TotalProratedSalary        decimal.Decimal
+	TotalOvertimePay           decimal.Decimal
+	TotalReimbursement         decimal.Decimal
+	TotalGrossEarnings         decimal.Decimal
+	TotalDeductions            decimal.Decimal
+	TotalTakeHomePay           decimal.Decimal
+	TotalEmployerContributions decimal.Decimal // Employer contributions on top of gross earnings, e.g. BPJS
+	TotalEmployerCost          decimal.Decimal // Gross earnings plus employer contributions
+}
+
+// reversedPayrollPeriod is the audited state of a payroll period after its payroll was reversed.
+type reversedPayrollPeriod struct {
+	domain.PayrollPeriod
+	ReversalReason string `json:"reversal_reason"`
+}
+
 // PayrollService provides business logic for payroll processing.
 type PayrollService struct {
 	payslipRepo         repository.PayslipRepository
 	payrollPeriodRepo   repository.PayrollPeriodRepository
 	employeeProfileRepo repository.EmployeeProfileRepository
+	salaryHistoryRepo   repository.SalaryHistoryRepository
 	attendanceRepo      repository.AttendanceRepository
 	overtimeRepo        repository.OvertimeRepository
 	reimbursementRepo   repository.ReimbursementRepository
+	holidayRepo         repository.HolidayRepository
+	workScheduleRepo    repository.WorkScheduleRepository
+	leaveRepo           repository.LeaveRepository
 	auditRepo           repository.AuditLogRepository
-	db                  *gorm.DB // For transaction management
+	uow                 repository.UnitOfWork // For transaction management
+	rounding            RoundingPolicy
+	attendance          AttendancePolicy
+	components          *PayslipComponentRegistry
 }
 
 // NewPayrollService creates a new PayrollService.
@@ -46,86 +85,155 @@ func NewPayrollService(
 	payslipRepo repository.PayslipRepository,
 	payrollPeriodRepo repository.PayrollPeriodRepository,
 	employeeProfileRepo repository.EmployeeProfileRepository,
+	salaryHistoryRepo repository.SalaryHistoryRepository,
 	attendanceRepo repository.AttendanceRepository,
 	overtimeRepo repository.OvertimeRepository,
 	reimbursementRepo repository.ReimbursementRepository,
+	holidayRepo repository.HolidayRepository,
+	workScheduleRepo repository.WorkScheduleRepository,
+	leaveRepo repository.LeaveRepository,
 	auditRepo repository.AuditLogRepository,
-	db *gorm.DB,
+	uow repository.UnitOfWork,
+	rounding RoundingPolicy,
+	attendance AttendancePolicy,
+	components *PayslipComponentRegistry,
 ) *PayrollService {
 	return &PayrollService{
 		payslipRepo:         payslipRepo,
 		payrollPeriodRepo:   payrollPeriodRepo,
 		employeeProfileRepo: employeeProfileRepo,
+		salaryHistoryRepo:   salaryHistoryRepo,
 		attendanceRepo:      attendanceRepo,
 		overtimeRepo:        overtimeRepo,
 		reimbursementRepo:   reimbursementRepo,
+		holidayRepo:         holidayRepo,
+		workScheduleRepo:    workScheduleRepo,
+		leaveRepo:           leaveRepo,
 		auditRepo:           auditRepo,
-		db:                  db,
+		uow:                 uow,
+		rounding:            rounding,
+		attendance:          attendance,
+		components:          components,
+	}
+}
+
+// repositories groups the service's repositories for reads that run outside a transaction.
+func (s *PayrollService) repositories() *repository.Repositories {
+	return &repository.Repositories{
+		PayrollPeriods:   s.payrollPeriodRepo,
+		EmployeeProfiles: s.employeeProfileRepo,
+		SalaryHistories:  s.salaryHistoryRepo,
+		Attendances:      s.attendanceRepo,
+		Overtimes:        s.overtimeRepo,
+		Reimbursements:   s.reimbursementRepo,
+		Payslips:         s.payslipRepo,
+		Holidays:         s.holidayRepo,
+		WorkSchedules:    s.workScheduleRepo,
+		Leaves:           s.leaveRepo,
+		AuditLogs:        s.auditRepo,
 	}
 }
 
+// RunPayroll processes payroll for a given payroll period.
 func (s *PayrollService) RunPayroll(periodID uuid.UUID, processedBy uuid.UUID, ipAddress string, requestID string) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		period, err := s.payrollPeriodRepo.GetPayrollPeriodByID(periodID)
+	return s.ProcessPayroll(periodID, processedBy, ipAddress, requestID, nil)
+}
+
+// ProcessPayroll processes payroll for a given payroll period. Every read and write, including the
+// audit log, goes through the same transaction; if any of them fails the whole run is rolled back.
+// A period with overtime, reimbursements or leave still pending review is not processed, see
+// CheckPendingItems.
+// The period row stays locked for the whole transaction, so a concurrent run or reversal of the
+// same period fails with ErrPayrollRunInProgress instead of creating duplicate payslips.
+// When onProgress is set it is called once the employees are loaded and again after every
+// calculated employee but the last; the last one is reported only once the transaction has
+// committed, so a run never shows every employee processed before its payslips are saved.
+func (s *PayrollService) ProcessPayroll(periodID uuid.UUID, processedBy uuid.UUID, ipAddress string, requestID string, onProgress PayrollProgressFunc) error {
+	var total int
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		period, err := lockPayrollPeriod(repos, periodID)
 		if err != nil {
 			return err
 		}
 		if period == nil {
-			return errors.New("payroll period not found")
+			return ErrPayrollPeriodNotFound
 		}
 		if period.IsProcessed {
-			return errors.New("payroll already processed")
+			return ErrPayrollAlreadyProcessed
+		}
+		if err := checkPendingItems(repos, period); err != nil {
+			return err
 		}
 
-		employees, err := s.employeeProfileRepo.GetAllEmployeeProfiles()
+		inputs, err := loadPayrollInputs(repos, period)
 		if err != nil {
 			return err
 		}
+		total = len(inputs)
+		if onProgress != nil {
+			onProgress(0, total)
+		}
 
-		for _, emp := range employees {
-			payslip, attendances, overtimes, reimbursements, err := s.CalculatePayslip(emp.UserID, period, processedBy, ipAddress)
-			if err != nil {
-				return fmt.Errorf("failed to calculate payslip for user %s: %w", emp.UserID, err)
+		payslips := make([]domain.Payslip, 0, len(inputs))
+		var attendanceIDs, overtimeIDs, reimbursementIDs []uuid.UUID
+		for i, input := range inputs {
+			payslip, attendances, overtimes, reimbursements := s.calculatePayslip(input, period, processedBy, ipAddress)
+			payslips = append(payslips, *payslip)
+			for _, att := range attendances {
+				attendanceIDs = append(attendanceIDs, att.ID)
 			}
-
-			// Save payslip
-			if err := s.payslipRepo.CreatePayslipTx(tx, payslip); err != nil {
-				return fmt.Errorf("failed to save payslip for user %s: %w", emp.UserID, err)
+			for _, ot := range overtimes {
+				overtimeIDs = append(overtimeIDs, ot.ID)
 			}
-
-			// Update related records
-			if err := s.attendanceRepo.UpdateAttendancesTx(tx, attendances); err != nil {
-				return fmt.Errorf("failed to update attendances for user %s: %w", emp.UserID, err)
+			for _, reimb := range reimbursements {
+				reimbursementIDs = append(reimbursementIDs, reimb.ID)
 			}
-			if err := s.overtimeRepo.UpdateOvertimesTx(tx, overtimes); err != nil {
-				return fmt.Errorf("failed to update overtimes for user %s: %w", emp.UserID, err)
+
+			if onProgress != nil && i+1 < total {
+				onProgress(i+1, total)
 			}
-			if err := s.reimbursementRepo.UpdateReimbursementsTx(tx, reimbursements); err != nil {
-				return fmt.Errorf("failed to update reimbursements for user %s: %w", emp.UserID, err)
+		}
+
+		// Save payslips
+		if err := repos.Payslips.CreatePayslips(payslips); err != nil {
+			if errors.Is(err, repository.ErrDuplicateRecord) {
+				return ErrPayrollRunInProgress
 			}
+			return fmt.Errorf("failed to save payslips: %w", err)
+		}
 
-			// Audit log for payslip creation
-			_ = repository.CreateAuditLog(
-				s.auditRepo,
-				&processedBy,
-				"CREATE",
-				"Payslip",
-				&payslip.ID,
-				nil,
-				payslip,
-				ipAddress,
-				requestID,
-			)
+		// Attach related records to the payroll period (immutability)
+		if err := repos.Attendances.AttachAttendancesToPayrollPeriod(attendanceIDs, periodID, processedBy, ipAddress); err != nil {
+			return fmt.Errorf("failed to update attendances: %w", err)
+		}
+		if err := repos.Overtimes.AttachOvertimesToPayrollPeriod(overtimeIDs, periodID, processedBy, ipAddress); err != nil {
+			return fmt.Errorf("failed to update overtimes: %w", err)
+		}
+		if err := repos.Reimbursements.AttachReimbursementsToPayrollPeriod(reimbursementIDs, periodID, processedBy, ipAddress); err != nil {
+			return fmt.Errorf("failed to update reimbursements: %w", err)
+		}
+
+		// Audit logs for payslip creation
+		audits := make([]*domain.AuditLog, 0, len(payslips))
+		for i := range payslips {
+			audit, err := repository.NewAuditLog(&processedBy, "CREATE", "Payslip", &payslips[i].ID, nil, payslips[i], ipAddress, requestID)
+			if err != nil {
+				return fmt.Errorf("failed to build audit log for payslip %s: %w", payslips[i].ID, err)
+			}
+			audits = append(audits, audit)
+		}
+		if err := repos.AuditLogs.CreateBatch(audits); err != nil {
+			return fmt.Errorf("failed to write audit logs for payslips: %w", err)
 		}
 
 		// Mark payroll as processed
-		if err := s.payrollPeriodRepo.MarkPayrollPeriodAsProcessedTx(tx, periodID); err != nil {
+		if err := repos.PayrollPeriods.MarkPayrollPeriodAsProcessed(periodID); err != nil {
 			return fmt.Errorf("failed to mark payroll period as processed: %w", err)
 		}
 
 		// Audit log for payroll period processing
-		_ = repository.CreateAuditLog(
-			s.auditRepo,
+		if err := repository.CreateAuditLog(
+			repos.AuditLogs,
 			&processedBy,
 			"UPDATE",
 			"PayrollPeriod",
@@ -134,12 +242,317 @@ func (s *PayrollService) RunPayroll(periodID uuid.UUID, processedBy uuid.UUID, i
 			period,
 			ipAddress,
 			requestID,
-		)
+		); err != nil {
+			return fmt.Errorf("failed to write audit log for payroll period: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if onProgress != nil && total > 0 {
+		onProgress(total, total)
+	}
+	return nil
+}
+
+// ReversePayroll reopens a processed payroll period so that it can be corrected and run again.
+// The period's payslips are voided, its attendance, overtime and reimbursement records are detached
+// and the period is marked as unprocessed. Every change is written to the audit log with its
+// before and after state; if any audit entry cannot be written the whole reversal is rolled back.
+func (s *PayrollService) ReversePayroll(periodID uuid.UUID, reason string, reversedBy uuid.UUID, ipAddress string, requestID string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required to reverse payroll")
+	}
+
+	return s.uow.Transaction(func(repos *repository.Repositories) error {
+		period, err := lockPayrollPeriod(repos, periodID)
+		if err != nil {
+			return err
+		}
+		if period == nil {
+			return ErrPayrollPeriodNotFound
+		}
+		if !period.IsProcessed {
+			return ErrPayrollNotProcessed
+		}
+
+		// Capture the state before the reversal for the audit trail
+		payslips, err := repos.Payslips.GetAllPayslipsByPeriodID(periodID)
+		if err != nil {
+			return err
+		}
+		attendances, err := repos.Attendances.GetAttendancesByPayrollPeriodID(periodID)
+		if err != nil {
+			return err
+		}
+		overtimes, err := repos.Overtimes.GetOvertimesByPayrollPeriodID(periodID)
+		if err != nil {
+			return err
+		}
+		reimbursements, err := repos.Reimbursements.GetReimbursementsByPayrollPeriodID(periodID)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Payslips.VoidPayslipsByPeriodID(periodID, reason, reversedBy); err != nil {
+			return fmt.Errorf("failed to void payslips: %w", err)
+		}
+		if err := repos.Attendances.DetachAttendancesFromPayrollPeriod(periodID, reversedBy, ipAddress); err != nil {
+			return fmt.Errorf("failed to detach attendances: %w", err)
+		}
+		if err := repos.Overtimes.DetachOvertimesFromPayrollPeriod(periodID, reversedBy, ipAddress); err != nil {
+			return fmt.Errorf("failed to detach overtimes: %w", err)
+		}
+		if err := repos.Reimbursements.DetachReimbursementsFromPayrollPeriod(periodID, reversedBy, ipAddress); err != nil {
+			return fmt.Errorf("failed to detach reimbursements: %w", err)
+		}
+		if err := repos.PayrollPeriods.MarkPayrollPeriodAsUnprocessed(periodID); err != nil {
+			return fmt.Errorf("failed to reopen payroll period: %w", err)
+		}
+
+		// Audit trail: one entry per voided payslip, one entry per detached record type
+		// and one entry for the payroll period itself.
+		for _, payslip := range payslips {
+			voided := payslip
+			voided.VoidReason = reason
+			if err := repository.CreateAuditLog(repos.AuditLogs, &reversedBy, "VOID", "Payslip", &payslip.ID, payslip, voided, ipAddress, requestID); err != nil {
+				return fmt.Errorf("failed to write audit log for payslip %s: %w", payslip.ID, err)
+			}
+		}
+
+		detachedAttendances := make([]domain.Attendance, len(attendances))
+		for i, att := range attendances {
+			detachedAttendances[i] = att
+			detachedAttendances[i].PayrollPeriodID = nil
+		}
+		if err := repository.CreateAuditLog(repos.AuditLogs, &reversedBy, "DETACH", "Attendance", nil, attendances, detachedAttendances, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for attendances: %w", err)
+		}
+
+		detachedOvertimes := make([]domain.Overtime, len(overtimes))
+		for i, ot := range overtimes {
+			detachedOvertimes[i] = ot
+			detachedOvertimes[i].PayrollPeriodID = nil
+		}
+		if err := repository.CreateAuditLog(repos.AuditLogs, &reversedBy, "DETACH", "Overtime", nil, overtimes, detachedOvertimes, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for overtimes: %w", err)
+		}
+
+		detachedReimbursements := make([]domain.Reimbursement, len(reimbursements))
+		for i, reimb := range reimbursements {
+			detachedReimbursements[i] = reimb
+			detachedReimbursements[i].PayrollPeriodID = nil
+		}
+		if err := repository.CreateAuditLog(repos.AuditLogs, &reversedBy, "DETACH", "Reimbursement", nil, reimbursements, detachedReimbursements, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for reimbursements: %w", err)
+		}
+
+		reopened := reversedPayrollPeriod{PayrollPeriod: *period, ReversalReason: reason}
+		reopened.IsProcessed = false
+		reopened.ProcessedAt = nil
+		if err := repository.CreateAuditLog(repos.AuditLogs, &reversedBy, "REVERSE", "PayrollPeriod", &period.ID, period, reopened, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for payroll period: %w", err)
+		}
 
 		return nil
 	})
 }
 
+// lockPayrollPeriod retrieves a payroll period and locks it for the rest of the transaction repos
+// is bound to. It returns ErrPayrollRunInProgress when another transaction holds the lock.
+func lockPayrollPeriod(repos *repository.Repositories, periodID uuid.UUID) (*domain.PayrollPeriod, error) {
+	period, err := repos.PayrollPeriods.LockPayrollPeriodByID(periodID)
+	if errors.Is(err, repository.ErrRecordLocked) {
+		return nil, ErrPayrollRunInProgress
+	}
+	return period, err
+}
+
+// CheckPendingItems returns ErrPayrollPendingItems, listing the records, when overtime, reimbursements
+// or leave dated in the payroll period are still pending review. They have to be approved or
+// rejected before the period is processed: once it is, they can no longer be reviewed or paid.
+func (s *PayrollService) CheckPendingItems(period *domain.PayrollPeriod) error {
+	return checkPendingItems(s.repositories(), period)
+}
+
+// checkPendingItems implements CheckPendingItems with the given repositories.
+func checkPendingItems(repos *repository.Repositories, period *domain.PayrollPeriod) error {
+	overtimes, err := repos.Overtimes.GetPendingOvertimesByPeriod(period.StartDate, period.EndDate)
+	if err != nil {
+		return err
+	}
+	reimbursements, err := repos.Reimbursements.GetPendingReimbursementsByPeriod(period.StartDate, period.EndDate)
+	if err != nil {
+		return err
+	}
+
+	leaves, err := repos.Leaves.GetPendingLeaveRequestsByPeriod(period.StartDate, period.EndDate)
+	if err != nil {
+		return err
+	}
+
+	pending := make([]string, 0, len(overtimes)+len(reimbursements)+len(leaves))
+	for _, o := range overtimes {
+		pending = append(pending, fmt.Sprintf("overtime %s on %s", o.ID, o.Date.Format("2006-01-02")))
+	}
+	for _, r := range reimbursements {
+		pending = append(pending, fmt.Sprintf("reimbursement %s on %s", r.ID, r.ExpenseDate.Format("2006-01-02")))
+	}
+	for _, l := range leaves {
+		pending = append(pending, fmt.Sprintf("leave request %s from %s to %s", l.ID, l.StartDate.Format("2006-01-02"), l.EndDate.Format("2006-01-02")))
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %s", ErrPayrollPendingItems, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// PreviewPayroll runs the payslip calculation for every employee of a payroll period and
+// returns the per-employee breakdowns together with the period totals. Nothing is written:
+// no payslips are created, no records are attached to the period and the period stays open.
+func (s *PayrollService) PreviewPayroll(periodID uuid.UUID) (*PayrollPreview, error) {
+	period, err := s.payrollPeriodRepo.GetPayrollPeriodByID(periodID)
+	if err != nil {
+		return nil, err
+	}
+	if period == nil {
+		return nil, ErrPayrollPeriodNotFound
+	}
+	if period.IsProcessed {
+		return nil, ErrPayrollAlreadyProcessed
+	}
+
+	inputs, err := loadPayrollInputs(s.repositories(), period)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &PayrollPreview{
+		Period:                     period,
+		Payslips:                   make([]domain.Payslip, 0, len(inputs)),
+		TotalBaseSalary:            decimal.Zero,
+		TotalProratedSalary:        decimal.Zero,
+		TotalOvertimePay:           decimal.Zero,
+		TotalReimbursement:         decimal.Zero,
+		TotalGrossEarnings:         decimal.Zero,
+		TotalDeductions:            decimal.Zero,
+		TotalTakeHomePay:           decimal.Zero,
+		TotalEmployerContributions: decimal.Zero,
+		TotalEmployerCost:          decimal.Zero,
+	}
+
+	for _, input := range inputs {
+		payslip, attendances, overtimes, _ := s.calculatePayslip(input, period, uuid.Nil, "")
+
+		payslip.PayrollPeriod = *period
+		payslip.WorkCalendar = input.Calendar
+		payslip.Attendances = make([]*domain.Attendance, len(attendances))
+		for i := range attendances {
+			payslip.Attendances[i] = &attendances[i]
+		}
+		payslip.Overtimes = make([]*domain.Overtime, len(overtimes))
+		for i := range overtimes {
+			payslip.Overtimes[i] = &overtimes[i]
+		}
+
+		preview.TotalBaseSalary = preview.TotalBaseSalary.Add(payslip.BaseSalary)
+		preview.TotalProratedSalary = preview.TotalProratedSalary.Add(payslip.ProratedSalary)
+		preview.TotalOvertimePay = preview.TotalOvertimePay.Add(payslip.OvertimePay)
+		preview.TotalReimbursement = preview.TotalReimbursement.Add(payslip.TotalReimbursement)
+		preview.TotalGrossEarnings = preview.TotalGrossEarnings.Add(payslip.GrossEarnings)
+		preview.TotalDeductions = preview.TotalDeductions.Add(payslip.TotalDeductions)
+		preview.TotalTakeHomePay = preview.TotalTakeHomePay.Add(payslip.TotalTakeHomePay)
+		preview.TotalEmployerContributions = preview.TotalEmployerContributions.Add(payslip.TotalEmployerContributions)
+		preview.TotalEmployerCost = preview.TotalEmployerCost.Add(payslip.GrossEarnings).Add(payslip.TotalEmployerContributions)
+		preview.Payslips = append(preview.Payslips, *payslip)
+	}
+
+	return preview, nil
+}
+
+// payrollInput holds the data the payslip calculation of a single employee is based on.
+type payrollInput struct {
+	Profile        domain.EmployeeProfile
+	SalaryHistory  []domain.SalaryHistory // Salary changes in force during the period, oldest first
+	Attendances    []domain.Attendance
+	Overtimes      []domain.Overtime
+	Reimbursements []domain.Reimbursement
+	Leaves         []domain.LeaveRequest // Approved leave requests with days in the period
+	YearToDate     []domain.Payslip
+	Calendar       domain.WorkCalendar // Holidays of the period and the employee's work schedule
+}
+
+// loadPayrollInputs loads the data of every employee for a payroll period with one query per
+// record type, instead of one query per record type and employee. Inside a payroll run repos is
+// bound to the run's transaction, so the calculation reads a consistent snapshot.
+func loadPayrollInputs(repos *repository.Repositories, period *domain.PayrollPeriod) ([]payrollInput, error) {
+	employees, err := repos.EmployeeProfiles.GetEmployeeProfilesForPeriod(period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	salaryHistories, err := repos.SalaryHistories.GetSalaryHistoriesForPeriodGroupedByUser(period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	attendances, err := repos.Attendances.GetAttendancesByPeriodGroupedByUser(period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	overtimes, err := repos.Overtimes.GetApprovedOvertimesByPeriodGroupedByUser(period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	reimbursements, err := repos.Reimbursements.GetPayableReimbursementsGroupedByUser(period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	leaves, err := repos.Leaves.GetApprovedLeaveRequestsByPeriodGroupedByUser(period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	yearToDate, err := loadYearToDatePayslips(repos, period)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := loadWorkCalendar(repos.Holidays, period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := repos.WorkSchedules.GetAssignedWorkSchedules()
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make([]payrollInput, len(employees))
+	for i, emp := range employees {
+		inputs[i] = payrollInput{
+			Profile:        emp,
+			SalaryHistory:  salaryHistories[emp.UserID],
+			Attendances:    attendances[emp.UserID],
+			Overtimes:      overtimes[emp.UserID],
+			Reimbursements: reimbursements[emp.UserID],
+			Leaves:         leaves[emp.UserID],
+			YearToDate:     yearToDate[emp.UserID],
+			Calendar:       calendar.WithSchedule(schedules[emp.UserID]),
+		}
+	}
+	return inputs, nil
+}
+
+// loadYearToDatePayslips loads the earlier payslips of the tax year for the annual PPh 21
+// true-up. They are only needed, and therefore only loaded, for a period ending in December.
+func loadYearToDatePayslips(repos *repository.Repositories, period *domain.PayrollPeriod) (map[uuid.UUID][]domain.Payslip, error) {
+	if period.EndDate.Month() != time.December {
+		return nil, nil
+	}
+	return repos.Payslips.GetYearToDatePayslipsGroupedByUser(period.EndDate.Year(), period.StartDate)
+}
+
+// CalculatePayslip loads the data of a single employee and calculates their payslip for a payroll period.
 func (s *PayrollService) CalculatePayslip(
 	userID uuid.UUID,
 	period *domain.PayrollPeriod,
@@ -154,82 +567,202 @@ func (s *PayrollService) CalculatePayslip(
 	if empProfile == nil {
 		return nil, nil, nil, nil, errors.New("employee profile not found")
 	}
+	if _, _, employed := empProfile.EmploymentWindow(period.StartDate, period.EndDate); !employed {
+		return nil, nil, nil, nil, ErrNotEmployedInPeriod
+	}
 
-	baseSalary := empProfile.Salary
+	salaryHistory, err := s.salaryHistoryRepo.GetSalaryHistoriesForPeriodByUserID(userID, period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
 
-	// Attendance
 	attendances, err := s.attendanceRepo.GetAttendancesByUserIDAndPeriod(userID, period.StartDate, period.EndDate)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
+	overtimes, err := s.overtimeRepo.GetApprovedOvertimesByUserIDAndPeriod(userID, period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	reimbursements, err := s.reimbursementRepo.GetPayableReimbursementsByUserID(userID, period.EndDate)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	leaves, err := s.leaveRepo.GetApprovedLeaveRequestsByUserIDAndPeriod(userID, period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	yearToDate, err := loadYearToDatePayslips(s.repositories(), period)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	calendar, err := loadWorkCalendar(s.holidayRepo, period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	schedule, err := s.workScheduleRepo.GetWorkScheduleByUserID(userID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
 
-	totalWorkedHours := 0.0
-	for _, att := range attendances {
-		if (att.Date.After(period.StartDate) || att.Date.Equal(period.StartDate)) &&
-			(att.Date.Before(period.EndDate) || att.Date.Equal(period.EndDate)) {
-
-			workedHours := att.CheckOutTime.Sub(att.CheckInTime).Hours()
+	payslip, attendances, overtimes, reimbursements := s.calculatePayslip(payrollInput{
+		Profile:        *empProfile,
+		SalaryHistory:  salaryHistory,
+		Attendances:    attendances,
+		Overtimes:      overtimes,
+		Reimbursements: reimbursements,
+		Leaves:         leaves,
+		YearToDate:     yearToDate[userID],
+		Calendar:       calendar.WithSchedule(schedule),
+	}, period, processedBy, ipAddress)
+	return payslip, attendances, overtimes, reimbursements, nil
+}
 
-			if workedHours > RegularWorkingHoursPerDay {
-				workedHours = RegularWorkingHoursPerDay // cap at 8h
-			} else if workedHours < RegularWorkingHoursPerDay {
-				workedHours = 0
+// calculatePayslip calculates the payslip of an employee from already loaded data. It does not
+// access the database, so it can be run for every employee of a period after a batch load.
+// The payslip lines are produced by the service's component registry; the fixed payslip columns
+// and totals are derived from those lines. The returned records are the ones the payslip paid,
+// attached to the payroll period: attendances with paid hours and overtime within the employee's
+// active window, and every payable reimbursement. Records outside the window, or attendances that
+// earned no hours, are left unattached.
+func (s *PayrollService) calculatePayslip(
+	input payrollInput,
+	period *domain.PayrollPeriod,
+	processedBy uuid.UUID,
+	ipAddress string,
+) (*domain.Payslip, []domain.Attendance, []domain.Overtime, []domain.Reimbursement) {
+	userID := input.Profile.UserID
+	reimbursements := input.Reimbursements
+
+	// The period is split at every salary change effective within it; attendance and overtime
+	// are paid at the salary in force on their date.
+	segments := salarySegments(input.Profile.Salary, input.SalaryHistory, period)
+
+	// Only the part of the period the employee is employed in counts: attendance and overtime
+	// outside of it are not paid.
+	activeFrom, activeTo, _ := input.Profile.EmploymentWindow(period.StartDate, period.EndDate)
+
+	// Attendance; the hours worked on a day are paid following the attendance pay mode, against
+	// the daily hours of the employee's schedule. An attendance still open has no check-out and
+	// counts no hours.
+	schedule := input.Calendar.Schedule()
+	dailyHours := schedule.DailyHours.InexactFloat64()
+	payMode := s.attendance.PayMode
+	totalWorkedHours := decimal.Zero
+	attended := make(map[string]bool, len(input.Attendances))
+	attendances := make([]domain.Attendance, 0, len(input.Attendances))
+	for _, att := range input.Attendances {
+		if !att.Date.Before(activeFrom) && !att.Date.After(activeTo) {
+
+			paidHours := payMode.PaidHours(att.WorkedHours(schedule), dailyHours)
+			totalWorkedHours = totalWorkedHours.Add(decimal.NewFromFloat(paidHours))
+			segment := salarySegmentOn(segments, att.Date)
+			segment.WorkedHours = segment.WorkedHours.Add(decimal.NewFromFloat(paidHours))
+			attended[att.Date.Format("2006-01-02")] = true
+			if paidHours > 0 {
+				attendances = append(attendances, att)
 			}
-			totalWorkedHours += workedHours
 		}
 	}
 
-	// totalPossibleWorkingHours = working days * 8 hours
-	totalPossibleWorkingHours := 0.0
-	for d := period.StartDate; !d.After(period.EndDate); d = d.Add(24 * time.Hour) {
-		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
-			totalPossibleWorkingHours += RegularWorkingHoursPerDay
+	// Approved leave; every working day of leave is paid as a full working day. Unpaid leave is
+	// deducted again, so that it shows on the payslip. A day with an attendance is paid for the
+	// attendance instead.
+	totalUnpaidLeaveHours := decimal.Zero
+	for _, leave := range input.Leaves {
+		for day := leave.StartDate; !day.After(leave.EndDate); day = day.AddDate(0, 0, 1) {
+			if day.Before(activeFrom) || day.After(activeTo) || !input.Calendar.IsWorkingDay(day) || attended[day.Format("2006-01-02")] {
+				continue
+			}
+			totalWorkedHours = totalWorkedHours.Add(schedule.DailyHours)
+			segment := salarySegmentOn(segments, day)
+			segment.WorkedHours = segment.WorkedHours.Add(schedule.DailyHours)
+			if !domain.IsPaidLeaveType(leave.LeaveType) {
+				totalUnpaidLeaveHours = totalUnpaidLeaveHours.Add(schedule.DailyHours)
+				segment.UnpaidLeaveHours = segment.UnpaidLeaveHours.Add(schedule.DailyHours)
+			}
 		}
 	}
 
-	var hourlyRate float64
-
-	proratedSalary := 0.0
-	if totalPossibleWorkingHours > 0 {
-		hourlyRate = baseSalary / totalPossibleWorkingHours
-		proratedSalary = hourlyRate * totalWorkedHours
+	// Overtime; overtime on rest days and holidays is paid at the rest day multiplier.
+	overtimes := make([]domain.Overtime, 0, len(input.Overtimes))
+	for _, ot := range input.Overtimes {
+		if ot.Date.Before(activeFrom) || ot.Date.After(activeTo) {
+			continue
+		}
+		salarySegmentOn(segments, ot.Date).addOvertime(input.Calendar, ot)
+		overtimes = append(overtimes, ot)
 	}
 
-	// Overtime
-	overtimes, err := s.overtimeRepo.GetOvertimesByUserIDAndPeriod(userID, period.StartDate, period.EndDate)
-	if err != nil {
-		return nil, nil, nil, nil, err
+	// The possible working hours only cover the employee's active window. The salary is prorated
+	// to that window by the share of the period's working hours it covers, so the hourly rate is
+	// the salary divided by the working hours of the whole period and a joiner or leaver who
+	// attends every day of their window is paid that share of the salary.
+	totalPossibleWorkingHours := input.Calendar.WorkingHours(activeFrom, activeTo)
+	periodWorkingHours := input.Calendar.WorkingHours(period.StartDate, period.EndDate)
+
+	// Hourly rates are kept at full precision; only money lines and totals are rounded.
+	for i := range segments {
+		if periodWorkingHours.IsPositive() {
+			segments[i].HourlyRate = segments[i].Salary.Div(periodWorkingHours)
+		}
 	}
-	totalOvertimeHours := 0.0
-	for _, ot := range overtimes {
-		totalOvertimeHours += ot.Hours
+
+	// The salary in force at the end of the period is the payslip's base salary and the wage
+	// contributions are calculated on.
+	current := segments[len(segments)-1]
+	baseSalary := current.Salary
+	profile := input.Profile
+	profile.Salary = baseSalary
+
+	// Pay components
+	calc := &PayslipCalculation{
+		Profile:          profile,
+		Period:           period,
+		Attendances:      attendances,
+		Overtimes:        overtimes,
+		Reimbursements:   reimbursements,
+		YearToDate:       input.YearToDate,
+		WorkedHours:      totalWorkedHours,
+		UnpaidLeaveHours: totalUnpaidLeaveHours,
+		WorkingHours:     totalPossibleWorkingHours,
+		Calendar:         input.Calendar,
+		HourlyRate:       current.HourlyRate,
+		SalarySegments:   segments,
+		Rounding:         s.rounding,
 	}
+	items := s.components.Calculate(calc)
 
-	overtimePay := totalOvertimeHours * hourlyRate * OvertimeMultiplier
+	grossEarnings := s.rounding.RoundTotal(calc.Total(domain.PayslipComponentEarning))
+	totalDeductions := s.rounding.RoundTotal(calc.Total(domain.PayslipComponentDeduction))
 
-	// Reimbursements
-	reimbursements, err := s.reimbursementRepo.GetReimbursementsByUserIDAndPeriod(userID, period.StartDate, period.EndDate)
-	if err != nil {
-		return nil, nil, nil, nil, err
-	}
-	totalReimbursement := 0.0
-	for _, reimb := range reimbursements {
-		totalReimbursement += reimb.Amount
+	now := time.Now()
+	for i := range items {
+		items[i].BaseModel = domain.BaseModel{
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: processedBy,
+			UpdatedBy: processedBy,
+			IPAddress: ipAddress,
+		}
 	}
 
-	totalTakeHomePay := proratedSalary + overtimePay + totalReimbursement
-
 	payslip := &domain.Payslip{
-		UserID:             userID,
-		PayrollPeriodID:    period.ID,
-		BaseSalary:         baseSalary,
-		ProratedSalary:     proratedSalary,
-		OvertimePay:        overtimePay,
-		TotalReimbursement: totalReimbursement,
-		TotalTakeHomePay:   totalTakeHomePay,
+		UserID:                     userID,
+		PayrollPeriodID:            period.ID,
+		AttendancePayMode:          payMode,
+		BaseSalary:                 baseSalary,
+		ProratedSalary:             calc.Amount(PayslipItemCodeBasicSalary),
+		OvertimePay:                calc.Amount(PayslipItemCodeOvertime),
+		TotalReimbursement:         calc.Amount(PayslipItemCodeReimbursement),
+		GrossEarnings:              grossEarnings,
+		TotalDeductions:            totalDeductions,
+		TotalEmployerContributions: s.rounding.RoundTotal(calc.Total(domain.PayslipComponentEmployerContribution)),
+		TotalTakeHomePay:           s.rounding.RoundTotal(grossEarnings.Sub(totalDeductions)),
+		Items:                      items,
 		BaseModel: domain.BaseModel{
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			CreatedAt: now,
+			UpdatedAt: now,
 			CreatedBy: processedBy,
 			UpdatedBy: processedBy,
 			IPAddress: ipAddress,
@@ -256,5 +789,5 @@ func (s *PayrollService) CalculatePayslip(
 		reimbursements[i].IPAddress = ipAddress
 	}
 
-	return payslip, attendances, overtimes, reimbursements, nil
+	return payslip, attendances, overtimes, reimbursements
 }
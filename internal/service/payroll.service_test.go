@@ -1,34 +1,81 @@
 package service_test
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 
 	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
 	"payroll-system/internal/service"
 	mockrepo "payroll-system/tests/mocks/repository"
 
 	"go.uber.org/mock/gomock"
 )
 
-func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, func()) {
-	db, mock, err := sqlmock.New()
-	require.NoError(t, err)
+// newTxRepositories creates mock repositories that stand for repositories bound to a transaction.
+func newTxRepositories(ctrl *gomock.Controller) (*repository.Repositories, *txMocks) {
+	m := &txMocks{
+		userRepo:                  mockrepo.NewMockUserRepository(ctrl),
+		payslipRepo:               mockrepo.NewMockPayslipRepository(ctrl),
+		payrollPeriodRepo:         mockrepo.NewMockPayrollPeriodRepository(ctrl),
+		employeeProfileRepo:       mockrepo.NewMockEmployeeProfileRepository(ctrl),
+		salaryHistoryRepo:         mockrepo.NewMockSalaryHistoryRepository(ctrl),
+		attendanceRepo:            mockrepo.NewMockAttendanceRepository(ctrl),
+		overtimeRepo:              mockrepo.NewMockOvertimeRepository(ctrl),
+		reimbursementRepo:         mockrepo.NewMockReimbursementRepository(ctrl),
+		reimbursementCategoryRepo: mockrepo.NewMockReimbursementCategoryRepository(ctrl),
+		holidayRepo:               mockrepo.NewMockHolidayRepository(ctrl),
+		workScheduleRepo:          mockrepo.NewMockWorkScheduleRepository(ctrl),
+		leaveRepo:                 mockrepo.NewMockLeaveRepository(ctrl),
+		auditRepo:                 mockrepo.NewMockAuditLogRepository(ctrl),
+	}
+	repos := &repository.Repositories{
+		Users:                   m.userRepo,
+		Payslips:                m.payslipRepo,
+		PayrollPeriods:          m.payrollPeriodRepo,
+		EmployeeProfiles:        m.employeeProfileRepo,
+		SalaryHistories:         m.salaryHistoryRepo,
+		Attendances:             m.attendanceRepo,
+		Overtimes:               m.overtimeRepo,
+		Reimbursements:          m.reimbursementRepo,
+		ReimbursementCategories: m.reimbursementCategoryRepo,
+		Holidays:                m.holidayRepo,
+		WorkSchedules:           m.workScheduleRepo,
+		Leaves:                  m.leaveRepo,
+		AuditLogs:               m.auditRepo,
+	}
+	return repos, m
+}
 
-	gormDB, err := gorm.Open(postgres.New(postgres.Config{
-		Conn: db,
-	}), &gorm.Config{})
-	require.NoError(t, err)
+// txMocks holds the mocks behind the transaction-bound repositories.
+type txMocks struct {
+	userRepo                  *mockrepo.MockUserRepository
+	payslipRepo               *mockrepo.MockPayslipRepository
+	payrollPeriodRepo         *mockrepo.MockPayrollPeriodRepository
+	employeeProfileRepo       *mockrepo.MockEmployeeProfileRepository
+	salaryHistoryRepo         *mockrepo.MockSalaryHistoryRepository
+	attendanceRepo            *mockrepo.MockAttendanceRepository
+	overtimeRepo              *mockrepo.MockOvertimeRepository
+	reimbursementRepo         *mockrepo.MockReimbursementRepository
+	reimbursementCategoryRepo *mockrepo.MockReimbursementCategoryRepository
+	holidayRepo               *mockrepo.MockHolidayRepository
+	workScheduleRepo          *mockrepo.MockWorkScheduleRepository
+	leaveRepo                 *mockrepo.MockLeaveRepository
+	auditRepo                 *mockrepo.MockAuditLogRepository
+}
 
-	cleanup := func() { db.Close() }
-	return gormDB, mock, cleanup
+// expectTransaction makes the unit of work run the transaction function with txRepos.
+func expectTransaction(uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+	uow.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(repos *repository.Repositories) error) error {
+		return fn(txRepos)
+	})
 }
 
 func TestRunPayroll(t *testing.T) {
@@ -45,6 +92,7 @@ func TestRunPayroll(t *testing.T) {
 			auditRepo *mockrepo.MockAuditLogRepository,
 		)
 		expectError bool
+		expectedErr error
 	}{
 		{
 			name: "success run payroll",
@@ -57,7 +105,7 @@ func TestRunPayroll(t *testing.T) {
 
 				// Payroll period exists and not processed
 				payrollPeriodRepo.EXPECT().
-					GetPayrollPeriodByID(gomock.Any()).
+					LockPayrollPeriodByID(gomock.Any()).
 					Return(&domain.PayrollPeriod{
 						BaseModel:   domain.BaseModel{ID: uuid.New()},
 						StartDate:   now.Add(-10 * 24 * time.Hour),
@@ -67,54 +115,59 @@ func TestRunPayroll(t *testing.T) {
 
 				// Employees
 				employeeProfileRepo.EXPECT().
-					GetAllEmployeeProfiles().
+					GetEmployeeProfilesForPeriod(gomock.Any(), gomock.Any()).
 					Return([]domain.EmployeeProfile{
 						{
 							UserID: userID,
-							Salary: 1000,
+							Salary: decimal.NewFromInt(1000),
 						},
 					}, nil)
 
-				// CalculatePayslip repo calls
-				employeeProfileRepo.EXPECT().
-					GetEmployeeProfileByUserID(gomock.Any()).
-					Return(&domain.EmployeeProfile{
-						UserID: userID,
-						Salary: 1000,
-					}, nil)
-
+				// Period data is loaded with one query per record type
+				attendanceID, overtimeID, reimbursementID := uuid.New(), uuid.New(), uuid.New()
 				attendanceRepo.EXPECT().
-					GetAttendancesByUserIDAndPeriod(gomock.Any(), gomock.Any(), gomock.Any()).
-					Return([]domain.Attendance{
-						{
+					GetAttendancesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).
+					Return(map[uuid.UUID][]domain.Attendance{
+						userID: {{
+							BaseModel:    domain.BaseModel{ID: attendanceID},
+							UserID:       userID,
 							CheckInTime:  now.Add(-8 * time.Hour),
-							CheckOutTime: now,
+							CheckOutTime: &now,
 							Date:         now,
-						},
+						}},
 					}, nil)
-
 				overtimeRepo.EXPECT().
-					GetOvertimesByUserIDAndPeriod(gomock.Any(), gomock.Any(), gomock.Any()).
-					Return([]domain.Overtime{}, nil)
-
+					GetApprovedOvertimesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).
+					Return(map[uuid.UUID][]domain.Overtime{
+						userID: {{BaseModel: domain.BaseModel{ID: overtimeID}, UserID: userID, Date: now, Hours: 1}},
+					}, nil)
 				reimbursementRepo.EXPECT().
-					GetReimbursementsByUserIDAndPeriod(gomock.Any(), gomock.Any(), gomock.Any()).
-					Return([]domain.Reimbursement{}, nil)
+					GetPayableReimbursementsGroupedByUser(gomock.Any()).
+					Return(map[uuid.UUID][]domain.Reimbursement{
+						userID: {{BaseModel: domain.BaseModel{ID: reimbursementID}, UserID: userID, Amount: decimal.NewFromInt(50)}},
+					}, nil)
 
-				// Save payslip and related records
+				// Payslips are saved and related records attached in bulk
 				payslipRepo.EXPECT().
-					CreatePayslipTx(gomock.Any(), gomock.Any()).
-					Return(nil)
-				attendanceRepo.EXPECT().UpdateAttendancesTx(gomock.Any(), gomock.Any()).Return(nil)
-				overtimeRepo.EXPECT().UpdateOvertimesTx(gomock.Any(), gomock.Any()).Return(nil)
-				reimbursementRepo.EXPECT().UpdateReimbursementsTx(gomock.Any(), gomock.Any()).Return(nil)
+					CreatePayslips(gomock.Any()).
+					DoAndReturn(func(payslips []domain.Payslip) error {
+						require.Len(t, payslips, 1)
+						assert.Equal(t, userID, payslips[0].UserID)
+						return nil
+					})
+				attendanceRepo.EXPECT().AttachAttendancesToPayrollPeriod([]uuid.UUID{attendanceID}, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				overtimeRepo.EXPECT().AttachOvertimesToPayrollPeriod([]uuid.UUID{overtimeID}, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				reimbursementRepo.EXPECT().AttachReimbursementsToPayrollPeriod([]uuid.UUID{reimbursementID}, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 
 				// Mark payroll as processed
 				payrollPeriodRepo.EXPECT().
-					MarkPayrollPeriodAsProcessedTx(gomock.Any(), gomock.Any()).
+					MarkPayrollPeriodAsProcessed(gomock.Any()).
 					Return(nil)
 
 				// Audit logs
+				auditRepo.EXPECT().
+					CreateBatch(gomock.Len(1)).
+					Return(nil)
 				auditRepo.EXPECT().
 					Create(gomock.Any()).
 					Return(nil).
@@ -122,6 +175,134 @@ func TestRunPayroll(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "records that earned nothing are left unattached",
+			mockSetup: func(t *testing.T, payslipRepo *mockrepo.MockPayslipRepository, payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
+				employeeProfileRepo *mockrepo.MockEmployeeProfileRepository, attendanceRepo *mockrepo.MockAttendanceRepository,
+				overtimeRepo *mockrepo.MockOvertimeRepository, reimbursementRepo *mockrepo.MockReimbursementRepository, auditRepo *mockrepo.MockAuditLogRepository) {
+
+				userID := uuid.New()
+				hireDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)
+				day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC) }
+				checkOut := func(d int) *time.Time { t := day(d).Add(17 * time.Hour); return &t }
+
+				payrollPeriodRepo.EXPECT().LockPayrollPeriodByID(gomock.Any()).Return(&domain.PayrollPeriod{
+					BaseModel: domain.BaseModel{ID: uuid.New()},
+					StartDate: day(1),
+					EndDate:   day(31),
+				}, nil)
+				employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(gomock.Any(), gomock.Any()).
+					Return([]domain.EmployeeProfile{{UserID: userID, Salary: decimal.NewFromInt(1000), HireDate: &hireDate}}, nil)
+
+				// Before the hire date, worked, and still open
+				beforeHire, worked, open := uuid.New(), uuid.New(), uuid.New()
+				attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(map[uuid.UUID][]domain.Attendance{
+					userID: {
+						{BaseModel: domain.BaseModel{ID: beforeHire}, UserID: userID, Date: day(11), CheckInTime: day(11).Add(9 * time.Hour), CheckOutTime: checkOut(11)},
+						{BaseModel: domain.BaseModel{ID: worked}, UserID: userID, Date: day(18), CheckInTime: day(18).Add(9 * time.Hour), CheckOutTime: checkOut(18)},
+						{BaseModel: domain.BaseModel{ID: open}, UserID: userID, Date: day(19), CheckInTime: day(19).Add(9 * time.Hour)},
+					},
+				}, nil)
+				overtimeBeforeHire, overtime := uuid.New(), uuid.New()
+				overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(map[uuid.UUID][]domain.Overtime{
+					userID: {
+						{BaseModel: domain.BaseModel{ID: overtimeBeforeHire}, UserID: userID, Date: day(12), Hours: 2},
+						{BaseModel: domain.BaseModel{ID: overtime}, UserID: userID, Date: day(18), Hours: 1},
+					},
+				}, nil)
+				reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(gomock.Any()).Return(nil, nil)
+
+				payslipRepo.EXPECT().CreatePayslips(gomock.Any()).Return(nil)
+				attendanceRepo.EXPECT().AttachAttendancesToPayrollPeriod([]uuid.UUID{worked}, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				overtimeRepo.EXPECT().AttachOvertimesToPayrollPeriod([]uuid.UUID{overtime}, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				reimbursementRepo.EXPECT().AttachReimbursementsToPayrollPeriod(nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				payrollPeriodRepo.EXPECT().MarkPayrollPeriodAsProcessed(gomock.Any()).Return(nil)
+				auditRepo.EXPECT().CreateBatch(gomock.Len(1)).Return(nil)
+				auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name: "audit failure rolls back the run",
+			mockSetup: func(t *testing.T, payslipRepo *mockrepo.MockPayslipRepository, payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
+				employeeProfileRepo *mockrepo.MockEmployeeProfileRepository, attendanceRepo *mockrepo.MockAttendanceRepository,
+				overtimeRepo *mockrepo.MockOvertimeRepository, reimbursementRepo *mockrepo.MockReimbursementRepository, auditRepo *mockrepo.MockAuditLogRepository) {
+
+				userID := uuid.New()
+				payrollPeriodRepo.EXPECT().LockPayrollPeriodByID(gomock.Any()).Return(&domain.PayrollPeriod{
+					BaseModel: domain.BaseModel{ID: uuid.New()},
+					StartDate: time.Now().Add(-24 * time.Hour),
+					EndDate:   time.Now(),
+				}, nil)
+				employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(gomock.Any(), gomock.Any()).Return([]domain.EmployeeProfile{{UserID: userID, Salary: decimal.NewFromInt(1000)}}, nil)
+				attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+				overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+				reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(gomock.Any()).Return(nil, nil)
+				payslipRepo.EXPECT().CreatePayslips(gomock.Any()).Return(nil)
+				attendanceRepo.EXPECT().AttachAttendancesToPayrollPeriod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				overtimeRepo.EXPECT().AttachOvertimesToPayrollPeriod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				reimbursementRepo.EXPECT().AttachReimbursementsToPayrollPeriod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+				// The error is returned from the transaction, so nothing is committed
+				auditRepo.EXPECT().CreateBatch(gomock.Any()).Return(errors.New("audit db down"))
+			},
+			expectError: true,
+		},
+		{
+			name: "period locked by a concurrent run",
+			mockSetup: func(t *testing.T, payslipRepo *mockrepo.MockPayslipRepository, payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
+				employeeProfileRepo *mockrepo.MockEmployeeProfileRepository, attendanceRepo *mockrepo.MockAttendanceRepository,
+				overtimeRepo *mockrepo.MockOvertimeRepository, reimbursementRepo *mockrepo.MockReimbursementRepository, auditRepo *mockrepo.MockAuditLogRepository) {
+
+				payrollPeriodRepo.EXPECT().
+					LockPayrollPeriodByID(gomock.Any()).
+					Return(nil, repository.ErrRecordLocked)
+			},
+			expectError: true,
+			expectedErr: service.ErrPayrollRunInProgress,
+		},
+		{
+			name: "payslips already created for the period",
+			mockSetup: func(t *testing.T, payslipRepo *mockrepo.MockPayslipRepository, payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
+				employeeProfileRepo *mockrepo.MockEmployeeProfileRepository, attendanceRepo *mockrepo.MockAttendanceRepository,
+				overtimeRepo *mockrepo.MockOvertimeRepository, reimbursementRepo *mockrepo.MockReimbursementRepository, auditRepo *mockrepo.MockAuditLogRepository) {
+
+				payrollPeriodRepo.EXPECT().LockPayrollPeriodByID(gomock.Any()).Return(&domain.PayrollPeriod{
+					BaseModel: domain.BaseModel{ID: uuid.New()},
+					StartDate: time.Now().Add(-24 * time.Hour),
+					EndDate:   time.Now(),
+				}, nil)
+				employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(gomock.Any(), gomock.Any()).Return([]domain.EmployeeProfile{{UserID: uuid.New(), Salary: decimal.NewFromInt(1000)}}, nil)
+				attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+				overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+				reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(gomock.Any()).Return(nil, nil)
+				payslipRepo.EXPECT().CreatePayslips(gomock.Any()).Return(repository.ErrDuplicateRecord)
+			},
+			expectError: true,
+			expectedErr: service.ErrPayrollRunInProgress,
+		},
+		{
+			name: "records pending review",
+			mockSetup: func(t *testing.T, payslipRepo *mockrepo.MockPayslipRepository, payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
+				employeeProfileRepo *mockrepo.MockEmployeeProfileRepository, attendanceRepo *mockrepo.MockAttendanceRepository,
+				overtimeRepo *mockrepo.MockOvertimeRepository, reimbursementRepo *mockrepo.MockReimbursementRepository, auditRepo *mockrepo.MockAuditLogRepository) {
+
+				day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC) }
+				payrollPeriodRepo.EXPECT().LockPayrollPeriodByID(gomock.Any()).Return(&domain.PayrollPeriod{
+					BaseModel: domain.BaseModel{ID: uuid.New()},
+					StartDate: day(1),
+					EndDate:   day(31),
+				}, nil)
+				overtimeID, reimbursementID := uuid.New(), uuid.New()
+				overtimeRepo.EXPECT().GetPendingOvertimesByPeriod(day(1), day(31)).
+					Return([]domain.Overtime{{BaseModel: domain.BaseModel{ID: overtimeID}, Date: day(18)}}, nil)
+				reimbursementRepo.EXPECT().GetPendingReimbursementsByPeriod(day(1), day(31)).
+					Return([]domain.Reimbursement{{BaseModel: domain.BaseModel{ID: reimbursementID}, ExpenseDate: day(20)}}, nil)
+				// Nothing is calculated or saved
+			},
+			expectError: true,
+			expectedErr: service.ErrPayrollPendingItems,
+		},
 		{
 			name: "payroll period not found",
 			mockSetup: func(t *testing.T, payslipRepo *mockrepo.MockPayslipRepository, payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
@@ -129,10 +310,11 @@ func TestRunPayroll(t *testing.T) {
 				overtimeRepo *mockrepo.MockOvertimeRepository, reimbursementRepo *mockrepo.MockReimbursementRepository, auditRepo *mockrepo.MockAuditLogRepository) {
 
 				payrollPeriodRepo.EXPECT().
-					GetPayrollPeriodByID(gomock.Any()).
+					LockPayrollPeriodByID(gomock.Any()).
 					Return(nil, nil)
 			},
 			expectError: true,
+			expectedErr: service.ErrPayrollPeriodNotFound,
 		},
 	}
 
@@ -141,43 +323,520 @@ func TestRunPayroll(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			// Mocks
+			// Repositories bound to the root database handle must not be used during a run:
+			// gomock fails on any call to them.
 			payslipRepo := mockrepo.NewMockPayslipRepository(ctrl)
 			payrollPeriodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
 			employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+			salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
 			attendanceRepo := mockrepo.NewMockAttendanceRepository(ctrl)
 			overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
 			reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+			holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+			workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+			leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
 			auditRepo := mockrepo.NewMockAuditLogRepository(ctrl)
 
-			// Setup DB
-			db, sqlmock, cleanup := setupTestDB(t)
-			defer cleanup()
+			txRepos, tx := newTxRepositories(ctrl)
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			expectTransaction(uow, txRepos)
 
-			// Begin transaction for all cases
-			sqlmock.ExpectBegin()
-			if !tt.expectError {
-				sqlmock.ExpectCommit()
-			} else {
-				sqlmock.ExpectRollback()
-			}
+			// No employee has a salary change, so everyone is paid their profile salary
+			tx.salaryHistoryRepo.EXPECT().GetSalaryHistoriesForPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			// The period has no holidays
+			tx.holidayRepo.EXPECT().GetHolidaysBetween(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			// Every employee follows the default work schedule
+			tx.workScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil).AnyTimes()
+			tx.leaveRepo.EXPECT().GetApprovedLeaveRequestsByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 			// Setup mocks
 			if tt.mockSetup != nil {
-				tt.mockSetup(t, payslipRepo, payrollPeriodRepo, employeeProfileRepo, attendanceRepo, overtimeRepo, reimbursementRepo, auditRepo)
+				tt.mockSetup(t, tx.payslipRepo, tx.payrollPeriodRepo, tx.employeeProfileRepo, tx.attendanceRepo, tx.overtimeRepo, tx.reimbursementRepo, tx.auditRepo)
 			}
+			// Nothing is pending review, unless the test case expects otherwise
+			tx.overtimeRepo.EXPECT().GetPendingOvertimesByPeriod(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			tx.reimbursementRepo.EXPECT().GetPendingReimbursementsByPeriod(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			tx.leaveRepo.EXPECT().GetPendingLeaveRequestsByPeriod(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-			svc := service.NewPayrollService(payslipRepo, payrollPeriodRepo, employeeProfileRepo, attendanceRepo, overtimeRepo, reimbursementRepo, auditRepo, db)
+			svc := service.NewPayrollService(payslipRepo, payrollPeriodRepo, employeeProfileRepo, salaryHistoryRepo, attendanceRepo, overtimeRepo, reimbursementRepo, holidayRepo, workScheduleRepo, leaveRepo, auditRepo, uow, service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), service.DefaultPayslipComponentRegistry(service.DefaultBPJSRates()))
 
 			err := svc.RunPayroll(uuid.New(), uuid.New(), "127.0.0.1", "req-123")
 			if tt.expectError {
 				assert.Error(t, err)
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				}
 			} else {
 				assert.NoError(t, err)
 			}
+		})
+	}
+}
+
+func TestCheckPendingItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC) }
+	period := &domain.PayrollPeriod{StartDate: day(1), EndDate: day(31)}
+	overtimeID, reimbursementID, leaveID := uuid.New(), uuid.New(), uuid.New()
+
+	overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
+	reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+	leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
+	overtimeRepo.EXPECT().GetPendingOvertimesByPeriod(day(1), day(31)).
+		Return([]domain.Overtime{{BaseModel: domain.BaseModel{ID: overtimeID}, Date: day(18)}}, nil)
+	reimbursementRepo.EXPECT().GetPendingReimbursementsByPeriod(day(1), day(31)).
+		Return([]domain.Reimbursement{{BaseModel: domain.BaseModel{ID: reimbursementID}, ExpenseDate: day(20)}}, nil)
+	// Leave that starts in the previous period is pending in this one too
+	leaveRepo.EXPECT().GetPendingLeaveRequestsByPeriod(day(1), day(31)).
+		Return([]domain.LeaveRequest{{BaseModel: domain.BaseModel{ID: leaveID}, StartDate: time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC), EndDate: day(1)}}, nil)
+
+	svc := service.NewPayrollService(nil, nil, nil, nil, nil, overtimeRepo, reimbursementRepo, nil, nil, leaveRepo, nil, nil, service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), service.DefaultPayslipComponentRegistry(service.DefaultBPJSRates()))
+	err := svc.CheckPendingItems(period)
+
+	assert.ErrorIs(t, err, service.ErrPayrollPendingItems)
+	assert.EqualError(t, err, fmt.Sprintf("payroll period has records pending review: overtime %s on 2025-08-18, reimbursement %s on 2025-08-20, leave request %s from 2025-07-30 to 2025-08-01",
+		overtimeID, reimbursementID, leaveID))
+}
+
+func TestProcessPayroll_Progress(t *testing.T) {
+	type progress struct {
+		processed, total int
+		committed        bool
+	}
+
+	tests := []struct {
+		name         string
+		saveErr      error
+		wantProgress []progress
+	}{
+		{
+			name: "last employee reported after commit",
+			wantProgress: []progress{
+				{processed: 0, total: 2},
+				{processed: 1, total: 2},
+				{processed: 2, total: 2, committed: true},
+			},
+		},
+		{
+			name:    "failed run never reports every employee",
+			saveErr: errors.New("db down"),
+			wantProgress: []progress{
+				{processed: 0, total: 2},
+				{processed: 1, total: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			txRepos, tx := newTxRepositories(ctrl)
+			committed := false
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(repos *repository.Repositories) error) error {
+				if err := fn(txRepos); err != nil {
+					return err
+				}
+				committed = true
+				return nil
+			})
+
+			tx.payrollPeriodRepo.EXPECT().LockPayrollPeriodByID(gomock.Any()).Return(&domain.PayrollPeriod{
+				BaseModel: domain.BaseModel{ID: uuid.New()},
+				StartDate: time.Now().Add(-24 * time.Hour),
+				EndDate:   time.Now(),
+			}, nil)
+			tx.employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(gomock.Any(), gomock.Any()).Return([]domain.EmployeeProfile{
+				{UserID: uuid.New(), Salary: decimal.NewFromInt(1000)},
+				{UserID: uuid.New(), Salary: decimal.NewFromInt(2000)},
+			}, nil)
+			tx.salaryHistoryRepo.EXPECT().GetSalaryHistoriesForPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			tx.holidayRepo.EXPECT().GetHolidaysBetween(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			tx.workScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil).AnyTimes()
+			tx.leaveRepo.EXPECT().GetApprovedLeaveRequestsByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			tx.overtimeRepo.EXPECT().GetPendingOvertimesByPeriod(gomock.Any(), gomock.Any()).Return(nil, nil)
+			tx.reimbursementRepo.EXPECT().GetPendingReimbursementsByPeriod(gomock.Any(), gomock.Any()).Return(nil, nil)
+			tx.leaveRepo.EXPECT().GetPendingLeaveRequestsByPeriod(gomock.Any(), gomock.Any()).Return(nil, nil)
+			tx.attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+			tx.overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+			tx.reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(gomock.Any()).Return(nil, nil)
+			tx.payslipRepo.EXPECT().CreatePayslips(gomock.Len(2)).Return(tt.saveErr)
+			if tt.saveErr == nil {
+				tx.attendanceRepo.EXPECT().AttachAttendancesToPayrollPeriod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				tx.overtimeRepo.EXPECT().AttachOvertimesToPayrollPeriod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				tx.reimbursementRepo.EXPECT().AttachReimbursementsToPayrollPeriod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				tx.auditRepo.EXPECT().CreateBatch(gomock.Len(2)).Return(nil)
+				tx.payrollPeriodRepo.EXPECT().MarkPayrollPeriodAsProcessed(gomock.Any()).Return(nil)
+				tx.auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			svc := service.NewPayrollService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, uow, service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), service.DefaultPayslipComponentRegistry(service.DefaultBPJSRates()))
+
+			var got []progress
+			err := svc.ProcessPayroll(uuid.New(), uuid.New(), "127.0.0.1", "req-123", func(processed, total int) {
+				got = append(got, progress{processed: processed, total: total, committed: committed})
+			})
+			if tt.saveErr != nil {
+				assert.ErrorIs(t, err, tt.saveErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantProgress, got)
+		})
+	}
+}
+
+func TestPreviewPayroll(t *testing.T) {
+	now := time.Date(2025, 8, 29, 17, 0, 0, 0, time.UTC) // Friday
+	period := &domain.PayrollPeriod{
+		BaseModel:   domain.BaseModel{ID: uuid.New()},
+		StartDate:   time.Date(2025, 8, 25, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2025, 8, 29, 0, 0, 0, 0, time.UTC),
+		IsProcessed: false,
+	}
+	userID := uuid.New()
+
+	tests := []struct {
+		name        string
+		mockSetup   func(payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository, employeeProfileRepo *mockrepo.MockEmployeeProfileRepository, attendanceRepo *mockrepo.MockAttendanceRepository, overtimeRepo *mockrepo.MockOvertimeRepository, reimbursementRepo *mockrepo.MockReimbursementRepository)
+		expectError string
+	}{
+		{
+			name: "success preview",
+			mockSetup: func(payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository, employeeProfileRepo *mockrepo.MockEmployeeProfileRepository, attendanceRepo *mockrepo.MockAttendanceRepository, overtimeRepo *mockrepo.MockOvertimeRepository, reimbursementRepo *mockrepo.MockReimbursementRepository) {
+				payrollPeriodRepo.EXPECT().GetPayrollPeriodByID(period.ID).Return(period, nil)
+				employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(gomock.Any(), gomock.Any()).Return([]domain.EmployeeProfile{{UserID: userID, Salary: decimal.NewFromInt(4000000)}}, nil)
+				attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Attendance{
+					userID: {{UserID: userID, Date: period.EndDate, CheckInTime: now.Add(-8 * time.Hour), CheckOutTime: &now}},
+				}, nil)
+				overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Overtime{
+					userID: {{UserID: userID, Date: period.EndDate, Hours: 2}},
+				}, nil)
+				reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(period.EndDate).Return(map[uuid.UUID][]domain.Reimbursement{
+					userID: {{UserID: userID, Amount: decimal.NewFromInt(150000)}},
+				}, nil)
+			},
+		},
+		{
+			name: "period already processed",
+			mockSetup: func(payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository, employeeProfileRepo *mockrepo.MockEmployeeProfileRepository, attendanceRepo *mockrepo.MockAttendanceRepository, overtimeRepo *mockrepo.MockOvertimeRepository, reimbursementRepo *mockrepo.MockReimbursementRepository) {
+				payrollPeriodRepo.EXPECT().GetPayrollPeriodByID(period.ID).Return(&domain.PayrollPeriod{IsProcessed: true}, nil)
+			},
+			expectError: "payroll already processed",
+		},
+		{
+			name: "period not found",
+			mockSetup: func(payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository, employeeProfileRepo *mockrepo.MockEmployeeProfileRepository, attendanceRepo *mockrepo.MockAttendanceRepository, overtimeRepo *mockrepo.MockOvertimeRepository, reimbursementRepo *mockrepo.MockReimbursementRepository) {
+				payrollPeriodRepo.EXPECT().GetPayrollPeriodByID(period.ID).Return(nil, nil)
+			},
+			expectError: "payroll period not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// No repository write or audit call is expected: gomock fails on any unexpected call.
+			payslipRepo := mockrepo.NewMockPayslipRepository(ctrl)
+			payrollPeriodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
+			employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+			salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+			attendanceRepo := mockrepo.NewMockAttendanceRepository(ctrl)
+			overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
+			reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+			holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+			workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+			leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
+			auditRepo := mockrepo.NewMockAuditLogRepository(ctrl)
+
+			// The preview must never open a transaction.
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+
+			tt.mockSetup(payrollPeriodRepo, employeeProfileRepo, attendanceRepo, overtimeRepo, reimbursementRepo)
+			salaryHistoryRepo.EXPECT().GetSalaryHistoriesForPeriodGroupedByUser(period.StartDate, period.EndDate).Return(nil, nil).AnyTimes()
+			holidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return(nil, nil).AnyTimes()
+			workScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil).AnyTimes()
+			leaveRepo.EXPECT().GetApprovedLeaveRequestsByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+			svc := service.NewPayrollService(payslipRepo, payrollPeriodRepo, employeeProfileRepo, salaryHistoryRepo, attendanceRepo, overtimeRepo, reimbursementRepo, holidayRepo, workScheduleRepo, leaveRepo, auditRepo, uow, service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), service.DefaultPayslipComponentRegistry(service.DefaultBPJSRates()))
+
+			preview, err := svc.PreviewPayroll(period.ID)
+			if tt.expectError != "" {
+				assert.EqualError(t, err, tt.expectError)
+				assert.Nil(t, preview)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, preview.Payslips, 1)
+
+				// 5 working days * 8h = 40h, hourly rate = 100000
+				assert.Equal(t, "800000", preview.TotalProratedSalary.String())
+				assert.Equal(t, "400000", preview.TotalOvertimePay.String())
+				assert.Equal(t, "150000", preview.TotalReimbursement.String())
+				assert.Equal(t, "1190000", preview.TotalTakeHomePay.String())
+
+				// One line per built-in component, in registration order
+				items := preview.Payslips[0].Items
+				require.Len(t, items, 12)
+				codes := make([]string, len(items))
+				for i, item := range items {
+					codes[i] = item.Code
+				}
+				assert.Equal(t, []string{
+					service.PayslipItemCodeBasicSalary, service.PayslipItemCodeOvertime, service.PayslipItemCodeReimbursement,
+					service.PayslipItemCodeBPJSJHT, service.PayslipItemCodeBPJSJP, service.PayslipItemCodeBPJSKesehatan,
+					service.PayslipItemCodeBPJSJHTEmployer, service.PayslipItemCodeBPJSJPEmployer, service.PayslipItemCodeBPJSJKK,
+					service.PayslipItemCodeBPJSJKM, service.PayslipItemCodeBPJSKesehatanEmployer, service.PayslipItemCodePPh21,
+				}, codes)
+				// Reimbursements are not taxed, the JKK, JKM and BPJS Kesehatan employer premiums are;
+				// 1,381,600 is below the first TER bracket
+				assert.Equal(t, "1381600", items[11].Quantity.String())
+				assert.True(t, items[11].Amount.IsZero())
+				assert.Equal(t, "8", items[0].Quantity.String())
+				assert.Equal(t, "100000", items[0].Rate.String())
+				assert.Equal(t, "200000", items[1].Rate.String())
+				assert.Equal(t, "1350000", preview.Payslips[0].GrossEarnings.String())
+				// BPJS is contributed on the full monthly salary of 4,000,000
+				assert.Equal(t, "160000", preview.Payslips[0].TotalDeductions.String())
+				assert.Equal(t, "409600", preview.Payslips[0].TotalEmployerContributions.String())
+				assert.Equal(t, "409600", preview.TotalEmployerContributions.String())
+				assert.Equal(t, "1759600", preview.TotalEmployerCost.String())
+				assert.Len(t, preview.Payslips[0].Attendances, 1)
+				assert.Len(t, preview.Payslips[0].Overtimes, 1)
+			}
+		})
+	}
+}
+
+func TestReversePayroll(t *testing.T) {
+	periodID := uuid.New()
+	processedAt := time.Now()
+	processedPeriod := &domain.PayrollPeriod{
+		BaseModel:   domain.BaseModel{ID: periodID},
+		IsProcessed: true,
+		ProcessedAt: &processedAt,
+	}
+
+	tests := []struct {
+		name      string
+		reason    string
+		mockSetup func(
+			payslipRepo *mockrepo.MockPayslipRepository,
+			payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
+			attendanceRepo *mockrepo.MockAttendanceRepository,
+			overtimeRepo *mockrepo.MockOvertimeRepository,
+			reimbursementRepo *mockrepo.MockReimbursementRepository,
+			auditRepo *mockrepo.MockAuditLogRepository,
+		)
+		expectTx    bool
+		expectError string
+	}{
+		{
+			name:   "success reverse payroll",
+			reason: "wrong salary for employee42",
+			mockSetup: func(payslipRepo *mockrepo.MockPayslipRepository, payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
+				attendanceRepo *mockrepo.MockAttendanceRepository, overtimeRepo *mockrepo.MockOvertimeRepository,
+				reimbursementRepo *mockrepo.MockReimbursementRepository, auditRepo *mockrepo.MockAuditLogRepository) {
+
+				payrollPeriodRepo.EXPECT().LockPayrollPeriodByID(periodID).Return(processedPeriod, nil)
+				payslipRepo.EXPECT().GetAllPayslipsByPeriodID(periodID).Return([]domain.Payslip{
+					{BaseModel: domain.BaseModel{ID: uuid.New()}, PayrollPeriodID: periodID},
+					{BaseModel: domain.BaseModel{ID: uuid.New()}, PayrollPeriodID: periodID},
+				}, nil)
+				attendanceRepo.EXPECT().GetAttendancesByPayrollPeriodID(periodID).Return([]domain.Attendance{{PayrollPeriodID: &periodID}}, nil)
+				overtimeRepo.EXPECT().GetOvertimesByPayrollPeriodID(periodID).Return([]domain.Overtime{}, nil)
+				reimbursementRepo.EXPECT().GetReimbursementsByPayrollPeriodID(periodID).Return([]domain.Reimbursement{}, nil)
+
+				payslipRepo.EXPECT().VoidPayslipsByPeriodID(periodID, "wrong salary for employee42", gomock.Any()).Return(nil)
+				attendanceRepo.EXPECT().DetachAttendancesFromPayrollPeriod(periodID, gomock.Any(), gomock.Any()).Return(nil)
+				overtimeRepo.EXPECT().DetachOvertimesFromPayrollPeriod(periodID, gomock.Any(), gomock.Any()).Return(nil)
+				reimbursementRepo.EXPECT().DetachReimbursementsFromPayrollPeriod(periodID, gomock.Any(), gomock.Any()).Return(nil)
+				payrollPeriodRepo.EXPECT().MarkPayrollPeriodAsUnprocessed(periodID).Return(nil)
+
+				// 2 payslips + 3 detached record types + 1 payroll period
+				auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(6)
+			},
+			expectTx: true,
+		},
+		{
+			name:        "missing reason",
+			reason:      "   ",
+			mockSetup:   nil,
+			expectError: "a reason is required to reverse payroll",
+		},
+		{
+			name:   "period not processed",
+			reason: "wrong salary",
+			mockSetup: func(payslipRepo *mockrepo.MockPayslipRepository, payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
+				attendanceRepo *mockrepo.MockAttendanceRepository, overtimeRepo *mockrepo.MockOvertimeRepository,
+				reimbursementRepo *mockrepo.MockReimbursementRepository, auditRepo *mockrepo.MockAuditLogRepository) {
+
+				payrollPeriodRepo.EXPECT().LockPayrollPeriodByID(periodID).Return(&domain.PayrollPeriod{IsProcessed: false}, nil)
+			},
+			expectTx:    true,
+			expectError: "payroll period has not been processed",
+		},
+		{
+			name:   "period not found",
+			reason: "wrong salary",
+			mockSetup: func(payslipRepo *mockrepo.MockPayslipRepository, payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
+				attendanceRepo *mockrepo.MockAttendanceRepository, overtimeRepo *mockrepo.MockOvertimeRepository,
+				reimbursementRepo *mockrepo.MockReimbursementRepository, auditRepo *mockrepo.MockAuditLogRepository) {
+
+				payrollPeriodRepo.EXPECT().LockPayrollPeriodByID(periodID).Return(nil, nil)
+			},
+			expectTx:    true,
+			expectError: service.ErrPayrollPeriodNotFound.Error(),
+		},
+		{
+			name:   "period locked by a concurrent run",
+			reason: "wrong salary",
+			mockSetup: func(payslipRepo *mockrepo.MockPayslipRepository, payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
+				attendanceRepo *mockrepo.MockAttendanceRepository, overtimeRepo *mockrepo.MockOvertimeRepository,
+				reimbursementRepo *mockrepo.MockReimbursementRepository, auditRepo *mockrepo.MockAuditLogRepository) {
+
+				payrollPeriodRepo.EXPECT().LockPayrollPeriodByID(periodID).Return(nil, repository.ErrRecordLocked)
+			},
+			expectTx:    true,
+			expectError: service.ErrPayrollRunInProgress.Error(),
+		},
+		{
+			name:   "audit failure rolls back the reversal",
+			reason: "wrong salary",
+			mockSetup: func(payslipRepo *mockrepo.MockPayslipRepository, payrollPeriodRepo *mockrepo.MockPayrollPeriodRepository,
+				attendanceRepo *mockrepo.MockAttendanceRepository, overtimeRepo *mockrepo.MockOvertimeRepository,
+				reimbursementRepo *mockrepo.MockReimbursementRepository, auditRepo *mockrepo.MockAuditLogRepository) {
+
+				payrollPeriodRepo.EXPECT().LockPayrollPeriodByID(periodID).Return(processedPeriod, nil)
+				payslipRepo.EXPECT().GetAllPayslipsByPeriodID(periodID).Return([]domain.Payslip{{PayrollPeriodID: periodID}}, nil)
+				attendanceRepo.EXPECT().GetAttendancesByPayrollPeriodID(periodID).Return(nil, nil)
+				overtimeRepo.EXPECT().GetOvertimesByPayrollPeriodID(periodID).Return(nil, nil)
+				reimbursementRepo.EXPECT().GetReimbursementsByPayrollPeriodID(periodID).Return(nil, nil)
+				payslipRepo.EXPECT().VoidPayslipsByPeriodID(periodID, gomock.Any(), gomock.Any()).Return(nil)
+				attendanceRepo.EXPECT().DetachAttendancesFromPayrollPeriod(periodID, gomock.Any(), gomock.Any()).Return(nil)
+				overtimeRepo.EXPECT().DetachOvertimesFromPayrollPeriod(periodID, gomock.Any(), gomock.Any()).Return(nil)
+				reimbursementRepo.EXPECT().DetachReimbursementsFromPayrollPeriod(periodID, gomock.Any(), gomock.Any()).Return(nil)
+				payrollPeriodRepo.EXPECT().MarkPayrollPeriodAsUnprocessed(periodID).Return(nil)
+				auditRepo.EXPECT().Create(gomock.Any()).Return(errors.New("audit db down"))
+			},
+			expectTx:    true,
+			expectError: "audit db down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			payslipRepo := mockrepo.NewMockPayslipRepository(ctrl)
+			payrollPeriodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
+			employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+			salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+			attendanceRepo := mockrepo.NewMockAttendanceRepository(ctrl)
+			overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
+			reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+			holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+			workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+			leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
+			auditRepo := mockrepo.NewMockAuditLogRepository(ctrl)
+
+			txRepos, tx := newTxRepositories(ctrl)
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			if tt.expectTx {
+				expectTransaction(uow, txRepos)
+			}
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(tx.payslipRepo, tx.payrollPeriodRepo, tx.attendanceRepo, tx.overtimeRepo, tx.reimbursementRepo, tx.auditRepo)
+			}
+
+			svc := service.NewPayrollService(payslipRepo, payrollPeriodRepo, employeeProfileRepo, salaryHistoryRepo, attendanceRepo, overtimeRepo, reimbursementRepo, holidayRepo, workScheduleRepo, leaveRepo, auditRepo, uow, service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), service.DefaultPayslipComponentRegistry(service.DefaultBPJSRates()))
+
+			err := svc.ReversePayroll(periodID, tt.reason, uuid.New(), "127.0.0.1", "req-123")
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPreviewPayroll_AttendancePayMode(t *testing.T) {
+	period := &domain.PayrollPeriod{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		StartDate: time.Date(2025, 8, 25, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 8, 29, 0, 0, 0, 0, time.UTC),
+	}
+	userID := uuid.New()
+
+	// Monday a full day, Tuesday 5 hours, Wednesday 3 hours, Thursday still clocked in
+	var attendances []domain.Attendance
+	for i, hours := range []int{8, 5, 3} {
+		checkIn := period.StartDate.AddDate(0, 0, i).Add(9 * time.Hour)
+		checkOut := checkIn.Add(time.Duration(hours) * time.Hour)
+		attendances = append(attendances, domain.Attendance{UserID: userID, Date: checkIn, CheckInTime: checkIn, CheckOutTime: &checkOut})
+	}
+	thursday := period.StartDate.AddDate(0, 0, 3).Add(9 * time.Hour)
+	attendances = append(attendances, domain.Attendance{UserID: userID, Date: thursday, CheckInTime: thursday})
+
+	tests := []struct {
+		mode          domain.AttendancePayMode
+		expectedHours string
+	}{
+		{domain.AttendancePayFullDay, "8"},
+		{domain.AttendancePayHourly, "16"},
+		{domain.AttendancePayHalfDay, "12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			payrollPeriodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
+			employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+			salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+			attendanceRepo := mockrepo.NewMockAttendanceRepository(ctrl)
+			overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
+			reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+			holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+			workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+			leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
+
+			payrollPeriodRepo.EXPECT().GetPayrollPeriodByID(period.ID).Return(period, nil)
+			employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(period.StartDate, period.EndDate).Return([]domain.EmployeeProfile{{UserID: userID, Salary: decimal.NewFromInt(4000000)}}, nil)
+			salaryHistoryRepo.EXPECT().GetSalaryHistoriesForPeriodGroupedByUser(period.StartDate, period.EndDate).Return(nil, nil)
+			attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Attendance{userID: attendances}, nil)
+			overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(nil, nil)
+			reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(period.EndDate).Return(nil, nil)
+			holidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return(nil, nil)
+			workScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil)
+			leaveRepo.EXPECT().GetApprovedLeaveRequestsByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+			policy := service.DefaultAttendancePolicy()
+			policy.PayMode = tt.mode
+			registry := service.NewPayslipComponentRegistry(service.BasicSalaryCalculator{})
+			svc := service.NewPayrollService(
+				mockrepo.NewMockPayslipRepository(ctrl), payrollPeriodRepo, employeeProfileRepo, salaryHistoryRepo, attendanceRepo, overtimeRepo, reimbursementRepo, holidayRepo, workScheduleRepo, leaveRepo,
+				mockrepo.NewMockAuditLogRepository(ctrl), mockrepo.NewMockUnitOfWork(ctrl), service.DefaultRoundingPolicy(), policy, registry,
+			)
+
+			preview, err := svc.PreviewPayroll(period.ID)
+			require.NoError(t, err)
+			require.Len(t, preview.Payslips, 1)
 
-			// Ensure all sqlmock expectations are met
-			require.NoError(t, sqlmock.ExpectationsWereMet())
+			// 5 working days * 8h = 40h, hourly rate = 100000
+			payslip := preview.Payslips[0]
+			assert.Equal(t, tt.mode, payslip.AttendancePayMode)
+			assert.Equal(t, tt.expectedHours, payslip.Items[0].Quantity.String())
+			assert.Equal(t, "100000", payslip.Items[0].Rate.String())
 		})
 	}
 }
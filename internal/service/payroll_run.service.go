@@ -0,0 +1,203 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+)
+
+// payrollRunProgressInterval is the number of processed employees between two persisted
+// progress updates, so that large runs do not write to the database for every employee.
+const payrollRunProgressInterval = 50
+
+var (
+	// ErrPayrollRunInProgress is returned when a payroll run is already queued or running for a period,
+	// or when another transaction is processing or reversing the period's payroll.
+	ErrPayrollRunInProgress = errors.New("a payroll run is already in progress for this period")
+	// ErrPayrollPeriodNotFound is returned when payroll is run, previewed or reversed for a payroll
+	// period that does not exist.
+	ErrPayrollPeriodNotFound = errors.New("payroll period not found")
+	// ErrPayrollAlreadyProcessed is returned when payroll is run or previewed for a period that has
+	// already been processed; the period has to be reversed before it can be run again.
+	ErrPayrollAlreadyProcessed = errors.New("payroll already processed")
+	// ErrPayrollPendingItems is returned when payroll is run for a period with records dated in it that
+	// are still pending review; once the period is processed they could no longer be reviewed and paid.
+	ErrPayrollPendingItems = errors.New("payroll period has records pending review")
+	// ErrPayrollNotProcessed is returned when payroll is reversed for a period that has not been processed.
+	ErrPayrollNotProcessed = errors.New("payroll period has not been processed")
+)
+
+// PayrollRunServiceInterface defines the methods of PayrollRunService for mocking purposes.
+//
+//go:generate mockgen -source=payroll_run.service.go -destination=../../tests/mocks/service/mock_payroll_run_service.go -package=mocks
+type PayrollRunServiceInterface interface {
+	// StartPayrollRun queues a background payroll run for a payroll period.
+	StartPayrollRun(periodID uuid.UUID, requestedBy uuid.UUID, ipAddress, requestID string) (*domain.PayrollRun, error)
+	// GetPayrollRunByID retrieves a payroll run by its ID.
+	GetPayrollRunByID(id uuid.UUID) (*domain.PayrollRun, error)
+	// GetAllPayrollRuns retrieves payroll runs, optionally filtered by payroll period.
+	GetAllPayrollRuns(periodID *uuid.UUID) ([]domain.PayrollRun, error)
+}
+
+// PayrollRunService runs payroll processing as background jobs and tracks their status.
+type PayrollRunService struct {
+	payrollRunRepo    repository.PayrollRunRepository
+	payrollPeriodRepo repository.PayrollPeriodRepository
+	payrollService    PayrollServiceInterface
+	auditRepo         repository.AuditLogRepository
+}
+
+// NewPayrollRunService creates a new PayrollRunService.
+func NewPayrollRunService(
+	payrollRunRepo repository.PayrollRunRepository,
+	payrollPeriodRepo repository.PayrollPeriodRepository,
+	payrollService PayrollServiceInterface,
+	auditRepo repository.AuditLogRepository,
+) *PayrollRunService {
+	return &PayrollRunService{
+		payrollRunRepo:    payrollRunRepo,
+		payrollPeriodRepo: payrollPeriodRepo,
+		payrollService:    payrollService,
+		auditRepo:         auditRepo,
+	}
+}
+
+// StartPayrollRun validates the payroll period, persists a queued payroll run and processes
+// it in the background. The returned run can be polled with GetPayrollRunByID. It returns
+// ErrPayrollPeriodNotFound, ErrPayrollAlreadyProcessed, ErrPayrollPendingItems or
+// ErrPayrollRunInProgress when the period cannot be run.
+func (s *PayrollRunService) StartPayrollRun(periodID uuid.UUID, requestedBy uuid.UUID, ipAddress string, requestID string) (*domain.PayrollRun, error) {
+	period, err := s.payrollPeriodRepo.GetPayrollPeriodByID(periodID)
+	if err != nil {
+		return nil, err
+	}
+	if period == nil {
+		return nil, ErrPayrollPeriodNotFound
+	}
+	if period.IsProcessed {
+		return nil, ErrPayrollAlreadyProcessed
+	}
+	if err := s.payrollService.CheckPendingItems(period); err != nil {
+		return nil, err
+	}
+
+	activeRun, err := s.payrollRunRepo.GetActivePayrollRunByPeriodID(periodID)
+	if err != nil {
+		return nil, err
+	}
+	if activeRun != nil {
+		return nil, ErrPayrollRunInProgress
+	}
+
+	now := time.Now()
+	run := &domain.PayrollRun{
+		PayrollPeriodID: periodID,
+		Status:          domain.PayrollRunStatusQueued,
+		RequestID:       requestID,
+		BaseModel: domain.BaseModel{
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: requestedBy,
+			UpdatedBy: requestedBy,
+			IPAddress: ipAddress,
+		},
+	}
+
+	// The active-run check above is not atomic; a unique index on active runs rejects a run
+	// queued concurrently for the same period.
+	if err := s.payrollRunRepo.CreatePayrollRun(run); err != nil {
+		if errors.Is(err, repository.ErrDuplicateRecord) {
+			return nil, ErrPayrollRunInProgress
+		}
+		return nil, err
+	}
+
+	// Audit log
+	_ = repository.CreateAuditLog(
+		s.auditRepo,
+		&requestedBy,
+		"CREATE",
+		"PayrollRun",
+		&run.ID,
+		nil,
+		run,
+		ipAddress,
+		requestID,
+	)
+
+	queued := *run
+	go s.execute(&queued)
+
+	return run, nil
+}
+
+// GetPayrollRunByID retrieves a payroll run by its ID.
+func (s *PayrollRunService) GetPayrollRunByID(id uuid.UUID) (*domain.PayrollRun, error) {
+	return s.payrollRunRepo.GetPayrollRunByID(id)
+}
+
+// GetAllPayrollRuns retrieves payroll runs, optionally filtered by payroll period.
+func (s *PayrollRunService) GetAllPayrollRuns(periodID *uuid.UUID) ([]domain.PayrollRun, error) {
+	return s.payrollRunRepo.GetAllPayrollRuns(periodID)
+}
+
+// execute processes a queued payroll run and records its outcome. A panic while processing fails
+// the run instead of crashing the server; the payroll transaction has been rolled back by then.
+func (s *PayrollRunService) execute(run *domain.PayrollRun) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("payroll run %s: panic: %v\n%s", run.ID, r, debug.Stack())
+			finishedAt := time.Now()
+			run.Status = domain.PayrollRunStatusFailed
+			run.Error = fmt.Sprintf("panic: %v", r)
+			run.FinishedAt = &finishedAt
+			run.UpdatedAt = finishedAt
+			if err := s.payrollRunRepo.UpdatePayrollRun(run); err != nil {
+				log.Printf("payroll run %s: failed to record outcome %q: %v", run.ID, run.Status, err)
+			}
+		}
+	}()
+
+	startedAt := time.Now()
+	run.Status = domain.PayrollRunStatusRunning
+	run.StartedAt = &startedAt
+	run.UpdatedAt = startedAt
+	if err := s.payrollRunRepo.UpdatePayrollRun(run); err != nil {
+		log.Printf("payroll run %s: failed to mark as running: %v", run.ID, err)
+	}
+
+	lastReported := 0
+	onProgress := func(processed, total int) {
+		run.ProcessedEmployees = processed
+		run.TotalEmployees = total
+		if processed != 0 && processed != total && processed-lastReported < payrollRunProgressInterval {
+			return
+		}
+		lastReported = processed
+		if err := s.payrollRunRepo.UpdatePayrollRunProgress(run.ID, processed, total); err != nil {
+			log.Printf("payroll run %s: failed to update progress: %v", run.ID, err)
+		}
+	}
+
+	runErr := s.payrollService.ProcessPayroll(run.PayrollPeriodID, run.CreatedBy, run.IPAddress, run.RequestID, onProgress)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.UpdatedAt = finishedAt
+	if runErr != nil {
+		run.Status = domain.PayrollRunStatusFailed
+		run.Error = runErr.Error()
+	} else {
+		run.Status = domain.PayrollRunStatusSucceeded
+	}
+	if err := s.payrollRunRepo.UpdatePayrollRun(run); err != nil {
+		log.Printf("payroll run %s: failed to record outcome %q: %v", run.ID, run.Status, err)
+	}
+}
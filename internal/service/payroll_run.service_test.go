@@ -0,0 +1,196 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+	"payroll-system/internal/service"
+	mockrepo "payroll-system/tests/mocks/repository"
+	mocksvc "payroll-system/tests/mocks/service"
+)
+
+func TestStartPayrollRun(t *testing.T) {
+	periodID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name         string
+		mockSetup    func(runRepo *mockrepo.MockPayrollRunRepository, periodRepo *mockrepo.MockPayrollPeriodRepository, payrollSvc *mocksvc.MockPayrollServiceInterface, auditRepo *mockrepo.MockAuditLogRepository, done chan *domain.PayrollRun)
+		wantErr      error
+		wantStatus   string
+		wantRunError string
+		waitForDone  bool
+	}{
+		{
+			name: "Success - run is queued and succeeds in the background",
+			mockSetup: func(runRepo *mockrepo.MockPayrollRunRepository, periodRepo *mockrepo.MockPayrollPeriodRepository, payrollSvc *mocksvc.MockPayrollServiceInterface, auditRepo *mockrepo.MockAuditLogRepository, done chan *domain.PayrollRun) {
+				periodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(&domain.PayrollPeriod{BaseModel: domain.BaseModel{ID: periodID}}, nil)
+				payrollSvc.EXPECT().CheckPendingItems(gomock.Any()).Return(nil)
+				runRepo.EXPECT().GetActivePayrollRunByPeriodID(periodID).Return(nil, nil)
+				runRepo.EXPECT().CreatePayrollRun(gomock.Any()).DoAndReturn(func(run *domain.PayrollRun) error {
+					run.ID = uuid.New()
+					return nil
+				})
+				auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+				gomock.InOrder(
+					runRepo.EXPECT().UpdatePayrollRun(gomock.Any()).DoAndReturn(func(run *domain.PayrollRun) error {
+						assert.Equal(t, domain.PayrollRunStatusRunning, run.Status)
+						assert.NotNil(t, run.StartedAt)
+						return nil
+					}),
+					runRepo.EXPECT().UpdatePayrollRun(gomock.Any()).DoAndReturn(func(run *domain.PayrollRun) error {
+						done <- run
+						return nil
+					}),
+				)
+				runRepo.EXPECT().UpdatePayrollRunProgress(gomock.Any(), gomock.Any(), 2).Return(nil).Times(2)
+				payrollSvc.EXPECT().ProcessPayroll(periodID, adminID, "127.0.0.1", "req-1", gomock.Any()).
+					DoAndReturn(func(_, _ uuid.UUID, _, _ string, onProgress service.PayrollProgressFunc) error {
+						onProgress(0, 2)
+						onProgress(1, 2)
+						onProgress(2, 2)
+						return nil
+					})
+			},
+			wantStatus:  domain.PayrollRunStatusSucceeded,
+			waitForDone: true,
+		},
+		{
+			name: "Failure - processing error is recorded on the run",
+			mockSetup: func(runRepo *mockrepo.MockPayrollRunRepository, periodRepo *mockrepo.MockPayrollPeriodRepository, payrollSvc *mocksvc.MockPayrollServiceInterface, auditRepo *mockrepo.MockAuditLogRepository, done chan *domain.PayrollRun) {
+				periodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(&domain.PayrollPeriod{BaseModel: domain.BaseModel{ID: periodID}}, nil)
+				payrollSvc.EXPECT().CheckPendingItems(gomock.Any()).Return(nil)
+				runRepo.EXPECT().GetActivePayrollRunByPeriodID(periodID).Return(nil, nil)
+				runRepo.EXPECT().CreatePayrollRun(gomock.Any()).Return(nil)
+				auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+				gomock.InOrder(
+					runRepo.EXPECT().UpdatePayrollRun(gomock.Any()).Return(nil),
+					runRepo.EXPECT().UpdatePayrollRun(gomock.Any()).DoAndReturn(func(run *domain.PayrollRun) error {
+						done <- run
+						return nil
+					}),
+				)
+				payrollSvc.EXPECT().ProcessPayroll(periodID, adminID, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New("failed to create payslip"))
+			},
+			wantStatus:   domain.PayrollRunStatusFailed,
+			wantRunError: "failed to create payslip",
+			waitForDone:  true,
+		},
+		{
+			name: "Failure - panic is recorded on the run",
+			mockSetup: func(runRepo *mockrepo.MockPayrollRunRepository, periodRepo *mockrepo.MockPayrollPeriodRepository, payrollSvc *mocksvc.MockPayrollServiceInterface, auditRepo *mockrepo.MockAuditLogRepository, done chan *domain.PayrollRun) {
+				periodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(&domain.PayrollPeriod{BaseModel: domain.BaseModel{ID: periodID}}, nil)
+				payrollSvc.EXPECT().CheckPendingItems(gomock.Any()).Return(nil)
+				runRepo.EXPECT().GetActivePayrollRunByPeriodID(periodID).Return(nil, nil)
+				runRepo.EXPECT().CreatePayrollRun(gomock.Any()).Return(nil)
+				auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+				gomock.InOrder(
+					runRepo.EXPECT().UpdatePayrollRun(gomock.Any()).Return(nil),
+					runRepo.EXPECT().UpdatePayrollRun(gomock.Any()).DoAndReturn(func(run *domain.PayrollRun) error {
+						done <- run
+						return nil
+					}),
+				)
+				payrollSvc.EXPECT().ProcessPayroll(periodID, adminID, gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_, _ uuid.UUID, _, _ string, _ service.PayrollProgressFunc) error {
+						panic("nil salary history")
+					})
+			},
+			wantStatus:   domain.PayrollRunStatusFailed,
+			wantRunError: "panic: nil salary history",
+			waitForDone:  true,
+		},
+		{
+			name: "Error - payroll period not found",
+			mockSetup: func(runRepo *mockrepo.MockPayrollRunRepository, periodRepo *mockrepo.MockPayrollPeriodRepository, payrollSvc *mocksvc.MockPayrollServiceInterface, auditRepo *mockrepo.MockAuditLogRepository, done chan *domain.PayrollRun) {
+				periodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(nil, nil)
+			},
+			wantErr: service.ErrPayrollPeriodNotFound,
+		},
+		{
+			name: "Error - payroll already processed",
+			mockSetup: func(runRepo *mockrepo.MockPayrollRunRepository, periodRepo *mockrepo.MockPayrollPeriodRepository, payrollSvc *mocksvc.MockPayrollServiceInterface, auditRepo *mockrepo.MockAuditLogRepository, done chan *domain.PayrollRun) {
+				periodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(&domain.PayrollPeriod{IsProcessed: true}, nil)
+			},
+			wantErr: service.ErrPayrollAlreadyProcessed,
+		},
+		{
+			name: "Error - records pending review",
+			mockSetup: func(runRepo *mockrepo.MockPayrollRunRepository, periodRepo *mockrepo.MockPayrollPeriodRepository, payrollSvc *mocksvc.MockPayrollServiceInterface, auditRepo *mockrepo.MockAuditLogRepository, done chan *domain.PayrollRun) {
+				period := &domain.PayrollPeriod{BaseModel: domain.BaseModel{ID: periodID}}
+				periodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(period, nil)
+				payrollSvc.EXPECT().CheckPendingItems(period).Return(fmt.Errorf("%w: overtime %s on 2025-08-18", service.ErrPayrollPendingItems, uuid.New()))
+			},
+			wantErr: service.ErrPayrollPendingItems,
+		},
+		{
+			name: "Error - run already in progress",
+			mockSetup: func(runRepo *mockrepo.MockPayrollRunRepository, periodRepo *mockrepo.MockPayrollPeriodRepository, payrollSvc *mocksvc.MockPayrollServiceInterface, auditRepo *mockrepo.MockAuditLogRepository, done chan *domain.PayrollRun) {
+				periodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(&domain.PayrollPeriod{BaseModel: domain.BaseModel{ID: periodID}}, nil)
+				payrollSvc.EXPECT().CheckPendingItems(gomock.Any()).Return(nil)
+				runRepo.EXPECT().GetActivePayrollRunByPeriodID(periodID).Return(&domain.PayrollRun{Status: domain.PayrollRunStatusRunning}, nil)
+			},
+			wantErr: service.ErrPayrollRunInProgress,
+		},
+		{
+			name: "Error - run queued concurrently for the same period",
+			mockSetup: func(runRepo *mockrepo.MockPayrollRunRepository, periodRepo *mockrepo.MockPayrollPeriodRepository, payrollSvc *mocksvc.MockPayrollServiceInterface, auditRepo *mockrepo.MockAuditLogRepository, done chan *domain.PayrollRun) {
+				periodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(&domain.PayrollPeriod{BaseModel: domain.BaseModel{ID: periodID}}, nil)
+				payrollSvc.EXPECT().CheckPendingItems(gomock.Any()).Return(nil)
+				runRepo.EXPECT().GetActivePayrollRunByPeriodID(periodID).Return(nil, nil)
+				runRepo.EXPECT().CreatePayrollRun(gomock.Any()).Return(repository.ErrDuplicateRecord)
+			},
+			wantErr: service.ErrPayrollRunInProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			runRepo := mockrepo.NewMockPayrollRunRepository(ctrl)
+			periodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
+			payrollSvc := mocksvc.NewMockPayrollServiceInterface(ctrl)
+			auditRepo := mockrepo.NewMockAuditLogRepository(ctrl)
+			done := make(chan *domain.PayrollRun, 1)
+
+			tt.mockSetup(runRepo, periodRepo, payrollSvc, auditRepo, done)
+
+			svc := service.NewPayrollRunService(runRepo, periodRepo, payrollSvc, auditRepo)
+			run, err := svc.StartPayrollRun(periodID, adminID, "127.0.0.1", "req-1")
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, domain.PayrollRunStatusQueued, run.Status)
+
+			if tt.waitForDone {
+				select {
+				case finished := <-done:
+					assert.Equal(t, tt.wantStatus, finished.Status)
+					assert.NotNil(t, finished.FinishedAt)
+					assert.Equal(t, tt.wantRunError, finished.Error)
+				case <-time.After(2 * time.Second):
+					t.Fatal("payroll run did not finish")
+				}
+			}
+		})
+	}
+}
@@ -2,21 +2,50 @@ package service
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 
 	"payroll-system/internal/domain"
 	"payroll-system/internal/repository"
 )
 
+// ErrPayslipNotFound is returned when a payslip is requested that does not exist, including one for a
+// payroll period that does not exist or has not been processed yet.
+var ErrPayslipNotFound = errors.New("payslip not found")
+
 // PayslipServiceInterface defines the methods of PayslipService for mocking purposes.
 //
 //go:generate mockgen -source=payslip.service.go -destination=../../tests/mocks/service/mock_payslip_service.go -package=mocks
 type PayslipServiceInterface interface {
 	// GetEmployeePayslip retrieves a payslip for a specific employee and payroll period.
 	GetEmployeePayslip(userID, periodID uuid.UUID) (*domain.Payslip, error)
+	// GetPayslip retrieves any payslip by its ID, with the same details as GetEmployeePayslip.
+	GetPayslip(id uuid.UUID) (*domain.Payslip, error)
+	// GetPayslipHistory returns a page of an employee's payslips and how many match the filter.
+	GetPayslipHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Payslip, int64, error)
 	// GetPayslipSummaryForPeriod retrieves a summary of all payslips for a given payroll period.
-	GetPayslipSummaryForPeriod(periodID uuid.UUID) ([]domain.Payslip, float64, error)
+	GetPayslipSummaryForPeriod(periodID uuid.UUID) (*PayslipSummary, error)
+}
+
+// PayslipComponentTotal is the total of one payslip item code over all payslips of a period.
+type PayslipComponentTotal struct {
+	ComponentType string
+	Code          string
+	Name          string
+	Amount        decimal.Decimal
+}
+
+// PayslipSummary holds the payslips of a processed payroll period together with the period totals.
+type PayslipSummary struct {
+	Payslips                   []domain.Payslip
+	TotalGrossEarnings         decimal.Decimal
+	TotalDeductions            decimal.Decimal
+	TotalEmployerContributions decimal.Decimal
+	TotalTakeHomePay           decimal.Decimal
+	TotalEmployerCost          decimal.Decimal         // Gross earnings plus employer contributions
+	Components                 []PayslipComponentTotal // Totals per item code, in the order the codes first appear
 }
 
 // PayslipService provides business logic for payslip generation.
@@ -25,6 +54,8 @@ type PayslipService struct {
 	payslipPeriodRepo repository.PayrollPeriodRepository
 	attendanceRepo    repository.AttendanceRepository
 	overtimeRepo      repository.OvertimeRepository
+	holidayRepo       repository.HolidayRepository
+	workScheduleRepo  repository.WorkScheduleRepository
 }
 
 // NewPayslipService creates a new PayslipService.
@@ -33,26 +64,31 @@ func NewPayslipService(
 	payslipPeriodRepo repository.PayrollPeriodRepository,
 	attendanceRepo repository.AttendanceRepository,
 	overtimeRepo repository.OvertimeRepository,
+	holidayRepo repository.HolidayRepository,
+	workScheduleRepo repository.WorkScheduleRepository,
 ) *PayslipService {
 	return &PayslipService{
 		payslipRepo:       payslipRepo,
 		payslipPeriodRepo: payslipPeriodRepo,
 		attendanceRepo:    attendanceRepo,
 		overtimeRepo:      overtimeRepo,
+		holidayRepo:       holidayRepo,
+		workScheduleRepo:  workScheduleRepo,
 	}
 }
 
-// GetEmployeePayslip retrieves a payslip for a specific employee and payroll period.
+// GetEmployeePayslip retrieves a payslip for a specific employee and payroll period. It returns
+// ErrPayslipNotFound if the period does not exist or is not processed, or the employee has no payslip in it.
 func (s *PayslipService) GetEmployeePayslip(userID, periodID uuid.UUID) (*domain.Payslip, error) {
 	period, err := s.payslipPeriodRepo.GetPayrollPeriodByID(periodID)
 	if err != nil {
 		return nil, err
 	}
 	if period == nil {
-		return nil, errors.New("payroll period not found")
+		return nil, fmt.Errorf("%w: payroll period not found", ErrPayslipNotFound)
 	}
 	if !period.IsProcessed {
-		return nil, errors.New("payslip can only be generated for processed payroll periods")
+		return nil, fmt.Errorf("%w: payslip can only be generated for processed payroll periods", ErrPayslipNotFound)
 	}
 
 	payslip, err := s.payslipRepo.GetPayslipByUserIDAndPeriodID(userID, periodID)
@@ -60,66 +96,151 @@ func (s *PayslipService) GetEmployeePayslip(userID, periodID uuid.UUID) (*domain
 		return nil, err
 	}
 	if payslip == nil {
-		return nil, errors.New("payslip not found for this user and period")
+		return nil, fmt.Errorf("%w: no payslip for this user and period", ErrPayslipNotFound)
 	}
 
-	// Attach related data
-	payslip.PayrollPeriod = *period
+	if err := s.attachPayslipDetails(payslip, period); err != nil {
+		return nil, err
+	}
+	return payslip, nil
+}
+
+// GetPayslip retrieves a payslip by its ID with its user, payroll period, attendances, overtimes and
+// work calendar, for admins to view any employee's payslip. It returns nil if there is no such payslip,
+// and ErrPayslipNotFound if its payroll period no longer exists.
+func (s *PayslipService) GetPayslip(id uuid.UUID) (*domain.Payslip, error) {
+	payslip, err := s.payslipRepo.GetPayslipByID(id)
+	if err != nil || payslip == nil {
+		return nil, err
+	}
 
-	attendances, err := s.attendanceRepo.GetAttendancesByUserIDAndPayrollPeriodID(userID, periodID)
+	period, err := s.payslipPeriodRepo.GetPayrollPeriodByID(payslip.PayrollPeriodID)
 	if err != nil {
 		return nil, err
 	}
+	if period == nil {
+		return nil, fmt.Errorf("%w: payroll period not found", ErrPayslipNotFound)
+	}
+
+	if err := s.attachPayslipDetails(payslip, period); err != nil {
+		return nil, err
+	}
+	return payslip, nil
+}
+
+// attachPayslipDetails attaches the payroll period of a payslip and the attendances, overtimes and
+// work calendar the payslip breakdown is calculated from.
+func (s *PayslipService) attachPayslipDetails(payslip *domain.Payslip, period *domain.PayrollPeriod) error {
+	payslip.PayrollPeriod = *period
+
+	attendances, err := s.attendanceRepo.GetAttendancesByUserIDAndPayrollPeriodID(payslip.UserID, period.ID)
+	if err != nil {
+		return err
+	}
 	payslip.Attendances = attendances
 
-	overtimes, err := s.overtimeRepo.GetOvertimesByUserIDAndPayrollPeriodID(userID, periodID)
+	overtimes, err := s.overtimeRepo.GetOvertimesByUserIDAndPayrollPeriodID(payslip.UserID, period.ID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	payslip.Overtimes = overtimes
 
-	return payslip, nil
+	calendar, err := loadWorkCalendar(s.holidayRepo, period.StartDate, period.EndDate)
+	if err != nil {
+		return err
+	}
+	schedule, err := s.workScheduleRepo.GetWorkScheduleByUserID(payslip.UserID)
+	if err != nil {
+		return err
+	}
+	payslip.WorkCalendar = calendar.WithSchedule(schedule)
+
+	return nil
+}
+
+// GetPayslipHistory returns a page of an employee's payslips with their payroll period, latest period
+// first, and how many payslips match the filter. Payslips exist only for processed periods, so it
+// lists every processed period the employee was paid in.
+func (s *PayslipService) GetPayslipHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Payslip, int64, error) {
+	return s.payslipRepo.GetPayslipHistory(userID, filter)
 }
 
-// GetPayslipSummaryForPeriod retrieves a summary of all payslips for a given payroll period.
-func (s *PayslipService) GetPayslipSummaryForPeriod(periodID uuid.UUID) ([]domain.Payslip, float64, error) {
+// GetPayslipSummaryForPeriod retrieves a summary of all payslips for a given payroll period,
+// with the period totals of take-home pay, deductions, employer contributions and every pay component.
+func (s *PayslipService) GetPayslipSummaryForPeriod(periodID uuid.UUID) (*PayslipSummary, error) {
 	period, err := s.payslipPeriodRepo.GetPayrollPeriodByID(periodID)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	if period == nil {
-		return nil, 0, errors.New("payroll period not found")
+		return nil, errors.New("payroll period not found")
 	}
 	if !period.IsProcessed {
-		return nil, 0, errors.New("payslip summary can only be generated for processed payroll periods")
+		return nil, errors.New("payslip summary can only be generated for processed payroll periods")
 	}
 
 	payslips, err := s.payslipRepo.GetAllPayslipsByPeriodID(periodID)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	var totalTakeHomePay float64
-	resultPayslips := make([]domain.Payslip, 0, len(payslips))
+	// Load the related records of every payslip at once instead of per payslip
+	attendances, err := s.attendanceRepo.GetAttendancesByPayrollPeriodIDGroupedByUser(periodID)
+	if err != nil {
+		return nil, err
+	}
+	overtimes, err := s.overtimeRepo.GetOvertimesByPayrollPeriodIDGroupedByUser(periodID)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := loadWorkCalendar(s.holidayRepo, period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.workScheduleRepo.GetAssignedWorkSchedules()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &PayslipSummary{
+		Payslips:                   make([]domain.Payslip, 0, len(payslips)),
+		TotalGrossEarnings:         decimal.Zero,
+		TotalDeductions:            decimal.Zero,
+		TotalEmployerContributions: decimal.Zero,
+		TotalTakeHomePay:           decimal.Zero,
+		TotalEmployerCost:          decimal.Zero,
+	}
+	componentIndex := make(map[string]int)
 
 	for _, p := range payslips {
 		p.PayrollPeriod = *period
-
-		attendances, err := s.attendanceRepo.GetAttendancesByUserIDAndPayrollPeriodID(p.UserID, periodID)
-		if err != nil {
-			return nil, 0, err
-		}
-		p.Attendances = attendances
-
-		overtimes, err := s.overtimeRepo.GetOvertimesByUserIDAndPayrollPeriodID(p.UserID, periodID)
-		if err != nil {
-			return nil, 0, err
+		p.Attendances = attendances[p.UserID]
+		p.Overtimes = overtimes[p.UserID]
+		p.WorkCalendar = calendar.WithSchedule(schedules[p.UserID])
+
+		summary.TotalGrossEarnings = summary.TotalGrossEarnings.Add(p.GrossEarnings)
+		summary.TotalDeductions = summary.TotalDeductions.Add(p.TotalDeductions)
+		summary.TotalEmployerContributions = summary.TotalEmployerContributions.Add(p.TotalEmployerContributions)
+		summary.TotalTakeHomePay = summary.TotalTakeHomePay.Add(p.TotalTakeHomePay)
+		summary.TotalEmployerCost = summary.TotalEmployerCost.Add(p.GrossEarnings).Add(p.TotalEmployerContributions)
+
+		for _, item := range p.Items {
+			i, ok := componentIndex[item.Code]
+			if !ok {
+				i = len(summary.Components)
+				componentIndex[item.Code] = i
+				summary.Components = append(summary.Components, PayslipComponentTotal{
+					ComponentType: item.ComponentType,
+					Code:          item.Code,
+					Name:          item.Name,
+					Amount:        decimal.Zero,
+				})
+			}
+			summary.Components[i].Amount = summary.Components[i].Amount.Add(item.Amount)
 		}
-		p.Overtimes = overtimes
 
-		totalTakeHomePay += p.TotalTakeHomePay
-		resultPayslips = append(resultPayslips, p)
+		summary.Payslips = append(summary.Payslips, p)
 	}
 
-	return resultPayslips, totalTakeHomePay, nil
+	return summary, nil
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
@@ -21,8 +22,10 @@ func TestPayslipService_GetEmployeePayslip(t *testing.T) {
 	mockPeriodRepo := mockRepo.NewMockPayrollPeriodRepository(ctrl)
 	mockAttendanceRepo := mockRepo.NewMockAttendanceRepository(ctrl)
 	mockOvertimeRepo := mockRepo.NewMockOvertimeRepository(ctrl)
+	mockHolidayRepo := mockRepo.NewMockHolidayRepository(ctrl)
+	mockWorkScheduleRepo := mockRepo.NewMockWorkScheduleRepository(ctrl)
 
-	svc := service.NewPayslipService(mockPayslipRepo, mockPeriodRepo, mockAttendanceRepo, mockOvertimeRepo)
+	svc := service.NewPayslipService(mockPayslipRepo, mockPeriodRepo, mockAttendanceRepo, mockOvertimeRepo, mockHolidayRepo, mockWorkScheduleRepo)
 
 	userID := uuid.New()
 	periodID := uuid.New()
@@ -36,11 +39,13 @@ func TestPayslipService_GetEmployeePayslip(t *testing.T) {
 			name: "success",
 			setupMocks: func() {
 				period := &domain.PayrollPeriod{BaseModel: domain.BaseModel{ID: periodID}, IsProcessed: true}
-				payslip := &domain.Payslip{UserID: userID, PayrollPeriodID: periodID, TotalTakeHomePay: 1000}
+				payslip := &domain.Payslip{UserID: userID, PayrollPeriodID: periodID, TotalTakeHomePay: decimal.NewFromInt(1000)}
 				mockPeriodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(period, nil)
 				mockPayslipRepo.EXPECT().GetPayslipByUserIDAndPeriodID(userID, periodID).Return(payslip, nil)
 				mockAttendanceRepo.EXPECT().GetAttendancesByUserIDAndPayrollPeriodID(userID, periodID).Return(nil, nil)
 				mockOvertimeRepo.EXPECT().GetOvertimesByUserIDAndPayrollPeriodID(userID, periodID).Return(nil, nil)
+				mockHolidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return(nil, nil)
+				mockWorkScheduleRepo.EXPECT().GetWorkScheduleByUserID(userID).Return(nil, nil)
 			},
 			expectErr: "",
 		},
@@ -49,14 +54,14 @@ func TestPayslipService_GetEmployeePayslip(t *testing.T) {
 			setupMocks: func() {
 				mockPeriodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(nil, nil)
 			},
-			expectErr: "payroll period not found",
+			expectErr: "payslip not found: payroll period not found",
 		},
 		{
 			name: "payroll not processed",
 			setupMocks: func() {
 				mockPeriodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(&domain.PayrollPeriod{IsProcessed: false}, nil)
 			},
-			expectErr: "payslip can only be generated for processed payroll periods",
+			expectErr: "payslip not found: payslip can only be generated for processed payroll periods",
 		},
 		{
 			name: "payslip not found",
@@ -64,7 +69,7 @@ func TestPayslipService_GetEmployeePayslip(t *testing.T) {
 				mockPeriodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(&domain.PayrollPeriod{IsProcessed: true}, nil)
 				mockPayslipRepo.EXPECT().GetPayslipByUserIDAndPeriodID(userID, periodID).Return(nil, nil)
 			},
-			expectErr: "payslip not found for this user and period",
+			expectErr: "payslip not found: no payslip for this user and period",
 		},
 		{
 			name: "repo error",
@@ -91,6 +96,88 @@ func TestPayslipService_GetEmployeePayslip(t *testing.T) {
 	}
 }
 
+func TestPayslipService_GetPayslip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPayslipRepo := mockRepo.NewMockPayslipRepository(ctrl)
+	mockPeriodRepo := mockRepo.NewMockPayrollPeriodRepository(ctrl)
+	mockAttendanceRepo := mockRepo.NewMockAttendanceRepository(ctrl)
+	mockOvertimeRepo := mockRepo.NewMockOvertimeRepository(ctrl)
+	mockHolidayRepo := mockRepo.NewMockHolidayRepository(ctrl)
+	mockWorkScheduleRepo := mockRepo.NewMockWorkScheduleRepository(ctrl)
+
+	svc := service.NewPayslipService(mockPayslipRepo, mockPeriodRepo, mockAttendanceRepo, mockOvertimeRepo, mockHolidayRepo, mockWorkScheduleRepo)
+
+	payslipID := uuid.New()
+	userID := uuid.New()
+	periodID := uuid.New()
+
+	tests := []struct {
+		name       string
+		setupMocks func()
+		expectNil  bool
+		expectErr  string
+	}{
+		{
+			name: "success",
+			setupMocks: func() {
+				period := &domain.PayrollPeriod{BaseModel: domain.BaseModel{ID: periodID}, IsProcessed: true}
+				payslip := &domain.Payslip{BaseModel: domain.BaseModel{ID: payslipID}, UserID: userID, PayrollPeriodID: periodID}
+				attendances := []*domain.Attendance{{UserID: userID}}
+				mockPayslipRepo.EXPECT().GetPayslipByID(payslipID).Return(payslip, nil)
+				mockPeriodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(period, nil)
+				mockAttendanceRepo.EXPECT().GetAttendancesByUserIDAndPayrollPeriodID(userID, periodID).Return(attendances, nil)
+				mockOvertimeRepo.EXPECT().GetOvertimesByUserIDAndPayrollPeriodID(userID, periodID).Return(nil, nil)
+				mockHolidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return(nil, nil)
+				mockWorkScheduleRepo.EXPECT().GetWorkScheduleByUserID(userID).Return(nil, nil)
+			},
+		},
+		{
+			name: "payslip not found",
+			setupMocks: func() {
+				mockPayslipRepo.EXPECT().GetPayslipByID(payslipID).Return(nil, nil)
+			},
+			expectNil: true,
+		},
+		{
+			name: "payroll period not found",
+			setupMocks: func() {
+				mockPayslipRepo.EXPECT().GetPayslipByID(payslipID).Return(&domain.Payslip{UserID: userID, PayrollPeriodID: periodID}, nil)
+				mockPeriodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(nil, nil)
+			},
+			expectErr: "payslip not found: payroll period not found",
+		},
+		{
+			name: "repo error",
+			setupMocks: func() {
+				mockPayslipRepo.EXPECT().GetPayslipByID(payslipID).Return(nil, errors.New("db error"))
+			},
+			expectErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+			payslip, err := svc.GetPayslip(payslipID)
+			if tt.expectErr != "" {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectErr, err.Error())
+				assert.Nil(t, payslip)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.expectNil {
+				assert.Nil(t, payslip)
+				return
+			}
+			assert.Equal(t, periodID, payslip.PayrollPeriod.ID)
+			assert.Len(t, payslip.Attendances, 1)
+		})
+	}
+}
+
 func TestPayslipService_GetPayslipSummaryForPeriod(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -99,11 +186,14 @@ func TestPayslipService_GetPayslipSummaryForPeriod(t *testing.T) {
 	mockPeriodRepo := mockRepo.NewMockPayrollPeriodRepository(ctrl)
 	mockAttendanceRepo := mockRepo.NewMockAttendanceRepository(ctrl)
 	mockOvertimeRepo := mockRepo.NewMockOvertimeRepository(ctrl)
+	mockHolidayRepo := mockRepo.NewMockHolidayRepository(ctrl)
+	mockWorkScheduleRepo := mockRepo.NewMockWorkScheduleRepository(ctrl)
 
-	svc := service.NewPayslipService(mockPayslipRepo, mockPeriodRepo, mockAttendanceRepo, mockOvertimeRepo)
+	svc := service.NewPayslipService(mockPayslipRepo, mockPeriodRepo, mockAttendanceRepo, mockOvertimeRepo, mockHolidayRepo, mockWorkScheduleRepo)
 
 	periodID := uuid.New()
 	userID := uuid.New()
+	otherUserID := uuid.New()
 
 	tests := []struct {
 		name       string
@@ -115,12 +205,27 @@ func TestPayslipService_GetPayslipSummaryForPeriod(t *testing.T) {
 			setupMocks: func() {
 				period := &domain.PayrollPeriod{BaseModel: domain.BaseModel{ID: periodID}, IsProcessed: true}
 				payslips := []domain.Payslip{
-					{UserID: userID, PayrollPeriodID: periodID, TotalTakeHomePay: 1000},
+					{UserID: userID, PayrollPeriodID: periodID, GrossEarnings: decimal.NewFromInt(650), TotalDeductions: decimal.NewFromInt(50), TotalTakeHomePay: decimal.NewFromInt(600), TotalEmployerContributions: decimal.NewFromInt(30),
+						Items: []domain.PayslipItem{
+							{ComponentType: domain.PayslipComponentEarning, Code: service.PayslipItemCodeBasicSalary, Amount: decimal.NewFromInt(650)},
+							{ComponentType: domain.PayslipComponentDeduction, Code: service.PayslipItemCodePPh21, Amount: decimal.NewFromInt(50)},
+							{ComponentType: domain.PayslipComponentEmployerContribution, Code: service.PayslipItemCodeBPJSJKK, Amount: decimal.NewFromInt(30)},
+						}},
+					{UserID: otherUserID, PayrollPeriodID: periodID, GrossEarnings: decimal.NewFromInt(420), TotalDeductions: decimal.NewFromInt(20), TotalTakeHomePay: decimal.NewFromInt(400),
+						Items: []domain.PayslipItem{
+							{ComponentType: domain.PayslipComponentEarning, Code: service.PayslipItemCodeBasicSalary, Amount: decimal.NewFromInt(420)},
+							{ComponentType: domain.PayslipComponentDeduction, Code: service.PayslipItemCodePPh21, Amount: decimal.NewFromInt(20)},
+						}},
 				}
 				mockPeriodRepo.EXPECT().GetPayrollPeriodByID(periodID).Return(period, nil)
 				mockPayslipRepo.EXPECT().GetAllPayslipsByPeriodID(periodID).Return(payslips, nil)
-				mockAttendanceRepo.EXPECT().GetAttendancesByUserIDAndPayrollPeriodID(userID, periodID).Return(nil, nil)
-				mockOvertimeRepo.EXPECT().GetOvertimesByUserIDAndPayrollPeriodID(userID, periodID).Return(nil, nil)
+				// Related records are loaded once for the whole period, not per payslip
+				mockAttendanceRepo.EXPECT().GetAttendancesByPayrollPeriodIDGroupedByUser(periodID).
+					Return(map[uuid.UUID][]*domain.Attendance{userID: {{UserID: userID}}}, nil).Times(1)
+				mockOvertimeRepo.EXPECT().GetOvertimesByPayrollPeriodIDGroupedByUser(periodID).
+					Return(map[uuid.UUID][]*domain.Overtime{otherUserID: {{UserID: otherUserID}}}, nil).Times(1)
+				mockHolidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return(nil, nil).Times(1)
+				mockWorkScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil).Times(1)
 			},
 			expectErr: "",
 		},
@@ -150,16 +255,32 @@ func TestPayslipService_GetPayslipSummaryForPeriod(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMocks()
-			payslips, total, err := svc.GetPayslipSummaryForPeriod(periodID)
+			summary, err := svc.GetPayslipSummaryForPeriod(periodID)
 			if tt.expectErr != "" {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectErr, err.Error())
-				assert.Nil(t, payslips)
-				assert.Equal(t, float64(0), total)
+				assert.Nil(t, summary)
 			} else {
 				assert.NoError(t, err)
-				assert.NotNil(t, payslips)
-				assert.Equal(t, 1000.0, total)
+				payslips := summary.Payslips
+				assert.Len(t, payslips, 2)
+				assert.Len(t, payslips[0].Attendances, 1)
+				assert.Empty(t, payslips[0].Overtimes)
+				assert.Empty(t, payslips[1].Attendances)
+				assert.Len(t, payslips[1].Overtimes, 1)
+				assert.True(t, decimal.NewFromInt(1000).Equal(summary.TotalTakeHomePay))
+				assert.True(t, decimal.NewFromInt(1070).Equal(summary.TotalGrossEarnings))
+				assert.True(t, decimal.NewFromInt(70).Equal(summary.TotalDeductions))
+				assert.True(t, decimal.NewFromInt(30).Equal(summary.TotalEmployerContributions))
+				// Employer cost is the gross earnings plus the employer contributions
+				assert.True(t, decimal.NewFromInt(1100).Equal(summary.TotalEmployerCost))
+
+				// Tax and the other components are totalled per item code
+				assert.Equal(t, []service.PayslipComponentTotal{
+					{ComponentType: domain.PayslipComponentEarning, Code: service.PayslipItemCodeBasicSalary, Amount: decimal.NewFromInt(1070)},
+					{ComponentType: domain.PayslipComponentDeduction, Code: service.PayslipItemCodePPh21, Amount: decimal.NewFromInt(70)},
+					{ComponentType: domain.PayslipComponentEmployerContribution, Code: service.PayslipItemCodeBPJSJKK, Amount: decimal.NewFromInt(30)},
+				}, summary.Components)
 			}
 		})
 	}
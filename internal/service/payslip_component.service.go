@@ -0,0 +1,266 @@
+package service
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+)
+
+// Payslip item codes of the built-in pay components.
+const (
+	PayslipItemCodeBasicSalary   = "BASIC_SALARY"
+	PayslipItemCodeOvertime      = "OVERTIME"
+	PayslipItemCodeReimbursement = "REIMBURSEMENT"
+	PayslipItemCodeUnpaidLeave   = "UNPAID_LEAVE"
+)
+
+// SalarySegment is a part of a payroll period during which one salary was in force, with the
+// hours worked in it.
+type SalarySegment struct {
+	EffectiveFrom        time.Time       // First day of the segment
+	Salary               decimal.Decimal // Monthly salary in force
+	HourlyRate           decimal.Decimal // Salary divided by the scheduled working hours of the whole period
+	WorkedHours          decimal.Decimal // Paid attendance and leave hours, unpaid leave included
+	UnpaidLeaveHours     decimal.Decimal // Hours of unpaid leave, deducted from the basic salary
+	OvertimeHours        decimal.Decimal // Overtime worked on working days
+	RestDayOvertimeHours decimal.Decimal // Overtime worked on weekends and holidays
+}
+
+// PayslipCalculation carries the inputs of a single payslip and the lines calculated so far.
+// It is passed to every registered PayslipComponentCalculator in order, so a calculator can base
+// its lines on the ones produced before it (e.g. a deduction on the taxable earnings).
+type PayslipCalculation struct {
+	Profile          domain.EmployeeProfile
+	Period           *domain.PayrollPeriod
+	Attendances      []domain.Attendance
+	Overtimes        []domain.Overtime
+	Reimbursements   []domain.Reimbursement
+	YearToDate       []domain.Payslip // Earlier payslips of the employee in the tax year; only loaded for periods ending in December
+	WorkedHours      decimal.Decimal  // Paid attendance and leave hours within the period
+	UnpaidLeaveHours decimal.Decimal  // Hours of unpaid leave within the period, included in WorkedHours
+	WorkingHours     decimal.Decimal  // Scheduled working hours of the period
+	Calendar         domain.WorkCalendar
+	HourlyRate       decimal.Decimal // Base salary divided by WorkingHours, kept at full precision
+	SalarySegments   []SalarySegment // One segment per salary in force during the period; when empty the period is one segment paid at HourlyRate
+	Rounding         RoundingPolicy
+	Items            []domain.PayslipItem
+}
+
+// Total returns the sum of the lines of a component type calculated so far.
+func (c *PayslipCalculation) Total(componentType string) decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range c.Items {
+		if item.ComponentType == componentType {
+			total = total.Add(item.Amount)
+		}
+	}
+	return total
+}
+
+// Amount returns the sum of the lines with the given code calculated so far.
+func (c *PayslipCalculation) Amount(code string) decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range c.Items {
+		if item.Code == code {
+			total = total.Add(item.Amount)
+		}
+	}
+	return total
+}
+
+// Segments returns the salary segments of the period. Without SalarySegments the whole period is a
+// single segment of WorkedHours and the overtime hours, paid at HourlyRate.
+func (c *PayslipCalculation) Segments() []SalarySegment {
+	if len(c.SalarySegments) > 0 {
+		return c.SalarySegments
+	}
+	segment := SalarySegment{
+		Salary:               c.Profile.Salary,
+		HourlyRate:           c.HourlyRate,
+		WorkedHours:          c.WorkedHours,
+		UnpaidLeaveHours:     c.UnpaidLeaveHours,
+		OvertimeHours:        decimal.Zero,
+		RestDayOvertimeHours: decimal.Zero,
+	}
+	for _, ot := range c.Overtimes {
+		segment.addOvertime(c.Calendar, ot)
+	}
+	if c.Period != nil {
+		segment.EffectiveFrom = c.Period.StartDate
+	}
+	return []SalarySegment{segment}
+}
+
+// addOvertime adds the hours of an overtime record to the segment's working day or rest day overtime.
+func (s *SalarySegment) addOvertime(calendar domain.WorkCalendar, ot domain.Overtime) {
+	hours := decimal.NewFromFloat(ot.Hours)
+	if calendar.IsWorkingDay(ot.Date) {
+		s.OvertimeHours = s.OvertimeHours.Add(hours)
+	} else {
+		s.RestDayOvertimeHours = s.RestDayOvertimeHours.Add(hours)
+	}
+}
+
+// PayslipComponentCalculator calculates the lines of one pay component of a payslip.
+type PayslipComponentCalculator interface {
+	// Calculate returns the payslip lines of the component. It must not modify calc; the
+	// registry appends the returned lines to calc.Items.
+	Calculate(calc *PayslipCalculation) []domain.PayslipItem
+}
+
+// PayslipComponentRegistry holds the calculators that are run, in registration order, for every payslip.
+type PayslipComponentRegistry struct {
+	calculators []PayslipComponentCalculator
+}
+
+// NewPayslipComponentRegistry creates a registry running the given calculators in order.
+func NewPayslipComponentRegistry(calculators ...PayslipComponentCalculator) *PayslipComponentRegistry {
+	return &PayslipComponentRegistry{calculators: calculators}
+}
+
+// DefaultPayslipComponentRegistry returns the registry with the built-in components:
+// basic salary, unpaid leave, overtime, reimbursements, BPJS contributions at the given rates and
+// PPh 21 income tax.
+func DefaultPayslipComponentRegistry(bpjsRates BPJSRates) *PayslipComponentRegistry {
+	return NewPayslipComponentRegistry(
+		BasicSalaryCalculator{},
+		UnpaidLeaveCalculator{},
+		OvertimeCalculator{},
+		ReimbursementCalculator{},
+		BPJSCalculator{Rates: bpjsRates},
+		PPh21Calculator{},
+	)
+}
+
+// Register appends a calculator; it runs after every calculator registered before it.
+func (r *PayslipComponentRegistry) Register(calculator PayslipComponentCalculator) {
+	r.calculators = append(r.calculators, calculator)
+}
+
+// Calculate runs every registered calculator and returns all payslip lines, numbered in the
+// order in which they were calculated.
+func (r *PayslipComponentRegistry) Calculate(calc *PayslipCalculation) []domain.PayslipItem {
+	for _, calculator := range r.calculators {
+		for _, item := range calculator.Calculate(calc) {
+			item.Sequence = len(calc.Items) + 1
+			calc.Items = append(calc.Items, item)
+		}
+	}
+	return calc.Items
+}
+
+// segmentName returns the line name of a salary segment; when the period has several segments the
+// name says from which day the segment's salary is in force.
+func segmentName(name string, segment SalarySegment, segments int) string {
+	if segments == 1 {
+		return name
+	}
+	return name + " (from " + segment.EffectiveFrom.Format("2006-01-02") + ")"
+}
+
+// BasicSalaryCalculator pays the base salary prorated by the hours worked in the period.
+type BasicSalaryCalculator struct{}
+
+// Calculate returns the prorated basic salary line, or one line per salary segment when the
+// salary changed during the period.
+func (BasicSalaryCalculator) Calculate(calc *PayslipCalculation) []domain.PayslipItem {
+	segments := calc.Segments()
+	items := make([]domain.PayslipItem, 0, len(segments))
+	for _, segment := range segments {
+		items = append(items, domain.PayslipItem{
+			ComponentType: domain.PayslipComponentEarning,
+			Code:          PayslipItemCodeBasicSalary,
+			Name:          segmentName("Basic Salary", segment, len(segments)),
+			Quantity:      segment.WorkedHours,
+			Rate:          segment.HourlyRate,
+			Amount:        calc.Rounding.RoundLine(segment.HourlyRate.Mul(segment.WorkedHours)),
+			Taxable:       true,
+		})
+	}
+	return items
+}
+
+// UnpaidLeaveCalculator deducts the hours of unpaid leave, which BasicSalaryCalculator pays with the
+// worked hours, at the hourly rate. The deduction takes back taxable earnings, so it is taxable:
+// it lowers the income PPh 21 is calculated on.
+type UnpaidLeaveCalculator struct{}
+
+// Calculate returns the unpaid leave line of every salary segment with unpaid leave.
+func (UnpaidLeaveCalculator) Calculate(calc *PayslipCalculation) []domain.PayslipItem {
+	segments := calc.Segments()
+	var items []domain.PayslipItem
+	for _, segment := range segments {
+		if segment.UnpaidLeaveHours.IsZero() {
+			continue
+		}
+		items = append(items, domain.PayslipItem{
+			ComponentType: domain.PayslipComponentDeduction,
+			Code:          PayslipItemCodeUnpaidLeave,
+			Name:          segmentName("Unpaid Leave", segment, len(segments)),
+			Quantity:      segment.UnpaidLeaveHours,
+			Rate:          segment.HourlyRate,
+			Amount:        calc.Rounding.RoundLine(segment.HourlyRate.Mul(segment.UnpaidLeaveHours)),
+			Taxable:       true,
+		})
+	}
+	return items
+}
+
+// OvertimeCalculator pays overtime hours on working days at OvertimeMultiplier times the hourly
+// rate, and overtime hours on weekends and holidays at RestDayOvertimeMultiplier times the hourly rate.
+type OvertimeCalculator struct{}
+
+// Calculate returns the overtime lines of every salary segment in which overtime was worked: one
+// for working days and one for rest days.
+func (OvertimeCalculator) Calculate(calc *PayslipCalculation) []domain.PayslipItem {
+	segments := calc.Segments()
+	var items []domain.PayslipItem
+	for _, segment := range segments {
+		lines := []struct {
+			name       string
+			hours      decimal.Decimal
+			multiplier float64
+		}{
+			{"Overtime", segment.OvertimeHours, OvertimeMultiplier},
+			{"Overtime (rest day)", segment.RestDayOvertimeHours, RestDayOvertimeMultiplier},
+		}
+		for _, line := range lines {
+			if line.hours.IsZero() {
+				continue
+			}
+			rate := segment.HourlyRate.Mul(decimal.NewFromFloat(line.multiplier))
+			items = append(items, domain.PayslipItem{
+				ComponentType: domain.PayslipComponentEarning,
+				Code:          PayslipItemCodeOvertime,
+				Name:          segmentName(line.name, segment, len(segments)),
+				Quantity:      line.hours,
+				Rate:          rate,
+				Amount:        calc.Rounding.RoundLine(line.hours.Mul(rate)),
+				Taxable:       true,
+			})
+		}
+	}
+	return items
+}
+
+// ReimbursementCalculator pays back the approved amount of every reimbursement approved in the
+// period. Reimbursements are not income and are therefore not taxable.
+type ReimbursementCalculator struct{}
+
+// Calculate returns one line per reimbursement.
+func (ReimbursementCalculator) Calculate(calc *PayslipCalculation) []domain.PayslipItem {
+	items := make([]domain.PayslipItem, 0, len(calc.Reimbursements))
+	for _, reimb := range calc.Reimbursements {
+		items = append(items, domain.PayslipItem{
+			ComponentType: domain.PayslipComponentEarning,
+			Code:          PayslipItemCodeReimbursement,
+			Name:          "Reimbursement",
+			Quantity:      decimal.NewFromInt(1),
+			Rate:          reimb.PaidAmount(),
+			Amount:        calc.Rounding.RoundLine(reimb.PaidAmount()),
+		})
+	}
+	return items
+}
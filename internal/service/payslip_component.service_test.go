@@ -0,0 +1,144 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+	mockrepo "payroll-system/tests/mocks/repository"
+)
+
+// fixedCalculator returns a single line with a fixed amount.
+type fixedCalculator struct {
+	componentType string
+	code          string
+	amount        int64
+}
+
+func (c fixedCalculator) Calculate(_ *service.PayslipCalculation) []domain.PayslipItem {
+	return []domain.PayslipItem{{
+		ComponentType: c.componentType,
+		Code:          c.code,
+		Quantity:      decimal.NewFromInt(1),
+		Rate:          decimal.NewFromInt(c.amount),
+		Amount:        decimal.NewFromInt(c.amount),
+	}}
+}
+
+// percentOfEarningsCalculator deducts a percentage of the earnings calculated before it.
+type percentOfEarningsCalculator struct {
+	code    string
+	percent int64
+}
+
+func (c percentOfEarningsCalculator) Calculate(calc *service.PayslipCalculation) []domain.PayslipItem {
+	base := calc.Total(domain.PayslipComponentEarning)
+	rate := decimal.NewFromInt(c.percent).Div(decimal.NewFromInt(100))
+	return []domain.PayslipItem{{
+		ComponentType: domain.PayslipComponentDeduction,
+		Code:          c.code,
+		Quantity:      base,
+		Rate:          rate,
+		Amount:        calc.Rounding.RoundLine(base.Mul(rate)),
+	}}
+}
+
+func TestPayslipComponentRegistry_Calculate(t *testing.T) {
+	registry := service.NewPayslipComponentRegistry(
+		fixedCalculator{componentType: domain.PayslipComponentEarning, code: "ALLOWANCE", amount: 500},
+	)
+	registry.Register(fixedCalculator{componentType: domain.PayslipComponentEarning, code: "BONUS", amount: 1500})
+	registry.Register(percentOfEarningsCalculator{code: "UNION_FEE", percent: 10})
+	registry.Register(fixedCalculator{componentType: domain.PayslipComponentEmployerContribution, code: "PENSION", amount: 300})
+
+	calc := &service.PayslipCalculation{Rounding: service.DefaultRoundingPolicy()}
+	items := registry.Calculate(calc)
+
+	require.Len(t, items, 4)
+	for i, item := range items {
+		assert.Equal(t, i+1, item.Sequence)
+	}
+	// Later calculators see the lines of earlier ones
+	assert.Equal(t, "200", items[2].Amount.String())
+	assert.Equal(t, "2000", calc.Total(domain.PayslipComponentEarning).String())
+	assert.Equal(t, "200", calc.Total(domain.PayslipComponentDeduction).String())
+	assert.Equal(t, "300", calc.Total(domain.PayslipComponentEmployerContribution).String())
+	assert.Equal(t, "1500", calc.Amount("BONUS").String())
+}
+
+func TestReimbursementCalculator_PaysApprovedAmount(t *testing.T) {
+	partial := decimal.NewFromInt(150000)
+	calc := &service.PayslipCalculation{
+		Rounding: service.DefaultRoundingPolicy(),
+		Reimbursements: []domain.Reimbursement{
+			{Amount: decimal.NewFromInt(200000), Status: domain.ReimbursementStatusApproved, ApprovedAmount: &partial},
+			// Approved before partial approval was introduced
+			{Amount: decimal.NewFromInt(50000), Status: domain.ReimbursementStatusApproved},
+		},
+	}
+
+	items := service.ReimbursementCalculator{}.Calculate(calc)
+
+	require.Len(t, items, 2)
+	assert.Equal(t, "150000", items[0].Amount.String())
+	assert.Equal(t, "50000", items[1].Amount.String())
+}
+
+func TestPreviewPayroll_CustomComponents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	period := &domain.PayrollPeriod{BaseModel: domain.BaseModel{ID: uuid.New()}}
+	userID := uuid.New()
+
+	payrollPeriodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
+	employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+	salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+	attendanceRepo := mockrepo.NewMockAttendanceRepository(ctrl)
+	overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
+	reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+	holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+	workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+	leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
+
+	payrollPeriodRepo.EXPECT().GetPayrollPeriodByID(period.ID).Return(period, nil)
+	salaryHistoryRepo.EXPECT().GetSalaryHistoriesForPeriodGroupedByUser(period.StartDate, period.EndDate).Return(nil, nil)
+	employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(gomock.Any(), gomock.Any()).Return([]domain.EmployeeProfile{{UserID: userID, Salary: decimal.NewFromInt(1000000)}}, nil)
+	attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+	overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+	reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(gomock.Any()).Return(nil, nil)
+	holidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return(nil, nil)
+	workScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil)
+	leaveRepo.EXPECT().GetApprovedLeaveRequestsByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	// A new pay component is added by registering a calculator; the service is unchanged.
+	registry := service.NewPayslipComponentRegistry(
+		fixedCalculator{componentType: domain.PayslipComponentEarning, code: "MEAL_ALLOWANCE", amount: 400000},
+		percentOfEarningsCalculator{code: "UNION_FEE", percent: 5},
+		fixedCalculator{componentType: domain.PayslipComponentEmployerContribution, code: "PENSION", amount: 30000},
+	)
+
+	svc := service.NewPayrollService(
+		mockrepo.NewMockPayslipRepository(ctrl), payrollPeriodRepo, employeeProfileRepo, salaryHistoryRepo, attendanceRepo, overtimeRepo, reimbursementRepo, holidayRepo, workScheduleRepo, leaveRepo,
+		mockrepo.NewMockAuditLogRepository(ctrl), mockrepo.NewMockUnitOfWork(ctrl), service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), registry,
+	)
+
+	preview, err := svc.PreviewPayroll(period.ID)
+	require.NoError(t, err)
+	require.Len(t, preview.Payslips, 1)
+
+	payslip := preview.Payslips[0]
+	require.Len(t, payslip.Items, 3)
+	assert.Equal(t, "400000", payslip.GrossEarnings.String())
+	assert.Equal(t, "20000", payslip.TotalDeductions.String())
+	assert.Equal(t, "30000", payslip.TotalEmployerContributions.String())
+	// Employer contributions do not reduce take-home pay
+	assert.Equal(t, "380000", payslip.TotalTakeHomePay.String())
+	assert.True(t, payslip.ProratedSalary.IsZero())
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"payroll-system/internal/repository"
+)
+
+// ErrPeriodLocked is returned when a record dated in a processed payroll period is created, changed
+// or deleted.
+var ErrPeriodLocked = errors.New("payroll period is locked")
+
+// PeriodLock guards the records a payroll run pays, attendance, overtime, reimbursements and leave,
+// against changes once the payroll period they are dated in is processed: the payslips of the period
+// would no longer match them. Reversing the payroll reopens the period and lifts the lock.
+//
+// The check takes a shared lock on the rows of the payroll periods it reads, so the change it guards
+// must be written in the same transaction: a payroll run cannot process the period between the check
+// and the commit, and a change checked while a run is in progress waits for the run to finish.
+type PeriodLock struct {
+	uow repository.UnitOfWork
+}
+
+// NewPeriodLock creates a new PeriodLock.
+func NewPeriodLock(uow repository.UnitOfWork) *PeriodLock {
+	return &PeriodLock{uow: uow}
+}
+
+// Transaction runs fn within a database transaction after checking, in that transaction, that no
+// calendar day from from through to falls in a processed payroll period. Services write the records
+// dated in those days through the repositories passed to fn.
+func (l *PeriodLock) Transaction(from, to time.Time, fn func(repos *repository.Repositories) error) error {
+	return l.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := l.CheckRange(repos, from, to); err != nil {
+			return err
+		}
+		return fn(repos)
+	})
+}
+
+// Check returns ErrPeriodLocked when the calendar day of date falls in a processed payroll period.
+// Services call it with the effective date of a record before creating, changing or deleting it,
+// within the transaction that writes the record.
+func (l *PeriodLock) Check(repos *repository.Repositories, date time.Time) error {
+	return l.CheckRange(repos, date, date)
+}
+
+// CheckRange returns ErrPeriodLocked when a calendar day from from through to falls in a processed
+// payroll period, for records such as leave that cover a range of dates.
+func (l *PeriodLock) CheckRange(repos *repository.Repositories, from, to time.Time) error {
+	periods, err := repos.PayrollPeriods.LockOverlappingPayrollPeriods(from, to)
+	if err != nil {
+		return err
+	}
+	for _, period := range periods {
+		if !period.IsProcessed {
+			continue
+		}
+		// Report the first day of the range in the period
+		day := from
+		if period.StartDate.Format("2006-01-02") > from.Format("2006-01-02") {
+			day = period.StartDate
+		}
+		return fmt.Errorf("%w: %s falls in the payroll period %s to %s, which has already been processed",
+			ErrPeriodLocked, day.Format("2006-01-02"), period.StartDate.Format("2006-01-02"), period.EndDate.Format("2006-01-02"))
+	}
+	return nil
+}
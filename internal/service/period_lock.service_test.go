@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+	"payroll-system/internal/service"
+	mockRepo "payroll-system/tests/mocks/repository"
+)
+
+// august2025 is a processed payroll period, used by the tests to lock the dates it covers.
+var august2025 = &domain.PayrollPeriod{
+	StartDate:   time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+	EndDate:     time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC),
+	IsProcessed: true,
+}
+
+// expectPeriodLock makes repo lock the dates of the processed period, or no dates if it is nil.
+func expectPeriodLock(repo *mockRepo.MockPayrollPeriodRepository, processed *domain.PayrollPeriod) {
+	repo.EXPECT().LockOverlappingPayrollPeriods(gomock.Any(), gomock.Any()).DoAndReturn(func(from, to time.Time) ([]domain.PayrollPeriod, error) {
+		if processed == nil || to.Before(processed.StartDate) || !from.Before(processed.EndDate.AddDate(0, 0, 1)) {
+			return nil, nil
+		}
+		return []domain.PayrollPeriod{*processed}, nil
+	}).AnyTimes()
+}
+
+// newUnitOfWork returns a UnitOfWork running every transaction on repos, with the dates of the
+// processed period locked, or no dates if it is nil, when repos has no payroll period repository.
+func newUnitOfWork(ctrl *gomock.Controller, processed *domain.PayrollPeriod, repos *repository.Repositories) *mockRepo.MockUnitOfWork {
+	txRepos := *repos
+	if txRepos.PayrollPeriods == nil {
+		payrollPeriodRepo := mockRepo.NewMockPayrollPeriodRepository(ctrl)
+		expectPeriodLock(payrollPeriodRepo, processed)
+		txRepos.PayrollPeriods = payrollPeriodRepo
+	}
+	uow := mockRepo.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(repos *repository.Repositories) error) error {
+		return fn(&txRepos)
+	}).AnyTimes()
+	return uow
+}
+
+// newPeriodLock returns a PeriodLock running its transactions on repos, with the dates of the
+// processed period locked, or no dates if it is nil.
+func newPeriodLock(ctrl *gomock.Controller, processed *domain.PayrollPeriod, repos *repository.Repositories) *service.PeriodLock {
+	return service.NewPeriodLock(newUnitOfWork(ctrl, processed, repos))
+}
+
+func TestPeriodLock_Check(t *testing.T) {
+	date := time.Date(2025, 8, 18, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		mockPeriods   []domain.PayrollPeriod
+		mockError     error
+		expectedError string
+	}{
+		{
+			name: "no period",
+		},
+		{
+			name:        "period not processed",
+			mockPeriods: []domain.PayrollPeriod{{StartDate: august2025.StartDate, EndDate: august2025.EndDate}},
+		},
+		{
+			name:          "processed period",
+			mockPeriods:   []domain.PayrollPeriod{*august2025},
+			expectedError: "payroll period is locked: 2025-08-18 falls in the payroll period 2025-08-01 to 2025-08-31, which has already been processed",
+		},
+		{
+			name:          "repository error",
+			mockError:     errors.New("db error"),
+			expectedError: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockRepo.NewMockPayrollPeriodRepository(ctrl)
+			repo.EXPECT().LockOverlappingPayrollPeriods(date, date).Return(tt.mockPeriods, tt.mockError)
+
+			err := service.NewPeriodLock(mockRepo.NewMockUnitOfWork(ctrl)).Check(&repository.Repositories{PayrollPeriods: repo}, date)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPeriodLock_CheckRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC)
+	july := domain.PayrollPeriod{StartDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)}
+
+	repo := mockRepo.NewMockPayrollPeriodRepository(ctrl)
+	repo.EXPECT().LockOverlappingPayrollPeriods(from, to).Return([]domain.PayrollPeriod{july, *august2025}, nil)
+
+	// The first day of the range in the processed period is reported
+	err := service.NewPeriodLock(mockRepo.NewMockUnitOfWork(ctrl)).CheckRange(&repository.Repositories{PayrollPeriods: repo}, from, to)
+	assert.EqualError(t, err, "payroll period is locked: 2025-08-01 falls in the payroll period 2025-08-01 to 2025-08-31, which has already been processed")
+}
+
+func TestPeriodLock_Transaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lock := newPeriodLock(ctrl, august2025, &repository.Repositories{})
+	called := false
+	write := func(repos *repository.Repositories) error {
+		called = true
+		return nil
+	}
+
+	assert.NoError(t, lock.Transaction(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 5, 0, 0, 0, 0, time.UTC), write))
+	assert.True(t, called)
+
+	called = false
+	err := lock.Transaction(time.Date(2025, 8, 30, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC), write)
+	assert.EqualError(t, err, "payroll period is locked: 2025-08-30 falls in the payroll period 2025-08-01 to 2025-08-31, which has already been processed")
+	assert.False(t, called)
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+)
+
+// Payslip item codes of the PPh 21 income tax components.
+const (
+	PayslipItemCodePPh21       = "PPH21"         // Monthly withholding at the TER rate
+	PayslipItemCodePPh21TrueUp = "PPH21_TRUE_UP" // December difference between the annual tax and the tax withheld during the year
+)
+
+// terBracket is a bracket of a TER (Tarif Efektif Rata-rata) table: monthly gross income up to
+// UpTo is withheld at Rate percent. The last bracket of a table has no upper bound (UpTo == 0).
+type terBracket struct {
+	UpTo int64
+	Rate string
+}
+
+// TER categories by PTKP status, as set by PP 58/2023.
+var terCategoryByPTKPStatus = map[string]string{
+	domain.PTKPStatusTK0: "A",
+	domain.PTKPStatusTK1: "A",
+	domain.PTKPStatusK0:  "A",
+	domain.PTKPStatusTK2: "B",
+	domain.PTKPStatusTK3: "B",
+	domain.PTKPStatusK1:  "B",
+	domain.PTKPStatusK2:  "B",
+	domain.PTKPStatusK3:  "C",
+}
+
+// Monthly TER tables (PP 58/2023, appendix), by category.
+var terTables = map[string][]terBracket{
+	"A": {
+		{5400000, "0"}, {5650000, "0.25"}, {5950000, "0.5"}, {6300000, "0.75"}, {6750000, "1"},
+		{7500000, "1.25"}, {8550000, "1.5"}, {9650000, "1.75"}, {10050000, "2"}, {10350000, "2.25"},
+		{10700000, "2.5"}, {11050000, "3"}, {11600000, "3.5"}, {12500000, "4"}, {13750000, "5"},
+		{15100000, "6"}, {16950000, "7"}, {19750000, "8"}, {24150000, "9"}, {26450000, "10"},
+		{28000000, "11"}, {30050000, "12"}, {32400000, "13"}, {35400000, "14"}, {39100000, "15"},
+		{43850000, "16"}, {47800000, "17"}, {51400000, "18"}, {56300000, "19"}, {62200000, "20"},
+		{68600000, "21"}, {77500000, "22"}, {89000000, "23"}, {103000000, "24"}, {125000000, "25"},
+		{157000000, "26"}, {206000000, "27"}, {337000000, "28"}, {454000000, "29"}, {550000000, "30"},
+		{695000000, "31"}, {910000000, "32"}, {1400000000, "33"}, {0, "34"},
+	},
+	"B": {
+		{6200000, "0"}, {6500000, "0.25"}, {6850000, "0.5"}, {7300000, "0.75"}, {9200000, "1"},
+		{10750000, "1.5"}, {11250000, "2"}, {11600000, "2.5"}, {12600000, "3"}, {13600000, "4"},
+		{14950000, "5"}, {16400000, "6"}, {18450000, "7"}, {21850000, "8"}, {26000000, "9"},
+		{27700000, "10"}, {29350000, "11"}, {31450000, "12"}, {33950000, "13"}, {37100000, "14"},
+		{41100000, "15"}, {45800000, "16"}, {49500000, "17"}, {53800000, "18"}, {58500000, "19"},
+		{64000000, "20"}, {71000000, "21"}, {80000000, "22"}, {93000000, "23"}, {109000000, "24"},
+		{129000000, "25"}, {163000000, "26"}, {211000000, "27"}, {374000000, "28"}, {459000000, "29"},
+		{555000000, "30"}, {704000000, "31"}, {957000000, "32"}, {1405000000, "33"}, {0, "34"},
+	},
+	"C": {
+		{6600000, "0"}, {6950000, "0.25"}, {7350000, "0.5"}, {7800000, "0.75"}, {8850000, "1"},
+		{9800000, "1.25"}, {10950000, "1.5"}, {11200000, "1.75"}, {12050000, "2"}, {12950000, "3"},
+		{14150000, "4"}, {15550000, "5"}, {17050000, "6"}, {19500000, "7"}, {22700000, "8"},
+		{26600000, "9"}, {28100000, "10"}, {30100000, "11"}, {32600000, "12"}, {35400000, "13"},
+		{38900000, "14"}, {43000000, "15"}, {47400000, "16"}, {51200000, "17"}, {55800000, "18"},
+		{60400000, "19"}, {66700000, "20"}, {74500000, "21"}, {83200000, "22"}, {95600000, "23"},
+		{110000000, "24"}, {134000000, "25"}, {169000000, "26"}, {221000000, "27"}, {390000000, "28"},
+		{463000000, "29"}, {561000000, "30"}, {709000000, "31"}, {965000000, "32"}, {1419000000, "33"},
+		{0, "34"},
+	},
+}
+
+// pasal17Bracket is a bracket of the progressive annual income tax rates of UU PPh Article 17
+// (as amended by UU HPP): taxable income up to UpTo is taxed at Rate percent.
+type pasal17Bracket struct {
+	UpTo int64
+	Rate int64
+}
+
+var pasal17Brackets = []pasal17Bracket{
+	{60000000, 5},
+	{250000000, 15},
+	{500000000, 25},
+	{5000000000, 30},
+	{0, 35},
+}
+
+const (
+	ptkpBase            = 54000000 // Annual PTKP of the taxpayer
+	ptkpAddition        = 4500000  // Annual PTKP added for being married and for each dependant
+	ptkpMaxDependants   = 3
+	biayaJabatanPercent = 5      // Occupational expense deduction, as a percentage of annual gross income
+	biayaJabatanMonthly = 500000 // Monthly cap of the occupational expense deduction (6,000,000 a year)
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// PPh21Calculator withholds the monthly PPh 21 income tax at the TER rate of the employee's PTKP
+// status. In a period ending in December it also adds a true-up line: the annual tax computed
+// with the Article 17 rates, less the tax withheld earlier in the year and in this period. The
+// true-up is negative when too much tax was withheld, which refunds the difference.
+//
+// The tax base is the sum of the taxable lines calculated before this calculator (taxable
+// earnings and taxable employer contributions), so it must be registered after them and after
+// BPJSCalculator.
+type PPh21Calculator struct{}
+
+// Calculate returns the PPh 21 line and, in December, the true-up line.
+func (PPh21Calculator) Calculate(calc *PayslipCalculation) []domain.PayslipItem {
+	status := calc.Profile.PTKPStatus
+	if !domain.IsValidPTKPStatus(status) {
+		status = domain.PTKPStatusTK0
+	}
+
+	gross := taxableIncome(calc.Items)
+	rate := terRate(status, gross)
+	tax := calc.Rounding.RoundLine(gross.Mul(rate))
+
+	items := []domain.PayslipItem{{
+		ComponentType: domain.PayslipComponentDeduction,
+		Code:          PayslipItemCodePPh21,
+		Name:          "PPh 21 (TER " + terCategoryByPTKPStatus[status] + ")",
+		Quantity:      gross,
+		Rate:          rate,
+		Amount:        tax,
+	}}
+
+	if calc.Period == nil || calc.Period.EndDate.Month() != time.December {
+		return items
+	}
+
+	annualGross := gross
+	pension := pensionContributions(calc.Items)
+	withheld := tax
+	for _, payslip := range calc.YearToDate {
+		annualGross = annualGross.Add(taxableIncome(payslip.Items))
+		pension = pension.Add(pensionContributions(payslip.Items))
+		for _, item := range payslip.Items {
+			if item.Code == PayslipItemCodePPh21 || item.Code == PayslipItemCodePPh21TrueUp {
+				withheld = withheld.Add(item.Amount)
+			}
+		}
+	}
+	months := int64(len(calc.YearToDate) + 1)
+
+	trueUp := calc.Rounding.RoundLine(annualPPh21(status, annualGross, pension, months).Sub(withheld))
+	if trueUp.IsZero() {
+		return items
+	}
+	return append(items, domain.PayslipItem{
+		ComponentType: domain.PayslipComponentDeduction,
+		Code:          PayslipItemCodePPh21TrueUp,
+		Name:          "PPh 21 Annual True-Up",
+		Quantity:      decimal.NewFromInt(1),
+		Rate:          trueUp,
+		Amount:        trueUp,
+	})
+}
+
+// taxableIncome returns the sum of the taxable earnings and taxable employer contributions, less
+// the taxable deductions, which take back taxable earnings such as the pay of unpaid leave.
+func taxableIncome(items []domain.PayslipItem) decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range items {
+		if !item.Taxable {
+			continue
+		}
+		if item.ComponentType == domain.PayslipComponentDeduction {
+			total = total.Sub(item.Amount)
+		} else {
+			total = total.Add(item.Amount)
+		}
+	}
+	return total
+}
+
+// pensionContributions returns the employee's BPJS JHT and JP contributions, which are deductible
+// from the annual income.
+func pensionContributions(items []domain.PayslipItem) decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range items {
+		if item.Code == PayslipItemCodeBPJSJHT || item.Code == PayslipItemCodeBPJSJP {
+			total = total.Add(item.Amount)
+		}
+	}
+	return total
+}
+
+// terRate returns the TER rate, as a fraction, for a monthly gross income.
+func terRate(ptkpStatus string, monthlyGross decimal.Decimal) decimal.Decimal {
+	brackets := terTables[terCategoryByPTKPStatus[ptkpStatus]]
+	for _, bracket := range brackets {
+		if bracket.UpTo == 0 || monthlyGross.LessThanOrEqual(decimal.NewFromInt(bracket.UpTo)) {
+			return decimal.RequireFromString(bracket.Rate).Div(hundred)
+		}
+	}
+	return decimal.Zero
+}
+
+// ptkp returns the annual non-taxable income of a PTKP status.
+func ptkp(ptkpStatus string) decimal.Decimal {
+	amount := int64(ptkpBase)
+	if ptkpStatus[0] == 'K' {
+		amount += ptkpAddition
+	}
+	dependants := min(int64(ptkpStatus[len(ptkpStatus)-1]-'0'), ptkpMaxDependants)
+	amount += dependants * ptkpAddition
+	return decimal.NewFromInt(amount)
+}
+
+// annualPPh21 returns the annual PPh 21 due on the gross income earned over the given number of
+// months of a tax year: the occupational expense deduction, the employee's pension contributions
+// and the PTKP are subtracted, the taxable income is rounded down to the thousand and taxed at the
+// Article 17 rates.
+func annualPPh21(ptkpStatus string, annualGross, pension decimal.Decimal, months int64) decimal.Decimal {
+	biayaJabatan := decimal.Min(
+		annualGross.Mul(decimal.NewFromInt(biayaJabatanPercent)).Div(hundred),
+		decimal.NewFromInt(biayaJabatanMonthly*months),
+	)
+
+	taxable := annualGross.Sub(biayaJabatan).Sub(pension).Sub(ptkp(ptkpStatus))
+	thousand := decimal.NewFromInt(1000)
+	taxable = taxable.Div(thousand).Floor().Mul(thousand)
+	if !taxable.IsPositive() {
+		return decimal.Zero
+	}
+
+	tax := decimal.Zero
+	lower := decimal.Zero
+	for _, bracket := range pasal17Brackets {
+		upper := taxable
+		if bracket.UpTo != 0 {
+			upper = decimal.Min(taxable, decimal.NewFromInt(bracket.UpTo))
+		}
+		if upper.GreaterThan(lower) {
+			tax = tax.Add(upper.Sub(lower).Mul(decimal.NewFromInt(bracket.Rate)).Div(hundred))
+		}
+		if bracket.UpTo == 0 || taxable.LessThanOrEqual(decimal.NewFromInt(bracket.UpTo)) {
+			break
+		}
+		lower = decimal.NewFromInt(bracket.UpTo)
+	}
+	return tax
+}
@@ -0,0 +1,203 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/service"
+	mockrepo "payroll-system/tests/mocks/repository"
+)
+
+// monthlyCalculation returns a calculation paying the given monthly salary in full.
+func monthlyCalculation(ptkpStatus string, salary int64, period *domain.PayrollPeriod) *service.PayslipCalculation {
+	return &service.PayslipCalculation{
+		Profile:      domain.EmployeeProfile{PTKPStatus: ptkpStatus, Salary: decimal.NewFromInt(salary)},
+		Period:       period,
+		WorkedHours:  decimal.NewFromInt(160),
+		WorkingHours: decimal.NewFromInt(160),
+		HourlyRate:   decimal.NewFromInt(salary).Div(decimal.NewFromInt(160)),
+		Rounding:     service.DefaultRoundingPolicy(),
+	}
+}
+
+func TestPPh21Calculator_MonthlyTER(t *testing.T) {
+	june := &domain.PayrollPeriod{
+		StartDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name       string
+		ptkpStatus string
+		salary     int64
+		wantRate   string
+		wantTax    string
+		wantName   string
+	}{
+		{name: "category A", ptkpStatus: domain.PTKPStatusTK0, salary: 10000000, wantRate: "0.02", wantTax: "200000", wantName: "PPh 21 (TER A)"},
+		{name: "category B", ptkpStatus: domain.PTKPStatusK1, salary: 10000000, wantRate: "0.015", wantTax: "150000", wantName: "PPh 21 (TER B)"},
+		{name: "category C", ptkpStatus: domain.PTKPStatusK3, salary: 10000000, wantRate: "0.015", wantTax: "150000", wantName: "PPh 21 (TER C)"},
+		{name: "upper bound of a bracket is inclusive", ptkpStatus: domain.PTKPStatusTK0, salary: 5400000, wantRate: "0", wantTax: "0", wantName: "PPh 21 (TER A)"},
+		{name: "top bracket", ptkpStatus: domain.PTKPStatusTK0, salary: 1500000000, wantRate: "0.34", wantTax: "510000000", wantName: "PPh 21 (TER A)"},
+		{name: "unknown status falls back to TK/0", ptkpStatus: "", salary: 10000000, wantRate: "0.02", wantTax: "200000", wantName: "PPh 21 (TER A)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := service.NewPayslipComponentRegistry(service.BasicSalaryCalculator{}, service.PPh21Calculator{})
+			items := registry.Calculate(monthlyCalculation(tt.ptkpStatus, tt.salary, june))
+
+			require.Len(t, items, 2)
+			tax := items[1]
+			assert.Equal(t, service.PayslipItemCodePPh21, tax.Code)
+			assert.Equal(t, domain.PayslipComponentDeduction, tax.ComponentType)
+			assert.Equal(t, tt.wantName, tax.Name)
+			assert.Equal(t, tt.wantRate, tax.Rate.String())
+			assert.Equal(t, tt.wantTax, tax.Amount.String())
+		})
+	}
+}
+
+func TestPPh21Calculator_ReimbursementsAreNotTaxed(t *testing.T) {
+	calc := monthlyCalculation(domain.PTKPStatusTK0, 10000000, &domain.PayrollPeriod{EndDate: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)})
+	calc.Reimbursements = []domain.Reimbursement{{Amount: decimal.NewFromInt(5000000)}}
+
+	registry := service.NewPayslipComponentRegistry(service.BasicSalaryCalculator{}, service.ReimbursementCalculator{}, service.PPh21Calculator{})
+	items := registry.Calculate(calc)
+
+	tax := items[len(items)-1]
+	assert.Equal(t, service.PayslipItemCodePPh21, tax.Code)
+	assert.Equal(t, "10000000", tax.Quantity.String())
+	assert.Equal(t, "200000", tax.Amount.String())
+}
+
+func TestPPh21Calculator_DecemberTrueUp(t *testing.T) {
+	december := &domain.PayrollPeriod{
+		StartDate: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	// January to November: 10,000,000 a month, 200,000 withheld at TER A each month
+	yearToDate := make([]domain.Payslip, 11)
+	for i := range yearToDate {
+		yearToDate[i].Items = []domain.PayslipItem{
+			{ComponentType: domain.PayslipComponentEarning, Code: service.PayslipItemCodeBasicSalary, Amount: decimal.NewFromInt(10000000), Taxable: true},
+			{ComponentType: domain.PayslipComponentEarning, Code: service.PayslipItemCodeReimbursement, Amount: decimal.NewFromInt(1000000)},
+			{ComponentType: domain.PayslipComponentDeduction, Code: service.PayslipItemCodePPh21, Amount: decimal.NewFromInt(200000)},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		ptkpStatus string
+		wantTrueUp string
+	}{
+		// Annual gross 120,000,000 - biaya jabatan 6,000,000 - PTKP 54,000,000 = 60,000,000 taxable,
+		// taxed at 5% = 3,000,000; 2,400,000 was withheld, so 600,000 is still due.
+		{name: "under-withheld tax is collected", ptkpStatus: domain.PTKPStatusTK0, wantTrueUp: "600000"},
+		// PTKP K/3 is 72,000,000: 42,000,000 taxable at 5% = 2,100,000; 300,000 is refunded.
+		{name: "over-withheld tax is refunded", ptkpStatus: domain.PTKPStatusK3, wantTrueUp: "-300000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calc := monthlyCalculation(tt.ptkpStatus, 10000000, december)
+			calc.YearToDate = yearToDate
+
+			// Withhold at TER A in December too, so that only the PTKP differs between the cases
+			calc.Profile.PTKPStatus = domain.PTKPStatusTK0
+			items := service.NewPayslipComponentRegistry(service.BasicSalaryCalculator{}).Calculate(calc)
+			require.Len(t, items, 1)
+			calc.Profile.PTKPStatus = tt.ptkpStatus
+
+			lines := service.PPh21Calculator{}.Calculate(calc)
+			require.Len(t, lines, 2)
+			assert.Equal(t, service.PayslipItemCodePPh21TrueUp, lines[1].Code)
+			assert.Equal(t, domain.PayslipComponentDeduction, lines[1].ComponentType)
+			assert.Equal(t, tt.wantTrueUp, lines[1].Amount.Sub(decimal.NewFromInt(200000)).Add(lines[0].Amount).String())
+		})
+	}
+}
+
+func TestPreviewPayroll_DecemberLoadsYearToDatePayslips(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	period := &domain.PayrollPeriod{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		StartDate: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+	userID := uuid.New()
+
+	payslipRepo := mockrepo.NewMockPayslipRepository(ctrl)
+	payrollPeriodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
+	employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+	salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+	attendanceRepo := mockrepo.NewMockAttendanceRepository(ctrl)
+	overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
+	reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+	holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+	workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+	leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
+
+	payrollPeriodRepo.EXPECT().GetPayrollPeriodByID(period.ID).Return(period, nil)
+	salaryHistoryRepo.EXPECT().GetSalaryHistoriesForPeriodGroupedByUser(period.StartDate, period.EndDate).Return(nil, nil)
+	employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(gomock.Any(), gomock.Any()).Return([]domain.EmployeeProfile{{UserID: userID, Salary: decimal.NewFromInt(10000000), PTKPStatus: domain.PTKPStatusTK0}}, nil)
+	attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+	overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+	reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(gomock.Any()).Return(nil, nil)
+	holidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return(nil, nil)
+	workScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil)
+	leaveRepo.EXPECT().GetApprovedLeaveRequestsByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+	payslipRepo.EXPECT().GetYearToDatePayslipsGroupedByUser(2025, period.StartDate).Return(map[uuid.UUID][]domain.Payslip{
+		userID: {{Items: []domain.PayslipItem{
+			{ComponentType: domain.PayslipComponentEarning, Code: service.PayslipItemCodeBasicSalary, Amount: decimal.NewFromInt(100000000), Taxable: true},
+		}}},
+	}, nil)
+
+	svc := service.NewPayrollService(payslipRepo, payrollPeriodRepo, employeeProfileRepo, salaryHistoryRepo, attendanceRepo, overtimeRepo, reimbursementRepo, holidayRepo, workScheduleRepo, leaveRepo,
+		mockrepo.NewMockAuditLogRepository(ctrl), mockrepo.NewMockUnitOfWork(ctrl), service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), service.DefaultPayslipComponentRegistry(service.DefaultBPJSRates()))
+
+	preview, err := svc.PreviewPayroll(period.ID)
+	require.NoError(t, err)
+	require.Len(t, preview.Payslips, 1)
+
+	// Nothing was worked in December, but the tax due on the income earned earlier in the year is collected
+	items := preview.Payslips[0].Items
+	trueUp := items[len(items)-1]
+	assert.Equal(t, service.PayslipItemCodePPh21TrueUp, trueUp.Code)
+	assert.True(t, trueUp.Amount.IsPositive())
+}
+
+func TestPPh21Calculator_TrueUpDeductsPensionContributions(t *testing.T) {
+	calc := monthlyCalculation(domain.PTKPStatusTK0, 10000000, &domain.PayrollPeriod{EndDate: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)})
+	pension := []domain.PayslipItem{
+		{ComponentType: domain.PayslipComponentDeduction, Code: service.PayslipItemCodeBPJSJHT, Amount: decimal.NewFromInt(200000)},
+		{ComponentType: domain.PayslipComponentDeduction, Code: service.PayslipItemCodeBPJSJP, Amount: decimal.NewFromInt(100000)},
+	}
+	calc.YearToDate = make([]domain.Payslip, 11)
+	for i := range calc.YearToDate {
+		calc.YearToDate[i].Items = append([]domain.PayslipItem{
+			{ComponentType: domain.PayslipComponentEarning, Code: service.PayslipItemCodeBasicSalary, Amount: decimal.NewFromInt(10000000), Taxable: true},
+			{ComponentType: domain.PayslipComponentDeduction, Code: service.PayslipItemCodePPh21, Amount: decimal.NewFromInt(200000)},
+		}, pension...)
+	}
+	service.NewPayslipComponentRegistry(service.BasicSalaryCalculator{}).Calculate(calc)
+	calc.Items = append(calc.Items, pension...)
+
+	lines := service.PPh21Calculator{}.Calculate(calc)
+
+	// 120,000,000 - biaya jabatan 6,000,000 - JHT and JP 3,600,000 - PTKP 54,000,000 = 56,400,000
+	// taxed at 5% = 2,820,000; 2,400,000 was withheld including this month
+	require.Len(t, lines, 2)
+	assert.Equal(t, "200000", lines[0].Amount.String())
+	assert.Equal(t, "420000", lines[1].Amount.String())
+}
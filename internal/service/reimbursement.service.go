@@ -1,76 +1,543 @@
 package service
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 
 	"payroll-system/internal/domain"
 	"payroll-system/internal/repository"
 )
 
+var (
+	// ErrReimbursementNotFound is returned when a reimbursement record does not exist.
+	ErrReimbursementNotFound = errors.New("reimbursement not found")
+	// ErrReimbursementReviewed is returned when reviewing a reimbursement that is no longer pending.
+	ErrReimbursementReviewed = errors.New("reimbursement has already been reviewed")
+	// ErrReimbursementReviewForbidden is returned when an admin reviews their own reimbursement.
+	ErrReimbursementReviewForbidden = errors.New("reimbursements cannot be reviewed by the employee who claimed them")
+	// ErrInvalidApprovedAmount is returned when the approved amount is not positive or exceeds the claimed amount.
+	ErrInvalidApprovedAmount = errors.New("approved amount must be greater than 0 and at most the claimed amount")
+	// ErrRejectionReasonRequired is returned when a reimbursement is rejected without a reason.
+	ErrRejectionReasonRequired = errors.New("a reason is required to reject a reimbursement")
+	// ErrInvalidReimbursement is returned when the expense date of a reimbursement is in the future.
+	ErrInvalidReimbursement = errors.New("invalid reimbursement")
+	// ErrReimbursementLimitExceeded is returned when a claim is above a limit of its category.
+	ErrReimbursementLimitExceeded = errors.New("reimbursement limit exceeded")
+	// ErrReimbursementCategoryNotFound is returned when a reimbursement category does not exist.
+	ErrReimbursementCategoryNotFound = errors.New("reimbursement category not found")
+	// ErrDuplicateReimbursementCategory is returned when another reimbursement category already has the name.
+	ErrDuplicateReimbursementCategory = errors.New("a reimbursement category with this name already exists")
+	// ErrInvalidReimbursementCategory is returned when the name or limits of a reimbursement category are invalid.
+	ErrInvalidReimbursementCategory = errors.New("invalid reimbursement category")
+	// ErrInvalidReceipt is returned when a claim has too many receipts or a receipt is too large or not an image or PDF.
+	ErrInvalidReceipt = errors.New("invalid receipt")
+	// ErrReceiptRequired is returned when a reimbursement without receipts is approved.
+	ErrReceiptRequired = errors.New("a reimbursement cannot be approved without a receipt")
+	// ErrReceiptNotFound is returned when a reimbursement has no receipt with the ID.
+	ErrReceiptNotFound = errors.New("receipt not found")
+	// ErrReceiptForbidden is returned when someone other than the claimant or an admin downloads a receipt.
+	ErrReceiptForbidden = errors.New("receipts can only be downloaded by the employee who claimed them and admins")
+)
+
+// Receipt limits. Receipts are sniffed for their content type rather than trusting the uploaded one.
+const (
+	MaxReceiptSize      = 5 << 20 // 5 MB
+	MaxReceiptsPerClaim = 5
+)
+
+// receiptContentTypes are the content types accepted for receipts: images and PDFs.
+var receiptContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// ReceiptUpload is a receipt file uploaded with a reimbursement claim.
+type ReceiptUpload struct {
+	FileName string
+	Content  io.Reader
+}
+
 // ReimbursementServiceInterface defines the methods of ReimbursementService for mocking purposes.
 //
 //go:generate mockgen -source=reimbursement.service.go -destination=../../tests/mocks/service/mock_reimbursement_service.go -package=mocks
 type ReimbursementServiceInterface interface {
 	// SubmitReimbursement allows an employee to submit a reimbursement request.
-	SubmitReimbursement(userID uuid.UUID, amount float64, description, ipAddress, requestID string) (*domain.Reimbursement, error)
+	SubmitReimbursement(userID, categoryID uuid.UUID, expenseDate time.Time, amount decimal.Decimal, description string, receipts []ReceiptUpload, ipAddress, requestID string) (*domain.Reimbursement, error)
+	// GetPendingReimbursements returns the reimbursements waiting for review.
+	GetPendingReimbursements() ([]domain.Reimbursement, error)
+	// GetReimbursementHistory returns a page of an employee's reimbursements and how many match the filter.
+	GetReimbursementHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Reimbursement, int64, error)
+	// ReviewReimbursement approves, in full or in part, or rejects a pending reimbursement.
+	ReviewReimbursement(id uuid.UUID, approve bool, approvedAmount *decimal.Decimal, reason string, reviewer *domain.User, ipAddress, requestID string) (*domain.Reimbursement, error)
+	// CreateReimbursementCategory creates a reimbursement category with its limits.
+	CreateReimbursementCategory(category *domain.ReimbursementCategory, createdBy uuid.UUID, ipAddress, requestID string) (*domain.ReimbursementCategory, error)
+	// UpdateReimbursementCategory changes the name and limits of a reimbursement category.
+	UpdateReimbursementCategory(id uuid.UUID, changes *domain.ReimbursementCategory, updatedBy uuid.UUID, ipAddress, requestID string) (*domain.ReimbursementCategory, error)
+	// GetReimbursementCategories returns every reimbursement category.
+	GetReimbursementCategories() ([]domain.ReimbursementCategory, error)
+	// GetReceipt opens a receipt of a reimbursement for the employee who claimed it or an admin.
+	GetReceipt(reimbursementID, receiptID uuid.UUID, requester *domain.User) (*domain.ReimbursementReceipt, io.ReadCloser, error)
 }
 
 // ReimbursementService provides business logic for reimbursement management.
 type ReimbursementService struct {
 	reimbursementRepo repository.ReimbursementRepository
-	auditLogRepo      repository.AuditLogRepository
+	categoryRepo      repository.ReimbursementCategoryRepository
+	receiptStorage    repository.BlobStorage
+	periodLock        *PeriodLock
+	uow               repository.UnitOfWork // For transaction management
 }
 
 // NewReimbursementService creates a new ReimbursementService.
 func NewReimbursementService(
 	reimbursementRepo repository.ReimbursementRepository,
-	auditLogRepo repository.AuditLogRepository,
+	categoryRepo repository.ReimbursementCategoryRepository,
+	receiptStorage repository.BlobStorage,
+	periodLock *PeriodLock,
+	uow repository.UnitOfWork,
 ) *ReimbursementService {
 	return &ReimbursementService{
 		reimbursementRepo: reimbursementRepo,
-		auditLogRepo:      auditLogRepo,
+		categoryRepo:      categoryRepo,
+		receiptStorage:    receiptStorage,
+		periodLock:        periodLock,
+		uow:               uow,
 	}
 }
 
-// SubmitReimbursement allows an employee to submit a reimbursement request.
+// SubmitReimbursement allows an employee to submit a reimbursement request for an expense in a
+// category on expenseDate, with at most MaxReceiptsPerClaim receipts; a claim without receipts can
+// be submitted but not approved. The expense date cannot be in a processed payroll period, and the
+// claim must be within the per-claim, monthly and yearly limits of the category. The request is
+// pending until an admin reviews it; only the approved amount is paid.
 func (s *ReimbursementService) SubmitReimbursement(
-	userID uuid.UUID,
-	amount float64,
-	description, ipAddress, requestID string,
+	userID, categoryID uuid.UUID,
+	expenseDate time.Time,
+	amount decimal.Decimal,
+	description string,
+	receipts []ReceiptUpload,
+	ipAddress, requestID string,
 ) (*domain.Reimbursement, error) {
+	year, month, day := time.Now().Date()
+	if expenseDate.After(time.Date(year, month, day, 0, 0, 0, 0, expenseDate.Location())) {
+		return nil, fmt.Errorf("%w: the expense date cannot be in the future", ErrInvalidReimbursement)
+	}
+
+	var newReimbursement *domain.Reimbursement
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := s.periodLock.Check(repos, expenseDate); err != nil {
+			return err
+		}
+
+		files, err := readReceipts(receipts)
+		if err != nil {
+			return err
+		}
+
+		category, err := repos.ReimbursementCategories.GetReimbursementCategoryByID(categoryID)
+		if err != nil {
+			return err
+		}
+		if category == nil {
+			return ErrReimbursementCategoryNotFound
+		}
+		if err := s.checkLimits(repos.Reimbursements, userID, category, expenseDate, amount); err != nil {
+			return err
+		}
 
-	newReimbursement := &domain.Reimbursement{
-		UserID:      userID,
-		Amount:      amount,
-		Description: description,
-		BaseModel: domain.BaseModel{
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-			CreatedBy: userID,
-			UpdatedBy: userID,
-			IPAddress: ipAddress,
-		},
-	}
-
-	// Save reimbursement
-	if err := s.reimbursementRepo.CreateReimbursement(newReimbursement); err != nil {
+		// Receipts are stored before the claim is saved; should saving fail, the stored files are only
+		// kept for the next upload of the same file.
+		if err := s.storeReceipts(files); err != nil {
+			return err
+		}
+
+		newReimbursement = &domain.Reimbursement{
+			UserID:      userID,
+			CategoryID:  &category.ID,
+			ExpenseDate: expenseDate,
+			Amount:      amount,
+			Description: description,
+			Status:      domain.ReimbursementStatusPending,
+			BaseModel: domain.BaseModel{
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+				CreatedBy: userID,
+				UpdatedBy: userID,
+				IPAddress: ipAddress,
+			},
+		}
+		for _, file := range files {
+			receipt := file.receipt
+			receipt.BaseModel = newReimbursement.BaseModel
+			newReimbursement.Receipts = append(newReimbursement.Receipts, receipt)
+		}
+
+		// Save reimbursement
+		if err := repos.Reimbursements.CreateReimbursement(newReimbursement); err != nil {
+			return err
+		}
+		newReimbursement.Category = category
+
+		// Create audit log
+		if err := repository.CreateAuditLog(repos.AuditLogs, &userID, "CREATE", "Reimbursement", &newReimbursement.ID, nil, newReimbursement, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for reimbursement: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Create audit log
-	_ = repository.CreateAuditLog(
-		s.auditLogRepo,
-		&userID,
-		"CREATE",
-		"Reimbursement",
-		&newReimbursement.ID,
-		nil, // oldValue is nil for creation
-		newReimbursement,
-		ipAddress,
-		requestID,
-	)
-
 	return newReimbursement, nil
 }
+
+// checkLimits returns ErrReimbursementLimitExceeded when a claim of amount in category would take the
+// employee over one of its limits. The monthly and yearly limits are for expenses in the calendar
+// month and year of expenseDate; pending claims count toward them at the claimed amount and approved
+// claims at the approved amount.
+func (s *ReimbursementService) checkLimits(reimbursementRepo repository.ReimbursementRepository, userID uuid.UUID, category *domain.ReimbursementCategory, expenseDate time.Time, amount decimal.Decimal) error {
+	if category.PerClaimLimit != nil && amount.GreaterThan(*category.PerClaimLimit) {
+		return fmt.Errorf("%w: %s is above the %s limit of %s per claim", ErrReimbursementLimitExceeded,
+			amount, category.Name, *category.PerClaimLimit)
+	}
+	if category.MonthlyLimit == nil && category.YearlyLimit == nil {
+		return nil
+	}
+
+	yearStart := time.Date(expenseDate.Year(), time.January, 1, 0, 0, 0, 0, expenseDate.Location())
+	yearEnd := time.Date(expenseDate.Year(), time.December, 31, 0, 0, 0, 0, expenseDate.Location())
+	claims, err := reimbursementRepo.GetClaimedReimbursementsByUserIDAndCategory(userID, category.ID, yearStart, yearEnd)
+	if err != nil {
+		return err
+	}
+
+	monthly, yearly := amount, amount
+	for _, claim := range claims {
+		claimed := claim.Amount
+		if claim.Status == domain.ReimbursementStatusApproved {
+			claimed = claim.PaidAmount()
+		}
+		yearly = yearly.Add(claimed)
+		if claim.ExpenseDate.Month() == expenseDate.Month() {
+			monthly = monthly.Add(claimed)
+		}
+	}
+
+	if category.MonthlyLimit != nil && monthly.GreaterThan(*category.MonthlyLimit) {
+		return fmt.Errorf("%w: %s claims in %s would total %s, above the monthly limit of %s", ErrReimbursementLimitExceeded,
+			category.Name, expenseDate.Format("January 2006"), monthly, *category.MonthlyLimit)
+	}
+	if category.YearlyLimit != nil && yearly.GreaterThan(*category.YearlyLimit) {
+		return fmt.Errorf("%w: %s claims in %d would total %s, above the yearly limit of %s", ErrReimbursementLimitExceeded,
+			category.Name, expenseDate.Year(), yearly, *category.YearlyLimit)
+	}
+	return nil
+}
+
+// GetPendingReimbursements returns every pending reimbursement with its employee, oldest first.
+func (s *ReimbursementService) GetPendingReimbursements() ([]domain.Reimbursement, error) {
+	return s.reimbursementRepo.GetPendingReimbursements()
+}
+
+// GetReimbursementHistory returns a page of an employee's reimbursements with their category and
+// receipts, latest expense first, and how many reimbursements match the filter.
+func (s *ReimbursementService) GetReimbursementHistory(userID uuid.UUID, filter domain.HistoryFilter) ([]domain.Reimbursement, int64, error) {
+	return s.reimbursementRepo.GetReimbursementHistory(userID, filter)
+}
+
+// ReviewReimbursement approves or rejects a pending reimbursement. An approval pays approvedAmount,
+// which may be less than the claimed amount, or the whole claim when it is nil, and needs a receipt;
+// a rejection needs a reason. Nobody can review their own reimbursement. The review and its audit log entry are written
+// in one transaction, and the approved amount is paid by the first payroll run covering the expense
+// date that is processed after the approval; a claim dated in a period that was already processed is
+// paid by the next run.
+func (s *ReimbursementService) ReviewReimbursement(
+	id uuid.UUID,
+	approve bool,
+	approvedAmount *decimal.Decimal,
+	reason string,
+	reviewer *domain.User,
+	ipAddress, requestID string,
+) (*domain.Reimbursement, error) {
+	reason = strings.TrimSpace(reason)
+	if !approve && reason == "" {
+		return nil, ErrRejectionReasonRequired
+	}
+
+	var reimbursement *domain.Reimbursement
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		var err error
+		reimbursement, err = repos.Reimbursements.GetReimbursementByID(id)
+		if err != nil {
+			return err
+		}
+		if reimbursement == nil {
+			return ErrReimbursementNotFound
+		}
+		if reimbursement.UserID == reviewer.ID {
+			return ErrReimbursementReviewForbidden
+		}
+		if reimbursement.Status != domain.ReimbursementStatusPending {
+			return ErrReimbursementReviewed
+		}
+		oldReimbursement := *reimbursement
+		now := time.Now()
+		action := "REJECT"
+		if approve {
+			if len(reimbursement.Receipts) == 0 {
+				return ErrReceiptRequired
+			}
+			amount := reimbursement.Amount
+			if approvedAmount != nil {
+				amount = *approvedAmount
+			}
+			if !amount.IsPositive() || amount.GreaterThan(reimbursement.Amount) {
+				return ErrInvalidApprovedAmount
+			}
+			action = "APPROVE"
+			reimbursement.Status = domain.ReimbursementStatusApproved
+			reimbursement.ApprovedAmount = &amount
+		} else {
+			reimbursement.Status = domain.ReimbursementStatusRejected
+			reimbursement.RejectionReason = reason
+		}
+		reimbursement.ReviewedBy = &reviewer.ID
+		reimbursement.ReviewedAt = &now
+		reimbursement.UpdatedAt = now
+		reimbursement.UpdatedBy = reviewer.ID
+		reimbursement.IPAddress = ipAddress
+
+		if err := repos.Reimbursements.UpdateReimbursement(reimbursement); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &reviewer.ID, action, "Reimbursement", &reimbursement.ID, oldReimbursement, reimbursement, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for reimbursement: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reimbursement, nil
+}
+
+// CreateReimbursementCategory creates a reimbursement category from the name and limits of category.
+// The category and its audit log entry are written in one transaction.
+func (s *ReimbursementService) CreateReimbursementCategory(category *domain.ReimbursementCategory, createdBy uuid.UUID, ipAddress, requestID string) (*domain.ReimbursementCategory, error) {
+	if err := normalizeReimbursementCategory(category); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	category.BaseModel = domain.BaseModel{
+		CreatedAt: now,
+		UpdatedAt: now,
+		CreatedBy: createdBy,
+		UpdatedBy: createdBy,
+		IPAddress: ipAddress,
+	}
+
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := repos.ReimbursementCategories.CreateReimbursementCategory(category); err != nil {
+			if errors.Is(err, repository.ErrDuplicateRecord) {
+				return ErrDuplicateReimbursementCategory
+			}
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &createdBy, "CREATE", "ReimbursementCategory", &category.ID, nil, category, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for reimbursement category: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// UpdateReimbursementCategory changes the name and limits of a reimbursement category to those of
+// changes. The new limits apply to claims submitted from now on.
+func (s *ReimbursementService) UpdateReimbursementCategory(id uuid.UUID, changes *domain.ReimbursementCategory, updatedBy uuid.UUID, ipAddress, requestID string) (*domain.ReimbursementCategory, error) {
+	if err := normalizeReimbursementCategory(changes); err != nil {
+		return nil, err
+	}
+
+	category, err := s.categoryRepo.GetReimbursementCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, ErrReimbursementCategoryNotFound
+	}
+
+	oldValue := *category
+	category.Name = changes.Name
+	category.PerClaimLimit = changes.PerClaimLimit
+	category.MonthlyLimit = changes.MonthlyLimit
+	category.YearlyLimit = changes.YearlyLimit
+	category.UpdatedAt = time.Now()
+	category.UpdatedBy = updatedBy
+	category.IPAddress = ipAddress
+
+	err = s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := repos.ReimbursementCategories.UpdateReimbursementCategory(category); err != nil {
+			if errors.Is(err, repository.ErrDuplicateRecord) {
+				return ErrDuplicateReimbursementCategory
+			}
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &updatedBy, "UPDATE", "ReimbursementCategory", &category.ID, oldValue, category, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for reimbursement category: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// GetReimbursementCategories returns every reimbursement category, ordered by name.
+func (s *ReimbursementService) GetReimbursementCategories() ([]domain.ReimbursementCategory, error) {
+	return s.categoryRepo.GetAllReimbursementCategories()
+}
+
+// normalizeReimbursementCategory trims the name of category and checks that it is set and that every
+// limit is greater than 0.
+func normalizeReimbursementCategory(category *domain.ReimbursementCategory) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidReimbursementCategory)
+	}
+	for _, limit := range []*decimal.Decimal{category.PerClaimLimit, category.MonthlyLimit, category.YearlyLimit} {
+		if limit != nil && !limit.IsPositive() {
+			return fmt.Errorf("%w: limits must be greater than 0", ErrInvalidReimbursementCategory)
+		}
+	}
+	return nil
+}
+
+// receiptFile is an uploaded receipt read into memory, with the receipt record to save for it.
+type receiptFile struct {
+	receipt domain.ReimbursementReceipt
+	content []byte
+}
+
+// readReceipts reads the uploaded receipts and checks that there are at most MaxReceiptsPerClaim
+// and that each is an image or PDF of at most MaxReceiptSize. A file uploaded twice is kept once.
+func readReceipts(uploads []ReceiptUpload) ([]receiptFile, error) {
+	if len(uploads) > MaxReceiptsPerClaim {
+		return nil, fmt.Errorf("%w: at most %d receipts can be attached to a claim", ErrInvalidReceipt, MaxReceiptsPerClaim)
+	}
+
+	files := make([]receiptFile, 0, len(uploads))
+	seen := make(map[string]bool)
+	for _, upload := range uploads {
+		name := filepath.Base(strings.TrimSpace(upload.FileName))
+		if name == "." || name == string(filepath.Separator) {
+			name = "receipt"
+		}
+		content, err := io.ReadAll(io.LimitReader(upload.Content, MaxReceiptSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read receipt %s: %w", name, err)
+		}
+		if len(content) == 0 {
+			return nil, fmt.Errorf("%w: %s is empty", ErrInvalidReceipt, name)
+		}
+		if len(content) > MaxReceiptSize {
+			return nil, fmt.Errorf("%w: %s is larger than %d MB", ErrInvalidReceipt, name, MaxReceiptSize>>20)
+		}
+		contentType := http.DetectContentType(content)
+		if !receiptContentTypes[contentType] {
+			return nil, fmt.Errorf("%w: %s is not a JPEG, PNG or WebP image or a PDF", ErrInvalidReceipt, name)
+		}
+
+		sum := sha256.Sum256(content)
+		checksum := hex.EncodeToString(sum[:])
+		if seen[checksum] {
+			continue
+		}
+		seen[checksum] = true
+
+		if len(name) > 255 {
+			name = name[len(name)-255:]
+		}
+		files = append(files, receiptFile{
+			receipt: domain.ReimbursementReceipt{
+				FileName:    name,
+				ContentType: contentType,
+				Size:        int64(len(content)),
+				SHA256:      checksum,
+				StorageKey:  "receipts/" + checksum[:2] + "/" + checksum,
+			},
+			content: content,
+		})
+	}
+	return files, nil
+}
+
+// storeReceipts puts the receipt files in blob storage. Files are stored under their checksum, so a
+// file already stored for another claim is not stored again.
+func (s *ReimbursementService) storeReceipts(files []receiptFile) error {
+	for _, file := range files {
+		exists, err := s.receiptStorage.Exists(file.receipt.StorageKey)
+		if err != nil {
+			return fmt.Errorf("failed to store receipt %s: %w", file.receipt.FileName, err)
+		}
+		if exists {
+			continue
+		}
+		if err := s.receiptStorage.Put(file.receipt.StorageKey, bytes.NewReader(file.content)); err != nil {
+			return fmt.Errorf("failed to store receipt %s: %w", file.receipt.FileName, err)
+		}
+	}
+	return nil
+}
+
+// GetReceipt returns a receipt of a reimbursement with its opened file, which the caller must close.
+// Only the employee who claimed the reimbursement and admins can get its receipts.
+func (s *ReimbursementService) GetReceipt(reimbursementID, receiptID uuid.UUID, requester *domain.User) (*domain.ReimbursementReceipt, io.ReadCloser, error) {
+	reimbursement, err := s.reimbursementRepo.GetReimbursementByID(reimbursementID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if reimbursement == nil {
+		return nil, nil, ErrReimbursementNotFound
+	}
+	if reimbursement.UserID != requester.ID && requester.Role != "admin" {
+		return nil, nil, ErrReceiptForbidden
+	}
+
+	receipt, err := s.reimbursementRepo.GetReimbursementReceipt(reimbursementID, receiptID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if receipt == nil {
+		return nil, nil, ErrReceiptNotFound
+	}
+
+	content, err := s.receiptStorage.Get(receipt.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open receipt %s: %w", receipt.FileName, err)
+	}
+	return receipt, content, nil
+}
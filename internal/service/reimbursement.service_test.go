@@ -1,75 +1,620 @@
 package service_test
 
 import (
+	"bytes"
 	"errors"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
 	"payroll-system/internal/service"
 	mockRepo "payroll-system/tests/mocks/repository"
 )
 
-func TestReimbursementService_SubmitReimbursement(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockReimbursementRepo := mockRepo.NewMockReimbursementRepository(ctrl)
-	mockAuditRepo := mockRepo.NewMockAuditLogRepository(ctrl)
-
-	svc := service.NewReimbursementService(mockReimbursementRepo, mockAuditRepo)
+// pdfReceipt is the content of a minimal PDF receipt.
+var pdfReceipt = []byte("%PDF-1.4\n%%EOF\n")
 
+func TestReimbursementService_SubmitReimbursement(t *testing.T) {
 	userID := uuid.New()
 	ipAddress := "127.0.0.1"
 	requestID := uuid.New().String()
 	description := "Travel expense"
-	amount := 100.0
+	expenseDate := time.Date(2025, time.August, 18, 0, 0, 0, 0, time.UTC)
+	limit := func(v int64) *decimal.Decimal {
+		d := decimal.NewFromInt(v)
+		return &d
+	}
+	category := &domain.ReimbursementCategory{
+		BaseModel:     domain.BaseModel{ID: uuid.New()},
+		Name:          "Travel",
+		PerClaimLimit: limit(500000),
+		MonthlyLimit:  limit(1000000),
+		YearlyLimit:   limit(3000000),
+	}
+	// Earlier claims this year: 600000 in August, counting the partly approved claim at its
+	// approved amount, and 1900000 in all.
+	claims := []domain.Reimbursement{
+		{ExpenseDate: expenseDate.AddDate(0, 0, -10), Amount: decimal.NewFromInt(400000), Status: domain.ReimbursementStatusPending},
+		{ExpenseDate: expenseDate.AddDate(0, 0, -5), Amount: decimal.NewFromInt(450000), ApprovedAmount: limit(200000), Status: domain.ReimbursementStatusApproved},
+		{ExpenseDate: expenseDate.AddDate(0, -3, 0), Amount: decimal.NewFromInt(1300000), Status: domain.ReimbursementStatusApproved},
+	}
+	yearStart := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
 
 	tests := []struct {
-		name       string
-		setupMocks func()
-		expectErr  string
+		name        string
+		expenseDate time.Time
+		amount      decimal.Decimal
+		processed   *domain.PayrollPeriod
+		setupMocks  func(reimbursementRepo *mockRepo.MockReimbursementRepository, categoryRepo *mockRepo.MockReimbursementCategoryRepository, auditRepo *mockRepo.MockAuditLogRepository)
+		expectErr   error
+		expectMsg   string
 	}{
 		{
-			name: "success",
-			setupMocks: func() {
-				mockReimbursementRepo.EXPECT().CreateReimbursement(gomock.Any()).Return(nil)
-				mockAuditRepo.EXPECT().
-					Create(gomock.Any()).
-					Return(nil).
-					Times(1)
+			name:        "success",
+			expenseDate: expenseDate,
+			amount:      decimal.NewFromInt(100000),
+			setupMocks: func(reimbursementRepo *mockRepo.MockReimbursementRepository, categoryRepo *mockRepo.MockReimbursementCategoryRepository, auditRepo *mockRepo.MockAuditLogRepository) {
+				categoryRepo.EXPECT().GetReimbursementCategoryByID(category.ID).Return(category, nil)
+				reimbursementRepo.EXPECT().GetClaimedReimbursementsByUserIDAndCategory(userID, category.ID, yearStart, yearEnd).Return(claims, nil)
+				reimbursementRepo.EXPECT().CreateReimbursement(gomock.Any()).Return(nil)
+				auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name:        "above the per-claim limit",
+			expenseDate: expenseDate,
+			amount:      decimal.NewFromInt(600000),
+			setupMocks: func(reimbursementRepo *mockRepo.MockReimbursementRepository, categoryRepo *mockRepo.MockReimbursementCategoryRepository, auditRepo *mockRepo.MockAuditLogRepository) {
+				categoryRepo.EXPECT().GetReimbursementCategoryByID(category.ID).Return(category, nil)
+			},
+			expectErr: service.ErrReimbursementLimitExceeded,
+			expectMsg: "600000 is above the Travel limit of 500000 per claim",
+		},
+		{
+			name:        "above the monthly limit",
+			expenseDate: expenseDate,
+			amount:      decimal.NewFromInt(450000),
+			setupMocks: func(reimbursementRepo *mockRepo.MockReimbursementRepository, categoryRepo *mockRepo.MockReimbursementCategoryRepository, auditRepo *mockRepo.MockAuditLogRepository) {
+				categoryRepo.EXPECT().GetReimbursementCategoryByID(category.ID).Return(category, nil)
+				reimbursementRepo.EXPECT().GetClaimedReimbursementsByUserIDAndCategory(userID, category.ID, yearStart, yearEnd).Return(claims, nil)
+			},
+			expectErr: service.ErrReimbursementLimitExceeded,
+			expectMsg: "Travel claims in August 2025 would total 1050000, above the monthly limit of 1000000",
+		},
+		{
+			name:        "above the yearly limit",
+			expenseDate: expenseDate.AddDate(0, 1, 0),
+			amount:      decimal.NewFromInt(500000),
+			setupMocks: func(reimbursementRepo *mockRepo.MockReimbursementRepository, categoryRepo *mockRepo.MockReimbursementCategoryRepository, auditRepo *mockRepo.MockAuditLogRepository) {
+				categoryRepo.EXPECT().GetReimbursementCategoryByID(category.ID).Return(category, nil)
+				reimbursementRepo.EXPECT().GetClaimedReimbursementsByUserIDAndCategory(userID, category.ID, yearStart, yearEnd).
+					Return(append(claims, domain.Reimbursement{ExpenseDate: expenseDate.AddDate(0, -1, 0), Amount: decimal.NewFromInt(700000), Status: domain.ReimbursementStatusApproved}), nil)
+			},
+			expectErr: service.ErrReimbursementLimitExceeded,
+			expectMsg: "Travel claims in 2025 would total 3100000, above the yearly limit of 3000000",
+		},
+		{
+			name:        "expense date in the future",
+			expenseDate: time.Now().AddDate(0, 0, 2),
+			amount:      decimal.NewFromInt(100000),
+			setupMocks: func(reimbursementRepo *mockRepo.MockReimbursementRepository, categoryRepo *mockRepo.MockReimbursementCategoryRepository, auditRepo *mockRepo.MockAuditLogRepository) {
+			},
+			expectErr: service.ErrInvalidReimbursement,
+		},
+		{
+			name:        "expense date in a processed payroll period",
+			expenseDate: expenseDate,
+			amount:      decimal.NewFromInt(100000),
+			processed:   august2025,
+			setupMocks: func(reimbursementRepo *mockRepo.MockReimbursementRepository, categoryRepo *mockRepo.MockReimbursementCategoryRepository, auditRepo *mockRepo.MockAuditLogRepository) {
+			},
+			expectErr: service.ErrPeriodLocked,
+			expectMsg: "2025-08-18 falls in the payroll period 2025-08-01 to 2025-08-31",
+		},
+		{
+			name:        "category not found",
+			expenseDate: expenseDate,
+			amount:      decimal.NewFromInt(100000),
+			setupMocks: func(reimbursementRepo *mockRepo.MockReimbursementRepository, categoryRepo *mockRepo.MockReimbursementCategoryRepository, auditRepo *mockRepo.MockAuditLogRepository) {
+				categoryRepo.EXPECT().GetReimbursementCategoryByID(category.ID).Return(nil, nil)
 			},
-			expectErr: "",
+			expectErr: service.ErrReimbursementCategoryNotFound,
 		},
 		{
-			name: "reimbursement repo error",
-			setupMocks: func() {
-				mockReimbursementRepo.EXPECT().CreateReimbursement(gomock.Any()).Return(errors.New("db error"))
+			name:        "reimbursement repo error",
+			expenseDate: expenseDate,
+			amount:      decimal.NewFromInt(100000),
+			setupMocks: func(reimbursementRepo *mockRepo.MockReimbursementRepository, categoryRepo *mockRepo.MockReimbursementCategoryRepository, auditRepo *mockRepo.MockAuditLogRepository) {
+				categoryRepo.EXPECT().GetReimbursementCategoryByID(category.ID).Return(category, nil)
+				reimbursementRepo.EXPECT().GetClaimedReimbursementsByUserIDAndCategory(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				reimbursementRepo.EXPECT().CreateReimbursement(gomock.Any()).Return(errors.New("db error"))
 			},
-			expectErr: "db error",
+			expectMsg: "db error",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.setupMocks()
-			reimbursement, err := svc.SubmitReimbursement(userID, amount, description, ipAddress, requestID)
-			if tt.expectErr != "" {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockReimbursementRepo := mockRepo.NewMockReimbursementRepository(ctrl)
+			mockCategoryRepo := mockRepo.NewMockReimbursementCategoryRepository(ctrl)
+			mockAuditRepo := mockRepo.NewMockAuditLogRepository(ctrl)
+			tt.setupMocks(mockReimbursementRepo, mockCategoryRepo, mockAuditRepo)
+
+			storage, err := repository.NewLocalBlobStorage(t.TempDir())
+			require.NoError(t, err)
+			receipts := []service.ReceiptUpload{{FileName: "ticket.pdf", Content: bytes.NewReader(pdfReceipt)}}
+
+			uow := newUnitOfWork(ctrl, tt.processed, &repository.Repositories{Reimbursements: mockReimbursementRepo, ReimbursementCategories: mockCategoryRepo, AuditLogs: mockAuditRepo})
+			svc := service.NewReimbursementService(mockReimbursementRepo, mockCategoryRepo, storage, service.NewPeriodLock(uow), uow)
+			reimbursement, err := svc.SubmitReimbursement(userID, category.ID, tt.expenseDate, tt.amount, description, receipts, ipAddress, requestID)
+			if tt.expectErr != nil || tt.expectMsg != "" {
 				assert.Error(t, err)
-				assert.Equal(t, tt.expectErr, err.Error())
+				if tt.expectErr != nil {
+					assert.ErrorIs(t, err, tt.expectErr)
+				}
+				assert.Contains(t, err.Error(), tt.expectMsg)
+				assert.Nil(t, reimbursement)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, reimbursement.UserID)
+			assert.Equal(t, category.ID, *reimbursement.CategoryID)
+			assert.Equal(t, "Travel", reimbursement.Category.Name)
+			assert.Equal(t, tt.expenseDate, reimbursement.ExpenseDate)
+			assert.Equal(t, tt.amount, reimbursement.Amount)
+			assert.Equal(t, description, reimbursement.Description)
+			assert.Equal(t, domain.ReimbursementStatusPending, reimbursement.Status)
+			// Approximate check for timestamps
+			assert.WithinDuration(t, time.Now(), reimbursement.CreatedAt, 2*time.Second)
+
+			require.Len(t, reimbursement.Receipts, 1)
+			receipt := reimbursement.Receipts[0]
+			assert.Equal(t, "ticket.pdf", receipt.FileName)
+			assert.Equal(t, "application/pdf", receipt.ContentType)
+			assert.Equal(t, int64(len(pdfReceipt)), receipt.Size)
+			assert.Equal(t, "receipts/"+receipt.SHA256[:2]+"/"+receipt.SHA256, receipt.StorageKey)
+			stored, err := storage.Exists(receipt.StorageKey)
+			require.NoError(t, err)
+			assert.True(t, stored)
+		})
+	}
+}
+
+func TestReimbursementService_SubmitReimbursement_Receipts(t *testing.T) {
+	userID := uuid.New()
+	category := &domain.ReimbursementCategory{BaseModel: domain.BaseModel{ID: uuid.New()}, Name: "Medical"}
+	expenseDate := time.Date(2025, time.August, 18, 0, 0, 0, 0, time.UTC)
+	pngReceipt := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	upload := func(name string, content []byte) service.ReceiptUpload {
+		return service.ReceiptUpload{FileName: name, Content: bytes.NewReader(content)}
+	}
+
+	tests := []struct {
+		name           string
+		receipts       []service.ReceiptUpload
+		alreadySaved   bool
+		expectReceipts int
+		expectStored   int
+		expectErr      string
+	}{
+		{
+			name:           "image and PDF",
+			receipts:       []service.ReceiptUpload{upload("photo.png", pngReceipt), upload("invoice.pdf", pdfReceipt)},
+			expectReceipts: 2,
+			expectStored:   2,
+		},
+		{
+			name:           "same file uploaded twice is kept once",
+			receipts:       []service.ReceiptUpload{upload("invoice.pdf", pdfReceipt), upload("invoice copy.pdf", pdfReceipt)},
+			expectReceipts: 1,
+			expectStored:   1,
+		},
+		{
+			name:           "file stored for another claim is not stored again",
+			receipts:       []service.ReceiptUpload{upload("invoice.pdf", pdfReceipt)},
+			alreadySaved:   true,
+			expectReceipts: 1,
+		},
+		{
+			name: "no receipt",
+		},
+		{
+			name: "too many receipts",
+			receipts: []service.ReceiptUpload{
+				upload("1.pdf", pdfReceipt), upload("2.pdf", pdfReceipt), upload("3.pdf", pdfReceipt),
+				upload("4.pdf", pdfReceipt), upload("5.pdf", pdfReceipt), upload("6.pdf", pdfReceipt),
+			},
+			expectErr: "at most 5 receipts can be attached to a claim",
+		},
+		{
+			name:      "too large",
+			receipts:  []service.ReceiptUpload{upload("scan.pdf", append(append([]byte{}, pdfReceipt...), make([]byte, service.MaxReceiptSize)...))},
+			expectErr: "scan.pdf is larger than 5 MB",
+		},
+		{
+			name:      "not an image or PDF",
+			receipts:  []service.ReceiptUpload{upload("receipt.pdf", []byte("<html><body>receipt</body></html>"))},
+			expectErr: "receipt.pdf is not a JPEG, PNG or WebP image or a PDF",
+		},
+		{
+			name:      "empty file",
+			receipts:  []service.ReceiptUpload{upload("receipt.pdf", nil)},
+			expectErr: "receipt.pdf is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			reimbursementRepo := mockRepo.NewMockReimbursementRepository(ctrl)
+			categoryRepo := mockRepo.NewMockReimbursementCategoryRepository(ctrl)
+			auditRepo := mockRepo.NewMockAuditLogRepository(ctrl)
+			storage := mockRepo.NewMockBlobStorage(ctrl)
+
+			if tt.expectErr == "" {
+				categoryRepo.EXPECT().GetReimbursementCategoryByID(category.ID).Return(category, nil)
+				storage.EXPECT().Exists(gomock.Any()).Return(tt.alreadySaved, nil).Times(tt.expectReceipts)
+				storage.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil).Times(tt.expectStored)
+				reimbursementRepo.EXPECT().CreateReimbursement(gomock.Any()).Return(nil)
+				auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			uow := newUnitOfWork(ctrl, nil, &repository.Repositories{Reimbursements: reimbursementRepo, ReimbursementCategories: categoryRepo, AuditLogs: auditRepo})
+			svc := service.NewReimbursementService(reimbursementRepo, categoryRepo, storage, service.NewPeriodLock(uow), uow)
+			reimbursement, err := svc.SubmitReimbursement(userID, category.ID, expenseDate, decimal.NewFromInt(100000), "Doctor visit", tt.receipts, "127.0.0.1", "req-123")
+
+			if tt.expectErr != "" {
+				assert.ErrorIs(t, err, service.ErrInvalidReceipt)
+				assert.Contains(t, err.Error(), tt.expectErr)
+				assert.Nil(t, reimbursement)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, reimbursement.Receipts, tt.expectReceipts)
+		})
+	}
+}
+
+func TestReimbursementService_GetReceipt(t *testing.T) {
+	employee := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "employee"}
+	colleague := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "employee"}
+	admin := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "admin"}
+	reimbursement := &domain.Reimbursement{BaseModel: domain.BaseModel{ID: uuid.New()}, UserID: employee.ID}
+	receipt := &domain.ReimbursementReceipt{BaseModel: domain.BaseModel{ID: uuid.New()}, ReimbursementID: reimbursement.ID, FileName: "invoice.pdf", StorageKey: "receipts/ab/abc"}
+
+	tests := []struct {
+		name      string
+		requester *domain.User
+		found     bool
+		expectErr error
+	}{
+		{name: "employee who claimed it", requester: employee, found: true},
+		{name: "admin", requester: admin, found: true},
+		{name: "another employee", requester: colleague, expectErr: service.ErrReceiptForbidden},
+		{name: "receipt not found", requester: employee, expectErr: service.ErrReceiptNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			reimbursementRepo := mockRepo.NewMockReimbursementRepository(ctrl)
+			storage := mockRepo.NewMockBlobStorage(ctrl)
+
+			reimbursementRepo.EXPECT().GetReimbursementByID(reimbursement.ID).Return(reimbursement, nil)
+			if tt.expectErr != service.ErrReceiptForbidden {
+				if tt.found {
+					reimbursementRepo.EXPECT().GetReimbursementReceipt(reimbursement.ID, receipt.ID).Return(receipt, nil)
+					storage.EXPECT().Get("receipts/ab/abc").Return(io.NopCloser(strings.NewReader("%PDF-1.4")), nil)
+				} else {
+					reimbursementRepo.EXPECT().GetReimbursementReceipt(reimbursement.ID, receipt.ID).Return(nil, nil)
+				}
+			}
+
+			uow := mockRepo.NewMockUnitOfWork(ctrl)
+			svc := service.NewReimbursementService(reimbursementRepo, mockRepo.NewMockReimbursementCategoryRepository(ctrl), storage, service.NewPeriodLock(uow), uow)
+			got, content, err := svc.GetReceipt(reimbursement.ID, receipt.ID, tt.requester)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, content)
+				return
+			}
+			require.NoError(t, err)
+			defer content.Close()
+			assert.Equal(t, "invoice.pdf", got.FileName)
+			data, err := io.ReadAll(content)
+			require.NoError(t, err)
+			assert.Equal(t, "%PDF-1.4", string(data))
+		})
+	}
+}
+
+func TestReimbursementService_ReviewReimbursement(t *testing.T) {
+	employeeID := uuid.New()
+	admin := &domain.User{BaseModel: domain.BaseModel{ID: uuid.New()}, Role: "admin"}
+	claimed := decimal.NewFromInt(200000)
+	amount := func(v int64) *decimal.Decimal {
+		d := decimal.NewFromInt(v)
+		return &d
+	}
+	claim := func(userID uuid.UUID, status string) *domain.Reimbursement {
+		return &domain.Reimbursement{
+			BaseModel:   domain.BaseModel{ID: uuid.New()},
+			UserID:      userID,
+			Amount:      claimed,
+			Description: "Client dinner",
+			Status:      status,
+			Receipts:    []domain.ReimbursementReceipt{{FileName: "receipt.pdf"}},
+		}
+	}
+	lateClaim := claim(employeeID, domain.ReimbursementStatusPending)
+	lateClaim.ExpenseDate = time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	withoutReceipt := func() *domain.Reimbursement {
+		c := claim(employeeID, domain.ReimbursementStatusPending)
+		c.Receipts = nil
+		return c
+	}
+
+	tests := []struct {
+		name           string
+		reimbursement  *domain.Reimbursement
+		found          bool
+		approve        bool
+		approvedAmount *decimal.Decimal
+		reason         string
+		expectStatus   string
+		expectApproved string
+		expectAction   string
+		expectErr      error
+	}{
+		{
+			name:           "approve in full",
+			reimbursement:  claim(employeeID, domain.ReimbursementStatusPending),
+			found:          true,
+			approve:        true,
+			expectStatus:   domain.ReimbursementStatusApproved,
+			expectApproved: "200000",
+			expectAction:   "APPROVE",
+		},
+		{
+			name:           "approve in part",
+			reimbursement:  claim(employeeID, domain.ReimbursementStatusPending),
+			found:          true,
+			approve:        true,
+			approvedAmount: amount(150000),
+			expectStatus:   domain.ReimbursementStatusApproved,
+			expectApproved: "150000",
+			expectAction:   "APPROVE",
+		},
+		{
+			name:          "reject",
+			reimbursement: claim(employeeID, domain.ReimbursementStatusPending),
+			found:         true,
+			reason:        " Not a business expense ",
+			expectStatus:  domain.ReimbursementStatusRejected,
+			expectAction:  "REJECT",
+		},
+		{
+			name:          "reject without a receipt",
+			reimbursement: withoutReceipt(),
+			found:         true,
+			reason:        " Not a business expense ",
+			expectStatus:  domain.ReimbursementStatusRejected,
+			expectAction:  "REJECT",
+		},
+		{
+			name:          "approve without a receipt",
+			reimbursement: withoutReceipt(),
+			found:         true,
+			approve:       true,
+			expectErr:     service.ErrReceiptRequired,
+		},
+		{
+			name:          "reject without a reason",
+			reimbursement: claim(employeeID, domain.ReimbursementStatusPending),
+			reason:        "  ",
+			expectErr:     service.ErrRejectionReasonRequired,
+		},
+		{
+			name:           "approved amount above the claim",
+			reimbursement:  claim(employeeID, domain.ReimbursementStatusPending),
+			found:          true,
+			approve:        true,
+			approvedAmount: amount(250000),
+			expectErr:      service.ErrInvalidApprovedAmount,
+		},
+		{
+			name:           "approved amount of zero",
+			reimbursement:  claim(employeeID, domain.ReimbursementStatusPending),
+			found:          true,
+			approve:        true,
+			approvedAmount: amount(0),
+			expectErr:      service.ErrInvalidApprovedAmount,
+		},
+		{
+			name:          "admin cannot review their own claim",
+			reimbursement: claim(admin.ID, domain.ReimbursementStatusPending),
+			found:         true,
+			approve:       true,
+			expectErr:     service.ErrReimbursementReviewForbidden,
+		},
+		{
+			name:          "already reviewed",
+			reimbursement: claim(employeeID, domain.ReimbursementStatusApproved),
+			found:         true,
+			approve:       true,
+			expectErr:     service.ErrReimbursementReviewed,
+		},
+		{
+			name:          "not found",
+			reimbursement: claim(employeeID, domain.ReimbursementStatusPending),
+			approve:       true,
+			expectErr:     service.ErrReimbursementNotFound,
+		},
+		{
+			// Paid by the next payroll run
+			name:           "expense in a processed payroll period",
+			reimbursement:  lateClaim,
+			found:          true,
+			approve:        true,
+			expectStatus:   domain.ReimbursementStatusApproved,
+			expectApproved: "200000",
+			expectAction:   "APPROVE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockRepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+
+			if tt.expectErr != service.ErrRejectionReasonRequired {
+				expectTransaction(uow, txRepos)
+				if tt.found {
+					tx.reimbursementRepo.EXPECT().GetReimbursementByID(tt.reimbursement.ID).Return(tt.reimbursement, nil)
+				} else {
+					tx.reimbursementRepo.EXPECT().GetReimbursementByID(tt.reimbursement.ID).Return(nil, nil)
+				}
+			}
+			if tt.expectErr == nil {
+				tx.reimbursementRepo.EXPECT().UpdateReimbursement(gomock.Any()).Return(nil)
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, tt.expectAction, log.Action)
+					assert.Equal(t, "Reimbursement", log.EntityName)
+					return nil
+				})
+			}
+
+			svc := service.NewReimbursementService(mockRepo.NewMockReimbursementRepository(ctrl), mockRepo.NewMockReimbursementCategoryRepository(ctrl), mockRepo.NewMockBlobStorage(ctrl), service.NewPeriodLock(uow), uow)
+			reimbursement, err := svc.ReviewReimbursement(tt.reimbursement.ID, tt.approve, tt.approvedAmount, tt.reason, admin, "127.0.0.1", "req-123")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
 				assert.Nil(t, reimbursement)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectStatus, reimbursement.Status)
+			assert.Equal(t, admin.ID, *reimbursement.ReviewedBy)
+			assert.NotNil(t, reimbursement.ReviewedAt)
+			if tt.approve {
+				require.NotNil(t, reimbursement.ApprovedAmount)
+				assert.Equal(t, tt.expectApproved, reimbursement.ApprovedAmount.String())
+				assert.Empty(t, reimbursement.RejectionReason)
 			} else {
-				assert.NoError(t, err)
-				assert.NotNil(t, reimbursement)
-				assert.Equal(t, userID, reimbursement.UserID)
-				assert.Equal(t, amount, reimbursement.Amount)
-				assert.Equal(t, description, reimbursement.Description)
-				// Approximate check for timestamps
-				assert.WithinDuration(t, time.Now(), reimbursement.CreatedAt, 2*time.Second)
+				assert.Nil(t, reimbursement.ApprovedAmount)
+				assert.Equal(t, "Not a business expense", reimbursement.RejectionReason)
+			}
+		})
+	}
+}
+
+func TestReimbursementService_CreateReimbursementCategory(t *testing.T) {
+	adminID := uuid.New()
+	zero := decimal.Zero
+	monthly := decimal.NewFromInt(1000000)
+
+	tests := []struct {
+		name      string
+		category  *domain.ReimbursementCategory
+		duplicate bool
+		expectErr error
+	}{
+		{name: "success", category: &domain.ReimbursementCategory{Name: " Medical ", MonthlyLimit: &monthly}},
+		{name: "name is required", category: &domain.ReimbursementCategory{Name: "  "}, expectErr: service.ErrInvalidReimbursementCategory},
+		{name: "limit of zero", category: &domain.ReimbursementCategory{Name: "Meals", PerClaimLimit: &zero}, expectErr: service.ErrInvalidReimbursementCategory},
+		{name: "duplicate name", category: &domain.ReimbursementCategory{Name: "Medical"}, duplicate: true, expectErr: service.ErrDuplicateReimbursementCategory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockRepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+
+			if tt.expectErr != service.ErrInvalidReimbursementCategory {
+				expectTransaction(uow, txRepos)
+				if tt.duplicate {
+					tx.reimbursementCategoryRepo.EXPECT().CreateReimbursementCategory(gomock.Any()).Return(repository.ErrDuplicateRecord)
+				} else {
+					tx.reimbursementCategoryRepo.EXPECT().CreateReimbursementCategory(gomock.Any()).Return(nil)
+					tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+						assert.Equal(t, "CREATE", log.Action)
+						assert.Equal(t, "ReimbursementCategory", log.EntityName)
+						return nil
+					})
+				}
+			}
+
+			svc := service.NewReimbursementService(mockRepo.NewMockReimbursementRepository(ctrl), mockRepo.NewMockReimbursementCategoryRepository(ctrl), mockRepo.NewMockBlobStorage(ctrl), service.NewPeriodLock(uow), uow)
+			category, err := svc.CreateReimbursementCategory(tt.category, adminID, "127.0.0.1", "req-123")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, category)
+				return
 			}
+			require.NoError(t, err)
+			assert.Equal(t, "Medical", category.Name)
+			assert.Equal(t, adminID, category.CreatedBy)
 		})
 	}
 }
+
+func TestReimbursementService_UpdateReimbursementCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	adminID := uuid.New()
+	yearly := decimal.NewFromInt(5000000)
+	existing := &domain.ReimbursementCategory{BaseModel: domain.BaseModel{ID: uuid.New()}, Name: "Travel"}
+
+	uow := mockRepo.NewMockUnitOfWork(ctrl)
+	categoryRepo := mockRepo.NewMockReimbursementCategoryRepository(ctrl)
+	txRepos, tx := newTxRepositories(ctrl)
+	svc := service.NewReimbursementService(mockRepo.NewMockReimbursementRepository(ctrl), categoryRepo, mockRepo.NewMockBlobStorage(ctrl), service.NewPeriodLock(uow), uow)
+
+	// Not found
+	missingID := uuid.New()
+	categoryRepo.EXPECT().GetReimbursementCategoryByID(missingID).Return(nil, nil)
+	_, err := svc.UpdateReimbursementCategory(missingID, &domain.ReimbursementCategory{Name: "Travel"}, adminID, "127.0.0.1", "req-123")
+	assert.ErrorIs(t, err, service.ErrReimbursementCategoryNotFound)
+
+	// Success
+	categoryRepo.EXPECT().GetReimbursementCategoryByID(existing.ID).Return(existing, nil)
+	expectTransaction(uow, txRepos)
+	tx.reimbursementCategoryRepo.EXPECT().UpdateReimbursementCategory(existing).Return(nil)
+	tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+		assert.Equal(t, "UPDATE", log.Action)
+		assert.Contains(t, string(log.OldValue), `"name":"Travel"`)
+		return nil
+	})
+
+	category, err := svc.UpdateReimbursementCategory(existing.ID, &domain.ReimbursementCategory{Name: "Business travel", YearlyLimit: &yearly}, adminID, "127.0.0.1", "req-123")
+	require.NoError(t, err)
+	assert.Equal(t, "Business travel", category.Name)
+	assert.Equal(t, "5000000", category.YearlyLimit.String())
+	assert.Equal(t, adminID, category.UpdatedBy)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode defines how a money amount is rounded to the configured precision.
+type RoundingMode string
+
+// RoundingScope defines at which stage of the payslip calculation rounding is applied.
+type RoundingScope string
+
+const (
+	RoundingModeHalfUp  RoundingMode = "half_up" // 0.5 is rounded away from zero
+	RoundingModeBankers RoundingMode = "bankers" // 0.5 is rounded to the nearest even digit
+
+	RoundingScopePerLine  RoundingScope = "per_line"  // every payslip line is rounded before totals are summed
+	RoundingScopePerTotal RoundingScope = "per_total" // lines keep full precision, only totals are rounded
+
+	DefaultRoundingPlaces = 2
+)
+
+// RoundingPolicy describes how money amounts are rounded during payroll calculation.
+type RoundingPolicy struct {
+	Mode   RoundingMode
+	Scope  RoundingScope
+	Places int32
+}
+
+// DefaultRoundingPolicy returns the policy used when nothing is configured:
+// half-up rounding of every payslip line to two decimal places.
+func DefaultRoundingPolicy() RoundingPolicy {
+	return RoundingPolicy{
+		Mode:   RoundingModeHalfUp,
+		Scope:  RoundingScopePerLine,
+		Places: DefaultRoundingPlaces,
+	}
+}
+
+// ParseRoundingPolicy builds a RoundingPolicy from its textual configuration.
+// Empty values fall back to the defaults of DefaultRoundingPolicy.
+func ParseRoundingPolicy(mode, scope, places string) (RoundingPolicy, error) {
+	policy := DefaultRoundingPolicy()
+
+	if mode != "" {
+		switch RoundingMode(mode) {
+		case RoundingModeHalfUp, RoundingModeBankers:
+			policy.Mode = RoundingMode(mode)
+		default:
+			return policy, fmt.Errorf("invalid rounding mode %q", mode)
+		}
+	}
+
+	if scope != "" {
+		switch RoundingScope(scope) {
+		case RoundingScopePerLine, RoundingScopePerTotal:
+			policy.Scope = RoundingScope(scope)
+		default:
+			return policy, fmt.Errorf("invalid rounding scope %q", scope)
+		}
+	}
+
+	if places != "" {
+		p, err := strconv.ParseInt(places, 10, 32)
+		if err != nil || p < 0 {
+			return policy, fmt.Errorf("invalid rounding places %q", places)
+		}
+		policy.Places = int32(p)
+	}
+
+	return policy, nil
+}
+
+// Round rounds the amount to the configured precision using the configured mode.
+func (p RoundingPolicy) Round(amount decimal.Decimal) decimal.Decimal {
+	if p.Mode == RoundingModeBankers {
+		return amount.RoundBank(p.Places)
+	}
+	return amount.Round(p.Places)
+}
+
+// RoundLine rounds a single payslip line when the policy rounds per line,
+// otherwise the amount is returned with full precision.
+func (p RoundingPolicy) RoundLine(amount decimal.Decimal) decimal.Decimal {
+	if p.Scope == RoundingScopePerTotal {
+		return amount
+	}
+	return p.Round(amount)
+}
+
+// RoundTotal rounds a payslip total. Totals are always rounded regardless of scope.
+func (p RoundingPolicy) RoundTotal(amount decimal.Decimal) decimal.Decimal {
+	return p.Round(amount)
+}
@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"payroll-system/internal/service"
+)
+
+func TestRoundingPolicy_Round(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   service.RoundingPolicy
+		amount   string
+		expected string
+	}{
+		{
+			name:     "half up rounds away from zero",
+			policy:   service.RoundingPolicy{Mode: service.RoundingModeHalfUp, Places: 0},
+			amount:   "2.5",
+			expected: "3",
+		},
+		{
+			name:     "bankers rounds half to even",
+			policy:   service.RoundingPolicy{Mode: service.RoundingModeBankers, Places: 0},
+			amount:   "2.5",
+			expected: "2",
+		},
+		{
+			name:     "two decimal places",
+			policy:   service.RoundingPolicy{Mode: service.RoundingModeHalfUp, Places: 2},
+			amount:   "11363.636363",
+			expected: "11363.64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Round(decimal.RequireFromString(tt.amount))
+			assert.True(t, decimal.RequireFromString(tt.expected).Equal(got), "got %s", got)
+		})
+	}
+}
+
+func TestRoundingPolicy_Scope(t *testing.T) {
+	amount := decimal.RequireFromString("10.005")
+
+	perLine := service.RoundingPolicy{Mode: service.RoundingModeHalfUp, Scope: service.RoundingScopePerLine, Places: 2}
+	assert.Equal(t, "10.01", perLine.RoundLine(amount).String())
+	assert.Equal(t, "10.01", perLine.RoundTotal(amount).String())
+
+	perTotal := service.RoundingPolicy{Mode: service.RoundingModeHalfUp, Scope: service.RoundingScopePerTotal, Places: 2}
+	assert.Equal(t, "10.005", perTotal.RoundLine(amount).String())
+	assert.Equal(t, "10.01", perTotal.RoundTotal(amount).String())
+}
+
+func TestParseRoundingPolicy(t *testing.T) {
+	policy, err := service.ParseRoundingPolicy("", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, service.DefaultRoundingPolicy(), policy)
+
+	policy, err = service.ParseRoundingPolicy("bankers", "per_total", "0")
+	assert.NoError(t, err)
+	assert.Equal(t, service.RoundingModeBankers, policy.Mode)
+	assert.Equal(t, service.RoundingScopePerTotal, policy.Scope)
+	assert.Equal(t, int32(0), policy.Places)
+
+	_, err = service.ParseRoundingPolicy("ceil", "", "")
+	assert.Error(t, err)
+
+	_, err = service.ParseRoundingPolicy("", "per_item", "")
+	assert.Error(t, err)
+
+	_, err = service.ParseRoundingPolicy("", "", "-1")
+	assert.Error(t, err)
+}
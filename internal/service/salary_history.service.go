@@ -0,0 +1,167 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+)
+
+// ErrDuplicateSalaryChange is returned when an employee already has a salary change on the same effective date.
+var ErrDuplicateSalaryChange = errors.New("a salary change is already effective on this date")
+
+// SalaryHistoryServiceInterface defines the methods of SalaryHistoryService for mocking purposes.
+//
+//go:generate mockgen -source=salary_history.service.go -destination=../../tests/mocks/service/mock_salary_history_service.go -package=mocks
+type SalaryHistoryServiceInterface interface {
+	// CreateSalaryChange records a new salary of an employee, in force from effectiveFrom.
+	CreateSalaryChange(userID uuid.UUID, salary decimal.Decimal, effectiveFrom time.Time, reason string, changedBy uuid.UUID, ipAddress, requestID string) (*domain.SalaryHistory, error)
+	// GetSalaryChanges returns every salary change of an employee, oldest first.
+	GetSalaryChanges(userID uuid.UUID) ([]domain.SalaryHistory, error)
+}
+
+// SalaryHistoryService provides business logic for employee salary changes.
+type SalaryHistoryService struct {
+	salaryHistoryRepo   repository.SalaryHistoryRepository
+	employeeProfileRepo repository.EmployeeProfileRepository
+	uow                 repository.UnitOfWork // For transaction management
+}
+
+// NewSalaryHistoryService creates a new SalaryHistoryService.
+func NewSalaryHistoryService(
+	salaryHistoryRepo repository.SalaryHistoryRepository,
+	employeeProfileRepo repository.EmployeeProfileRepository,
+	uow repository.UnitOfWork,
+) *SalaryHistoryService {
+	return &SalaryHistoryService{
+		salaryHistoryRepo:   salaryHistoryRepo,
+		employeeProfileRepo: employeeProfileRepo,
+		uow:                 uow,
+	}
+}
+
+// CreateSalaryChange records a new salary of an employee, in force from effectiveFrom until the
+// next change. The change and its audit log entry are written in one transaction. Payroll periods
+// that are already processed are not recalculated; reverse and rerun them to apply the change.
+func (s *SalaryHistoryService) CreateSalaryChange(
+	userID uuid.UUID,
+	salary decimal.Decimal,
+	effectiveFrom time.Time,
+	reason string,
+	changedBy uuid.UUID,
+	ipAddress, requestID string,
+) (*domain.SalaryHistory, error) {
+	if !salary.IsPositive() {
+		return nil, errors.New("salary must be greater than 0")
+	}
+
+	profile, err := s.employeeProfileRepo.GetEmployeeProfileByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	now := time.Now()
+	history := &domain.SalaryHistory{
+		UserID:        userID,
+		EffectiveFrom: effectiveFrom,
+		Salary:        salary,
+		Reason:        reason,
+		BaseModel: domain.BaseModel{
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: changedBy,
+			UpdatedBy: changedBy,
+			IPAddress: ipAddress,
+		},
+	}
+
+	err = s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := repos.SalaryHistories.CreateSalaryHistory(history); err != nil {
+			if errors.Is(err, repository.ErrDuplicateRecord) {
+				return ErrDuplicateSalaryChange
+			}
+			return err
+		}
+
+		if err := repository.CreateAuditLog(
+			repos.AuditLogs,
+			&changedBy,
+			"CREATE",
+			"SalaryHistory",
+			&history.ID,
+			nil, // oldValue is nil for creation
+			history,
+			ipAddress,
+			requestID,
+		); err != nil {
+			return fmt.Errorf("failed to write audit log for salary change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetSalaryChanges returns every salary change of an employee, oldest first.
+func (s *SalaryHistoryService) GetSalaryChanges(userID uuid.UUID) ([]domain.SalaryHistory, error) {
+	profile, err := s.employeeProfileRepo.GetEmployeeProfileByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrEmployeeNotFound
+	}
+	return s.salaryHistoryRepo.GetSalaryHistoriesByUserID(userID)
+}
+
+// salarySegments splits a payroll period at the salary changes effective within it. The first
+// segment starts on the period's start date with the salary in force on that day: the latest change
+// effective on or before it, or profileSalary when there is none. histories must be sorted by
+// effective date.
+func salarySegments(profileSalary decimal.Decimal, histories []domain.SalaryHistory, period *domain.PayrollPeriod) []SalarySegment {
+	segments := []SalarySegment{newSalarySegment(period.StartDate, profileSalary)}
+	for _, h := range histories {
+		switch {
+		case !h.EffectiveFrom.After(period.StartDate):
+			segments[0].Salary = h.Salary
+		case !h.EffectiveFrom.After(period.EndDate):
+			segments = append(segments, newSalarySegment(h.EffectiveFrom, h.Salary))
+		}
+	}
+	return segments
+}
+
+func newSalarySegment(effectiveFrom time.Time, salary decimal.Decimal) SalarySegment {
+	return SalarySegment{
+		EffectiveFrom:        effectiveFrom,
+		Salary:               salary,
+		HourlyRate:           decimal.Zero,
+		WorkedHours:          decimal.Zero,
+		UnpaidLeaveHours:     decimal.Zero,
+		OvertimeHours:        decimal.Zero,
+		RestDayOvertimeHours: decimal.Zero,
+	}
+}
+
+// salarySegmentOn returns the segment whose salary is in force on date. Dates before the first
+// segment belong to the first one.
+func salarySegmentOn(segments []SalarySegment, date time.Time) *SalarySegment {
+	i := 0
+	for j := range segments {
+		if !date.Before(segments[j].EffectiveFrom) {
+			i = j
+		}
+	}
+	return &segments[i]
+}
@@ -0,0 +1,226 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+	"payroll-system/internal/service"
+	mockrepo "payroll-system/tests/mocks/repository"
+)
+
+func TestSalaryHistoryService_CreateSalaryChange(t *testing.T) {
+	userID := uuid.New()
+	adminID := uuid.New()
+	effectiveFrom := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		salary     decimal.Decimal
+		setupMocks func(profileRepo *mockrepo.MockEmployeeProfileRepository, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories)
+		expectErr  error
+		errMessage string
+	}{
+		{
+			name:   "success",
+			salary: decimal.NewFromInt(6000000),
+			setupMocks: func(profileRepo *mockrepo.MockEmployeeProfileRepository, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				profileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID}, nil)
+				expectTransaction(uow, txRepos)
+				tx.salaryHistoryRepo.EXPECT().CreateSalaryHistory(gomock.Any()).DoAndReturn(func(h *domain.SalaryHistory) error {
+					assert.Equal(t, userID, h.UserID)
+					assert.Equal(t, effectiveFrom, h.EffectiveFrom)
+					assert.Equal(t, adminID, h.CreatedBy)
+					return nil
+				})
+				// The change is audited in the same transaction
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, "CREATE", log.Action)
+					assert.Equal(t, "SalaryHistory", log.EntityName)
+					return nil
+				})
+			},
+		},
+		{
+			name:   "salary not positive",
+			salary: decimal.Zero,
+			setupMocks: func(profileRepo *mockrepo.MockEmployeeProfileRepository, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+			},
+			errMessage: "salary must be greater than 0",
+		},
+		{
+			name:   "employee not found",
+			salary: decimal.NewFromInt(6000000),
+			setupMocks: func(profileRepo *mockrepo.MockEmployeeProfileRepository, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				profileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(nil, nil)
+			},
+			expectErr: service.ErrEmployeeNotFound,
+		},
+		{
+			name:   "change already effective on the date",
+			salary: decimal.NewFromInt(6000000),
+			setupMocks: func(profileRepo *mockrepo.MockEmployeeProfileRepository, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				profileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID}, nil)
+				expectTransaction(uow, txRepos)
+				tx.salaryHistoryRepo.EXPECT().CreateSalaryHistory(gomock.Any()).Return(repository.ErrDuplicateRecord)
+			},
+			expectErr: service.ErrDuplicateSalaryChange,
+		},
+		{
+			name:   "audit failure rolls back the change",
+			salary: decimal.NewFromInt(6000000),
+			setupMocks: func(profileRepo *mockrepo.MockEmployeeProfileRepository, tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				profileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID}, nil)
+				expectTransaction(uow, txRepos)
+				tx.salaryHistoryRepo.EXPECT().CreateSalaryHistory(gomock.Any()).Return(nil)
+				tx.auditRepo.EXPECT().Create(gomock.Any()).Return(errors.New("audit db down"))
+			},
+			errMessage: "failed to write audit log for salary change: audit db down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+			profileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			tt.setupMocks(profileRepo, tx, uow, txRepos)
+
+			svc := service.NewSalaryHistoryService(salaryHistoryRepo, profileRepo, uow)
+			history, err := svc.CreateSalaryChange(userID, tt.salary, effectiveFrom, "Promotion", adminID, "127.0.0.1", "req-123")
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, history)
+			case tt.errMessage != "":
+				assert.EqualError(t, err, tt.errMessage)
+				assert.Nil(t, history)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, "Promotion", history.Reason)
+				assert.True(t, tt.salary.Equal(history.Salary))
+			}
+		})
+	}
+}
+
+func TestSalaryHistoryService_GetSalaryChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+	profileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+	svc := service.NewSalaryHistoryService(salaryHistoryRepo, profileRepo, mockrepo.NewMockUnitOfWork(ctrl))
+
+	userID := uuid.New()
+	profileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID}, nil)
+	salaryHistoryRepo.EXPECT().GetSalaryHistoriesByUserID(userID).Return([]domain.SalaryHistory{{UserID: userID}, {UserID: userID}}, nil)
+
+	histories, err := svc.GetSalaryChanges(userID)
+	require.NoError(t, err)
+	assert.Len(t, histories, 2)
+
+	unknownID := uuid.New()
+	profileRepo.EXPECT().GetEmployeeProfileByUserID(unknownID).Return(nil, nil)
+	_, err = svc.GetSalaryChanges(unknownID)
+	assert.ErrorIs(t, err, service.ErrEmployeeNotFound)
+}
+
+func TestPreviewPayroll_MidPeriodRaise(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Monday 4 to Friday 15 August: 10 working days, 80 hours
+	period := &domain.PayrollPeriod{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		StartDate: time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC),
+	}
+	userID := uuid.New()
+
+	var attendances []domain.Attendance
+	for d := period.StartDate; !d.After(period.EndDate); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		checkIn := d.Add(9 * time.Hour)
+		checkOut := checkIn.Add(8 * time.Hour)
+		attendances = append(attendances, domain.Attendance{UserID: userID, Date: d, CheckInTime: checkIn, CheckOutTime: &checkOut})
+	}
+
+	payrollPeriodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
+	employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+	salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+	attendanceRepo := mockrepo.NewMockAttendanceRepository(ctrl)
+	overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
+	reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+	holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+	workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+	leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
+
+	payrollPeriodRepo.EXPECT().GetPayrollPeriodByID(period.ID).Return(period, nil)
+	// The profile salary is superseded by the salary history
+	employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(gomock.Any(), gomock.Any()).Return([]domain.EmployeeProfile{{UserID: userID, Salary: decimal.NewFromInt(4000000)}}, nil)
+	salaryHistoryRepo.EXPECT().GetSalaryHistoriesForPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.SalaryHistory{
+		userID: {
+			{UserID: userID, EffectiveFrom: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), Salary: decimal.NewFromInt(8000000)},
+			{UserID: userID, EffectiveFrom: time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC), Salary: decimal.NewFromInt(12000000)},
+		},
+	}, nil)
+	attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Attendance{userID: attendances}, nil)
+	overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Overtime{
+		userID: {
+			{UserID: userID, Date: time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC), Hours: 1},
+			{UserID: userID, Date: time.Date(2025, 8, 12, 0, 0, 0, 0, time.UTC), Hours: 2},
+		},
+	}, nil)
+	reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(period.EndDate).Return(nil, nil)
+	holidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return(nil, nil)
+	workScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(nil, nil)
+	leaveRepo.EXPECT().GetApprovedLeaveRequestsByPeriodGroupedByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	registry := service.NewPayslipComponentRegistry(service.BasicSalaryCalculator{}, service.OvertimeCalculator{})
+	svc := service.NewPayrollService(
+		mockrepo.NewMockPayslipRepository(ctrl), payrollPeriodRepo, employeeProfileRepo, salaryHistoryRepo, attendanceRepo, overtimeRepo, reimbursementRepo, holidayRepo, workScheduleRepo, leaveRepo,
+		mockrepo.NewMockAuditLogRepository(ctrl), mockrepo.NewMockUnitOfWork(ctrl), service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), registry,
+	)
+
+	preview, err := svc.PreviewPayroll(period.ID)
+	require.NoError(t, err)
+	require.Len(t, preview.Payslips, 1)
+	payslip := preview.Payslips[0]
+
+	// The first week is paid at 8,000,000 / 80h, the second at 12,000,000 / 80h
+	require.Len(t, payslip.Items, 4)
+	assert.Equal(t, "Basic Salary (from 2025-08-04)", payslip.Items[0].Name)
+	assert.Equal(t, "40", payslip.Items[0].Quantity.String())
+	assert.Equal(t, "100000", payslip.Items[0].Rate.String())
+	assert.Equal(t, "4000000", payslip.Items[0].Amount.String())
+	assert.Equal(t, "Basic Salary (from 2025-08-11)", payslip.Items[1].Name)
+	assert.Equal(t, "150000", payslip.Items[1].Rate.String())
+	assert.Equal(t, "6000000", payslip.Items[1].Amount.String())
+
+	// Overtime is paid at the rate in force on the day it was worked
+	assert.Equal(t, "Overtime (from 2025-08-04)", payslip.Items[2].Name)
+	assert.Equal(t, "200000", payslip.Items[2].Amount.String())
+	assert.Equal(t, "Overtime (from 2025-08-11)", payslip.Items[3].Name)
+	assert.Equal(t, "600000", payslip.Items[3].Amount.String())
+
+	assert.Equal(t, "12000000", payslip.BaseSalary.String())
+	assert.Equal(t, "10000000", payslip.ProratedSalary.String())
+	assert.Equal(t, "800000", payslip.OvertimePay.String())
+	assert.Equal(t, "10800000", payslip.TotalTakeHomePay.String())
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+)
+
+var (
+	// ErrWorkScheduleNotFound is returned when a work schedule does not exist.
+	ErrWorkScheduleNotFound = errors.New("work schedule not found")
+	// ErrDuplicateWorkSchedule is returned when another work schedule already has the name.
+	ErrDuplicateWorkSchedule = errors.New("a work schedule with this name already exists")
+	// ErrInvalidWorkSchedule is returned when the working days, shift or hours of a work schedule are invalid.
+	ErrInvalidWorkSchedule = errors.New("invalid work schedule")
+)
+
+// WorkScheduleServiceInterface defines the methods of WorkScheduleService for mocking purposes.
+//
+//go:generate mockgen -source=work_schedule.service.go -destination=../../tests/mocks/service/mock_work_schedule_service.go -package=mocks
+type WorkScheduleServiceInterface interface {
+	// CreateWorkSchedule creates a named work schedule.
+	CreateWorkSchedule(schedule *domain.WorkSchedule, createdBy uuid.UUID, ipAddress, requestID string) (*domain.WorkSchedule, error)
+	// UpdateWorkSchedule changes the name, working days, shift and hours of a work schedule.
+	UpdateWorkSchedule(id uuid.UUID, schedule *domain.WorkSchedule, updatedBy uuid.UUID, ipAddress, requestID string) (*domain.WorkSchedule, error)
+	// GetWorkSchedule returns a work schedule by its ID.
+	GetWorkSchedule(id uuid.UUID) (*domain.WorkSchedule, error)
+	// GetWorkSchedules returns every work schedule, ordered by name.
+	GetWorkSchedules() ([]domain.WorkSchedule, error)
+	// AssignWorkSchedule assigns a work schedule to an employee, or the default schedule when scheduleID is nil.
+	AssignWorkSchedule(userID uuid.UUID, scheduleID *uuid.UUID, assignedBy uuid.UUID, ipAddress, requestID string) (*domain.EmployeeProfile, error)
+}
+
+// WorkScheduleService provides business logic for work schedules and their assignment to employees.
+type WorkScheduleService struct {
+	workScheduleRepo repository.WorkScheduleRepository
+	uow              repository.UnitOfWork // For transaction management
+}
+
+// NewWorkScheduleService creates a new WorkScheduleService.
+func NewWorkScheduleService(workScheduleRepo repository.WorkScheduleRepository, uow repository.UnitOfWork) *WorkScheduleService {
+	return &WorkScheduleService{
+		workScheduleRepo: workScheduleRepo,
+		uow:              uow,
+	}
+}
+
+// CreateWorkSchedule creates a named work schedule from the name, working days, shift, daily hours
+// and break of schedule. The schedule and its audit log entry are written in one transaction.
+func (s *WorkScheduleService) CreateWorkSchedule(schedule *domain.WorkSchedule, createdBy uuid.UUID, ipAddress, requestID string) (*domain.WorkSchedule, error) {
+	if err := normalizeWorkSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	schedule.BaseModel = domain.BaseModel{
+		CreatedAt: now,
+		UpdatedAt: now,
+		CreatedBy: createdBy,
+		UpdatedBy: createdBy,
+		IPAddress: ipAddress,
+	}
+
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := repos.WorkSchedules.CreateWorkSchedule(schedule); err != nil {
+			if errors.Is(err, repository.ErrDuplicateRecord) {
+				return ErrDuplicateWorkSchedule
+			}
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &createdBy, "CREATE", "WorkSchedule", &schedule.ID, nil, schedule, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for work schedule: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// UpdateWorkSchedule changes the name, working days, shift, daily hours and break of a work
+// schedule to those of changes. Payroll periods that are already processed are not recalculated;
+// reverse and rerun them to apply the change.
+func (s *WorkScheduleService) UpdateWorkSchedule(id uuid.UUID, changes *domain.WorkSchedule, updatedBy uuid.UUID, ipAddress, requestID string) (*domain.WorkSchedule, error) {
+	if err := normalizeWorkSchedule(changes); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.workScheduleRepo.GetWorkScheduleByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrWorkScheduleNotFound
+	}
+
+	oldValue := *schedule
+	schedule.Name = changes.Name
+	schedule.WorkingDays = changes.WorkingDays
+	schedule.ShiftStart = changes.ShiftStart
+	schedule.ShiftEnd = changes.ShiftEnd
+	schedule.DailyHours = changes.DailyHours
+	schedule.BreakMinutes = changes.BreakMinutes
+	schedule.UpdatedAt = time.Now()
+	schedule.UpdatedBy = updatedBy
+	schedule.IPAddress = ipAddress
+
+	err = s.uow.Transaction(func(repos *repository.Repositories) error {
+		if err := repos.WorkSchedules.UpdateWorkSchedule(schedule); err != nil {
+			if errors.Is(err, repository.ErrDuplicateRecord) {
+				return ErrDuplicateWorkSchedule
+			}
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &updatedBy, "UPDATE", "WorkSchedule", &schedule.ID, oldValue, schedule, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for work schedule: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// GetWorkSchedule returns a work schedule by its ID.
+func (s *WorkScheduleService) GetWorkSchedule(id uuid.UUID) (*domain.WorkSchedule, error) {
+	schedule, err := s.workScheduleRepo.GetWorkScheduleByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrWorkScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// GetWorkSchedules returns every work schedule, ordered by name.
+func (s *WorkScheduleService) GetWorkSchedules() ([]domain.WorkSchedule, error) {
+	return s.workScheduleRepo.GetAllWorkSchedules()
+}
+
+// AssignWorkSchedule assigns a work schedule to an employee. With a nil scheduleID the employee
+// follows the default schedule again. Attendance is validated against the new schedule from now on;
+// payroll periods that are already processed are not recalculated.
+func (s *WorkScheduleService) AssignWorkSchedule(userID uuid.UUID, scheduleID *uuid.UUID, assignedBy uuid.UUID, ipAddress, requestID string) (*domain.EmployeeProfile, error) {
+	var profile *domain.EmployeeProfile
+	err := s.uow.Transaction(func(repos *repository.Repositories) error {
+		var err error
+		profile, err = loadEmployee(repos.Users, repos.EmployeeProfiles, userID)
+		if err != nil {
+			return err
+		}
+		if scheduleID != nil {
+			schedule, err := repos.WorkSchedules.GetWorkScheduleByID(*scheduleID)
+			if err != nil {
+				return err
+			}
+			if schedule == nil {
+				return ErrWorkScheduleNotFound
+			}
+		}
+		oldProfile := *profile
+
+		profile.WorkScheduleID = scheduleID
+		profile.UpdatedAt = time.Now()
+		profile.UpdatedBy = assignedBy
+		profile.IPAddress = ipAddress
+
+		if err := repos.EmployeeProfiles.UpdateEmployeeProfile(profile); err != nil {
+			return err
+		}
+
+		if err := repository.CreateAuditLog(repos.AuditLogs, &assignedBy, "ASSIGN", "EmployeeProfile", &profile.ID, oldProfile, profile, ipAddress, requestID); err != nil {
+			return fmt.Errorf("failed to write audit log for employee profile: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// normalizeWorkSchedule validates a work schedule and puts its working days in the canonical order.
+// The daily hours must fit in the shift once the break is taken off.
+func normalizeWorkSchedule(schedule *domain.WorkSchedule) error {
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	if schedule.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWorkSchedule)
+	}
+
+	workingDays, err := domain.ParseWeekdays(schedule.WorkingDays)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWorkSchedule, err)
+	}
+	schedule.WorkingDays = workingDays
+
+	for _, shiftTime := range []*string{&schedule.ShiftStart, &schedule.ShiftEnd} {
+		parsed, err := time.Parse("15:04", *shiftTime)
+		if err != nil {
+			return fmt.Errorf("%w: invalid shift time %q, use HH:MM", ErrInvalidWorkSchedule, *shiftTime)
+		}
+		*shiftTime = parsed.Format("15:04")
+	}
+	if schedule.ShiftStart == schedule.ShiftEnd {
+		return fmt.Errorf("%w: shift start and end cannot be the same", ErrInvalidWorkSchedule)
+	}
+	shift, err := schedule.ShiftDuration()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWorkSchedule, err)
+	}
+	if schedule.BreakMinutes < 0 {
+		return fmt.Errorf("%w: break minutes cannot be negative", ErrInvalidWorkSchedule)
+	}
+	if !schedule.DailyHours.IsPositive() {
+		return fmt.Errorf("%w: daily hours must be greater than 0", ErrInvalidWorkSchedule)
+	}
+	paidHours := shift.Hours() - float64(schedule.BreakMinutes)/60
+	if schedule.DailyHours.InexactFloat64() > paidHours {
+		return fmt.Errorf("%w: %s daily hours do not fit in a %s-%s shift with a %d minute break",
+			ErrInvalidWorkSchedule, schedule.DailyHours, schedule.ShiftStart, schedule.ShiftEnd, schedule.BreakMinutes)
+	}
+	return nil
+}
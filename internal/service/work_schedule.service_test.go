@@ -0,0 +1,339 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payroll-system/internal/domain"
+	"payroll-system/internal/repository"
+	"payroll-system/internal/service"
+	mockrepo "payroll-system/tests/mocks/repository"
+)
+
+// nightShift is a six-day schedule whose shift crosses midnight.
+func nightShift() *domain.WorkSchedule {
+	return &domain.WorkSchedule{
+		BaseModel:    domain.BaseModel{ID: uuid.New()},
+		Name:         "Night Shift",
+		WorkingDays:  "mon,tue,wed,thu,fri,sat",
+		ShiftStart:   "22:00",
+		ShiftEnd:     "06:00",
+		DailyHours:   decimal.NewFromInt(7),
+		BreakMinutes: 60,
+	}
+}
+
+func TestWorkScheduleService_CreateWorkSchedule(t *testing.T) {
+	adminID := uuid.New()
+
+	tests := []struct {
+		name       string
+		schedule   domain.WorkSchedule
+		setupMocks func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories)
+		expectErr  error
+		errMessage string
+	}{
+		{
+			name: "success normalizes working days and shift times",
+			schedule: domain.WorkSchedule{
+				Name: " Retail ", WorkingDays: "Sat, mon,tue,wed,thu,fri,mon", ShiftStart: "9:00", ShiftEnd: "17:00",
+				DailyHours: decimal.NewFromInt(7), BreakMinutes: 60,
+			},
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.workScheduleRepo.EXPECT().CreateWorkSchedule(gomock.Any()).DoAndReturn(func(s *domain.WorkSchedule) error {
+					assert.Equal(t, "Retail", s.Name)
+					assert.Equal(t, "mon,tue,wed,thu,fri,sat", s.WorkingDays)
+					assert.Equal(t, "09:00", s.ShiftStart)
+					assert.Equal(t, adminID, s.CreatedBy)
+					return nil
+				})
+				// The schedule is audited in the same transaction
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, "CREATE", log.Action)
+					assert.Equal(t, "WorkSchedule", log.EntityName)
+					return nil
+				})
+			},
+		},
+		{
+			name:     "overnight shift",
+			schedule: *nightShift(),
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.workScheduleRepo.EXPECT().CreateWorkSchedule(gomock.Any()).Return(nil)
+				tx.auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "name already taken",
+			schedule: domain.WorkSchedule{
+				Name: "Retail", WorkingDays: "mon", ShiftStart: "09:00", ShiftEnd: "17:00", DailyHours: decimal.NewFromInt(8),
+			},
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {
+				expectTransaction(uow, txRepos)
+				tx.workScheduleRepo.EXPECT().CreateWorkSchedule(gomock.Any()).Return(repository.ErrDuplicateRecord)
+			},
+			expectErr: service.ErrDuplicateWorkSchedule,
+		},
+		{
+			name:       "no working days",
+			schedule:   domain.WorkSchedule{Name: "Empty", ShiftStart: "09:00", ShiftEnd: "17:00", DailyHours: decimal.NewFromInt(8)},
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {},
+			errMessage: "invalid work schedule: at least one working day is required",
+		},
+		{
+			name:       "unknown weekday",
+			schedule:   domain.WorkSchedule{Name: "Typo", WorkingDays: "mon,thur", ShiftStart: "09:00", ShiftEnd: "17:00", DailyHours: decimal.NewFromInt(8)},
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {},
+			errMessage: `invalid work schedule: unknown weekday "thur"`,
+		},
+		{
+			name:       "invalid shift time",
+			schedule:   domain.WorkSchedule{Name: "Late", WorkingDays: "mon", ShiftStart: "25:00", ShiftEnd: "17:00", DailyHours: decimal.NewFromInt(8)},
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {},
+			errMessage: `invalid work schedule: invalid shift time "25:00", use HH:MM`,
+		},
+		{
+			name:       "daily hours longer than the shift",
+			schedule:   domain.WorkSchedule{Name: "Long", WorkingDays: "mon", ShiftStart: "09:00", ShiftEnd: "17:00", DailyHours: decimal.NewFromInt(8), BreakMinutes: 30},
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {},
+			errMessage: "invalid work schedule: 8 daily hours do not fit in a 09:00-17:00 shift with a 30 minute break",
+		},
+		{
+			name:       "no daily hours",
+			schedule:   domain.WorkSchedule{Name: "Zero", WorkingDays: "mon", ShiftStart: "09:00", ShiftEnd: "17:00"},
+			setupMocks: func(tx *txMocks, uow *mockrepo.MockUnitOfWork, txRepos *repository.Repositories) {},
+			errMessage: "invalid work schedule: daily hours must be greater than 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			tt.setupMocks(tx, uow, txRepos)
+
+			svc := service.NewWorkScheduleService(mockrepo.NewMockWorkScheduleRepository(ctrl), uow)
+			schedule := tt.schedule
+			created, err := svc.CreateWorkSchedule(&schedule, adminID, "127.0.0.1", "req-123")
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, created)
+			case tt.errMessage != "":
+				assert.ErrorIs(t, err, service.ErrInvalidWorkSchedule)
+				assert.EqualError(t, err, tt.errMessage)
+				assert.Nil(t, created)
+			default:
+				require.NoError(t, err)
+				assert.NotNil(t, created)
+			}
+		})
+	}
+}
+
+func TestWorkScheduleService_UpdateWorkSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	adminID := uuid.New()
+	existing := nightShift()
+	changes := &domain.WorkSchedule{Name: "Night Shift", WorkingDays: "mon,tue,wed,thu,fri", ShiftStart: "21:00", ShiftEnd: "05:00", DailyHours: decimal.NewFromInt(8)}
+
+	workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+	uow := mockrepo.NewMockUnitOfWork(ctrl)
+	svc := service.NewWorkScheduleService(workScheduleRepo, uow)
+
+	t.Run("not found", func(t *testing.T) {
+		workScheduleRepo.EXPECT().GetWorkScheduleByID(existing.ID).Return(nil, nil)
+
+		_, err := svc.UpdateWorkSchedule(existing.ID, changes, adminID, "127.0.0.1", "req-123")
+		assert.ErrorIs(t, err, service.ErrWorkScheduleNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		txRepos, tx := newTxRepositories(ctrl)
+		expectTransaction(uow, txRepos)
+		workScheduleRepo.EXPECT().GetWorkScheduleByID(existing.ID).Return(existing, nil)
+		tx.workScheduleRepo.EXPECT().UpdateWorkSchedule(existing).Return(nil)
+		tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+			assert.Equal(t, "UPDATE", log.Action)
+			assert.Contains(t, string(log.OldValue), `"working_days":"mon,tue,wed,thu,fri,sat"`)
+			assert.Contains(t, string(log.NewValue), `"working_days":"mon,tue,wed,thu,fri"`)
+			return nil
+		})
+
+		updated, err := svc.UpdateWorkSchedule(existing.ID, changes, adminID, "127.0.0.1", "req-123")
+		require.NoError(t, err)
+		assert.Equal(t, "21:00", updated.ShiftStart)
+		assert.Equal(t, 0, updated.BreakMinutes)
+		assert.Equal(t, adminID, updated.UpdatedBy)
+	})
+}
+
+func TestWorkScheduleService_AssignWorkSchedule(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	schedule := nightShift()
+
+	tests := []struct {
+		name       string
+		scheduleID *uuid.UUID
+		setupMocks func(tx *txMocks)
+		expectErr  error
+	}{
+		{
+			name:       "success",
+			scheduleID: &schedule.ID,
+			setupMocks: func(tx *txMocks) {
+				tx.workScheduleRepo.EXPECT().GetWorkScheduleByID(schedule.ID).Return(schedule, nil)
+				tx.employeeProfileRepo.EXPECT().UpdateEmployeeProfile(gomock.Any()).DoAndReturn(func(p *domain.EmployeeProfile) error {
+					assert.Equal(t, schedule.ID, *p.WorkScheduleID)
+					return nil
+				})
+				tx.auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *domain.AuditLog) error {
+					assert.Equal(t, "ASSIGN", log.Action)
+					assert.Equal(t, "EmployeeProfile", log.EntityName)
+					return nil
+				})
+			},
+		},
+		{
+			name: "back to the default schedule",
+			setupMocks: func(tx *txMocks) {
+				tx.employeeProfileRepo.EXPECT().UpdateEmployeeProfile(gomock.Any()).DoAndReturn(func(p *domain.EmployeeProfile) error {
+					assert.Nil(t, p.WorkScheduleID)
+					return nil
+				})
+				tx.auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:       "schedule not found",
+			scheduleID: &schedule.ID,
+			setupMocks: func(tx *txMocks) {
+				tx.workScheduleRepo.EXPECT().GetWorkScheduleByID(schedule.ID).Return(nil, nil)
+			},
+			expectErr: service.ErrWorkScheduleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uow := mockrepo.NewMockUnitOfWork(ctrl)
+			txRepos, tx := newTxRepositories(ctrl)
+			expectTransaction(uow, txRepos)
+			tx.employeeProfileRepo.EXPECT().GetEmployeeProfileByUserID(userID).Return(&domain.EmployeeProfile{UserID: userID, WorkScheduleID: &schedule.ID}, nil)
+			tx.userRepo.EXPECT().GetUserByID(userID).Return(&domain.User{BaseModel: domain.BaseModel{ID: userID}}, nil)
+			tt.setupMocks(tx)
+
+			svc := service.NewWorkScheduleService(mockrepo.NewMockWorkScheduleRepository(ctrl), uow)
+			profile, err := svc.AssignWorkSchedule(userID, tt.scheduleID, adminID, "127.0.0.1", "req-123")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, profile)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.scheduleID, profile.WorkScheduleID)
+		})
+	}
+}
+
+func TestPreviewPayroll_FollowsWorkSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Monday 2 June through Sunday 8 June 2025: six working days of the night shift
+	period := &domain.PayrollPeriod{
+		BaseModel: domain.BaseModel{ID: uuid.New()},
+		StartDate: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC),
+	}
+	userID := uuid.New()
+	schedule := nightShift()
+
+	// Attendance times are loaded without their date: a 22:00 check-in with a 06:00 check-out
+	// is a full night. Saturday's shift ends at 02:00, short of the daily hours.
+	var attendances []domain.Attendance
+	for day := 2; day <= 7; day++ {
+		checkOut := time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC)
+		if day == 7 {
+			checkOut = time.Date(0, 1, 1, 2, 0, 0, 0, time.UTC)
+		}
+		attendances = append(attendances, domain.Attendance{
+			UserID:       userID,
+			Date:         time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC),
+			CheckInTime:  time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+			CheckOutTime: &checkOut,
+		})
+	}
+
+	payrollPeriodRepo := mockrepo.NewMockPayrollPeriodRepository(ctrl)
+	employeeProfileRepo := mockrepo.NewMockEmployeeProfileRepository(ctrl)
+	salaryHistoryRepo := mockrepo.NewMockSalaryHistoryRepository(ctrl)
+	attendanceRepo := mockrepo.NewMockAttendanceRepository(ctrl)
+	overtimeRepo := mockrepo.NewMockOvertimeRepository(ctrl)
+	reimbursementRepo := mockrepo.NewMockReimbursementRepository(ctrl)
+	holidayRepo := mockrepo.NewMockHolidayRepository(ctrl)
+	workScheduleRepo := mockrepo.NewMockWorkScheduleRepository(ctrl)
+	leaveRepo := mockrepo.NewMockLeaveRepository(ctrl)
+
+	payrollPeriodRepo.EXPECT().GetPayrollPeriodByID(period.ID).Return(period, nil)
+	employeeProfileRepo.EXPECT().GetEmployeeProfilesForPeriod(period.StartDate, period.EndDate).Return([]domain.EmployeeProfile{
+		{UserID: userID, Salary: decimal.NewFromInt(4200000), WorkScheduleID: &schedule.ID},
+	}, nil)
+	salaryHistoryRepo.EXPECT().GetSalaryHistoriesForPeriodGroupedByUser(period.StartDate, period.EndDate).Return(nil, nil)
+	attendanceRepo.EXPECT().GetAttendancesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Attendance{userID: attendances}, nil)
+	overtimeRepo.EXPECT().GetApprovedOvertimesByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(map[uuid.UUID][]domain.Overtime{
+		userID: {
+			{UserID: userID, Date: time.Date(2025, 6, 7, 0, 0, 0, 0, time.UTC), Hours: 2}, // Saturday is a working day
+			{UserID: userID, Date: time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC), Hours: 1}, // Sunday is a rest day
+		},
+	}, nil)
+	reimbursementRepo.EXPECT().GetPayableReimbursementsGroupedByUser(period.EndDate).Return(nil, nil)
+	holidayRepo.EXPECT().GetHolidaysBetween(period.StartDate, period.EndDate).Return(nil, nil)
+	workScheduleRepo.EXPECT().GetAssignedWorkSchedules().Return(map[uuid.UUID]*domain.WorkSchedule{userID: schedule}, nil)
+	leaveRepo.EXPECT().GetApprovedLeaveRequestsByPeriodGroupedByUser(period.StartDate, period.EndDate).Return(nil, nil)
+
+	registry := service.NewPayslipComponentRegistry(service.BasicSalaryCalculator{}, service.OvertimeCalculator{})
+	svc := service.NewPayrollService(
+		mockrepo.NewMockPayslipRepository(ctrl), payrollPeriodRepo, employeeProfileRepo, salaryHistoryRepo, attendanceRepo, overtimeRepo, reimbursementRepo, holidayRepo, workScheduleRepo, leaveRepo,
+		mockrepo.NewMockAuditLogRepository(ctrl), mockrepo.NewMockUnitOfWork(ctrl), service.DefaultRoundingPolicy(), service.DefaultAttendancePolicy(), registry,
+	)
+
+	preview, err := svc.PreviewPayroll(period.ID)
+	require.NoError(t, err)
+	require.Len(t, preview.Payslips, 1)
+	payslip := preview.Payslips[0]
+
+	// Six working days of 7 hours: 4,200,000 / 42h = 100,000 per hour. Five full nights are paid.
+	require.Len(t, payslip.Items, 3)
+	assert.Equal(t, "35", payslip.Items[0].Quantity.String())
+	assert.Equal(t, "100000", payslip.Items[0].Rate.String())
+	assert.Equal(t, "3500000", payslip.ProratedSalary.String())
+
+	// Overtime on the working Saturday is paid at 2x, on the Sunday rest day at 3x
+	assert.Equal(t, "Overtime", payslip.Items[1].Name)
+	assert.Equal(t, "400000", payslip.Items[1].Amount.String())
+	assert.Equal(t, "Overtime (rest day)", payslip.Items[2].Name)
+	assert.Equal(t, "300000", payslip.Items[2].Amount.String())
+
+	// The employee's schedule is attached for the payslip response
+	assert.Equal(t, "Night Shift", payslip.WorkCalendar.Schedule().Name)
+}